@@ -0,0 +1,174 @@
+package build
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/schema"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Blocklist is a builder for the admin/blocklists page
+// It can only be accessed by a Domain Owner
+type Blocklist struct {
+	_blocklist *model.Blocklist
+	CommonWithTemplate
+}
+
+// NewBlocklist returns a fully initialized `Blocklist` builder.
+func NewBlocklist(factory Factory, request *http.Request, response http.ResponseWriter, blocklist *model.Blocklist, template model.Template, actionID string) (Blocklist, error) {
+
+	const location = "build.NewBlocklist"
+
+	// Create the underlying Common builder
+	common, err := NewCommonWithTemplate(factory, request, response, template, actionID)
+
+	if err != nil {
+		return Blocklist{}, derp.Wrap(err, location, "Error creating common builder")
+	}
+
+	// Verify that the user is a Domain Owner
+	if !common._authorization.DomainOwner {
+		return Blocklist{}, derp.NewForbiddenError(location, "Must be domain owner to continue")
+	}
+
+	// Return the Blocklist builder
+	return Blocklist{
+		_blocklist:         blocklist,
+		CommonWithTemplate: common,
+	}, nil
+}
+
+/******************************************
+ * Renderer Interface
+ ******************************************/
+
+// Render generates the string value for this Blocklist
+func (w Blocklist) Render() (template.HTML, error) {
+
+	var buffer bytes.Buffer
+
+	// Execute step (write HTML to buffer, update context)
+	status := Pipeline(w._action.Steps).Get(w._factory, &w, &buffer)
+
+	if status.Error != nil {
+		err := derp.Wrap(status.Error, "build.Blocklist.Render", "Error generating HTML")
+		derp.Report(err)
+		return "", err
+	}
+
+	// Success!
+	status.Apply(w._response)
+	return template.HTML(buffer.String()), nil
+}
+
+// View executes a separate view for this Blocklist
+func (w Blocklist) View(actionID string) (template.HTML, error) {
+
+	const location = "build.Blocklist.View"
+
+	builder, err := NewBlocklist(w._factory, w._request, w._response, w._blocklist, w._template, actionID)
+
+	if err != nil {
+		return template.HTML(""), derp.Wrap(err, location, "Error creating Blocklist builder")
+	}
+
+	return builder.Render()
+}
+
+func (w Blocklist) NavigationID() string {
+	return "admin"
+}
+
+func (w Blocklist) Permalink() string {
+	return w.Hostname() + "/admin/blocklists/" + w.BlocklistID()
+}
+
+func (w Blocklist) BasePath() string {
+	return "/admin/blocklists/" + w.BlocklistID()
+}
+
+func (w Blocklist) Token() string {
+	return "blocklists"
+}
+
+func (w Blocklist) PageTitle() string {
+	return "Settings"
+}
+
+func (w Blocklist) object() data.Object {
+	return w._blocklist
+}
+
+func (w Blocklist) objectID() primitive.ObjectID {
+	return w._blocklist.BlocklistID
+}
+
+func (w Blocklist) objectType() string {
+	return "Blocklist"
+}
+
+func (w Blocklist) schema() schema.Schema {
+	return schema.New(model.BlocklistSchema())
+}
+
+func (w Blocklist) service() service.ModelService {
+	return w._factory.Blocklist()
+}
+
+func (w Blocklist) clone(action string) (Builder, error) {
+	return NewBlocklist(w._factory, w._request, w._response, w._blocklist, w._template, action)
+}
+
+/******************************************
+ * DATA ACCESSORS
+ ******************************************/
+
+func (w Blocklist) BlocklistID() string {
+	if w._blocklist == nil {
+		return ""
+	}
+	return w._blocklist.BlocklistID.Hex()
+}
+
+// Blocklist returns the Blocklist being displayed
+func (w Blocklist) Blocklist() model.Blocklist {
+	if w._blocklist == nil {
+		return model.NewBlocklist()
+	}
+	return *w._blocklist
+}
+
+/******************************************
+ * QUERY BUILDERS
+ ******************************************/
+
+// Blocklists returns a query builder for all Blocklist subscriptions, sorted by label
+func (w Blocklist) Blocklists() *QueryBuilder[model.Blocklist] {
+
+	result := NewQueryBuilder[model.Blocklist](w._factory.Blocklist(), exp.All())
+	result.SortField = "label"
+
+	return &result
+}
+
+// Rules returns all server-wide Rules that were imported from the current Blocklist
+func (w Blocklist) Rules() ([]model.Rule, error) {
+
+	if w._blocklist == nil {
+		return make([]model.Rule, 0), nil
+	}
+
+	return w._factory.Rule().QueryByBlocklist(w._blocklist.BlocklistID)
+}
+
+func (w Blocklist) debug() {
+	log.Debug().Interface("object", w.object()).Msg("builder_admin_blocklist")
+}
@@ -165,6 +165,20 @@ func (w Domain) PropertyForm() form.Element {
 	return w.Theme(w.ThemeID()).Form
 }
 
+/******************************************
+ * Storage Methods
+ ******************************************/
+
+// StorageUsage returns a report of the attachment storage used by every User in this Domain
+func (w Domain) StorageUsage() ([]model.StorageUsage, error) {
+	return w._factory.Storage().Report()
+}
+
+// StorageQuota returns the default storage quota (in megabytes) for Users in this Domain
+func (w Domain) StorageQuota() int64 {
+	return w._domain.StorageQuota
+}
+
 /******************************************
  * Registration Methods
  ******************************************/
@@ -0,0 +1,161 @@
+package build
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/schema"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DomainPolicy is a builder for the admin/federation page
+// It can only be accessed by a Domain Owner
+type DomainPolicy struct {
+	_domainPolicy *model.DomainPolicy
+	CommonWithTemplate
+}
+
+// NewDomainPolicy returns a fully initialized `DomainPolicy` builder.
+func NewDomainPolicy(factory Factory, request *http.Request, response http.ResponseWriter, domainPolicy *model.DomainPolicy, template model.Template, actionID string) (DomainPolicy, error) {
+
+	const location = "build.NewDomainPolicy"
+
+	// Create the underlying Common builder
+	common, err := NewCommonWithTemplate(factory, request, response, template, actionID)
+
+	if err != nil {
+		return DomainPolicy{}, derp.Wrap(err, location, "Error creating common builder")
+	}
+
+	// Verify that the user is a Domain Owner
+	if !common._authorization.DomainOwner {
+		return DomainPolicy{}, derp.NewForbiddenError(location, "Must be domain owner to continue")
+	}
+
+	// Return the DomainPolicy builder
+	return DomainPolicy{
+		_domainPolicy:      domainPolicy,
+		CommonWithTemplate: common,
+	}, nil
+}
+
+/******************************************
+ * Renderer Interface
+ ******************************************/
+
+// Render generates the string value for this DomainPolicy
+func (w DomainPolicy) Render() (template.HTML, error) {
+
+	var buffer bytes.Buffer
+
+	// Execute step (write HTML to buffer, update context)
+	status := Pipeline(w._action.Steps).Get(w._factory, &w, &buffer)
+
+	if status.Error != nil {
+		err := derp.Wrap(status.Error, "build.DomainPolicy.Render", "Error generating HTML")
+		derp.Report(err)
+		return "", err
+	}
+
+	// Success!
+	status.Apply(w._response)
+	return template.HTML(buffer.String()), nil
+}
+
+// View executes a separate view for this DomainPolicy
+func (w DomainPolicy) View(actionID string) (template.HTML, error) {
+
+	const location = "build.DomainPolicy.View"
+
+	builder, err := NewDomainPolicy(w._factory, w._request, w._response, w._domainPolicy, w._template, actionID)
+
+	if err != nil {
+		return template.HTML(""), derp.Wrap(err, location, "Error creating DomainPolicy builder")
+	}
+
+	return builder.Render()
+}
+
+func (w DomainPolicy) NavigationID() string {
+	return "admin"
+}
+
+func (w DomainPolicy) Permalink() string {
+	return w.Hostname() + "/admin/federation/" + w.DomainPolicyID()
+}
+
+func (w DomainPolicy) BasePath() string {
+	return "/admin/federation/" + w.DomainPolicyID()
+}
+
+func (w DomainPolicy) Token() string {
+	return "federation"
+}
+
+func (w DomainPolicy) PageTitle() string {
+	return "Settings"
+}
+
+func (w DomainPolicy) object() data.Object {
+	return w._domainPolicy
+}
+
+func (w DomainPolicy) objectID() primitive.ObjectID {
+	return w._domainPolicy.DomainPolicyID
+}
+
+func (w DomainPolicy) objectType() string {
+	return "DomainPolicy"
+}
+
+func (w DomainPolicy) schema() schema.Schema {
+	return schema.New(model.DomainPolicySchema())
+}
+
+func (w DomainPolicy) service() service.ModelService {
+	return w._factory.DomainPolicy()
+}
+
+func (w DomainPolicy) clone(action string) (Builder, error) {
+	return NewDomainPolicy(w._factory, w._request, w._response, w._domainPolicy, w._template, action)
+}
+
+/******************************************
+ * DATA ACCESSORS
+ ******************************************/
+
+func (w DomainPolicy) DomainPolicyID() string {
+	if w._domainPolicy == nil {
+		return ""
+	}
+	return w._domainPolicy.DomainPolicyID.Hex()
+}
+
+// AuthorizedFetch returns TRUE if this Domain requires signatures on ActivityPub GET requests
+func (w DomainPolicy) AuthorizedFetch() bool {
+	return w._factory.Domain().Get().AuthorizedFetch
+}
+
+/******************************************
+ * QUERY BUILDERS
+ ******************************************/
+
+// DomainPolicies returns a query builder for all DomainPolicies, sorted by hostname
+func (w DomainPolicy) DomainPolicies() *QueryBuilder[model.DomainPolicy] {
+
+	result := NewQueryBuilder[model.DomainPolicy](w._factory.DomainPolicy(), exp.All())
+	result.SortField = "hostname"
+
+	return &result
+}
+
+func (w DomainPolicy) debug() {
+	log.Debug().Interface("object", w.object()).Msg("builder_admin_domainPolicy")
+}
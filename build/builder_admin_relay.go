@@ -0,0 +1,164 @@
+package build
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/schema"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Relay is a builder for the admin/relays page
+// It can only be accessed by a Domain Owner
+type Relay struct {
+	_relay *model.Relay
+	CommonWithTemplate
+}
+
+// NewRelay returns a fully initialized `Relay` builder.
+func NewRelay(factory Factory, request *http.Request, response http.ResponseWriter, relay *model.Relay, template model.Template, actionID string) (Relay, error) {
+
+	const location = "build.NewRelay"
+
+	// Create the underlying Common builder
+	common, err := NewCommonWithTemplate(factory, request, response, template, actionID)
+
+	if err != nil {
+		return Relay{}, derp.Wrap(err, location, "Error creating common builder")
+	}
+
+	// Verify that the user is a Domain Owner
+	if !common._authorization.DomainOwner {
+		return Relay{}, derp.NewForbiddenError(location, "Must be domain owner to continue")
+	}
+
+	// Return the Relay builder
+	return Relay{
+		_relay:             relay,
+		CommonWithTemplate: common,
+	}, nil
+}
+
+/******************************************
+ * Renderer Interface
+ ******************************************/
+
+// Render generates the string value for this Relay
+func (w Relay) Render() (template.HTML, error) {
+
+	var buffer bytes.Buffer
+
+	// Execute step (write HTML to buffer, update context)
+	status := Pipeline(w._action.Steps).Get(w._factory, &w, &buffer)
+
+	if status.Error != nil {
+		err := derp.Wrap(status.Error, "build.Relay.Render", "Error generating HTML")
+		derp.Report(err)
+		return "", err
+	}
+
+	// Success!
+	status.Apply(w._response)
+	return template.HTML(buffer.String()), nil
+}
+
+// View executes a separate view for this Relay
+func (w Relay) View(actionID string) (template.HTML, error) {
+
+	const location = "build.Relay.View"
+
+	builder, err := NewRelay(w._factory, w._request, w._response, w._relay, w._template, actionID)
+
+	if err != nil {
+		return template.HTML(""), derp.Wrap(err, location, "Error creating Relay builder")
+	}
+
+	return builder.Render()
+}
+
+func (w Relay) NavigationID() string {
+	return "admin"
+}
+
+func (w Relay) Permalink() string {
+	return w.Hostname() + "/admin/relays/" + w.RelayID()
+}
+
+func (w Relay) BasePath() string {
+	return "/admin/relays/" + w.RelayID()
+}
+
+func (w Relay) Token() string {
+	return "relays"
+}
+
+func (w Relay) PageTitle() string {
+	return "Settings"
+}
+
+func (w Relay) object() data.Object {
+	return w._relay
+}
+
+func (w Relay) objectID() primitive.ObjectID {
+	return w._relay.RelayID
+}
+
+func (w Relay) objectType() string {
+	return "Relay"
+}
+
+func (w Relay) schema() schema.Schema {
+	return schema.New(model.RelaySchema())
+}
+
+func (w Relay) service() service.ModelService {
+	return w._factory.Relay()
+}
+
+func (w Relay) clone(action string) (Builder, error) {
+	return NewRelay(w._factory, w._request, w._response, w._relay, w._template, action)
+}
+
+/******************************************
+ * DATA ACCESSORS
+ ******************************************/
+
+func (w Relay) RelayID() string {
+	if w._relay == nil {
+		return ""
+	}
+	return w._relay.RelayID.Hex()
+}
+
+// Relay returns the Relay being displayed
+func (w Relay) Relay() model.Relay {
+	if w._relay == nil {
+		return model.NewRelay()
+	}
+	return *w._relay
+}
+
+/******************************************
+ * QUERY BUILDERS
+ ******************************************/
+
+// Relays returns a query builder for all Relay subscriptions, sorted by label
+func (w Relay) Relays() *QueryBuilder[model.Relay] {
+
+	result := NewQueryBuilder[model.Relay](w._factory.Relay(), exp.All())
+	result.SortField = "label"
+
+	return &result
+}
+
+func (w Relay) debug() {
+	log.Debug().Interface("object", w.object()).Msg("builder_admin_relay")
+}
@@ -0,0 +1,188 @@
+package build
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/first"
+	"github.com/benpate/rosetta/schema"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Report is a builder for the admin/reports page
+// It can only be accessed by a Domain Owner
+type Report struct {
+	_report *model.Report
+	CommonWithTemplate
+}
+
+// NewReport returns a fully initialized `Report` builder.
+func NewReport(factory Factory, request *http.Request, response http.ResponseWriter, report *model.Report, template model.Template, actionID string) (Report, error) {
+
+	const location = "build.NewReport"
+
+	// Create the underlying Common builder
+	common, err := NewCommonWithTemplate(factory, request, response, template, actionID)
+
+	if err != nil {
+		return Report{}, derp.Wrap(err, location, "Error creating common builder")
+	}
+
+	// Verify that the user is a Domain Owner
+	if !common._authorization.DomainOwner {
+		return Report{}, derp.NewForbiddenError(location, "Must be domain owner to continue")
+	}
+
+	// Return the Report builder
+	return Report{
+		_report:            report,
+		CommonWithTemplate: common,
+	}, nil
+}
+
+/******************************************
+ * Renderer Interface
+ ******************************************/
+
+// Render generates the string value for this Report
+func (w Report) Render() (template.HTML, error) {
+
+	var buffer bytes.Buffer
+
+	// Execute step (write HTML to buffer, update context)
+	status := Pipeline(w._action.Steps).Get(w._factory, &w, &buffer)
+
+	if status.Error != nil {
+		err := derp.Wrap(status.Error, "build.Report.Render", "Error generating HTML")
+		derp.Report(err)
+		return "", err
+	}
+
+	// Success!
+	status.Apply(w._response)
+	return template.HTML(buffer.String()), nil
+}
+
+// View executes a separate view for this Report
+func (w Report) View(actionID string) (template.HTML, error) {
+
+	const location = "build.Report.View"
+
+	builder, err := NewReport(w._factory, w._request, w._response, w._report, w._template, actionID)
+
+	if err != nil {
+		return template.HTML(""), derp.Wrap(err, location, "Error creating Report builder")
+	}
+
+	return builder.Render()
+}
+
+func (w Report) NavigationID() string {
+	return "admin"
+}
+
+func (w Report) Permalink() string {
+	return w.Hostname() + "/admin/reports/" + w.ReportID()
+}
+
+func (w Report) BasePath() string {
+	return "/admin/reports/" + w.ReportID()
+}
+
+func (w Report) Token() string {
+	return "reports"
+}
+
+func (w Report) PageTitle() string {
+	return "Settings"
+}
+
+func (w Report) object() data.Object {
+	return w._report
+}
+
+func (w Report) objectID() primitive.ObjectID {
+	return w._report.ReportID
+}
+
+func (w Report) objectType() string {
+	return "Report"
+}
+
+func (w Report) schema() schema.Schema {
+	return schema.New(model.ReportSchema())
+}
+
+func (w Report) service() service.ModelService {
+	return w._factory.Report()
+}
+
+func (w Report) clone(action string) (Builder, error) {
+	return NewReport(w._factory, w._request, w._response, w._report, w._template, action)
+}
+
+/******************************************
+ * DATA ACCESSORS
+ ******************************************/
+
+func (w Report) ReportID() string {
+	if w._report == nil {
+		return ""
+	}
+	return w._report.ReportID.Hex()
+}
+
+// Report returns the Report being displayed
+func (w Report) Report() model.Report {
+	if w._report == nil {
+		return model.NewReport()
+	}
+	return *w._report
+}
+
+// StatusFilter returns the report status that is being displayed in the queue
+func (w Report) StatusFilter() string {
+	return first.String(w._request.URL.Query().Get("statusId"), model.ReportStatusPending)
+}
+
+// CanForward returns TRUE if the current Report can be forwarded to a remote server
+func (w Report) CanForward() bool {
+
+	if w._report == nil {
+		return false
+	}
+
+	if !w._report.IsPending() || w._report.IsForwarded() {
+		return false
+	}
+
+	return !strings.HasPrefix(w._report.TargetURL, w.Host()+"/")
+}
+
+/******************************************
+ * QUERY BUILDERS
+ ******************************************/
+
+// Reports returns a query builder for all Reports with the currently selected status
+func (w Report) Reports() *QueryBuilder[model.Report] {
+
+	criteria := exp.Equal("statusId", w.StatusFilter())
+
+	result := NewQueryBuilder[model.Report](w._factory.Report(), criteria)
+	result.SortField = "createDate"
+	result.SortDirection = "desc"
+
+	return &result
+}
+
+func (w Report) debug() {
+	log.Debug().Interface("object", w.object()).Msg("builder_admin_report")
+}
@@ -0,0 +1,175 @@
+package build
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/schema"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Trend is a builder for the admin/trends page, where trending hashtags,
+// links, and statuses are reviewed before they are shown publicly.
+// It can only be accessed by a Domain Owner
+type Trend struct {
+	_trend *model.Trend
+	CommonWithTemplate
+}
+
+// NewTrend returns a fully initialized `Trend` builder.
+func NewTrend(factory Factory, request *http.Request, response http.ResponseWriter, trend *model.Trend, template model.Template, actionID string) (Trend, error) {
+
+	const location = "build.NewTrend"
+
+	// Create the underlying Common builder
+	common, err := NewCommonWithTemplate(factory, request, response, template, actionID)
+
+	if err != nil {
+		return Trend{}, derp.Wrap(err, location, "Error creating common builder")
+	}
+
+	// Verify that the user is a Domain Owner
+	if !common._authorization.DomainOwner {
+		return Trend{}, derp.NewForbiddenError(location, "Must be domain owner to continue")
+	}
+
+	// Return the Trend builder
+	return Trend{
+		_trend:             trend,
+		CommonWithTemplate: common,
+	}, nil
+}
+
+/******************************************
+ * Renderer Interface
+ ******************************************/
+
+// Render generates the string value for this Trend
+func (w Trend) Render() (template.HTML, error) {
+
+	var buffer bytes.Buffer
+
+	// Execute step (write HTML to buffer, update context)
+	status := Pipeline(w._action.Steps).Get(w._factory, &w, &buffer)
+
+	if status.Error != nil {
+		err := derp.Wrap(status.Error, "build.Trend.Render", "Error generating HTML")
+		derp.Report(err)
+		return "", err
+	}
+
+	// Success!
+	status.Apply(w._response)
+	return template.HTML(buffer.String()), nil
+}
+
+// View executes a separate view for this Trend
+func (w Trend) View(actionID string) (template.HTML, error) {
+
+	const location = "build.Trend.View"
+
+	builder, err := NewTrend(w._factory, w._request, w._response, w._trend, w._template, actionID)
+
+	if err != nil {
+		return template.HTML(""), derp.Wrap(err, location, "Error creating Trend builder")
+	}
+
+	return builder.Render()
+}
+
+func (w Trend) NavigationID() string {
+	return "admin"
+}
+
+func (w Trend) Permalink() string {
+	return w.Hostname() + "/admin/trends/" + w.TrendID()
+}
+
+func (w Trend) BasePath() string {
+	return "/admin/trends/" + w.TrendID()
+}
+
+func (w Trend) Token() string {
+	return "trends"
+}
+
+func (w Trend) PageTitle() string {
+	return "Settings"
+}
+
+func (w Trend) object() data.Object {
+	return w._trend
+}
+
+func (w Trend) objectID() primitive.ObjectID {
+	return w._trend.TrendID
+}
+
+func (w Trend) objectType() string {
+	return "Trend"
+}
+
+func (w Trend) schema() schema.Schema {
+	return schema.New(model.TrendSchema())
+}
+
+func (w Trend) service() service.ModelService {
+	return w._factory.Trend()
+}
+
+func (w Trend) clone(action string) (Builder, error) {
+	return NewTrend(w._factory, w._request, w._response, w._trend, w._template, action)
+}
+
+/******************************************
+ * DATA ACCESSORS
+ ******************************************/
+
+func (w Trend) TrendID() string {
+	if w._trend == nil {
+		return ""
+	}
+	return w._trend.TrendID.Hex()
+}
+
+// Trend returns the Trend being displayed
+func (w Trend) Trend() model.Trend {
+	if w._trend == nil {
+		return model.NewTrend()
+	}
+	return *w._trend
+}
+
+/******************************************
+ * QUERIES
+ ******************************************/
+
+// Tags returns the active hashtag Trends, most popular first
+func (w Trend) Tags() ([]model.Trend, error) {
+	return w.trendsByType(model.TrendTypeTag)
+}
+
+// Links returns the active link Trends, most popular first
+func (w Trend) Links() ([]model.Trend, error) {
+	return w.trendsByType(model.TrendTypeLink)
+}
+
+// Statuses returns the active status Trends, most popular first
+func (w Trend) Statuses() ([]model.Trend, error) {
+	return w.trendsByType(model.TrendTypeStatus)
+}
+
+func (w Trend) trendsByType(trendType string) ([]model.Trend, error) {
+	return w._factory.Trend().QueryByType(trendType, exp.All())
+}
+
+func (w Trend) debug() {
+	log.Debug().Interface("object", w.object()).Msg("builder_admin_trend")
+}
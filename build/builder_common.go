@@ -303,6 +303,9 @@ func (w Common) ActivityStream(url string) streams.Document {
 		derp.Report(derp.Wrap(err, "build.Common.ActivityStream", "Error loading ActivityStream"))
 	}
 
+	// Remove media and add content warnings required by the Domain's federation policies
+	w._factory.DomainPolicy().Apply(&result)
+
 	// Search for rules that might add a LABEL to this document.
 	ruleService := w._factory.Rule()
 	filter := ruleService.Filter(w.AuthenticatedID(), service.WithLabelsOnly())
@@ -500,6 +503,26 @@ func (w Common) AdminSections() []form.LookupCode {
 			Value: "rules",
 			Label: "Rules",
 		},
+		{
+			Value: "blocklists",
+			Label: "Blocklists",
+		},
+		{
+			Value: "relays",
+			Label: "Relays",
+		},
+		{
+			Value: "trends",
+			Label: "Trends",
+		},
+		{
+			Value: "reports",
+			Label: "Reports",
+		},
+		{
+			Value: "federation",
+			Label: "Federation",
+		},
 		{
 			Value: "connections",
 			Label: "Connections",
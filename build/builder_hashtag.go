@@ -0,0 +1,181 @@
+package build
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/rosetta/schema"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// hashtagPageSize is the number of local and remote posts shown on a hashtag page
+const hashtagPageSize = 24
+
+// Hashtag is a builder for the public /tags/:tag pages, which show the public posts
+// on this Domain (and the public posts that this Domain has received) that include a hashtag.
+type Hashtag struct {
+	_followedTag *model.FollowedTag
+	CommonWithTemplate
+}
+
+// NewHashtag returns a fully initialized `Hashtag` builder.
+func NewHashtag(factory Factory, request *http.Request, response http.ResponseWriter, followedTag *model.FollowedTag, template model.Template, actionID string) (Hashtag, error) {
+
+	const location = "build.NewHashtag"
+
+	// Create the underlying Common builder
+	common, err := NewCommonWithTemplate(factory, request, response, template, actionID)
+
+	if err != nil {
+		return Hashtag{}, derp.Wrap(err, location, "Error creating common builder")
+	}
+
+	// Return the Hashtag builder
+	return Hashtag{
+		_followedTag:       followedTag,
+		CommonWithTemplate: common,
+	}, nil
+}
+
+/******************************************
+ * Renderer Interface
+ ******************************************/
+
+// Render generates the string value for this Hashtag
+func (w Hashtag) Render() (template.HTML, error) {
+
+	var buffer bytes.Buffer
+
+	// Execute step (write HTML to buffer, update context)
+	status := Pipeline(w._action.Steps).Get(w._factory, &w, &buffer)
+
+	if status.Error != nil {
+		err := derp.Wrap(status.Error, "build.Hashtag.Render", "Error generating HTML")
+		derp.Report(err)
+		return "", err
+	}
+
+	// Success!
+	status.Apply(w._response)
+	return template.HTML(buffer.String()), nil
+}
+
+// View executes a separate view for this Hashtag
+func (w Hashtag) View(actionID string) (template.HTML, error) {
+
+	const location = "build.Hashtag.View"
+
+	builder, err := NewHashtag(w._factory, w._request, w._response, w._followedTag, w._template, actionID)
+
+	if err != nil {
+		return template.HTML(""), derp.Wrap(err, location, "Error creating Hashtag builder")
+	}
+
+	return builder.Render()
+}
+
+func (w Hashtag) NavigationID() string {
+	return ""
+}
+
+func (w Hashtag) Permalink() string {
+	return w.Host() + w.BasePath()
+}
+
+func (w Hashtag) BasePath() string {
+	return "/tags/" + w._followedTag.Name
+}
+
+func (w Hashtag) Token() string {
+	return w._followedTag.Name
+}
+
+func (w Hashtag) PageTitle() string {
+	return w._followedTag.Hashtag()
+}
+
+func (w Hashtag) object() data.Object {
+	return w._followedTag
+}
+
+func (w Hashtag) objectID() primitive.ObjectID {
+	return w._followedTag.FollowedTagID
+}
+
+func (w Hashtag) objectType() string {
+	return "FollowedTag"
+}
+
+func (w Hashtag) schema() schema.Schema {
+	return schema.New(model.FollowedTagSchema())
+}
+
+func (w Hashtag) service() service.ModelService {
+	return w._factory.FollowedTag()
+}
+
+func (w Hashtag) clone(action string) (Builder, error) {
+	return NewHashtag(w._factory, w._request, w._response, w._followedTag, w._template, action)
+}
+
+/******************************************
+ * DATA ACCESSORS
+ ******************************************/
+
+// Name returns the normalized name of this hashtag (without the leading "#")
+func (w Hashtag) Name() string {
+	return w._followedTag.Name
+}
+
+// Hashtag returns the display value of this hashtag (including the leading "#")
+func (w Hashtag) Hashtag() string {
+	return w._followedTag.Hashtag()
+}
+
+// IsFollowing returns TRUE if the signed-in User follows this hashtag
+func (w Hashtag) IsFollowing() bool {
+	return !w._followedTag.IsNew()
+}
+
+/******************************************
+ * QUERY BUILDERS
+ ******************************************/
+
+// Streams returns the most recent public Streams on this Domain that include this hashtag
+func (w Hashtag) Streams() []model.Stream {
+
+	result, err := w._factory.Stream().QueryByHashtags([]string{w.Name()}, exp.All(), option.SortDesc("publishDate"), option.MaxRows(hashtagPageSize))
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, "build.Hashtag.Streams", "Error loading Streams", w.Name()))
+		return make([]model.Stream, 0)
+	}
+
+	return result
+}
+
+// Documents returns the most recent public documents received by this Domain that include this hashtag
+func (w Hashtag) Documents() []streams.Document {
+
+	result, err := w._factory.FollowedTag().QueryDocuments([]string{w.Name()}, exp.All(), option.SortDesc("published"), option.MaxRows(hashtagPageSize))
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, "build.Hashtag.Documents", "Error loading documents", w.Name()))
+		return make([]streams.Document, 0)
+	}
+
+	return result
+}
+
+func (w Hashtag) debug() {
+	log.Debug().Interface("object", w.object()).Msg("builder_hashtag")
+}
@@ -329,7 +329,7 @@ func (w Inbox) Inbox() (QueryBuilder[model.Message], error) {
 
 	criteria := exp.And(
 		exp.Equal("userId", w.AuthenticatedID()),
-		exp.Equal("folderId", folderID),
+		w.folderCriteria(folderID),
 		exp.Equal("deleteDate", 0),
 		expBuilder.Evaluate(queryString),
 	)
@@ -337,6 +337,22 @@ func (w Inbox) Inbox() (QueryBuilder[model.Message], error) {
 	return NewQueryBuilder[model.Message](w._factory.Inbox(), criteria), nil
 }
 
+// folderCriteria returns the criteria that selects the messages in a folder.  Standard folders
+// contain the messages delivered into them, and smart folders contain the messages that match their query.
+func (w Inbox) folderCriteria(folderID primitive.ObjectID) exp.Expression {
+
+	folderService := w._factory.Folder()
+	folder := model.NewFolder()
+
+	if err := folderService.LoadByID(w.AuthenticatedID(), folderID, &folder); err == nil {
+		if folder.IsSmart() {
+			return folder.Query.Expression()
+		}
+	}
+
+	return exp.Equal("folderId", folderID)
+}
+
 // IsInboxEmpty returns TRUE if the inbox has no results and there are no filters applied
 // This corresponds to there being NOTHING in the inbox, instead of just being filtered out.
 func (w Inbox) IsInboxEmpty(inbox []model.Message) bool {
@@ -493,8 +509,14 @@ func (w Inbox) Message() model.Message {
 	// If sibling (prev/next) is specified, then try to look that up before returning.
 	if sibling := w._request.URL.Query().Get("sibling"); sibling != "" {
 
-		// Otherwise, look up the next/previous message
-		criteria := exp.Equal("userId", w.AuthenticatedID()).AndEqual("folderId", message.FolderID)
+		// Otherwise, look up the next/previous message in the selected folder (which may be a smart folder)
+		folderID := message.FolderID
+
+		if selectedID, err := primitive.ObjectIDFromHex(w.QueryParam("folderId")); err == nil {
+			folderID = selectedID
+		}
+
+		criteria := exp.Equal("userId", w.AuthenticatedID()).And(w.folderCriteria(folderID))
 		options := []option.Option{option.MaxRows(1)}
 
 		if sibling == "next" {
@@ -550,7 +572,7 @@ func (w Inbox) RepliesBefore(url string, dateString string, maxRows int) sliceof
 	// Filter replies based on rules
 	ruleService := w._factory.Rule()
 	ruleFilter := ruleService.Filter(w.AuthenticatedID())
-	filteredReplies := ruleFilter.Channel(replies)
+	filteredReplies := ruleFilter.Channel(w._factory.DomainPolicy().Channel(replies, false))
 
 	// Limit to maximum number of replies
 	// limitedReplies := channel.Limit(maxRows, filteredReplies, done)
@@ -573,7 +595,7 @@ func (w Inbox) RepliesAfter(url string, dateString string, maxRows int) sliceof.
 	// Filter replies based on rules
 	ruleService := w._factory.Rule()
 	ruleFilter := ruleService.Filter(w.AuthenticatedID())
-	filteredReplies := ruleFilter.Channel(replies)
+	filteredReplies := ruleFilter.Channel(w._factory.DomainPolicy().Channel(replies, false))
 
 	// Limit to maximum number of replies
 	limitedReplies := channel.Limit(maxRows, filteredReplies, done)
@@ -595,7 +617,7 @@ func (w Inbox) AnnouncesBefore(url string, dateString string, maxRows int) slice
 	// Filter replies based on rules
 	ruleService := w._factory.Rule()
 	ruleFilter := ruleService.Filter(w.AuthenticatedID())
-	filteredAnnounces := ruleFilter.Channel(announces)
+	filteredAnnounces := ruleFilter.Channel(w._factory.DomainPolicy().Channel(announces, false))
 
 	// Limit to maximum number of replies
 	limitedAnnounces := channel.Limit(maxRows, filteredAnnounces, done)
@@ -617,7 +639,7 @@ func (w Inbox) LikesBefore(url string, dateString string, maxRows int) sliceof.O
 	// Filter replies based on rules
 	ruleService := w._factory.Rule()
 	ruleFilter := ruleService.Filter(w.AuthenticatedID())
-	filteredLikes := ruleFilter.Channel(announces)
+	filteredLikes := ruleFilter.Channel(w._factory.DomainPolicy().Channel(announces, false))
 
 	// Limit to maximum number of replies
 	limitedLikes := channel.Limit(maxRows, filteredLikes, done)
@@ -295,6 +295,21 @@ func (w Stream) InReplyTo() streams.Document {
 	return w.ActivityStream(w._stream.InReplyTo)
 }
 
+// IsQuote returns TRUE if this stream quotes another stream or resource
+func (w Stream) IsQuote() bool {
+	return (w._stream.QuoteURL != "")
+}
+
+// Quote returns an ActivityStream reference to the URL that this stream quotes
+func (w Stream) Quote() streams.Document {
+
+	if w._stream.QuoteURL == "" {
+		return streams.NilDocument()
+	}
+
+	return w.ActivityStream(w._stream.QuoteURL)
+}
+
 // Returns the body content as an HTML template
 func (w Stream) ContentHTML() template.HTML {
 	return template.HTML(w._stream.Content.HTML)
@@ -549,7 +564,7 @@ func (w Stream) RepliesBefore(dateString string, maxRows int) sliceof.Object[str
 	// Filter results based on blocks
 	ruleService := w._factory.Rule()
 	ruleFilter := ruleService.Filter(w.AuthenticatedID())
-	filteredResult := ruleFilter.Channel(replies)
+	filteredResult := ruleFilter.Channel(w._factory.DomainPolicy().Channel(replies, true))
 
 	// Limit to `maxRows` records
 	limitedFilter := channel.Limit(maxRows, filteredResult, done)
@@ -570,7 +585,7 @@ func (w Stream) RepliesAfter(dateString string, maxRows int) sliceof.Object[stre
 	// Filter results based on blocks
 	ruleService := w._factory.Rule()
 	ruleFilter := ruleService.Filter(w.AuthenticatedID())
-	filteredResult := ruleFilter.Channel(replies)
+	filteredResult := ruleFilter.Channel(w._factory.DomainPolicy().Channel(replies, true))
 
 	// Limit to `maxRows` records
 	limitedFilter := channel.Limit(maxRows, filteredResult, done)
@@ -592,7 +607,7 @@ func (w Stream) AnnouncesBefore(dateString string, maxRows int) sliceof.Object[s
 	// Filter results based on blocks
 	ruleService := w._factory.Rule()
 	ruleFilter := ruleService.Filter(w.AuthenticatedID())
-	filteredResult := ruleFilter.Channel(announces)
+	filteredResult := ruleFilter.Channel(w._factory.DomainPolicy().Channel(announces, true))
 
 	// Limit to `maxRows` records
 	limitedFilter := channel.Limit(maxRows, filteredResult, done)
@@ -614,7 +629,7 @@ func (w Stream) LikesBefore(dateString string, maxRows int) sliceof.Object[strea
 	// Filter results based on blocks
 	ruleService := w._factory.Rule()
 	ruleFilter := ruleService.Filter(w.AuthenticatedID())
-	filteredResult := ruleFilter.Channel(likes)
+	filteredResult := ruleFilter.Channel(w._factory.DomainPolicy().Channel(likes, true))
 
 	// Limit to `maxRows` records
 	limitedFilter := channel.Limit(maxRows, filteredResult, done)
@@ -718,6 +733,35 @@ func (w Stream) Attachments() ([]model.Attachment, error) {
 	return w.factory().Attachment().QueryByObjectID(model.AttachmentObjectTypeStream, w._stream.StreamID)
 }
 
+/******************************************
+ * Editorial Workflow
+ ******************************************/
+
+// Transitions returns all of the editorial workflow Transitions
+// that can begin from this stream's current state.
+func (w Stream) Transitions() []model.Transition {
+	return w._template.TransitionsFrom(w._stream.StateID)
+}
+
+// PendingReview returns the Review that is currently waiting on reviewers (if any)
+func (w Stream) PendingReview() model.Review {
+	result := model.NewReview()
+	if err := w.factory().Review().LoadPending(w._stream.StreamID, &result); err != nil {
+		return model.Review{}
+	}
+	return result
+}
+
+// Reviews lists all of the Reviews (past and present) for this stream.
+func (w Stream) Reviews() ([]model.Review, error) {
+	return w.factory().Review().QueryByStream(w._stream.StreamID)
+}
+
+// ReviewComments lists all of the unresolved editorial comments for this stream.
+func (w Stream) ReviewComments() ([]model.ReviewComment, error) {
+	return w.factory().ReviewComment().QueryOpenByStream(w._stream.StreamID)
+}
+
 /******************************************
  * Content Actors
  ******************************************/
@@ -24,8 +24,10 @@ type Factory interface {
 	Model(string) (service.ModelService, error)
 	ActivityStream() *service.ActivityStream
 	Attachment() *service.Attachment
+	Blocklist() *service.Blocklist
 	Connection() *service.Connection
 	Folder() *service.Folder
+	FollowedTag() *service.FollowedTag
 	Following() *service.Following
 	Follower() *service.Follower
 	Group() *service.Group
@@ -34,12 +36,18 @@ type Factory interface {
 	Outbox() *service.Outbox
 	Provider() *service.Provider
 	Registration() *service.Registration
+	Relay() *service.Relay
 	Response() *service.Response
+	Report() *service.Report
+	Review() *service.Review
+	ReviewComment() *service.ReviewComment
 	Rule() *service.Rule
+	Storage() *service.Storage
 	Stream() *service.Stream
 	StreamDraft() *service.StreamDraft
 	Template() *service.Template
 	Theme() *service.Theme
+	Trend() *service.Trend
 	User() *service.User
 	Widget() *service.Widget
 
@@ -47,6 +55,7 @@ type Factory interface {
 	Config() config.Domain
 	Content() *service.Content
 	Domain() *service.Domain
+	DomainPolicy() *service.DomainPolicy
 	Email() *service.DomainEmail
 	Host() string
 	Hostname() string
@@ -23,6 +23,12 @@ func ExecutableStep(stepInfo step.Step) Step {
 	case step.AddModelObject:
 		return StepAddModelObject(s)
 
+	case step.AddReport:
+		return StepAddReport(s)
+
+	case step.AddReviewComment:
+		return StepAddReviewComment(s)
+
 	case step.AddStream:
 		return StepAddStream(s)
 
@@ -35,6 +41,9 @@ func ExecutableStep(stepInfo step.Step) Step {
 	case step.AsTooltip:
 		return StepAsTooltip(s)
 
+	case step.DecideReview:
+		return StepDecideReview(s)
+
 	case step.Delete:
 		return StepDelete(s)
 
@@ -47,6 +56,12 @@ func ExecutableStep(stepInfo step.Step) Step {
 	case step.EditConnection:
 		return StepEditConnection(s)
 
+	case step.EditAttachment:
+		return StepEditAttachment(s)
+
+	case step.EditAuthorizedFetch:
+		return StepEditAuthorizedFetch(s)
+
 	case step.EditContent:
 		return StepEditContent(s)
 
@@ -56,12 +71,18 @@ func ExecutableStep(stepInfo step.Step) Step {
 	case step.EditRegistration:
 		return StepEditRegistration(s)
 
+	case step.EditStorageQuota:
+		return StepEditStorageQuota(s)
+
 	case step.EditTemplate:
 		return StepEditTemplate(s)
 
 	case step.EditWidget:
 		return StepEditWidget(s)
 
+	case step.FollowHashtag:
+		return StepFollowHashtag(s)
+
 	case step.ForwardTo:
 		return StepForwardTo(s)
 
@@ -80,6 +101,12 @@ func ExecutableStep(stepInfo step.Step) Step {
 	case step.InlineSuccess:
 		return StepInlineSuccess(s)
 
+	case step.MigrateAccount:
+		return StepMigrateAccount(s)
+
+	case step.ModerateReport:
+		return StepModerateReport(s)
+
 	case step.ProcessContent:
 		return StepProcessContent(s)
 
@@ -101,6 +128,15 @@ func ExecutableStep(stepInfo step.Step) Step {
 	case step.RemoveEvent:
 		return StepRemoveEvent(s)
 
+	case step.RequestReview:
+		return StepRequestReview(s)
+
+	case step.ResolveReviewComment:
+		return StepResolveReviewComment(s)
+
+	case step.RSSCloud:
+		return StepRSSCloud(s)
+
 	case step.Save:
 		return StepSave(s)
 
@@ -143,12 +179,21 @@ func ExecutableStep(stepInfo step.Step) Step {
 	case step.StreamPromoteDraft:
 		return StepStreamPromoteDraft(s)
 
+	case step.SubscribeRelay:
+		return StepSubscribeRelay(s)
+
+	case step.SyncBlocklist:
+		return StepSyncBlocklist(s)
+
 	case step.TableEditor:
 		return StepTableEditor(s)
 
 	case step.TriggerEvent:
 		return StepTriggerEvent(s)
 
+	case step.UnfollowHashtag:
+		return StepUnfollowHashtag(s)
+
 	case step.UnPublish:
 		return StepUnPublish(s)
 
@@ -0,0 +1,136 @@
+package build
+
+import (
+	"io"
+	"strings"
+
+	"github.com/benpate/derp"
+	"github.com/benpate/form"
+	"github.com/benpate/html"
+	"github.com/benpate/rosetta/convert"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/rosetta/schema"
+)
+
+// StepAddReport represents an action-step that lets a User report an account or document to the Domain Owners
+type StepAddReport struct {
+	Title string
+}
+
+// Get displays a modal form where the User can describe the problem.  The reported
+// document and account are passed in the "url" and "account" query parameters.
+func (step StepAddReport) Get(builder Builder, buffer io.Writer) PipelineBehavior {
+
+	const location = "build.StepAddReport.Get"
+
+	value := mapof.Any{
+		"url":      builder.QueryParam("url"),
+		"account":  builder.QueryParam("account"),
+		"category": "OTHER",
+	}
+
+	// Try to write form HTML
+	formHTML, err := form.Editor(step.schema(), step.form(builder), value, builder.lookupProvider())
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error building form"))
+	}
+
+	// Write the rest of the HTML that contains the form
+	b := html.New()
+
+	b.H1().InnerText(step.Title).Close()
+	b.Div().Class("margin-bottom").InnerText("Reports are reviewed by the moderators of this server.").Close()
+
+	b.Form("", "").
+		Data("hx-post", builder.URL()).
+		Data("hx-swap", "none").
+		Data("hx-push-url", "false").
+		EndBracket()
+
+	b.WriteString(formHTML)
+	b.Div()
+	b.Button().Type("submit").Class("primary").InnerText("Send Report").Close()
+	b.Button().Type("button").Script("on click trigger closeModal").InnerText("Cancel").Close()
+	b.CloseAll()
+
+	modalHTML := WrapModal(builder.response(), b.String())
+
+	// nolint:errcheck
+	io.WriteString(buffer, modalHTML)
+	return Halt().AsFullPage()
+}
+
+// Post files a new Report on behalf of the signed-in User
+func (step StepAddReport) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepAddReport.Post"
+
+	// Try to parse the form input
+	request := builder.request()
+
+	if err := request.ParseForm(); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error parsing form input"))
+	}
+
+	// Load the User who is filing the Report
+	user, err := builder.getUser()
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error loading User"))
+	}
+
+	// File the Report
+	objectURLs := make([]string, 0, 1)
+
+	if url := strings.TrimSpace(request.Form.Get("url")); url != "" {
+		objectURLs = append(objectURLs, url)
+	}
+
+	account := request.Form.Get("account")
+	category := request.Form.Get("category")
+	comment := request.Form.Get("comment")
+	forward := convert.Bool(request.Form.Get("forward"))
+
+	if _, err := builder.factory().Report().File(&user, account, objectURLs, category, comment, forward); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error filing Report"))
+	}
+
+	return Continue().WithEvent("closeModal", "true")
+}
+
+// schema returns the validating schema for this form
+func (step StepAddReport) schema() schema.Schema {
+	return schema.Schema{
+		Element: schema.Object{
+			Properties: map[string]schema.Element{
+				"url":      schema.String{Format: "url"},
+				"account":  schema.String{Format: "url"},
+				"category": schema.String{},
+				"comment":  schema.String{MaxLength: 2048},
+				"forward":  schema.Boolean{},
+			},
+		},
+	}
+}
+
+// form returns the form to be displayed.  Reports about remote accounts
+// can also be forwarded to the moderators of the remote server.
+func (step StepAddReport) form(builder Builder) form.Element {
+
+	children := []form.Element{
+		{Type: "hidden", Path: "url"},
+		{Type: "hidden", Path: "account"},
+		{Type: "select", Path: "category", Label: "What's the problem?", Options: mapof.Any{"provider": "report-categories"}},
+		{Type: "textarea", Path: "comment", Label: "Additional Comments"},
+	}
+
+	if !strings.HasPrefix(builder.QueryParam("url"), builder.Host()+"/") {
+		children = append(children, form.Element{Type: "toggle", Path: "forward", Label: "Forward this report to the remote server?"})
+	}
+
+	return form.Element{
+		Type:     "layout-vertical",
+		Children: children,
+	}
+}
@@ -0,0 +1,67 @@
+package build
+
+import (
+	"io"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StepAddReviewComment represents an action-step that adds an inline editorial comment to a Stream
+type StepAddReviewComment struct{}
+
+func (step StepAddReviewComment) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post saves a new ReviewComment using the "path", "selection", and "body" form values
+func (step StepAddReviewComment) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepAddReviewComment.Post"
+
+	if _, ok := builder.(*Stream); !ok {
+		return Halt().WithError(derp.NewInternalError(location, "This step can only be used on a Stream"))
+	}
+
+	// Try to parse the form input
+	request := builder.request()
+
+	if err := request.ParseForm(); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error parsing form input"))
+	}
+
+	// Load the User who is writing the comment
+	user, err := builder.getUser()
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error loading User"))
+	}
+
+	factory := builder.factory()
+	streamID := builder.objectID()
+
+	// Create the new comment
+	comment := model.NewReviewComment()
+	comment.StreamID = streamID
+	comment.Author = user.PersonLink()
+	comment.Path = request.Form.Get("path")
+	comment.Selection = request.Form.Get("selection")
+	comment.Body = request.Form.Get("body")
+
+	// Attach the comment to the pending Review, if one exists
+	review := model.NewReview()
+	if err := factory.Review().LoadPending(streamID, &review); err == nil {
+		comment.ReviewID = review.ReviewID
+	} else if !derp.NotFound(err) {
+		return Halt().WithError(derp.Wrap(err, location, "Error loading pending Review"))
+	} else {
+		comment.ReviewID = primitive.NilObjectID
+	}
+
+	if err := factory.ReviewComment().Save(&comment, "Created by "+user.DisplayName); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error saving ReviewComment"))
+	}
+
+	return nil
+}
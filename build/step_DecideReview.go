@@ -0,0 +1,165 @@
+package build
+
+import (
+	"io"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/form"
+	"github.com/benpate/html"
+	"github.com/benpate/rosetta/convert"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/rosetta/schema"
+)
+
+// StepDecideReview represents an action-step that records a reviewer's decision on the pending Review for a Stream
+type StepDecideReview struct {
+	Title   string
+	Message string
+}
+
+// Get displays a modal form where the reviewer can approve or reject the pending Review
+func (step StepDecideReview) Get(builder Builder, buffer io.Writer) PipelineBehavior {
+
+	const location = "build.StepDecideReview.Get"
+
+	// Confirm that there is a pending Review to decide
+	review := model.NewReview()
+
+	if err := step.loadReview(builder, &review); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error loading Review"))
+	}
+
+	// Try to write form HTML
+	formHTML, err := form.Editor(step.schema(), step.form(), mapof.Any{"decision": model.ReviewDecisionApprove}, builder.lookupProvider())
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error building form"))
+	}
+
+	// Write the rest of the HTML that contains the form
+	b := html.New()
+
+	// Heading
+	b.H1().InnerText(step.Title).Close()
+
+	if step.Message != "" {
+		b.Div().Class("margin-bottom").InnerText(step.Message).Close()
+	}
+
+	if review.Comment != "" {
+		b.Div().Class("margin-bottom").InnerText(review.RequestedBy.Name + ": " + review.Comment).Close()
+	}
+
+	// Form
+	b.Form("", "").
+		Data("hx-post", builder.URL()).
+		Data("hx-swap", "none").
+		Data("hx-push-url", "false").
+		EndBracket()
+
+	b.Input("hidden", "reviewId").Value(review.ReviewID.Hex()).Close()
+	b.WriteString(formHTML)
+	b.Div()
+	b.Button().Type("submit").Class("primary").InnerText("Submit Decision").Close()
+	b.Button().Type("button").Script("on click trigger closeModal").InnerText("Cancel").Close()
+	b.CloseAll()
+
+	modalHTML := WrapModal(builder.response(), b.String())
+
+	// nolint:errcheck
+	io.WriteString(buffer, modalHTML)
+	return Halt().AsFullPage()
+}
+
+// Post records the reviewer's decision, moving the Stream into its new State once enough approvals are collected
+func (step StepDecideReview) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepDecideReview.Post"
+
+	streamBuilder, ok := builder.(*Stream)
+
+	if !ok {
+		return Halt().WithError(derp.NewInternalError(location, "This step can only be used on a Stream"))
+	}
+
+	// Try to parse the form input
+	request := builder.request()
+
+	if err := request.ParseForm(); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error parsing form input"))
+	}
+
+	// Load the pending Review
+	review := model.NewReview()
+
+	if err := step.loadReview(builder, &review); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error loading Review"))
+	}
+
+	// Find the Transition that is being reviewed
+	transition, exists := streamBuilder._template.Transition(review.TransitionID)
+
+	if !exists {
+		return Halt().WithError(derp.NewInternalError(location, "Unrecognized transition", review.TransitionID))
+	}
+
+	// Load the User who is making the decision
+	user, err := builder.getUser()
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error loading User"))
+	}
+
+	// Record the decision
+	authorization := builder.authorization()
+	reviewService := builder.factory().Review()
+	decision := convert.String(request.Form["decision"])
+	comment := convert.String(request.Form["comment"])
+
+	if err := reviewService.Decide(&review, streamBuilder._stream, transition, &user, &authorization, decision, comment); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error recording decision"))
+	}
+
+	return Continue().WithEvent("closeModal", "true")
+}
+
+// loadReview loads the Review named in the request (via "reviewId"), or the pending Review for this Stream
+func (step StepDecideReview) loadReview(builder Builder, review *model.Review) error {
+
+	reviewService := builder.factory().Review()
+	streamID := builder.objectID()
+
+	if token := builder.QueryParam("reviewId"); token != "" {
+		return reviewService.LoadByToken(streamID, token, review)
+	}
+
+	if token := builder.request().FormValue("reviewId"); token != "" {
+		return reviewService.LoadByToken(streamID, token, review)
+	}
+
+	return reviewService.LoadPending(streamID, review)
+}
+
+// schema returns the validating schema for this form
+func (step StepDecideReview) schema() schema.Schema {
+	return schema.Schema{
+		Element: schema.Object{
+			Properties: map[string]schema.Element{
+				"decision": schema.String{Enum: []string{model.ReviewDecisionApprove, model.ReviewDecisionReject}},
+				"comment":  schema.String{MaxLength: 2048},
+			},
+		},
+	}
+}
+
+// form returns the form to be displayed
+func (step StepDecideReview) form() form.Element {
+	return form.Element{
+		Type: "layout-vertical",
+		Children: []form.Element{
+			{Type: "radio", Path: "decision", Label: "Decision", Options: mapof.Any{"provider": "review-decisions"}},
+			{Type: "textarea", Path: "comment", Label: "Comment"},
+		},
+	}
+}
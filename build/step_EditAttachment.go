@@ -0,0 +1,130 @@
+package build
+
+import (
+	"io"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/form"
+	"github.com/benpate/html"
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StepEditAttachment represents an action-step that can edit the alt text of a single attachment
+type StepEditAttachment struct {
+	Title string
+}
+
+// Get displays a modal form to edit the Attachment identified by the "attachmentId" query parameter
+func (step StepEditAttachment) Get(builder Builder, buffer io.Writer) PipelineBehavior {
+
+	const location = "build.StepEditAttachment.Get"
+
+	attachment, err := step.load(builder)
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error loading Attachment"))
+	}
+
+	// Try to write form HTML
+	formHTML, err := form.Editor(step.schema(), step.form(), &attachment, builder.lookupProvider())
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error building form"))
+	}
+
+	// Write the rest of the HTML that contains the form
+	b := html.New()
+
+	b.H1().InnerText(step.Title).Close()
+
+	b.Form("", "").
+		Data("hx-post", builder.URL()).
+		Data("hx-swap", "none").
+		Data("hx-push-url", "false").
+		EndBracket()
+
+	b.WriteString(formHTML)
+	b.Div()
+	b.Button().Type("submit").Class("primary").InnerText("Save Changes").Close()
+	b.Button().Type("button").Script("on click trigger closeModal").InnerText("Cancel").Close()
+	b.CloseAll()
+
+	modalHTML := WrapModal(builder.response(), b.String())
+
+	// nolint:errcheck
+	io.WriteString(buffer, modalHTML)
+	return Halt().AsFullPage()
+}
+
+// Post saves the updated alt text into the Attachment
+func (step StepEditAttachment) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepEditAttachment.Post"
+
+	attachment, err := step.load(builder)
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error loading Attachment"))
+	}
+
+	// Try to parse the form input
+	request := builder.request()
+
+	if err := request.ParseForm(); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error parsing form input"))
+	}
+
+	attachment.AltText = request.Form.Get("altText")
+
+	// Save the Attachment
+	if err := builder.factory().Attachment().Save(&attachment, "Updated alt text"); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error saving Attachment"))
+	}
+
+	return Continue().WithEvent("closeModal", "true").WithEvent("attachments-updated", "true")
+}
+
+// load retrieves the Attachment identified by the "attachmentId" query parameter
+func (step StepEditAttachment) load(builder Builder) (model.Attachment, error) {
+
+	const location = "build.StepEditAttachment.load"
+
+	objectType := builder.service().ObjectType()
+	objectID := builder.objectID()
+
+	// Special case:  Drafts store their attachments on the parent stream.
+	if objectType == "StreamDraft" {
+		objectType = model.AttachmentObjectTypeStream
+	}
+
+	attachmentID, err := primitive.ObjectIDFromHex(builder.QueryParam("attachmentId"))
+
+	if err != nil {
+		return model.Attachment{}, derp.Wrap(err, location, "Invalid attachment ID", builder.QueryParam("attachmentId"))
+	}
+
+	attachment := model.NewAttachment(objectType, objectID)
+
+	if err := builder.factory().Attachment().LoadByID(objectType, objectID, attachmentID, &attachment); err != nil {
+		return model.Attachment{}, derp.Wrap(err, location, "Error loading Attachment", attachmentID)
+	}
+
+	return attachment, nil
+}
+
+// schema returns the validating schema for this form
+func (step StepEditAttachment) schema() schema.Schema {
+	return schema.New(model.AttachmentSchema())
+}
+
+// form returns the form to be displayed
+func (step StepEditAttachment) form() form.Element {
+	return form.Element{
+		Type: "layout-vertical",
+		Children: []form.Element{
+			{Type: "textarea", Path: "altText", Label: "Alt Text", Description: "Describe this file for people who use screen readers."},
+		},
+	}
+}
@@ -0,0 +1,103 @@
+package build
+
+import (
+	"io"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/form"
+	"github.com/benpate/html"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/rosetta/schema"
+)
+
+// StepEditAuthorizedFetch represents an action-step that can enable or disable authorized fetch for a Domain
+type StepEditAuthorizedFetch struct{}
+
+// Get displays a modal form to edit the Domain's authorized fetch setting
+func (step StepEditAuthorizedFetch) Get(builder Builder, buffer io.Writer) PipelineBehavior {
+
+	const location = "build.StepEditAuthorizedFetch.Get"
+
+	// Require that this is only run in a Domain Builder
+	domainBuilder, ok := builder.(Domain)
+
+	if !ok {
+		return Halt().WithError(derp.NewInternalError(location, "Step edit-authorized-fetch can only be used in an admin/domain template"))
+	}
+
+	// Try to write form HTML
+	formHTML, err := form.Editor(step.schema(), step.form(), domainBuilder._domain, builder.lookupProvider())
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error building form"))
+	}
+
+	// Write the rest of the HTML that contains the form
+	b := html.New()
+
+	b.H1().ID("modal-title").InnerText("Authorized Fetch").Close()
+
+	b.Form("", "").
+		Data("hx-post", builder.URL()).
+		Data("hx-swap", "none").
+		Data("hx-push-url", "false").
+		EndBracket()
+
+	b.WriteString(formHTML)
+	b.Div()
+	b.Button().Type("submit").Class("primary").InnerText("Save Changes").Close()
+	b.Button().Type("button").Script("on click trigger closeModal").InnerText("Cancel").Close()
+	b.CloseAll()
+
+	modalHTML := WrapModal(builder.response(), b.String())
+
+	// nolint:errcheck
+	io.WriteString(buffer, modalHTML)
+	return Halt().AsFullPage()
+}
+
+// Post updates the Domain with the new authorized fetch setting.  It does not save the Domain,
+// so this step should be followed by a "save" step.
+func (step StepEditAuthorizedFetch) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepEditAuthorizedFetch.Post"
+
+	// Require that this is only run in a Domain Builder
+	domainBuilder, ok := builder.(Domain)
+
+	if !ok {
+		return Halt().WithError(derp.NewInternalError(location, "Step edit-authorized-fetch can only be used in an admin/domain template"))
+	}
+
+	// Bind to the form POST data
+	body := mapof.NewAny()
+
+	if err := bind(builder.request(), &body); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error binding form data"))
+	}
+
+	// Apply the form data to the Domain (limited and validated by the form schema)
+	stepForm := form.New(step.schema(), step.form())
+
+	if err := stepForm.SetAll(domainBuilder._domain, body, builder.lookupProvider()); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error applying form data to Domain", body))
+	}
+
+	return Continue().WithEvent("closeModal", "true")
+}
+
+// schema returns the validating schema for this form
+func (step StepEditAuthorizedFetch) schema() schema.Schema {
+	return schema.New(model.DomainSchema())
+}
+
+// form returns the form to be displayed
+func (step StepEditAuthorizedFetch) form() form.Element {
+	return form.Element{
+		Type: "layout-vertical",
+		Children: []form.Element{
+			{Type: "toggle", Path: "authorizedFetch", Label: "Require signatures on ActivityPub requests", Description: "Remote servers must sign every request for posts and collections, so that blocked actors and domains cannot read them.  Profiles remain visible so that other servers can verify signatures.  Some older software may not be able to view content on this site."},
+		},
+	}
+}
@@ -0,0 +1,40 @@
+package build
+
+import (
+	"io"
+
+	"github.com/benpate/derp"
+)
+
+// StepFollowHashtag represents an action-step that follows the current hashtag for the signed-in User
+type StepFollowHashtag struct{}
+
+func (step StepFollowHashtag) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post adds the current hashtag to the signed-in User's followed hashtags
+func (step StepFollowHashtag) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepFollowHashtag.Post"
+
+	hashtagBuilder, ok := builder.(Hashtag)
+
+	if !ok {
+		return Halt().WithError(derp.NewInternalError(location, "Step follow-hashtag can only be used in a hashtag template"))
+	}
+
+	if !hashtagBuilder.IsAuthenticated() {
+		return Halt().WithError(derp.NewUnauthorizedError(location, "You must be signed in to follow hashtags"))
+	}
+
+	followedTag, err := builder.factory().FollowedTag().Follow(hashtagBuilder.AuthenticatedID(), hashtagBuilder.Name())
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error following hashtag", hashtagBuilder.Name()))
+	}
+
+	*hashtagBuilder._followedTag = followedTag
+
+	return nil
+}
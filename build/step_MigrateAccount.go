@@ -0,0 +1,136 @@
+package build
+
+import (
+	"io"
+	"strings"
+
+	"github.com/benpate/derp"
+	"github.com/benpate/html"
+)
+
+// StepMigrateAccount represents an action-step that manages a User's account aliases,
+// and can move the User to another ActivityPub account.
+type StepMigrateAccount struct{}
+
+// Get displays a modal form with the User's aliases, the account to move to, and CSV import/export links
+func (step StepMigrateAccount) Get(builder Builder, buffer io.Writer) PipelineBehavior {
+
+	const location = "build.StepMigrateAccount.Get"
+
+	// Require that this is only run in an Outbox Builder
+	outboxBuilder, ok := builder.(Outbox)
+
+	if !ok {
+		return Halt().WithError(derp.NewInternalError(location, "Step migrate-account can only be used in a user-outbox template"))
+	}
+
+	user := outboxBuilder._user
+
+	b := html.New()
+
+	b.H1().ID("modal-title").InnerText("Account Migration").Close()
+
+	// Form to update aliases and move to a new account
+	b.Form("", "").
+		Data("hx-post", builder.URL()).
+		Data("hx-swap", "none").
+		Data("hx-push-url", "false").
+		EndBracket()
+
+	b.Div().Class("layout layout-vertical")
+	b.Div().Class("layout-vertical-elements")
+
+	b.Div().Class("layout-vertical-element")
+	b.Label("migrate-aliases").InnerText("Account Aliases").Close()
+	b.Textarea("alsoKnownAs").ID("migrate-aliases").Class("height100").InnerText(strings.Join(user.AlsoKnownAs, "\n")).Close()
+	b.Div().Class("text-sm text-gray").InnerText("To move another account into this one, enter its address here (one per line) before moving.").Close()
+	b.Close()
+
+	b.Div().Class("layout-vertical-element")
+	b.Label("migrate-moved-to").InnerText("Move to a New Account").Close()
+	b.Input("text", "movedTo").ID("migrate-moved-to").Value(user.MovedTo).Attr("placeholder", "username@server.social").Close()
+	b.Div().Class("text-sm text-gray").InnerText("Your followers will be asked to follow the new account.  The new account must list this one as an alias first.").Close()
+	b.Close()
+
+	b.Close() // layout-vertical-elements
+	b.Close() // layout
+
+	b.Div()
+	b.Button().Type("submit").Class("primary").InnerText("Save Changes").Close()
+	b.Button().Type("button").Script("on click trigger closeModal").InnerText("Cancel").Close()
+	b.Close()
+	b.Close() // form
+
+	// Links to export and import followers/following
+	b.H2().InnerText("Export & Import").Close()
+
+	b.Div().Class("margin-bottom")
+	b.A("/@me/following.csv").Attr("download", "following.csv").Class("button").InnerText("Export Following").Close()
+	b.Space()
+	b.A("/@me/followers.csv").Attr("download", "followers.csv").Class("button").InnerText("Export Followers").Close()
+	b.Close()
+
+	b.Form("", "").
+		Data("hx-post", "/@me/following.csv").
+		Data("hx-encoding", "multipart/form-data").
+		Data("hx-push-url", "false").
+		EndBracket()
+
+	b.Label("migrate-import").InnerText("Import Following (CSV)").Close()
+	b.Input("file", "file").ID("migrate-import").Attr("accept", ".csv,text/csv").Close()
+	b.Button().Type("submit").InnerText("Import").Close()
+	b.CloseAll()
+
+	modalHTML := WrapModal(builder.response(), b.String())
+
+	// nolint:errcheck
+	io.WriteString(buffer, modalHTML)
+	return Halt().AsFullPage()
+}
+
+// Post updates the User's aliases, and moves the User to a new account if requested
+func (step StepMigrateAccount) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepMigrateAccount.Post"
+
+	// Require that this is only run in an Outbox Builder
+	outboxBuilder, ok := builder.(Outbox)
+
+	if !ok {
+		return Halt().WithError(derp.NewInternalError(location, "Step migrate-account can only be used in a user-outbox template"))
+	}
+
+	user := outboxBuilder._user
+	userService := builder.factory().User()
+
+	// Try to parse the form input
+	request := builder.request()
+
+	if err := request.ParseForm(); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error parsing form input"))
+	}
+
+	// Update aliases
+	aliases := strings.Split(request.Form.Get("alsoKnownAs"), "\n")
+
+	if err := userService.SetAliases(user, aliases); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error updating aliases"))
+	}
+
+	// Move to a new account (this also saves the User)
+	if movedTo := strings.TrimSpace(request.Form.Get("movedTo")); (movedTo != "") && (movedTo != user.MovedTo) {
+
+		if err := userService.Move(user, movedTo); err != nil {
+			return Halt().WithError(derp.Wrap(err, location, "Error moving account", movedTo))
+		}
+
+		return Continue().WithEvent("closeModal", "true").WithEvent("refreshPage", "true")
+	}
+
+	// Otherwise, just save the aliases
+	if err := userService.Save(user, "Updated account aliases"); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error saving User"))
+	}
+
+	return Continue().WithEvent("closeModal", "true").WithEvent("refreshPage", "true")
+}
@@ -0,0 +1,53 @@
+package build
+
+import (
+	"io"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+)
+
+// StepModerateReport represents an action-step that forwards, resolves, or dismisses a moderation Report
+type StepModerateReport struct {
+	Action string
+}
+
+func (step StepModerateReport) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post applies the requested moderation action to the current Report
+func (step StepModerateReport) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepModerateReport.Post"
+
+	reportBuilder, ok := builder.(Report)
+
+	if !ok {
+		return Halt().WithError(derp.NewInternalError(location, "Step moderate-report can only be used in an admin-reports template"))
+	}
+
+	report := reportBuilder._report
+	reportService := builder.factory().Report()
+	userID := builder.authorization().UserID
+
+	switch step.Action {
+
+	case "forward":
+		if err := reportService.Forward(report); err != nil {
+			return Halt().WithError(derp.Wrap(err, location, "Error forwarding Report", report.ReportID))
+		}
+
+	case "resolve":
+		if err := reportService.Resolve(report, userID, model.ReportStatusResolved); err != nil {
+			return Halt().WithError(derp.Wrap(err, location, "Error resolving Report", report.ReportID))
+		}
+
+	case "dismiss":
+		if err := reportService.Resolve(report, userID, model.ReportStatusDismissed); err != nil {
+			return Halt().WithError(derp.Wrap(err, location, "Error dismissing Report", report.ReportID))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package build
+
+import (
+	"encoding/xml"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/list"
+)
+
+// StepRSSCloud represents an action-step that accepts RSSCloud "pleaseNotify" requests
+// https://www.rssboard.org/rsscloud-interface
+type StepRSSCloud struct {
+}
+
+// rssCloudResult is the XML response expected by RSSCloud subscribers
+type rssCloudResult struct {
+	XMLName xml.Name `xml:"notifyResult"`
+	Success bool     `xml:"success,attr"`
+	Message string   `xml:"msg,attr"`
+}
+
+// Get is not required by RSSCloud.  So let's redirect to the primary action.
+func (step StepRSSCloud) Get(builder Builder, buffer io.Writer) PipelineBehavior {
+
+	newLocation := list.RemoveLast(builder.URL(), list.DelimiterSlash)
+	if err := redirect(builder.response(), http.StatusSeeOther, newLocation); err != nil {
+		return Halt().WithError(derp.Wrap(err, "build.StepRSSCloud.Get", "Error writing redirection", newLocation))
+	}
+	return nil
+}
+
+// Post accepts an RSSCloud request, verifies it, and potentially creates a new Follower record.
+func (step StepRSSCloud) Post(builder Builder, buffer io.Writer) PipelineBehavior {
+
+	request := builder.request()
+
+	if err := request.ParseForm(); err != nil {
+		return step.result(buffer, derp.Wrap(err, "build.StepRSSCloud.Post", "Error parsing form data"))
+	}
+
+	// RSSCloud 2.0 subscribers may identify their own domain.  Otherwise, use the requesting IP address
+	domain := request.PostForm.Get("domain")
+	challenge := (domain != "")
+
+	if domain == "" {
+		domain, _, _ = net.SplitHostPort(request.RemoteAddr)
+	}
+
+	callback := step.callbackURL(request.PostForm.Get("protocol"), domain, request.PostForm.Get("port"), request.PostForm.Get("path"))
+
+	// Verify the request and save the follower.  This happens synchronously
+	// because RSSCloud subscribers expect the result in the response.
+	factory := builder.factory()
+
+	task := service.NewTaskCreateRSSCloudFollower(
+		factory.Follower(),
+		factory.Locator(),
+		builder.objectType(),
+		builder.objectID(),
+		request.PostForm.Get("url1"),
+		callback,
+		challenge,
+	)
+
+	return step.result(buffer, task.Run())
+}
+
+// callbackURL calculates the URL where a subscriber will receive notifications
+func (step StepRSSCloud) callbackURL(protocol string, domain string, port string, path string) string {
+
+	result := url.URL{
+		Scheme: "http",
+		Host:   net.JoinHostPort(domain, port),
+		Path:   "/" + strings.TrimPrefix(path, "/"),
+	}
+
+	if (protocol == "https-post") || (port == "443") {
+		result.Scheme = "https"
+	}
+
+	// Omit default ports
+	if (port == "") || (result.Scheme == "http" && port == "80") || (result.Scheme == "https" && port == "443") {
+		result.Host = domain
+	}
+
+	return result.String()
+}
+
+// result writes an RSSCloud notifyResult document
+func (step StepRSSCloud) result(buffer io.Writer, err error) PipelineBehavior {
+
+	result := rssCloudResult{
+		Success: true,
+		Message: "Thanks for the registration. It worked. When the feed changes we'll notify you.",
+	}
+
+	if err != nil {
+		derp.Report(err)
+		result.Success = false
+		result.Message = derp.Message(err)
+	}
+
+	// nolint:errcheck
+	buffer.Write([]byte(xml.Header))
+
+	if err := xml.NewEncoder(buffer).Encode(result); err != nil {
+		return Halt().WithError(derp.Wrap(err, "build.StepRSSCloud.result", "Error writing response"))
+	}
+
+	return Halt().WithContentType("text/xml; charset=UTF-8")
+}
@@ -0,0 +1,18 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRSSCloudCallbackURL(t *testing.T) {
+
+	step := StepRSSCloud{}
+
+	require.Equal(t, "http://example.com/notify", step.callbackURL("http-post", "example.com", "80", "/notify"))
+	require.Equal(t, "https://example.com/notify", step.callbackURL("http-post", "example.com", "443", "notify"))
+	require.Equal(t, "https://example.com/notify", step.callbackURL("https-post", "example.com", "", "/notify"))
+	require.Equal(t, "http://example.com:5337/notify", step.callbackURL("http-post", "example.com", "5337", "/notify"))
+	require.Equal(t, "http://127.0.0.1:5337/notify", step.callbackURL("http-post", "127.0.0.1", "5337", "/notify"))
+}
@@ -0,0 +1,154 @@
+package build
+
+import (
+	"io"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/id"
+	"github.com/benpate/derp"
+	"github.com/benpate/form"
+	"github.com/benpate/html"
+	"github.com/benpate/rosetta/convert"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/rosetta/schema"
+)
+
+// StepRequestReview represents an action-step that asks one or more reviewers to approve a workflow Transition
+type StepRequestReview struct {
+	Transition string
+	Title      string
+	Message    string
+}
+
+// Get displays a modal form where the requester can choose reviewers
+func (step StepRequestReview) Get(builder Builder, buffer io.Writer) PipelineBehavior {
+
+	const location = "build.StepRequestReview.Get"
+
+	// Validate the Transition before displaying the form
+	if _, err := step.transition(builder); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Invalid transition"))
+	}
+
+	// Try to write form HTML
+	formHTML, err := form.Editor(step.schema(), step.form(), mapof.NewAny(), builder.lookupProvider())
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error building form"))
+	}
+
+	// Write the rest of the HTML that contains the form
+	b := html.New()
+
+	// Heading
+	b.H1().InnerText(step.Title).Close()
+
+	if step.Message != "" {
+		b.Div().Class("margin-bottom").InnerText(step.Message).Close()
+	}
+
+	// Form
+	b.Form("", "").
+		Data("hx-post", builder.URL()).
+		Data("hx-swap", "none").
+		Data("hx-push-url", "false").
+		EndBracket()
+
+	b.WriteString(formHTML)
+	b.Div()
+	b.Button().Type("submit").Class("primary").InnerText("Request Review").Close()
+	b.Button().Type("button").Script("on click trigger closeModal").InnerText("Cancel").Close()
+	b.CloseAll()
+
+	modalHTML := WrapModal(builder.response(), b.String())
+
+	// nolint:errcheck
+	io.WriteString(buffer, modalHTML)
+	return Halt().AsFullPage()
+}
+
+// Post creates a new Review and notifies all of the selected reviewers
+func (step StepRequestReview) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepRequestReview.Post"
+
+	streamBuilder, ok := builder.(*Stream)
+
+	if !ok {
+		return Halt().WithError(derp.NewInternalError(location, "This step can only be used on a Stream"))
+	}
+
+	transition, err := step.transition(builder)
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Invalid transition"))
+	}
+
+	// Try to parse the form input
+	request := builder.request()
+
+	if err := request.ParseForm(); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error parsing form input"))
+	}
+
+	// Load the User who is requesting the Review
+	user, err := builder.getUser()
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error loading User"))
+	}
+
+	// Create the Review
+	reviewService := builder.factory().Review()
+	assigneeIDs := id.SliceOfID(request.Form["assigneeIds"])
+	comment := convert.String(request.Form["comment"])
+
+	if _, err := reviewService.Request(streamBuilder._stream, transition, &user, assigneeIDs, comment); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error requesting review"))
+	}
+
+	return Continue().WithEvent("closeModal", "true")
+}
+
+// transition returns the Template Transition that this step requests
+func (step StepRequestReview) transition(builder Builder) (model.Transition, error) {
+
+	const location = "build.StepRequestReview.transition"
+
+	template, exists := getTemplate(builder)
+
+	if !exists {
+		return model.Transition{}, derp.NewInternalError(location, "This step cannot be used in this Renderer.")
+	}
+
+	transition, exists := template.Transition(step.Transition)
+
+	if !exists {
+		return model.Transition{}, derp.NewInternalError(location, "Unrecognized transition", step.Transition)
+	}
+
+	return transition, nil
+}
+
+// schema returns the validating schema for this form
+func (step StepRequestReview) schema() schema.Schema {
+	return schema.Schema{
+		Element: schema.Object{
+			Properties: map[string]schema.Element{
+				"assigneeIds": schema.Array{Items: schema.String{Format: "objectId"}},
+				"comment":     schema.String{MaxLength: 2048},
+			},
+		},
+	}
+}
+
+// form returns the form to be displayed
+func (step StepRequestReview) form() form.Element {
+	return form.Element{
+		Type: "layout-vertical",
+		Children: []form.Element{
+			{Type: "multiselect", Path: "assigneeIds", Label: "Reviewers", Options: mapof.Any{"provider": "users"}},
+			{Type: "textarea", Path: "comment", Label: "Note to Reviewers"},
+		},
+	}
+}
@@ -0,0 +1,48 @@
+package build
+
+import (
+	"io"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+)
+
+// StepResolveReviewComment represents an action-step that marks an inline editorial comment as addressed
+type StepResolveReviewComment struct{}
+
+func (step StepResolveReviewComment) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post resolves the ReviewComment identified by the "reviewCommentId" query parameter
+func (step StepResolveReviewComment) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepResolveReviewComment.Post"
+
+	if _, ok := builder.(*Stream); !ok {
+		return Halt().WithError(derp.NewInternalError(location, "This step can only be used on a Stream"))
+	}
+
+	// Load the User who is resolving the comment
+	user, err := builder.getUser()
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error loading User"))
+	}
+
+	// Load the ReviewComment
+	commentService := builder.factory().ReviewComment()
+	comment := model.NewReviewComment()
+	token := builder.QueryParam("reviewCommentId")
+
+	if err := commentService.LoadByToken(builder.objectID(), token, &comment); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error loading ReviewComment", token))
+	}
+
+	// Mark it as resolved
+	if err := commentService.Resolve(&comment, &user); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error resolving ReviewComment", token))
+	}
+
+	return nil
+}
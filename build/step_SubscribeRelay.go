@@ -0,0 +1,34 @@
+package build
+
+import (
+	"io"
+
+	"github.com/benpate/derp"
+)
+
+// StepSubscribeRelay represents an action-step that sends a subscription (Follow) request to an ActivityPub Relay
+type StepSubscribeRelay struct{}
+
+func (step StepSubscribeRelay) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post sends a Follow activity from the instance actor to the current Relay
+func (step StepSubscribeRelay) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepSubscribeRelay.Post"
+
+	relayBuilder, ok := builder.(Relay)
+
+	if !ok {
+		return Halt().WithError(derp.NewInternalError(location, "Step subscribe-relay can only be used in an admin-relays template"))
+	}
+
+	relay := relayBuilder._relay
+
+	if err := builder.factory().Relay().Subscribe(relay); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error subscribing to Relay", relay.URL))
+	}
+
+	return nil
+}
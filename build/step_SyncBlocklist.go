@@ -0,0 +1,34 @@
+package build
+
+import (
+	"io"
+
+	"github.com/benpate/derp"
+)
+
+// StepSyncBlocklist represents an action-step that re-fetches a Blocklist subscription immediately
+type StepSyncBlocklist struct{}
+
+func (step StepSyncBlocklist) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post fetches the current Blocklist and synchronizes its entries into server-wide Rules
+func (step StepSyncBlocklist) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepSyncBlocklist.Post"
+
+	blocklistBuilder, ok := builder.(Blocklist)
+
+	if !ok {
+		return Halt().WithError(derp.NewInternalError(location, "Step sync-blocklist can only be used in an admin-blocklists template"))
+	}
+
+	blocklist := blocklistBuilder._blocklist
+
+	if err := builder.factory().Blocklist().Sync(blocklist); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error synchronizing Blocklist", blocklist.URL))
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package build
+
+import (
+	"io"
+
+	"github.com/benpate/derp"
+)
+
+// StepUnfollowHashtag represents an action-step that unfollows the current hashtag for the signed-in User
+type StepUnfollowHashtag struct{}
+
+func (step StepUnfollowHashtag) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post removes the current hashtag from the signed-in User's followed hashtags
+func (step StepUnfollowHashtag) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepUnfollowHashtag.Post"
+
+	hashtagBuilder, ok := builder.(Hashtag)
+
+	if !ok {
+		return Halt().WithError(derp.NewInternalError(location, "Step unfollow-hashtag can only be used in a hashtag template"))
+	}
+
+	if !hashtagBuilder.IsAuthenticated() {
+		return Halt().WithError(derp.NewUnauthorizedError(location, "You must be signed in to unfollow hashtags"))
+	}
+
+	if err := builder.factory().FollowedTag().Unfollow(hashtagBuilder.AuthenticatedID(), hashtagBuilder.Name()); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error unfollowing hashtag", hashtagBuilder.Name()))
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package build
 import (
 	"encoding/json"
 	"io"
+	"strings"
 
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/benpate/derp"
@@ -21,6 +22,8 @@ type StepUploadAttachments struct {
 	Category       string // Category to apply to the Attachment
 	Maximum        int    // Maximum number of uploads to allow (Default: 1)
 	JSONResult     bool   // If TRUE, return a JSON structure with result data. This forces Maximum=1
+	AltTextField   string // Name of the form field that contains alt text for each file (Default: "altText")
+	AltTextRequire bool   // If TRUE, then every uploaded file must include alt text
 }
 
 func (step StepUploadAttachments) Get(builder Builder, _ io.Writer) PipelineBehavior {
@@ -65,19 +68,49 @@ func (step StepUploadAttachments) Post(builder Builder, buffer io.Writer) Pipeli
 		files = files[:step.Maximum]
 	}
 
+	// Alt text (if provided) is matched to each file by its position in the form
+	altTexts := form.Value[step.AltTextField]
+
+	if step.AltTextRequire {
+		for index := range files {
+			if (index >= len(altTexts)) || (strings.TrimSpace(altTexts[index]) == "") {
+				return Halt().WithError(derp.NewBadRequestError(location, "Alt text is required for every uploaded file", files[index].Filename))
+			}
+		}
+	}
+
+	// Uploads count against the current User's storage quota
+	userID := builder.authorization().UserID
+	uploadSize := int64(0)
+
+	for _, fileHeader := range files {
+		uploadSize += fileHeader.Size
+	}
+
+	if err := factory.Storage().CheckQuota(userID, uploadSize); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Upload exceeds storage quota"))
+	}
+
 	// Make room for new attachments
 	if err := attachmentService.MakeRoom(objectType, objectID, step.Category, step.Action, step.Maximum, len(files)); err != nil {
 		return Halt().WithError(derp.Wrap(err, location, "Error making room for new Attachments"))
 	}
 
 	// Make attachments for each uploaded file
-	for _, fileHeader := range files {
+	for index, fileHeader := range files {
 
 		log.Trace().Str("Filename", fileHeader.Filename).Msg("Found file")
 
 		attachment := model.NewAttachment(objectType, objectID)
 		attachment.Original = fileHeader.Filename
 		attachment.Category = step.Category
+		attachment.MediaType = attachment.CalcMediaType()
+		attachment.UserID = userID
+		attachment.Size = fileHeader.Size
+
+		if index < len(altTexts) {
+			attachment.AltText = strings.TrimSpace(altTexts[index])
+		}
 
 		// Open the source (from the POST request)
 		source, err := fileHeader.Open()
@@ -99,11 +132,21 @@ func (step StepUploadAttachments) Post(builder Builder, buffer io.Writer) Pipeli
 		attachment.Width = width
 		attachment.Height = height
 
+		// Calculate a placeholder for images
+		if err := attachmentService.CalcBlurhash(&attachment); err != nil {
+			derp.Report(derp.Wrap(err, location, "Error calculating blurhash", attachment))
+		}
+
 		// Try to save the Attachment
 		if err := attachmentService.Save(&attachment, "Uploaded file: "+fileHeader.Filename); err != nil {
 			return Halt().WithError(derp.Wrap(err, location, "Error saving attachment", attachment))
 		}
 
+		// Video and audio files are transcoded in the background
+		if err := attachmentService.Transcode(&attachment); err != nil {
+			return Halt().WithError(derp.Wrap(err, location, "Error transcoding attachment", attachment))
+		}
+
 		// Try to put the the attachmentId into the object
 		if step.AttachmentPath != "" {
 			log.Trace().Str("AttachmentPath", step.AttachmentPath).Str("Value", attachment.AttachmentID.Hex()).Msg("Setting attachment path")
@@ -2,12 +2,12 @@ package build
 
 import (
 	"encoding/json"
+	"encoding/xml"
 	"io"
 	"time"
 
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/tools/convert"
-	"github.com/benpate/data"
 	"github.com/benpate/derp"
 	"github.com/benpate/rosetta/iterator"
 	"github.com/benpate/rosetta/slice"
@@ -34,10 +34,17 @@ func (step StepViewFeed) Get(builder Builder, buffer io.Writer) PipelineBehavior
 	}
 
 	mimeType := step.detectMimeType(builder)
+	streams := iterator.Slice(children, model.NewStream)
+	attachments := step.attachments(builder, streams)
 
 	// Special case for JSONFeed
 	if mimeType == model.MimeTypeJSONFeed {
-		return step.asJSONFeed(builder, buffer, children)
+		return step.asJSONFeed(builder, buffer, streams, attachments)
+	}
+
+	// Special case for Podcasts (RSS feeds with audio)
+	if (mimeType == model.MimeTypeRSS) && convert.IsPodcast(attachments) {
+		return step.asPodcast(builder, buffer, streams, attachments)
 	}
 
 	// Initialize the result RSS feed
@@ -49,7 +56,7 @@ func (step StepViewFeed) Get(builder Builder, buffer io.Writer) PipelineBehavior
 		Created:     time.Now(),
 	}
 
-	result.Items = slice.Map(iterator.Slice(children, model.NewStream), convert.StreamToGorillaFeed)
+	result.Items = slice.Map(streams, convert.StreamToGorillaFeed)
 
 	// Now write the feed into the requested format
 	{
@@ -66,6 +73,11 @@ func (step StepViewFeed) Get(builder Builder, buffer io.Writer) PipelineBehavior
 		case model.MimeTypeRSS:
 			mimeType = "application/rss+xml; charset=UTF=8"
 			xml, err = result.ToRss()
+
+			// Advertise RSSCloud notifications, which gorilla/feeds does not support
+			if cloud, ok := convert.NewRSSCloud(builder.Permalink() + "/rsscloud"); ok {
+				xml = convert.InsertRSSCloud(xml, cloud)
+			}
 		}
 
 		if err != nil {
@@ -105,7 +117,7 @@ func (step StepViewFeed) detectMimeType(builder Builder) string {
 	return model.MimeTypeJSONFeed
 }
 
-func (step StepViewFeed) asJSONFeed(builder Builder, buffer io.Writer, children data.Iterator) PipelineBehavior {
+func (step StepViewFeed) asJSONFeed(builder Builder, buffer io.Writer, streams []model.Stream, attachments map[string][]model.Attachment) PipelineBehavior {
 
 	feed := jsonfeed.Feed{
 		Version:     "https://jsonfeed.org/version/1.1",
@@ -121,7 +133,12 @@ func (step StepViewFeed) asJSONFeed(builder Builder, buffer io.Writer, children
 		},
 	}
 
-	feed.Items = slice.Map(iterator.Slice(children, model.NewStream), convert.StreamToJsonFeed)
+	feed.Items = slice.Map(streams, convert.StreamToJsonFeed)
+
+	// Add podcast (and other media) attachments
+	for index, stream := range streams {
+		feed.Items[index].Attachments = convert.AttachmentsToJsonFeed(attachments[stream.StreamID.Hex()])
+	}
 
 	builder.response().Header().Add("Content-Type", model.MimeTypeJSONFeed)
 
@@ -137,3 +154,62 @@ func (step StepViewFeed) asJSONFeed(builder Builder, buffer io.Writer, children
 	// Set ContentType
 	return Halt().WithContentType(model.MimeTypeJSONFeed)
 }
+
+// asPodcast writes an RSS feed with iTunes extensions
+func (step StepViewFeed) asPodcast(builder Builder, buffer io.Writer, streams []model.Stream, attachments map[string][]model.Attachment) PipelineBehavior {
+
+	const location = "build.StepViewFeed.asPodcast"
+
+	// Use the show's author and cover art, if available
+	author, imageURL := "", ""
+
+	if streamBuilder, ok := builder.(*Stream); ok {
+		author = streamBuilder.Author().Name
+		imageURL = streamBuilder.IconURL()
+	}
+
+	feed := convert.NewPodcastFeed(builder.PageTitle(), builder.Permalink(), builder.Summary(), author, imageURL)
+
+	if cloud, ok := convert.NewRSSCloud(builder.Permalink() + "/rsscloud"); ok {
+		feed.Channel.Cloud = &cloud
+	}
+
+	for _, stream := range streams {
+		feed.Channel.Items = append(feed.Channel.Items, convert.StreamToPodcastItem(stream, attachments[stream.StreamID.Hex()]))
+	}
+
+	// nolint:errcheck
+	buffer.Write([]byte(xml.Header))
+
+	encoder := xml.NewEncoder(buffer)
+	encoder.Indent("", "\t")
+
+	if err := encoder.Encode(feed); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Error generating podcast feed"))
+	}
+
+	return Halt().WithContentType("application/rss+xml; charset=UTF-8")
+}
+
+// attachments loads the Attachments for each of the provided Streams, indexed by StreamID
+func (step StepViewFeed) attachments(builder Builder, streams []model.Stream) map[string][]model.Attachment {
+
+	const location = "build.StepViewFeed.attachments"
+
+	attachmentService := builder.factory().Attachment()
+	result := make(map[string][]model.Attachment, len(streams))
+
+	for _, stream := range streams {
+
+		attachments, err := attachmentService.QueryByObjectID(model.AttachmentObjectTypeStream, stream.StreamID)
+
+		if err != nil {
+			derp.Report(derp.Wrap(err, location, "Error loading attachments", stream.StreamID))
+			continue
+		}
+
+		result[stream.StreamID.Hex()] = attachments
+	}
+
+	return result
+}
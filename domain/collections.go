@@ -3,12 +3,18 @@ package domain
 // CollectionAttachment is the name of the database collection where Attachments are stored
 const CollectionAttachment = "Attachment"
 
+// CollectionBlocklist is the name of the database collection where Blocklist subscriptions are stored
+const CollectionBlocklist = "Blocklist"
+
 // CollectionConnection is the name of the database collection where Connection records are stored
 const CollectionConnection = "Connection"
 
 // CollectionGroup is the name of the database collection where the singleton Domain record is stored
 const CollectionDomain = "Domain"
 
+// CollectionDomainPolicy is the name of the database collection where DomainPolicy records are stored
+const CollectionDomainPolicy = "DomainPolicy"
+
 // CollectionEncryptionKey is the name of the database collection where EncryptionKey records are stored
 const CollectionEncryptionKey = "EncryptionKey"
 
@@ -21,6 +27,9 @@ const CollectionFollower = "Follower"
 // CollectionFollowing is the name of the database collection where Following records are stored
 const CollectionFollowing = "Following"
 
+// CollectionFollowedTag is the name of the database collection where FollowedTag records are stored
+const CollectionFollowedTag = "FollowedTag"
+
 // CollectionGroup is the name of the database collection where Group records are stored
 const CollectionGroup = "Group"
 
@@ -33,6 +42,21 @@ const CollectionJWT = "JWT"
 // CollectionMention is the name of the database collection where Mention records are stored
 const CollectionMention = "Mention"
 
+// CollectionRelay is the name of the database collection where ActivityPub Relay subscriptions are stored
+const CollectionRelay = "Relay"
+
+// CollectionRelayMessage is the name of the database collection where messages forwarded by Relays are stored
+const CollectionRelayMessage = "RelayMessage"
+
+// CollectionReport is the name of the database collection where moderation Report records are stored
+const CollectionReport = "Report"
+
+// CollectionReview is the name of the database collection where editorial workflow Review records are stored
+const CollectionReview = "Review"
+
+// CollectionReviewComment is the name of the database collection where inline editorial ReviewComment records are stored
+const CollectionReviewComment = "ReviewComment"
+
 // CollectionRule is the name of the database collection where Rule records are stored
 const CollectionRule = "Rule"
 
@@ -60,5 +84,8 @@ const CollectionResponse = "Response"
 // CollectionTemplate is the name of the database collection where Templates are stored
 const CollectionTemplate = "Template"
 
+// CollectionTrend is the name of the database collection where trending hashtags, links, and statuses are stored
+const CollectionTrend = "Trend"
+
 // CollectionUser is the name of the database collection where Users are stored
 const CollectionUser = "User"
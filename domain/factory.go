@@ -41,6 +41,7 @@ type Factory struct {
 	providerService     *service.Provider
 	taskQueue           queue.Queue
 	activityService     *service.ActivityStream
+	requestSigner       *service.RequestSigner
 	httpCache           *httpcache.HTTPCache
 
 	// Upload Directories (from server)
@@ -48,27 +49,40 @@ type Factory struct {
 	attachmentCache     afero.Fs
 
 	// services (within this domain/factory)
-	attachmentService    service.Attachment
-	connectionService    service.Connection
-	domainService        service.Domain
-	emailService         service.DomainEmail
-	encryptionKeyService service.EncryptionKey
-	folderService        service.Folder
-	followerService      service.Follower
-	followingService     service.Following
-	groupService         service.Group
-	inboxService         service.Inbox
-	jwtService           service.JWT
-	mentionService       service.Mention
-	oauthClient          service.OAuthClient
-	oauthUserToken       service.OAuthUserToken
-	outboxService        service.Outbox
-	responseService      service.Response
-	ruleService          service.Rule
-	streamService        service.Stream
-	streamDraftService   service.StreamDraft
-	realtimeBroker       RealtimeBroker
-	userService          service.User
+	activityStreamService service.ActivityStream
+	attachmentService     service.Attachment
+	authorizedFetch       service.AuthorizedFetch
+	blocklistService      service.Blocklist
+	connectionService     service.Connection
+	domainService         service.Domain
+	domainPolicyService   service.DomainPolicy
+	emailService          service.DomainEmail
+	encryptionKeyService  service.EncryptionKey
+	folderService         service.Folder
+	followedTagService    service.FollowedTag
+	followerService       service.Follower
+	followingService      service.Following
+	groupService          service.Group
+	inboxService          service.Inbox
+	instanceActorService  service.InstanceActor
+	jwtService            service.JWT
+	mentionService        service.Mention
+	oauthClient           service.OAuthClient
+	oauthUserToken        service.OAuthUserToken
+	outboxService         service.Outbox
+	relayService          service.Relay
+	relayMessageService   service.RelayMessage
+	responseService       service.Response
+	reportService         service.Report
+	reviewService         service.Review
+	reviewCommentService  service.ReviewComment
+	ruleService           service.Rule
+	storageService        service.Storage
+	streamService         service.Stream
+	streamDraftService    service.StreamDraft
+	realtimeBroker        RealtimeBroker
+	trendService          service.Trend
+	userService           service.User
 
 	// real-time watchers
 	streamUpdateChannel chan model.Stream
@@ -77,7 +91,7 @@ type Factory struct {
 }
 
 // NewFactory creates a new factory tied to a MongoDB database
-func NewFactory(domain config.Domain, port string, providers []config.Provider, activityService *service.ActivityStream, registrationService *service.Registration, serverEmail *service.ServerEmail, themeService *service.Theme, templateService *service.Template, widgetService *service.Widget, contentService *service.Content, providerService *service.Provider, taskQueue queue.Queue, attachmentOriginals afero.Fs, attachmentCache afero.Fs, httpCache *httpcache.HTTPCache) (*Factory, error) {
+func NewFactory(domain config.Domain, port string, providers []config.Provider, activityService *service.ActivityStream, requestSigner *service.RequestSigner, registrationService *service.Registration, serverEmail *service.ServerEmail, themeService *service.Theme, templateService *service.Template, widgetService *service.Widget, contentService *service.Content, providerService *service.Provider, taskQueue queue.Queue, attachmentOriginals afero.Fs, attachmentCache afero.Fs, httpCache *httpcache.HTTPCache) (*Factory, error) {
 
 	log.Info().Msg("Starting domain: " + domain.Hostname)
 
@@ -91,6 +105,7 @@ func NewFactory(domain config.Domain, port string, providers []config.Provider,
 		providerService:     providerService,
 		taskQueue:           taskQueue,
 		activityService:     activityService,
+		requestSigner:       requestSigner,
 
 		httpCache:           httpCache,
 		attachmentOriginals: attachmentOriginals,
@@ -112,24 +127,36 @@ func NewFactory(domain config.Domain, port string, providers []config.Provider,
 
 	// Create empty service pointers.  These will be populated in the Refresh() step.
 	factory.attachmentService = service.NewAttachment()
+	factory.authorizedFetch = service.NewAuthorizedFetch()
+	factory.blocklistService = service.NewBlocklist()
 	factory.connectionService = service.NewConnection()
 	factory.domainService = service.NewDomain()
+	factory.domainPolicyService = service.NewDomainPolicy()
 	factory.emailService = service.NewDomainEmail(serverEmail)
 	factory.encryptionKeyService = service.NewEncryptionKey()
 	factory.folderService = service.NewFolder()
+	factory.followedTagService = service.NewFollowedTag()
 	factory.followerService = service.NewFollower()
 	factory.followingService = service.NewFollowing()
 	factory.groupService = service.NewGroup()
 	factory.inboxService = service.NewInbox()
+	factory.instanceActorService = service.NewInstanceActor()
 	factory.jwtService = service.NewJWT()
 	factory.mentionService = service.NewMention()
 	factory.oauthClient = service.NewOAuthClient()
 	factory.oauthUserToken = service.NewOAuthUserToken()
 	factory.outboxService = service.NewOutbox()
+	factory.relayService = service.NewRelay()
+	factory.relayMessageService = service.NewRelayMessage()
 	factory.responseService = service.NewResponse()
+	factory.reportService = service.NewReport()
+	factory.reviewService = service.NewReview()
+	factory.reviewCommentService = service.NewReviewComment()
 	factory.ruleService = service.NewRule()
+	factory.storageService = service.NewStorage()
 	factory.streamService = service.NewStream()
 	factory.streamDraftService = service.NewStreamDraft()
+	factory.trendService = service.NewTrend()
 	factory.userService = service.NewUser()
 
 	// Refresh the configuration with values that (may) change during the lifetime of the factory
@@ -139,6 +166,11 @@ func NewFactory(domain config.Domain, port string, providers []config.Provider,
 
 	// Start() is okay here because it will check for nil configuration before polling.
 	go factory.followingService.Start()
+	go factory.followingService.StartPurge()
+	go factory.storageService.Start()
+	go factory.blocklistService.Start()
+	go factory.relayService.Start()
+	go factory.trendService.Start()
 
 	// Success!
 	return &factory, nil
@@ -150,6 +182,9 @@ func (factory *Factory) Refresh(domain config.Domain, providers []config.Provide
 	factory.attachmentOriginals = attachmentOriginals
 	factory.attachmentCache = attachmentCache
 
+	// Share the server-wide ActivityStream cache, but sign outbound requests as this Domain
+	factory.activityStreamService = factory.activityService.ForDomain(domain.Hostname)
+
 	// If the database connect string has changed, then update the database connection
 	if (factory.config.ConnectString != domain.ConnectString) || (factory.config.DatabaseName != domain.DatabaseName) {
 
@@ -187,9 +222,26 @@ func (factory *Factory) Refresh(domain config.Domain, providers []config.Provide
 		factory.attachmentService.Refresh(
 			factory.collection(CollectionAttachment),
 			factory.MediaServer(),
+			factory.AttachmentOriginals(),
+			factory.AttachmentCache(),
+			factory.Queue(),
 			factory.Host(),
 		)
 
+		// Populate AuthorizedFetch Service
+		factory.authorizedFetch.Refresh(
+			factory.Domain(),
+			factory.DomainPolicy(),
+			factory.Rule(),
+			factory.ActivityStream(),
+		)
+
+		// Populate Blocklist Service
+		factory.blocklistService.Refresh(
+			factory.collection(CollectionBlocklist),
+			factory.Rule(),
+		)
+
 		factory.connectionService.Refresh(
 			factory.collection(CollectionConnection),
 		)
@@ -207,6 +259,11 @@ func (factory *Factory) Refresh(domain config.Domain, providers []config.Provide
 			factory.Hostname(),
 		)
 
+		// Populate DomainPolicy Service
+		factory.domainPolicyService.Refresh(
+			factory.collection(CollectionDomainPolicy),
+		)
+
 		// Populate EncryptionKey Service
 		factory.encryptionKeyService.Refresh(
 			factory.collection(CollectionEncryptionKey),
@@ -219,6 +276,22 @@ func (factory *Factory) Refresh(domain config.Domain, providers []config.Provide
 			factory.Theme(),
 			factory.Domain(),
 			factory.Inbox(),
+			factory.User(),
+			factory.Email(),
+			factory.Queue(),
+		)
+
+		// Populate FollowedTag Service
+		factory.followedTagService.Refresh(
+			factory.collection(CollectionFollowedTag),
+			factory.Folder(),
+			factory.Inbox(),
+			factory.ActivityStream(),
+			factory.DomainPolicy(),
+			factory.RelayMessage(),
+			factory.Rule(),
+			factory.Queue(),
+			factory.Host(),
 		)
 
 		// Populate Follower Service
@@ -240,8 +313,11 @@ func (factory *Factory) Refresh(domain config.Domain, providers []config.Provide
 			factory.User(),
 			factory.Inbox(),
 			factory.Folder(),
+			factory.FollowedTag(),
+			factory.DomainPolicy(),
 			factory.EncryptionKey(),
 			factory.ActivityStream(),
+			factory.Queue(),
 			factory.Host(),
 		)
 
@@ -255,9 +331,20 @@ func (factory *Factory) Refresh(domain config.Domain, providers []config.Provide
 			factory.collection(CollectionInbox),
 			factory.Rule(),
 			factory.Folder(),
+			factory.Response(),
 			factory.Host(),
 		)
 
+		// Populate InstanceActor Service
+		factory.instanceActorService.Refresh(
+			factory.Domain(),
+			factory.EncryptionKey(),
+			factory.DomainPolicy(),
+			factory.requestSigner,
+			factory.Host(),
+			factory.Hostname(),
+		)
+
 		// Populate the JWT Key Service
 		factory.jwtService.Refresh(
 			factory.collection(CollectionJWT),
@@ -293,6 +380,7 @@ func (factory *Factory) Refresh(domain config.Domain, providers []config.Provide
 			factory.Stream(),
 			factory.ActivityStream(),
 			factory.Follower(),
+			factory.Relay(),
 			factory.Template(),
 			factory.User(),
 			factory.Email(),
@@ -305,6 +393,21 @@ func (factory *Factory) Refresh(domain config.Domain, providers []config.Provide
 			factory.Queue(),
 		)
 
+		// Populate Relay Service
+		factory.relayService.Refresh(
+			factory.collection(CollectionRelay),
+			factory.RelayMessage(),
+			factory.InstanceActor(),
+			factory.DomainPolicy(),
+			factory.Rule(),
+			factory.FollowedTag(),
+		)
+
+		// Populate RelayMessage Service
+		factory.relayMessageService.Refresh(
+			factory.collection(CollectionRelayMessage),
+		)
+
 		// Populate the Response Service
 		factory.responseService.Refresh(
 			factory.collection(CollectionResponse),
@@ -313,6 +416,30 @@ func (factory *Factory) Refresh(domain config.Domain, providers []config.Provide
 			factory.Host(),
 		)
 
+		// Populate the Report Service
+		factory.reportService.Refresh(
+			factory.collection(CollectionReport),
+			factory.ActivityStream(),
+			factory.InstanceActor(),
+			factory.Stream(),
+			factory.User(),
+			factory.Host(),
+		)
+
+		// Populate the Review Service
+		factory.reviewService.Refresh(
+			factory.collection(CollectionReview),
+			factory.Stream(),
+			factory.User(),
+			factory.Email(),
+			factory.Host(),
+		)
+
+		// Populate the ReviewComment Service
+		factory.reviewCommentService.Refresh(
+			factory.collection(CollectionReviewComment),
+		)
+
 		// Populate the Rule Service
 		factory.ruleService.Refresh(
 			factory.collection(CollectionRule),
@@ -333,12 +460,27 @@ func (factory *Factory) Refresh(domain config.Domain, providers []config.Provide
 			factory.Content(),
 			factory.EncryptionKey(),
 			factory.Follower(),
+			factory.Following(),
 			factory.Rule(),
+			factory.DomainPolicy(),
 			factory.User(),
+			factory.Review(),
+			factory.ReviewComment(),
 			factory.Host(),
 			factory.StreamUpdateChannel(),
 		)
 
+		// Populate Storage Service
+		factory.storageService.Refresh(
+			factory.Attachment(),
+			factory.Domain(),
+			factory.Group(),
+			factory.Stream(),
+			factory.User(),
+			factory.AttachmentOriginals(),
+			factory.AttachmentCache(),
+		)
+
 		// Populate StreamDraft Service
 		factory.streamDraftService.Refresh(
 			factory.collection(CollectionStreamDraft),
@@ -346,14 +488,27 @@ func (factory *Factory) Refresh(domain config.Domain, providers []config.Provide
 			factory.Stream(),
 		)
 
+		// Populate Trend Service
+		factory.trendService.Refresh(
+			factory.collection(CollectionTrend),
+			factory.Inbox(),
+			factory.Response(),
+			factory.Stream(),
+			factory.Review(),
+			factory.ActivityStream(),
+			factory.Host(),
+		)
+
 		// Populate User Service
 		factory.userService.Refresh(
 			factory.collection(CollectionUser),
 			factory.collection(CollectionFollower),
 			factory.collection(CollectionFollowing),
 			factory.collection(CollectionRule),
+			factory.ActivityStream(),
 			factory.Attachment(),
 			factory.Domain(),
+			factory.DomainPolicy(),
 			factory.Email(),
 			factory.Folder(),
 			factory.Follower(),
@@ -399,6 +554,11 @@ func (factory *Factory) Close() {
 	factory.realtimeBroker.Close()
 	factory.streamService.Close()
 	factory.followingService.Close()
+	factory.storageService.Close()
+	factory.blocklistService.Close()
+	factory.relayService.Close()
+	factory.trendService.Close()
+	factory.instanceActorService.Close()
 	factory.followerService.Close()
 	factory.jwtService.Close()
 	factory.userService.Close()
@@ -468,7 +628,7 @@ func (factory *Factory) Model(name string) (service.ModelService, error) {
 }
 
 func (factory *Factory) ActivityStream() *service.ActivityStream {
-	return factory.activityService
+	return &factory.activityStreamService
 }
 
 // Attachment returns a fully populated Attachment service
@@ -476,6 +636,11 @@ func (factory *Factory) Attachment() *service.Attachment {
 	return &factory.attachmentService
 }
 
+// AuthorizedFetch returns a fully populated AuthorizedFetch service
+func (factory *Factory) AuthorizedFetch() *service.AuthorizedFetch {
+	return &factory.authorizedFetch
+}
+
 // Rule returns a fully populated Rule service
 func (factory *Factory) Rule() *service.Rule {
 	return &factory.ruleService
@@ -486,6 +651,16 @@ func (factory *Factory) Domain() *service.Domain {
 	return &factory.domainService
 }
 
+// DomainPolicy returns a fully populated DomainPolicy service
+func (factory *Factory) DomainPolicy() *service.DomainPolicy {
+	return &factory.domainPolicyService
+}
+
+// Blocklist returns a fully populated Blocklist service
+func (factory *Factory) Blocklist() *service.Blocklist {
+	return &factory.blocklistService
+}
+
 // Connection returns a fully populated Connection service
 func (factory *Factory) Connection() *service.Connection {
 	return &factory.connectionService
@@ -496,6 +671,11 @@ func (factory *Factory) EncryptionKey() *service.EncryptionKey {
 	return &factory.encryptionKeyService
 }
 
+// FollowedTag returns a fully populated FollowedTag service
+func (factory *Factory) FollowedTag() *service.FollowedTag {
+	return &factory.followedTagService
+}
+
 // Follower returns a fully populated Follower service
 func (factory *Factory) Follower() *service.Follower {
 	return &factory.followerService
@@ -521,6 +701,11 @@ func (factory *Factory) Inbox() *service.Inbox {
 	return &factory.inboxService
 }
 
+// InstanceActor returns a fully populated InstanceActor service
+func (factory *Factory) InstanceActor() *service.InstanceActor {
+	return &factory.instanceActorService
+}
+
 // Mention returns a fully populated Mention service
 func (factory *Factory) Mention() *service.Mention {
 	return &factory.mentionService
@@ -541,6 +726,11 @@ func (factory *Factory) Outbox() *service.Outbox {
 	return &factory.outboxService
 }
 
+// Storage returns a fully populated Storage service
+func (factory *Factory) Storage() *service.Storage {
+	return &factory.storageService
+}
+
 // Stream returns a fully populated Stream service
 func (factory *Factory) Stream() *service.Stream {
 	return &factory.streamService
@@ -551,11 +741,41 @@ func (factory *Factory) StreamDraft() *service.StreamDraft {
 	return &factory.streamDraftService
 }
 
+// Trend returns a fully populated Trend service
+func (factory *Factory) Trend() *service.Trend {
+	return &factory.trendService
+}
+
+// Relay returns a fully populated Relay service
+func (factory *Factory) Relay() *service.Relay {
+	return &factory.relayService
+}
+
+// RelayMessage returns a fully populated RelayMessage service
+func (factory *Factory) RelayMessage() *service.RelayMessage {
+	return &factory.relayMessageService
+}
+
 // Response returns a fully populated Response service
 func (factory *Factory) Response() *service.Response {
 	return &factory.responseService
 }
 
+// Report returns a fully populated Report service
+func (factory *Factory) Report() *service.Report {
+	return &factory.reportService
+}
+
+// Review returns a fully populated Review service
+func (factory *Factory) Review() *service.Review {
+	return &factory.reviewService
+}
+
+// ReviewComment returns a fully populated ReviewComment service
+func (factory *Factory) ReviewComment() *service.ReviewComment {
+	return &factory.reviewCommentService
+}
+
 // User returns a fully populated User service
 func (factory *Factory) User() *service.User {
 	return &factory.userService
@@ -687,7 +907,7 @@ func (factory *Factory) Steranko() *steranko.Steranko {
 
 // LookupProvider returns a fully populated LookupProvider service
 func (factory *Factory) LookupProvider(userID primitive.ObjectID) form.LookupProvider {
-	return service.NewLookupProvider(factory.Folder(), factory.Group(), factory.Registration(), factory.Template(), factory.Theme(), userID)
+	return service.NewLookupProvider(factory.Folder(), factory.Group(), factory.Registration(), factory.Template(), factory.Theme(), factory.User(), userID)
 }
 
 /******************************************
@@ -728,9 +948,24 @@ func (factory *Factory) ModelService(object data.Object) service.ModelService {
 	case *model.Rule:
 		return factory.Rule()
 
+	case *model.Blocklist:
+		return factory.Blocklist()
+
+	case *model.Relay:
+		return factory.Relay()
+
+	case *model.Trend:
+		return factory.Trend()
+
+	case *model.DomainPolicy:
+		return factory.DomainPolicy()
+
 	case *model.Folder:
 		return factory.Folder()
 
+	case *model.FollowedTag:
+		return factory.FollowedTag()
+
 	case *model.Follower:
 		return factory.Follower()
 
@@ -743,6 +978,15 @@ func (factory *Factory) ModelService(object data.Object) service.ModelService {
 	case *model.Response:
 		return factory.Response()
 
+	case *model.Report:
+		return factory.Report()
+
+	case *model.Review:
+		return factory.Review()
+
+	case *model.ReviewComment:
+		return factory.ReviewComment()
+
 	case *model.Stream:
 		return factory.Stream()
 
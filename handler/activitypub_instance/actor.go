@@ -0,0 +1,40 @@
+package activitypub_instance
+
+import (
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/labstack/echo/v4"
+)
+
+// GetJSONLD returns the ActivityPub profile of the instance actor
+func GetJSONLD(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "activitypub_instance.GetJSONLD"
+
+	return func(ctx echo.Context) error {
+
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Unrecognized domain name")
+		}
+
+		// RULE: The instance actor can be retrieved without a signature (so that remote
+		// servers can verify our keys) but signed requests from blocked actors are still rejected
+		if err := factory.AuthorizedFetch().Allow(ctx.Request()); err != nil {
+			return derp.Wrap(err, location, "Request is not authorized")
+		}
+
+		result, err := factory.InstanceActor().JSONLD()
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error building instance actor")
+		}
+
+		ctx.Response().Header().Set(vocab.ContentType, vocab.ContentTypeActivityPub)
+		return ctx.JSON(http.StatusOK, result)
+	}
+}
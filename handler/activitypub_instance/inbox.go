@@ -0,0 +1,70 @@
+package activitypub_instance
+
+import (
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/inbox"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// PostInbox receives ActivityPub activities that are addressed to the instance actor
+func PostInbox(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "activitypub_instance.PostInbox"
+
+	return func(ctx echo.Context) error {
+
+		// Find the factory for this hostname
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Invalid Domain")
+		}
+
+		// RULE: Reject requests from suspended domains before verifying signatures
+		domainPolicyService := factory.DomainPolicy()
+
+		if err := domainPolicyService.AllowRequest(ctx.Request()); err != nil {
+			return derp.Wrap(err, location, "Request Not Accepted")
+		}
+
+		// Retrieve the activity from the request body (this also validates the HTTP signature)
+		activity, err := inbox.ReceiveRequest(ctx.Request(), factory.ActivityStream())
+		signer := activity.Actor().ID()
+
+		if err != nil {
+
+			// Relays forward activities from other actors, signed with the relay's own key
+			activity, signer, err = receiveForwardedRequest(factory, ctx.Request())
+
+			if err != nil {
+				return derp.Wrap(err, location, "Error parsing ActivityPub request")
+			}
+		}
+
+		// RULE: Reject activities whose actor or object is on a suspended domain
+		if err := domainPolicyService.AllowActivity(activity); err != nil {
+			return derp.Wrap(err, location, "Activity Not Accepted")
+		}
+
+		log.Info().Str("host", factory.Host()).Str("activity", activity.ID()).Msg("Instance Inbox: Received new activity")
+
+		// Create a new Context.  Relays forward activities from other actors, so
+		// the signer of the request identifies which relay delivered the activity
+		context := Context{
+			factory:  factory,
+			signerID: signer,
+		}
+
+		// Handle the ActivityPub request
+		if err := instanceRouter.Handle(context, activity); err != nil {
+			return derp.Wrap(err, location, "Error handling ActivityPub request")
+		}
+
+		// Send the response to the client
+		return ctx.String(http.StatusOK, "")
+	}
+}
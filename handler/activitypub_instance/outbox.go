@@ -0,0 +1,33 @@
+package activitypub_instance
+
+import (
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/labstack/echo/v4"
+)
+
+// GetOutboxCollection returns the (always empty) outbox of the instance actor
+func GetOutboxCollection(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "activitypub_instance.GetOutboxCollection"
+
+	return func(ctx echo.Context) error {
+
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Unrecognized domain name")
+		}
+
+		// The instance actor does not publish anything, so this is always empty
+		result := streams.NewOrderedCollection()
+		result.ID = factory.InstanceActor().OutboxURL()
+
+		ctx.Response().Header().Set(vocab.ContentType, vocab.ContentTypeActivityPub)
+		return ctx.JSON(http.StatusOK, result)
+	}
+}
@@ -0,0 +1,45 @@
+package activitypub_instance
+
+import (
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+)
+
+func init() {
+	instanceRouter.Add(vocab.ActivityTypeAccept, vocab.Any, receive_Accept)
+}
+
+// receive_Accept handles "Accept" activities, which are sent by relays that
+// have accepted the instance actor's subscription (Follow) request.
+func receive_Accept(context Context, activity streams.Document) error {
+
+	const location = "handler.activitypub_instance.receive_Accept"
+
+	relayService := context.factory.Relay()
+
+	// The Object.ID of the activity should be our original "Follow" activity
+	relay := model.NewRelay()
+	if err := relayService.LoadByFollowID(activity.Object().ID(), &relay); err != nil {
+		return derp.Wrap(err, location, "Error loading relay", activity.Object().ID())
+	}
+
+	// RULE: Accepts must be signed by the relay itself (not forwarded by another relay)
+	if err := signedByActor(context, activity); err != nil {
+		return derp.Wrap(err, location, "Invalid Accept")
+	}
+
+	// RULE: Validate that the Relay matches the Accept
+	actorID := context.signerID
+
+	if (relay.ActorID != "") && (relay.ActorID != actorID) {
+		return derp.NewForbiddenError(location, "Invalid Accept", relay.ActorID, actorID)
+	}
+
+	if err := relayService.Accept(&relay, actorID); err != nil {
+		return derp.Wrap(err, location, "Error accepting relay subscription", relay.RelayID)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package activitypub_instance
+
+import (
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+)
+
+func init() {
+	instanceRouter.Add(vocab.ActivityTypeAnnounce, vocab.Any, receive_Announce)
+}
+
+// receive_Announce handles "Announce" activities, which LitePub relays use
+// to forward public posts into this Domain's public timeline.
+func receive_Announce(context Context, activity streams.Document) error {
+
+	const location = "handler.activitypub_instance.receive_Announce"
+
+	// RULE: Announces must be signed by the relay itself (not forwarded by another relay)
+	if err := signedByActor(context, activity); err != nil {
+		return derp.Wrap(err, location, "Invalid Announce")
+	}
+
+	relay, err := loadRelay(context.factory, context.signerID)
+
+	if err != nil {
+		if derp.NotFound(err) {
+			return IgnoreAny(context, activity)
+		}
+		return derp.Wrap(err, location, "Error loading relay", context.signerID)
+	}
+
+	// Relays usually announce the ID of the original document, so load the whole thing
+	document, err := activity.Object().Load()
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading announced document", activity.Object().ID())
+	}
+
+	// Skip forwarded activities (like Deletes and Updates) that are not documents
+	if vocab.ValidateActivityType(document.Type()) != vocab.Unknown {
+		return IgnoreAny(context, activity)
+	}
+
+	if err := context.factory.Relay().Receive(&relay, document); err != nil {
+		return derp.Wrap(err, location, "Error receiving relay message", document.ID())
+	}
+
+	return nil
+}
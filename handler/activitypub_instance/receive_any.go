@@ -0,0 +1,17 @@
+package activitypub_instance
+
+import (
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/rs/zerolog/log"
+)
+
+func init() {
+	instanceRouter.Add(vocab.Any, vocab.Any, IgnoreAny)
+}
+
+// IgnoreAny accepts (and discards) activities that the instance actor does not handle
+func IgnoreAny(context Context, activity streams.Document) error {
+	log.Debug().Str("activity", activity.ID()).Str("type", activity.Type()).Msg("Instance Inbox: Ignoring activity")
+	return nil
+}
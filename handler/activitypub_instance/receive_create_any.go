@@ -0,0 +1,55 @@
+package activitypub_instance
+
+import (
+	"github.com/EmissarySocial/emissary/tools/ascache"
+	"github.com/benpate/derp"
+	"github.com/benpate/domain"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+)
+
+func init() {
+	instanceRouter.Add(vocab.ActivityTypeCreate, vocab.Any, receive_Create)
+}
+
+// receive_Create handles "Create" activities, which Mastodon-style relays forward
+// as-is (signed by the relay) into this Domain's public timeline.
+func receive_Create(context Context, activity streams.Document) error {
+
+	const location = "handler.activitypub_instance.receive_Create"
+
+	relay, err := loadRelay(context.factory, context.signerID)
+
+	if err != nil {
+		if derp.NotFound(err) {
+			return IgnoreAny(context, activity)
+		}
+		return derp.Wrap(err, location, "Error loading relay", context.signerID)
+	}
+
+	// RULE: Forwarded activities are signed by the relay, not by their author, so
+	// the document is reloaded from its origin instead of trusting the request body.
+	document, err := context.factory.ActivityStream().Load(activity.Object().ID(), ascache.WithForceReload())
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading forwarded document", activity.Object().ID())
+	}
+
+	// RULE: The forwarded document must be written by the actor who created it
+	authorID := document.AttributedTo().ID()
+
+	if authorID != activity.Actor().ID() {
+		return derp.NewForbiddenError(location, "Document is not attributed to the actor", document.ID(), activity.Actor().ID())
+	}
+
+	// RULE: The document must be hosted on its author's server
+	if domain.NameOnly(document.ID()) != domain.NameOnly(authorID) {
+		return derp.NewForbiddenError(location, "Document is not hosted by its author", document.ID(), authorID)
+	}
+
+	if err := context.factory.Relay().Receive(&relay, document); err != nil {
+		return derp.Wrap(err, location, "Error receiving relay message", document.ID())
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package activitypub_instance
+
+import (
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+)
+
+func init() {
+	instanceRouter.Add(vocab.ActivityTypeDelete, vocab.Any, receive_Delete)
+}
+
+// receive_Delete handles "Delete" activities that relays forward, and removes
+// the deleted document from this Domain's public timeline.
+func receive_Delete(context Context, activity streams.Document) error {
+
+	const location = "handler.activitypub_instance.receive_Delete"
+
+	relayMessageService := context.factory.RelayMessage()
+
+	message := model.NewRelayMessage()
+	if err := relayMessageService.LoadByURL(activity.Object().ID(), &message); err != nil {
+		if derp.NotFound(err) {
+			return IgnoreAny(context, activity)
+		}
+		return derp.Wrap(err, location, "Error loading relay message", activity.Object().ID())
+	}
+
+	// RULE: Only the author can delete a document
+	if message.AttributedTo.ProfileURL != activity.Actor().ID() {
+		return derp.NewForbiddenError(location, "Only the author can delete a document", message.URL, activity.Actor().ID())
+	}
+
+	// RULE: Forwarded Deletes are signed by a relay, not by the author, so
+	// confirm that the document is actually gone from its origin.
+	if signedByActor(context, activity) != nil {
+
+		deleted, err := isDeletedAtOrigin(context.factory, message.URL)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error confirming forwarded Delete", message.URL)
+		}
+
+		if !deleted {
+			return derp.NewForbiddenError(location, "Document still exists at its origin", message.URL)
+		}
+	}
+
+	if err := relayMessageService.Delete(&message); err != nil {
+		return derp.Wrap(err, location, "Error deleting relay message", message.URL)
+	}
+
+	return nil
+}
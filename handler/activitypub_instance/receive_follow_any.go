@@ -0,0 +1,44 @@
+package activitypub_instance
+
+import (
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func init() {
+	instanceRouter.Add(vocab.ActivityTypeFollow, vocab.Any, receive_Follow)
+}
+
+// receive_Follow handles "Follow" activities.  LitePub relays follow the instance actor
+// back after accepting a subscription, so that the instance actor can publish to them.
+// Follows from all other actors are ignored.
+func receive_Follow(context Context, activity streams.Document) error {
+
+	const location = "handler.activitypub_instance.receive_Follow"
+
+	instanceActor := context.factory.InstanceActor()
+
+	// RULE: Only the instance actor can be followed
+	if !instanceActor.IsInstanceActor(activity.Object().ID()) {
+		return derp.NewBadRequestError(location, "Follow must be addressed to the instance actor", activity.Object().ID())
+	}
+
+	// RULE: Only subscribed relays can follow the instance actor
+	if _, err := loadRelay(context.factory, activity.Actor().ID()); err != nil {
+		if derp.NotFound(err) {
+			return IgnoreAny(context, activity)
+		}
+		return derp.Wrap(err, location, "Error loading relay", activity.Actor().ID())
+	}
+
+	actor, err := instanceActor.ActivityPubActor()
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading instance actor")
+	}
+
+	actor.SendAccept(instanceActor.ActorID()+"/accept/"+primitive.NewObjectID().Hex(), activity)
+	return nil
+}
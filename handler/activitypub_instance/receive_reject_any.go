@@ -0,0 +1,43 @@
+package activitypub_instance
+
+import (
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+)
+
+func init() {
+	instanceRouter.Add(vocab.ActivityTypeReject, vocab.Any, receive_Reject)
+}
+
+// receive_Reject handles "Reject" activities, which are sent by relays that
+// have declined the instance actor's subscription (Follow) request.
+func receive_Reject(context Context, activity streams.Document) error {
+
+	const location = "handler.activitypub_instance.receive_Reject"
+
+	relayService := context.factory.Relay()
+
+	// The Object.ID of the activity should be our original "Follow" activity
+	relay := model.NewRelay()
+	if err := relayService.LoadByFollowID(activity.Object().ID(), &relay); err != nil {
+		return derp.Wrap(err, location, "Error loading relay", activity.Object().ID())
+	}
+
+	// RULE: Rejects must be signed by the relay itself (not forwarded by another relay)
+	if err := signedByActor(context, activity); err != nil {
+		return derp.Wrap(err, location, "Invalid Reject")
+	}
+
+	// RULE: Validate that the Relay matches the Reject
+	if actorID := context.signerID; (relay.ActorID != "") && (relay.ActorID != actorID) {
+		return derp.NewForbiddenError(location, "Invalid Reject", relay.ActorID, actorID)
+	}
+
+	if err := relayService.Reject(&relay); err != nil {
+		return derp.Wrap(err, location, "Error rejecting relay subscription", relay.RelayID)
+	}
+
+	return nil
+}
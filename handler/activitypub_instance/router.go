@@ -0,0 +1,15 @@
+package activitypub_instance
+
+import (
+	"github.com/EmissarySocial/emissary/domain"
+	"github.com/benpate/hannibal/inbox"
+)
+
+// instanceRouter defines the package-level router for instance actor/ActivityPub requests
+var instanceRouter inbox.Router[Context] = inbox.NewRouter[Context]()
+
+// Context includes all of the necessary objects to handle an ActivityPub request
+type Context struct {
+	factory  *domain.Factory
+	signerID string // ID of the actor whose key signed the request
+}
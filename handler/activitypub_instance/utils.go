@@ -0,0 +1,92 @@
+package activitypub_instance
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/domain"
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/ascache"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/re"
+)
+
+// receiveForwardedRequest reads an activity that was forwarded by a Relay.  The request
+// must be signed by an active Relay, but the activity itself is written by another actor,
+// so its contents cannot be trusted until they are reloaded from their origin.  It returns
+// the activity and the ID of the Relay that signed the request.
+func receiveForwardedRequest(factory *domain.Factory, request *http.Request) (streams.Document, string, error) {
+
+	const location = "handler.activitypub_instance.receiveForwardedRequest"
+
+	// Verify the HTTP signature, and identify the actor who signed it
+	signerID, err := factory.AuthorizedFetch().VerifyPost(request)
+
+	if err != nil {
+		return streams.NilDocument(), "", derp.Wrap(err, location, "Unable to verify HTTP signature")
+	}
+
+	// RULE: Only active Relays can forward activities from other actors
+	relay, err := loadRelay(factory, signerID)
+
+	if err != nil {
+		return streams.NilDocument(), "", derp.Wrap(err, location, "Signer is not a Relay", signerID, derp.WithCode(http.StatusForbidden))
+	}
+
+	if !relay.IsActive() {
+		return streams.NilDocument(), "", derp.NewForbiddenError(location, "Relay is not active", signerID)
+	}
+
+	// Parse the forwarded activity
+	body, err := re.ReadRequestBody(request)
+
+	if err != nil {
+		return streams.NilDocument(), "", derp.Wrap(err, location, "Error reading request body")
+	}
+
+	activity := streams.NilDocument(streams.WithClient(factory.ActivityStream()))
+
+	if err := json.Unmarshal(body, &activity); err != nil {
+		return streams.NilDocument(), "", derp.Wrap(err, location, "Error parsing forwarded activity", derp.WithCode(http.StatusBadRequest))
+	}
+
+	return activity, signerID, nil
+}
+
+// loadRelay finds the Relay that is represented by the provided actor
+func loadRelay(factory *domain.Factory, actorID string) (model.Relay, error) {
+	relay := model.NewRelay()
+	err := factory.Relay().LoadByActorID(actorID, &relay)
+	return relay, err
+}
+
+// signedByActor returns an error unless the activity's actor is the same actor who
+// signed the request.  Forwarded activities are signed by a Relay, so they fail this check.
+func signedByActor(context Context, activity streams.Document) error {
+
+	if actorID := activity.Actor().ID(); actorID != context.signerID {
+		return derp.NewForbiddenError("handler.activitypub_instance.signedByActor", "Activity was not signed by its actor", actorID, context.signerID)
+	}
+
+	return nil
+}
+
+// isDeletedAtOrigin reloads a document from its origin server, and returns TRUE
+// if the document no longer exists there (or has been replaced by a Tombstone)
+func isDeletedAtOrigin(factory *domain.Factory, url string) (bool, error) {
+
+	document, err := factory.ActivityStream().Load(url, ascache.WithForceReload())
+
+	if err != nil {
+
+		if derp.NotFound(err) || (derp.ErrorCode(err) == http.StatusGone) {
+			return true, nil
+		}
+
+		return false, derp.Wrap(err, "handler.activitypub_instance.isDeletedAtOrigin", "Error loading document", url)
+	}
+
+	return document.Type() == vocab.ObjectTypeTombstone, nil
+}
@@ -27,6 +27,13 @@ func PostInbox(serverFactory *server.Factory) echo.HandlerFunc {
 			return derp.NewNotFoundError(location, "Actor not found")
 		}
 
+		// RULE: Reject requests from suspended domains before verifying signatures
+		domainPolicyService := factory.DomainPolicy()
+
+		if err := domainPolicyService.AllowRequest(ctx.Request()); err != nil {
+			return derp.Wrap(err, location, "Request Not Accepted")
+		}
+
 		// Retrieve the activity from the request body
 		activity, err := inbox.ReceiveRequest(ctx.Request(), factory.ActivityStream())
 
@@ -34,6 +41,11 @@ func PostInbox(serverFactory *server.Factory) echo.HandlerFunc {
 			return derp.Wrap(err, location, "Error parsing ActivityPub request")
 		}
 
+		// RULE: Reject activities whose actor or object is on a suspended domain
+		if err := domainPolicyService.AllowActivity(activity); err != nil {
+			return derp.Wrap(err, location, "Activity Not Accepted")
+		}
+
 		log.Info().Str("host", factory.Host()).Str("activity", activity.ID()).Msg("Stream Inbox: Received new activity")
 
 		// Create a new request context for the ActivityPub router
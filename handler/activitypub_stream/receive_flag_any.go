@@ -0,0 +1,24 @@
+package activitypub_stream
+
+import (
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+)
+
+func init() {
+	streamRouter.Add(vocab.ActivityTypeFlag, vocab.Any, receiveFlag)
+}
+
+// receiveFlag handles "Flag" activities, which are moderation reports sent by other servers.
+// Each Flag is added to the admin report queue.
+func receiveFlag(context Context, activity streams.Document) error {
+
+	const location = "handler.activitypub_stream.receiveFlag"
+
+	if err := context.factory.Report().Receive(activity); err != nil {
+		return derp.Wrap(err, location, "Error receiving report", activity.ID())
+	}
+
+	return nil
+}
@@ -1,8 +1,25 @@
 package activitypub_stream
 
 import (
+	"github.com/EmissarySocial/emissary/domain"
+	"github.com/EmissarySocial/emissary/model"
 	"github.com/benpate/hannibal/inbox"
+	"github.com/benpate/hannibal/streams"
 )
 
 // streamRouter defines the package-level router for stream/ActivityPub requests
 var streamRouter inbox.Router[Context] = inbox.NewRouter[Context]()
+
+// HandleActivity routes an activity that has already been received (and verified)
+// into the inbox of the provided Stream.  This is used by the shared inbox to deliver
+// a single activity to many local Streams.
+func HandleActivity(factory *domain.Factory, stream *model.Stream, actor *model.StreamActor, activity streams.Document) error {
+
+	context := Context{
+		factory: factory,
+		stream:  stream,
+		actor:   actor,
+	}
+
+	return streamRouter.Handle(context, activity)
+}
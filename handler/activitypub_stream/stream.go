@@ -26,12 +26,23 @@ func GetJSONLD(serverFactory *server.Factory) echo.HandlerFunc {
 
 		// If this Stream is not an Actor, then just return a standard JSON-LD response.
 		if actor.IsNil() {
+
+			// RULE: Content requires a valid signature when authorized fetch is enabled
+			if err := factory.AuthorizedFetch().Require(ctx.Request()); err != nil {
+				return derp.Wrap(err, location, "Request is not authorized")
+			}
+
 			jsonld := streamService.JSONLD(&stream)
 			ctx.Response().Header().Set("Content-Type", vocab.ContentTypeActivityPub)
 			return ctx.JSON(http.StatusOK, jsonld)
 		}
 
-		// Try to locate the domain
+		// RULE: Actors can be retrieved without a signature (so that remote servers can
+		// verify our keys) but signed requests from blocked actors are still rejected
+		if err := factory.AuthorizedFetch().Allow(ctx.Request()); err != nil {
+			return derp.Wrap(err, location, "Request is not authorized")
+		}
+
 		// Try to load the Encryption Key for this Actor
 		keyService := factory.EncryptionKey()
 		key := model.NewEncryptionKey()
@@ -15,6 +15,12 @@ func RenderProfileJSONLD(context echo.Context, factory *domain.Factory, user *mo
 
 	const location = "handler.activitypub.buildProfileJSONLD"
 
+	// RULE: Profiles can be retrieved without a signature (so that remote servers can
+	// verify our keys) but signed requests from blocked actors are still rejected
+	if err := factory.AuthorizedFetch().Allow(context.Request()); err != nil {
+		return derp.Wrap(err, location, "Request is not authorized")
+	}
+
 	// Try to load the key from the Datbase
 	keyService := factory.EncryptionKey()
 	key := model.NewEncryptionKey()
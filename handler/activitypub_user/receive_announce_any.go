@@ -0,0 +1,54 @@
+package activitypub_user
+
+import (
+	"github.com/EmissarySocial/emissary/handler/activitypub"
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+)
+
+func init() {
+	inboxRouter.Add(vocab.ActivityTypeAnnounce, vocab.Any, receive_Announce)
+}
+
+// receive_Announce handles "Announce" activities (boosts).  The announced document is added
+// to the User's inbox, with a reference to the account that boosted it.
+func receive_Announce(context Context, activity streams.Document) error {
+
+	const location = "handler.activitypub_user.receive_Announce"
+
+	// Load the announced document from the Interwebs
+	object, err := activity.Object().Load()
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading announced document", activity.Object().ID())
+	}
+
+	// RULE: Only documents can be announced, not other activities or actors
+	switch object.Type() {
+
+	case vocab.ObjectTypeRelationship,
+		vocab.ObjectTypeProfile,
+		vocab.ObjectTypeTombstone:
+		return nil
+	}
+
+	// RULE: If the Activity does not have an ID, then make a new "fake" one.
+	if activity.ID() == "" {
+		activity.SetProperty(vocab.PropertyID, activitypub.FakeActivityID(activity))
+	}
+
+	// Add the announced document and the Announce itself into the ActivityStream cache
+	activityService := context.factory.ActivityStream()
+	activityService.Put(object)
+	activityService.Put(activity)
+
+	// Add the announced document into the User's Inbox, referencing the account that boosted it
+	if err := saveMessage(context, object, activity.Actor().ID(), model.OriginTypeAnnounce); err != nil {
+		return derp.Wrap(err, location, "Error saving message", context.user.UserID, activity.Value())
+	}
+
+	// Success.
+	return nil
+}
@@ -0,0 +1,24 @@
+package activitypub_user
+
+import (
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+)
+
+func init() {
+	inboxRouter.Add(vocab.ActivityTypeFlag, vocab.Any, receive_Flag)
+}
+
+// receive_Flag handles "Flag" activities, which are moderation reports sent by other servers.
+// Each Flag is added to the admin report queue.
+func receive_Flag(context Context, activity streams.Document) error {
+
+	const location = "handler.activitypub_user.receive_Flag"
+
+	if err := context.factory.Report().Receive(activity); err != nil {
+		return derp.Wrap(err, location, "Error receiving report", activity.ID())
+	}
+
+	return nil
+}
@@ -9,15 +9,14 @@ import (
 )
 
 func init() {
-	inboxRouter.Add(vocab.ActivityTypeAnnounce, vocab.Any, receiveLikeOrAnnounce)
-	inboxRouter.Add(vocab.ActivityTypeLike, vocab.Any, receiveLikeOrAnnounce)
-	inboxRouter.Add(vocab.ActivityTypeDislike, vocab.Any, receiveLikeOrAnnounce)
+	inboxRouter.Add(vocab.ActivityTypeLike, vocab.Any, receiveLikeOrDislike)
+	inboxRouter.Add(vocab.ActivityTypeDislike, vocab.Any, receiveLikeOrDislike)
 }
 
-// receiveLikeOrAnnounce handles all Like Dislike activities
-func receiveLikeOrAnnounce(context Context, activity streams.Document) error {
+// receiveLikeOrDislike handles all Like Dislike activities
+func receiveLikeOrDislike(context Context, activity streams.Document) error {
 
-	const location = "handler.activitypub_user.receiveLikeOrAnnounce"
+	const location = "handler.activitypub_user.receiveLikeOrDislike"
 
 	// Add then Shared/Liked Object into the ActivityStream cache
 	if err := inboxRouter.Handle(context, activity.Object().LoadLink()); err != nil {
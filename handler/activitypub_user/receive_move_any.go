@@ -0,0 +1,39 @@
+package activitypub_user
+
+import (
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+)
+
+func init() {
+	inboxRouter.Add(vocab.ActivityTypeMove, vocab.Any, receive_Move)
+}
+
+// receive_Move handles ActivityPub "Move" activities, which are sent when a remote
+// actor migrates to a new account.  Following records for the old account are
+// re-pointed to the new account.
+func receive_Move(context Context, activity streams.Document) error {
+
+	const location = "handler.activitypub_user.receive_Move"
+
+	oldActorID := activity.Actor().ID()
+	newActorID := activity.Target().ID()
+
+	// RULE: Actors can only move themselves
+	if activity.Object().ID() != oldActorID {
+		return derp.NewForbiddenError(location, "Actor can only move itself", oldActorID, activity.Object().ID())
+	}
+
+	// RULE: Move must include a target
+	if newActorID == "" {
+		return derp.NewBadRequestError(location, "Move must include a target", activity.Value())
+	}
+
+	// Re-point the Following record to the new account
+	if err := context.factory.Following().Move(context.user.UserID, oldActorID, newActorID); err != nil {
+		return derp.Wrap(err, location, "Error moving Following record", oldActorID, newActorID)
+	}
+
+	return nil
+}
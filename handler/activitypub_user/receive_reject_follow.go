@@ -0,0 +1,48 @@
+package activitypub_user
+
+import (
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+)
+
+func init() {
+	inboxRouter.Add(vocab.ActivityTypeReject, vocab.ActivityTypeFollow, receive_RejectFollow)
+}
+
+// receive_RejectFollow handles ActivityPub "Reject/Follow" activities, meaning that
+// a remote server has rejected (or revoked) our follow request.
+func receive_RejectFollow(context Context, activity streams.Document) error {
+
+	const location = "handler.activitypub_user.receive_RejectFollow"
+
+	followingService := context.factory.Following()
+
+	// Parse the Object.ID of the activity, which should be our original "Follow" activity
+	userID, followingID, err := service.ParseProfileURL_AsFollowing(activity.Object().ID())
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error parsing followingID", activity.Object().ID())
+	}
+
+	// Try to load the original "Following" record.
+	// If it doesn't already exist, then this message is invalid.
+	following := model.NewFollowing()
+	if err := followingService.LoadByID(userID, followingID, &following); err != nil {
+		return derp.Wrap(err, location, "Error loading following record", userID, followingID)
+	}
+
+	// RULE: Validate that the Following record matches the Reject
+	if following.ProfileURL != activity.Actor().ID() {
+		return derp.NewForbiddenError(location, "Invalid Reject", following.ProfileURL, activity.Actor().ID())
+	}
+
+	// Mark the "Following" record as rejected so that it is not retried
+	if err := followingService.SetStatusRejected(&following, "Follow request was rejected"); err != nil {
+		return derp.Wrap(err, location, "Error saving following", following)
+	}
+
+	return nil
+}
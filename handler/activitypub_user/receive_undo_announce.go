@@ -0,0 +1,49 @@
+package activitypub_user
+
+import (
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+)
+
+func init() {
+	inboxRouter.Add(vocab.ActivityTypeUndo, vocab.ActivityTypeAnnounce, receive_UndoAnnounce)
+	inboxRouter.Add(vocab.ActivityTypeDelete, vocab.ActivityTypeAnnounce, receive_UndoAnnounce)
+}
+
+// receive_UndoAnnounce handles "Undo/Announce" activities (un-boosts).  The boost is removed
+// from the ActivityStream cache, and its reference is removed from the User's inbox.
+func receive_UndoAnnounce(context Context, activity streams.Document) error {
+
+	const location = "handler.activitypub_user.receive_UndoAnnounce"
+
+	// The Object is the original Announce activity
+	originalActivity, err := activity.Object().Load()
+
+	if err != nil {
+		return nil
+	}
+
+	// RULE: Actor undoing the Announce must be the same as the original Actor
+	actorID := activity.Actor().ID()
+
+	if actorID != originalActivity.Actor().ID() {
+		return derp.NewUnauthorizedError(location, "Actor undoing this activity must be the same as the original activity")
+	}
+
+	// Remove the boost from the User's Inbox (if we follow the booster)
+	followingService := context.factory.Following()
+	following := model.NewFollowing()
+
+	if err := followingService.LoadByURL(context.user.UserID, actorID, &following); err == nil {
+		if err := followingService.RemoveMessageReference(&following, originalActivity.Object().ID(), model.OriginTypeAnnounce); err != nil {
+			return derp.Wrap(err, location, "Error removing boost from inbox", context.user.UserID, originalActivity.ID())
+		}
+	} else if !derp.NotFound(err) {
+		return derp.Wrap(err, location, "Error loading Following record", context.user.UserID, actorID)
+	}
+
+	// Remove the Announce from the ActivityStream cache
+	return undoResponse(context, activity)
+}
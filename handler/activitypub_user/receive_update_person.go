@@ -0,0 +1,36 @@
+package activitypub_user
+
+import (
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+)
+
+func init() {
+	inboxRouter.Add(vocab.ActivityTypeUpdate, vocab.ActorTypeApplication, receive_UpdateActor)
+	inboxRouter.Add(vocab.ActivityTypeUpdate, vocab.ActorTypeGroup, receive_UpdateActor)
+	inboxRouter.Add(vocab.ActivityTypeUpdate, vocab.ActorTypeOrganization, receive_UpdateActor)
+	inboxRouter.Add(vocab.ActivityTypeUpdate, vocab.ActorTypePerson, receive_UpdateActor)
+	inboxRouter.Add(vocab.ActivityTypeUpdate, vocab.ActorTypeService, receive_UpdateActor)
+}
+
+// receive_UpdateActor handles "Update" activities for remote actors, which are sent
+// when a profile changes.  The actor is reloaded into the cache, and the matching
+// Following record is updated with the new name and avatar.
+func receive_UpdateActor(context Context, activity streams.Document) error {
+
+	const location = "handler.activitypub_user.receive_UpdateActor"
+
+	// RULE: Actors can only update themselves, not other actors
+	actorID := activity.Actor().ID()
+
+	if actorID != activity.Object().ID() {
+		return derp.NewForbiddenError(location, "Actor and Object must be the same", actorID, activity.Object().ID())
+	}
+
+	if err := context.factory.Following().RefreshActor(context.user.UserID, actorID); err != nil {
+		return derp.Wrap(err, location, "Error refreshing actor", actorID)
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/EmissarySocial/emissary/domain"
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/benpate/hannibal/inbox"
+	"github.com/benpate/hannibal/streams"
 )
 
 var inboxRouter inbox.Router[Context] = inbox.NewRouter[Context]()
@@ -12,3 +13,16 @@ type Context struct {
 	factory *domain.Factory
 	user    *model.User
 }
+
+// HandleActivity routes an activity that has already been received (and verified)
+// into the inbox of the provided User.  This is used by the shared inbox to deliver
+// a single activity to many local Users.
+func HandleActivity(factory *domain.Factory, user *model.User, activity streams.Document) error {
+
+	context := Context{
+		factory: factory,
+		user:    user,
+	}
+
+	return inboxRouter.Handle(context, activity)
+}
@@ -103,6 +103,61 @@ func buildAdmin_GetBuilder(factory *domain.Factory, ctx *steranko.Context, templ
 
 		return build.NewRule(factory, ctx.Request(), ctx.Response(), &rule, template, actionID)
 
+	case "report":
+		report := model.NewReport()
+
+		if !objectID.IsZero() {
+			if err := factory.Report().LoadByID(objectID, &report); err != nil {
+				return nil, derp.Wrap(err, location, "Error loading Report", objectID)
+			}
+		}
+
+		return build.NewReport(factory, ctx.Request(), ctx.Response(), &report, template, actionID)
+
+	case "blocklist":
+		blocklist := model.NewBlocklist()
+
+		if !objectID.IsZero() {
+			if err := factory.Blocklist().LoadByID(objectID, &blocklist); err != nil {
+				return nil, derp.Wrap(err, location, "Error loading Blocklist", objectID)
+			}
+		}
+
+		return build.NewBlocklist(factory, ctx.Request(), ctx.Response(), &blocklist, template, actionID)
+
+	case "relay":
+		relay := model.NewRelay()
+
+		if !objectID.IsZero() {
+			if err := factory.Relay().LoadByID(objectID, &relay); err != nil {
+				return nil, derp.Wrap(err, location, "Error loading Relay", objectID)
+			}
+		}
+
+		return build.NewRelay(factory, ctx.Request(), ctx.Response(), &relay, template, actionID)
+
+	case "trend":
+		trend := model.NewTrend()
+
+		if !objectID.IsZero() {
+			if err := factory.Trend().LoadByID(objectID, &trend); err != nil {
+				return nil, derp.Wrap(err, location, "Error loading Trend", objectID)
+			}
+		}
+
+		return build.NewTrend(factory, ctx.Request(), ctx.Response(), &trend, template, actionID)
+
+	case "domain-policy":
+		domainPolicy := model.NewDomainPolicy()
+
+		if !objectID.IsZero() {
+			if err := factory.DomainPolicy().LoadByID(objectID, &domainPolicy); err != nil {
+				return nil, derp.Wrap(err, location, "Error loading DomainPolicy", objectID)
+			}
+		}
+
+		return build.NewDomainPolicy(factory, ctx.Request(), ctx.Response(), &domainPolicy, template, actionID)
+
 	case "domain":
 		return build.NewDomain(factory, ctx.Request(), ctx.Response(), template, actionID)
 
@@ -143,6 +198,6 @@ func buildAdmin_GetBuilder(factory *domain.Factory, ctx *steranko.Context, templ
 		return build.NewUser(factory, ctx.Request(), ctx.Response(), template, &user, actionID)
 
 	default:
-		return nil, derp.NewNotFoundError(location, "Template MODEL must be one of: 'rule', 'domain', 'group', 'stream', or 'user'", template.Model)
+		return nil, derp.NewNotFoundError(location, "Template MODEL must be one of: 'rule', 'report', 'blocklist', 'relay', 'trend', 'domain-policy', 'domain', 'group', 'stream', or 'user'", template.Model)
 	}
 }
@@ -65,6 +65,11 @@ func GetAttachment(factoryManager *server.Factory) echo.HandlerFunc {
 			return derp.Wrap(err, location, "Cannot create builder")
 		}
 
+		// Video and audio files cannot be downloaded until they have been transcoded
+		if !attachment.IsReady() {
+			return derp.NewNotFoundError(location, "Attachment is still being processed", attachmentID)
+		}
+
 		// Retrieve the file from the mediaserver
 		ms := factory.MediaServer()
 		filespec := ms.FileSpec(ctx.Request().URL, attachment.DownloadExtension())
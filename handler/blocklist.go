@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+
+	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/derp"
+	"github.com/labstack/echo/v4"
+)
+
+// GetBlocklistCSV exports all server-wide domain blocks as a Mastodon-compatible CSV file
+func GetBlocklistCSV(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.GetBlocklistCSV"
+
+	return func(ctx echo.Context) error {
+
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error loading domain factory")
+		}
+
+		// Buffer the file so that errors can still be reported with the correct status code
+		var buffer bytes.Buffer
+
+		if err := factory.Blocklist().ExportCSV(&buffer); err != nil {
+			return derp.Wrap(err, location, "Error exporting domain blocks")
+		}
+
+		return writeCSV(ctx, "domain_blocks.csv", buffer.Bytes())
+	}
+}
+
+// GetBlocklistJSON exports all server-wide domain blocks as a JSON array of Mastodon DomainBlocks
+func GetBlocklistJSON(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.GetBlocklistJSON"
+
+	return func(ctx echo.Context) error {
+
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error loading domain factory")
+		}
+
+		// Buffer the file so that errors can still be reported with the correct status code
+		var buffer bytes.Buffer
+
+		if err := factory.Blocklist().ExportJSON(&buffer); err != nil {
+			return derp.Wrap(err, location, "Error exporting domain blocks")
+		}
+
+		ctx.Response().Header().Set("Content-Disposition", `attachment; filename="domain_blocks.json"`)
+		return ctx.Blob(http.StatusOK, "application/json; charset=utf-8", buffer.Bytes())
+	}
+}
+
+// PostBlocklist imports a blocklist file (CSV or JSON) into server-wide domain blocks
+func PostBlocklist(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.PostBlocklist"
+
+	return func(ctx echo.Context) error {
+
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error loading domain factory")
+		}
+
+		// Read the uploaded file
+		fileHeader, err := ctx.FormFile("file")
+
+		if err != nil {
+			return derp.Wrap(err, location, "Missing blocklist file", derp.WithBadRequest())
+		}
+
+		file, err := fileHeader.Open()
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error opening blocklist file")
+		}
+
+		defer file.Close()
+
+		// Import the file into server-wide Rules
+		count, err := factory.Blocklist().Import(file)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error importing blocklist file")
+		}
+
+		// Close the modal and refresh the page
+		ctx.Response().Header().Set("HX-Trigger", `{"closeModal":true, "refreshPage":true}`)
+		return ctx.String(http.StatusOK, "Imported "+strconv.Itoa(count)+" domains")
+	}
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"github.com/EmissarySocial/emissary/build"
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/first"
+	"github.com/benpate/steranko"
+	"github.com/labstack/echo/v4"
+)
+
+// hashtagTemplateID is the Template used to render the public hashtag pages
+const hashtagTemplateID = "global-hashtag"
+
+// GetHashtag handles GET requests for the public hashtag pages
+func GetHashtag(serverFactory *server.Factory) echo.HandlerFunc {
+	return buildHashtag(serverFactory, build.ActionMethodGet)
+}
+
+// PostHashtag handles POST requests (follow and unfollow) for the public hashtag pages
+func PostHashtag(serverFactory *server.Factory) echo.HandlerFunc {
+	return buildHashtag(serverFactory, build.ActionMethodPost)
+}
+
+func buildHashtag(serverFactory *server.Factory, actionMethod build.ActionMethod) echo.HandlerFunc {
+
+	const location = "handler.buildHashtag"
+
+	return func(ctx echo.Context) error {
+
+		// Cast the context into a steranko context (which includes authentication data)
+		sterankoContext := ctx.(*steranko.Context)
+
+		// Get the domain factory from the context
+		factory, err := serverFactory.ByContext(sterankoContext)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error loading domain factory")
+		}
+
+		// Validate the hashtag
+		name := model.NormalizeHashtag(ctx.Param("tag"))
+
+		if name == "" {
+			return derp.NewNotFoundError(location, "Hashtag not found", ctx.Param("tag"))
+		}
+
+		// Load the Template for hashtag pages
+		template, err := factory.Template().Load(hashtagTemplateID)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error loading template", hashtagTemplateID)
+		}
+
+		// Signed-in Users may already follow this hashtag
+		followedTag := model.NewFollowedTag()
+		authorization := getAuthorization(sterankoContext)
+
+		if authorization.IsAuthenticated() {
+			if err := factory.FollowedTag().LoadByName(authorization.UserID, name, &followedTag); !derp.NilOrNotFound(err) {
+				return derp.Wrap(err, location, "Error loading followed hashtag", name)
+			}
+		}
+
+		followedTag.Name = name
+
+		// Build the page
+		actionID := first.String(ctx.Param("action"), "index")
+		builder, err := build.NewHashtag(factory, ctx.Request(), ctx.Response(), &followedTag, template, actionID)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error creating builder")
+		}
+
+		return build.AsHTML(factory, sterankoContext, builder, actionMethod)
+	}
+}
@@ -135,7 +135,7 @@ func Mastodon(serverFactory *server.Factory) toot.API[model.Authorization] {
 		PostMarker: mastodon.PostMarker(serverFactory),
 
 		// https://docs.joinmastodon.org/methods/media/
-		PostMedia: mastodon.PostMedia(serverFactory),
+		// Media routes are registered separately in server.go
 
 		// https://docs.joinmastodon.org/methods/mutes/
 		GetMutes: mastodon.GetMutes(serverFactory),
@@ -3,6 +3,8 @@ package mastodon
 import (
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
 	"github.com/benpate/toot"
 	"github.com/benpate/toot/object"
 	"github.com/benpate/toot/txn"
@@ -11,7 +13,31 @@ import (
 // https://docs.joinmastodon.org/methods/followed_tags/
 func GetFollowedTags(serverFactory *server.Factory) func(model.Authorization, txn.GetFollowedTags) ([]object.Tag, toot.PageInfo, error) {
 
-	return func(model.Authorization, txn.GetFollowedTags) ([]object.Tag, toot.PageInfo, error) {
-		return []object.Tag{}, toot.PageInfo{}, nil
+	const location = "handler.mastodon.GetFollowedTags"
+
+	return func(auth model.Authorization, t txn.GetFollowedTags) ([]object.Tag, toot.PageInfo, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByDomainName(t.Host)
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Invalid Domain")
+		}
+
+		// Query the database
+		followedTags, err := factory.FollowedTag().QueryByUser(auth.UserID, queryExpression(t), option.SortDesc("createDate"), option.MaxRows(getLimit(t.Limit)))
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Error querying database")
+		}
+
+		// Link each hashtag to its page on this Domain
+		result := make([]object.Tag, len(followedTags))
+
+		for index, followedTag := range followedTags {
+			result[index] = getTagToot(factory, followedTag.Name, true)
+		}
+
+		return result, getPageInfo(followedTags), nil
 	}
 }
@@ -105,6 +105,17 @@ func GetInstance_DomainBlocks(serverFactory *server.Factory) func(model.Authoriz
 			}
 		})
 
+		// Append all public federation policies
+		policies, err := factory.DomainPolicy().QueryPublic()
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Error querying federation policies")
+		}
+
+		for _, policy := range policies {
+			result = append(result, policy.Toot())
+		}
+
 		return result, nil
 	}
 }
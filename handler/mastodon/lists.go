@@ -1,9 +1,11 @@
 package mastodon
 
 import (
+	"github.com/EmissarySocial/emissary/domain"
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/server"
 	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/first"
 	"github.com/benpate/rosetta/slice"
 	"github.com/benpate/toot"
 	"github.com/benpate/toot/object"
@@ -91,6 +93,8 @@ func PostList(serverFactory *server.Factory) func(model.Authorization, txn.PostL
 		folder := model.NewFolder()
 		folder.UserID = auth.UserID
 		folder.Label = t.Title
+		folder.RepliesPolicy = first.String(t.RepliesPolicy, object.ListRepliesPolicyList)
+		folder.Exclusive = t.Exclusive
 
 		// Save it to the database
 		folderService := factory.Folder()
@@ -135,6 +139,10 @@ func PutList(serverFactory *server.Factory) func(model.Authorization, txn.PutLis
 		// Update Folder Data
 		folder.Label = t.Title
 
+		if t.RepliesPolicy != "" {
+			folder.RepliesPolicy = t.RepliesPolicy
+		}
+
 		// Save it to the database
 		if err := folderService.Save(&folder, "Created via Mastodon API"); err != nil {
 			return object.List{}, derp.Wrap(err, location, "Error saving folder")
@@ -173,6 +181,12 @@ func DeleteList(serverFactory *server.Factory) func(model.Authorization, txn.Del
 			return struct{}{}, derp.Wrap(err, location, "Error loading folder")
 		}
 
+		// Remove all members from the list
+		followingService := factory.Following()
+		if err := followingService.RemoveAllFromList(auth.UserID, folderID); err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Error removing list members")
+		}
+
 		// Delete the Folder
 		if err := folderService.Delete(&folder, "Deleted via Mastodon API"); err != nil {
 			return struct{}{}, derp.Wrap(err, location, "Error deleting folder")
@@ -204,10 +218,10 @@ func GetList_Accounts(serverFactory *server.Factory) func(model.Authorization, t
 			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Invalid Domain Name", t.Host)
 		}
 
-		// Query all Following records in this Folder
+		// Query all Following records that are members of this list
 		followingService := factory.Following()
 		criteria := queryExpression(t)
-		followingSummaries, err := followingService.QueryByFolderAndExp(auth.UserID, folderID, criteria)
+		followingSummaries, err := followingService.QueryByList(auth.UserID, folderID, criteria)
 
 		if err != nil {
 			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Error querying database")
@@ -217,9 +231,10 @@ func GetList_Accounts(serverFactory *server.Factory) func(model.Authorization, t
 		result := slice.Map(followingSummaries, func(following model.FollowingSummary) object.Account {
 
 			return object.Account{
-				ID:     following.FollowingID.Hex(),
-				URL:    following.URL,
-				Avatar: following.IconURL,
+				ID:          first.String(following.ProfileURL, following.URL),
+				URL:         following.URL,
+				DisplayName: following.Label,
+				Avatar:      following.IconURL,
 			}
 		})
 
@@ -228,15 +243,83 @@ func GetList_Accounts(serverFactory *server.Factory) func(model.Authorization, t
 	}
 }
 
+// https://docs.joinmastodon.org/methods/lists/#accounts-add
 func PostList_Accounts(serverFactory *server.Factory) func(model.Authorization, txn.PostList_Accounts) (struct{}, error) {
 
-	return func(model.Authorization, txn.PostList_Accounts) (struct{}, error) {
-		return struct{}{}, derp.NewBadRequestError("handler.mastodon.PostListAccounts", "Not Implemented")
+	const location = "handler.mastodon.PostList_Accounts"
+
+	return func(auth model.Authorization, t txn.PostList_Accounts) (struct{}, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByDomainName(t.Host)
+
+		if err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Invalid Domain Name", t.Host)
+		}
+
+		// Confirm that the list exists
+		folder, err := loadList(factory, auth.UserID, t.ID)
+
+		if err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Error loading list", t.ID)
+		}
+
+		// Add each account to the list
+		followingService := factory.Following()
+		if err := followingService.AddToList(auth.UserID, folder.FolderID, t.AccountIDs); err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Error adding accounts to list", t.AccountIDs)
+		}
+
+		return struct{}{}, nil
 	}
 }
+
+// https://docs.joinmastodon.org/methods/lists/#accounts-remove
 func DeleteList_Accounts(serverFactory *server.Factory) func(model.Authorization, txn.DeleteList_Accounts) (struct{}, error) {
 
-	return func(model.Authorization, txn.DeleteList_Accounts) (struct{}, error) {
-		return struct{}{}, derp.NewBadRequestError("handler.mastodon.PostListAccounts", "Not Implemented")
+	const location = "handler.mastodon.DeleteList_Accounts"
+
+	return func(auth model.Authorization, t txn.DeleteList_Accounts) (struct{}, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByDomainName(t.Host)
+
+		if err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Invalid Domain Name", t.Host)
+		}
+
+		// Confirm that the list exists
+		folder, err := loadList(factory, auth.UserID, t.ID)
+
+		if err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Error loading list", t.ID)
+		}
+
+		// Remove each account from the list
+		followingService := factory.Following()
+		if err := followingService.RemoveFromList(auth.UserID, folder.FolderID, t.AccountIDs); err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Error removing accounts from list", t.AccountIDs)
+		}
+
+		return struct{}{}, nil
+	}
+}
+
+// loadList loads the Folder that represents a Mastodon list
+func loadList(factory *domain.Factory, userID primitive.ObjectID, token string) (model.Folder, error) {
+
+	const location = "handler.mastodon.loadList"
+
+	folder := model.NewFolder()
+	folderID, err := primitive.ObjectIDFromHex(token)
+
+	if err != nil {
+		return folder, derp.Wrap(err, location, "Invalid List ID", token)
 	}
+
+	if err := factory.Folder().LoadByID(userID, folderID, &folder); err != nil {
+		return folder, derp.Wrap(err, location, "Error loading list", token)
+	}
+
+	return folder, nil
 }
@@ -1,17 +1,245 @@
 package mastodon
 
 import (
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/domain"
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/server"
 	"github.com/benpate/derp"
-	"github.com/benpate/toot/object"
-	"github.com/benpate/toot/txn"
+	"github.com/benpate/rosetta/slice"
+	"github.com/benpate/toot/scope"
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// https://docs.joinmastodon.org/methods/media/
-func PostMedia(serverFactory *server.Factory) func(model.Authorization, txn.PostMedia) (object.MediaAttachment, error) {
+// The Mastodon media API is registered directly with echo (instead of through toot-echo)
+// because it needs to read multipart file uploads and return non-200 status codes
+// while media files are still being processed.
+
+// PostMedia_V1 uploads a new media file that can be attached to a status later.
+// The v1 endpoint is synchronous, so video and audio files are processed
+// before this returns 200 OK.
+// https://docs.joinmastodon.org/methods/media/#v1
+func PostMedia_V1(serverFactory *server.Factory) echo.HandlerFunc {
+	return postMedia(serverFactory, false)
+}
+
+// PostMedia_V2 uploads a new media file that can be attached to a status later.
+// Video and audio files are processed in the background, so this returns
+// 202 Accepted until the file is ready.
+// https://docs.joinmastodon.org/methods/media/#v2
+func PostMedia_V2(serverFactory *server.Factory) echo.HandlerFunc {
+	return postMedia(serverFactory, true)
+}
+
+// postMedia implements both versions of the media upload API.  If async is FALSE,
+// then video and audio files are transcoded before the response is written.
+func postMedia(serverFactory *server.Factory, async bool) echo.HandlerFunc {
+
+	const location = "handler.mastodon.postMedia"
+
+	return func(ctx echo.Context) error {
+
+		authorization, factory, err := authorizeMedia(serverFactory, ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Request is not authorized")
+		}
+
+		// Read the uploaded file
+		fileHeader, err := ctx.FormFile("file")
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error reading uploaded file", derp.WithBadRequest())
+		}
+
+		// Uploads count against the User's storage quota
+		if err := factory.Storage().CheckQuota(authorization.UserID, fileHeader.Size); err != nil {
+			return derp.Wrap(err, location, "Upload exceeds storage quota")
+		}
+
+		source, err := fileHeader.Open()
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error opening uploaded file")
+		}
+
+		defer source.Close()
+
+		// Create a new (unattached) Attachment for this User
+		attachmentService := factory.Attachment()
+		attachment := model.NewAttachment(model.AttachmentObjectTypeUser, authorization.UserID)
+		attachment.Original = fileHeader.Filename
+		attachment.Category = model.AttachmentCategoryMedia
+		attachment.MediaType = attachment.CalcMediaType()
+		attachment.UserID = authorization.UserID
+		attachment.Size = fileHeader.Size
+		attachment.AltText = ctx.FormValue("description")
+		attachment.SetFocus(ctx.FormValue("focus"))
+
+		// Add the file into the media server
+		width, height, err := factory.MediaServer().Put(attachment.AttachmentID.Hex(), source)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error saving file to mediaserver")
+		}
+
+		attachment.Width = width
+		attachment.Height = height
+
+		if err := attachmentService.CalcBlurhash(&attachment); err != nil {
+			derp.Report(derp.Wrap(err, location, "Error calculating blurhash", attachment))
+		}
+
+		if err := attachmentService.Save(&attachment, "Uploaded via Mastodon API"); err != nil {
+			return derp.Wrap(err, location, "Error saving attachment")
+		}
+
+		// v1 uploads are transcoded before responding
+		if !async {
+
+			if err := attachmentService.TranscodeNow(&attachment); err != nil {
+				return derp.Wrap(err, location, "Error transcoding attachment")
+			}
+
+			return writeMedia(ctx, attachment, http.StatusOK)
+		}
+
+		// v2 uploads are transcoded in the background
+		if err := attachmentService.Transcode(&attachment); err != nil {
+			return derp.Wrap(err, location, "Error transcoding attachment")
+		}
+
+		return writeMedia(ctx, attachment, http.StatusAccepted)
+	}
+}
+
+// GetMedia returns a single media file, which clients poll until processing is complete.
+// This returns 206 Partial Content while the file is still being processed.
+// https://docs.joinmastodon.org/methods/media/#get
+func GetMedia(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.mastodon.GetMedia"
+
+	return func(ctx echo.Context) error {
+
+		authorization, factory, err := authorizeMedia(serverFactory, ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Request is not authorized")
+		}
+
+		attachment, err := loadMedia(factory, authorization, ctx.Param("id"))
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error loading media")
+		}
+
+		return writeMedia(ctx, attachment, http.StatusPartialContent)
+	}
+}
+
+// PutMedia updates the description and focal point of a media file that has not yet been attached to a status.
+// https://docs.joinmastodon.org/methods/media/#update
+func PutMedia(serverFactory *server.Factory) echo.HandlerFunc {
 
-	return func(model.Authorization, txn.PostMedia) (object.MediaAttachment, error) {
-		return object.MediaAttachment{}, derp.NewForbiddenError("handler.mastodon.PostMedia", "Media uploads are not supported")
+	const location = "handler.mastodon.PutMedia"
+
+	return func(ctx echo.Context) error {
+
+		authorization, factory, err := authorizeMedia(serverFactory, ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Request is not authorized")
+		}
+
+		attachment, err := loadMedia(factory, authorization, ctx.Param("id"))
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error loading media")
+		}
+
+		// Apply changes
+		if description := ctx.FormValue("description"); description != "" {
+			attachment.AltText = description
+		}
+
+		if focus := ctx.FormValue("focus"); focus != "" {
+			if !attachment.SetFocus(focus) {
+				return derp.NewBadRequestError(location, "Invalid focus point", focus)
+			}
+		}
+
+		if err := factory.Attachment().Save(&attachment, "Updated via Mastodon API"); err != nil {
+			return derp.Wrap(err, location, "Error saving attachment")
+		}
+
+		return writeMedia(ctx, attachment, http.StatusPartialContent)
+	}
+}
+
+// authorizeMedia validates the OAuth token for a media request
+func authorizeMedia(serverFactory *server.Factory, ctx echo.Context) (model.Authorization, *domain.Factory, error) {
+
+	const location = "handler.mastodon.authorizeMedia"
+
+	factory, err := serverFactory.ByContext(ctx)
+
+	if err != nil {
+		return model.Authorization{}, nil, derp.Wrap(err, location, "Unrecognized Domain")
+	}
+
+	authorization, err := Authorizer(serverFactory)(ctx.Request())
+
+	if err != nil {
+		return model.Authorization{}, nil, derp.Wrap(err, location, "Invalid OAuth token", derp.WithCode(http.StatusUnauthorized))
+	}
+
+	scopes := authorization.Scopes()
+
+	if !slice.Contains(scopes, scope.WriteMedia) && !slice.Contains(scopes, scope.Write) {
+		return model.Authorization{}, nil, derp.NewUnauthorizedError(location, "Token does not include the required scope", scope.WriteMedia)
+	}
+
+	return authorization, factory, nil
+}
+
+// loadMedia loads an unattached media file that belongs to the authorized User
+func loadMedia(factory *domain.Factory, authorization model.Authorization, token string) (model.Attachment, error) {
+
+	const location = "handler.mastodon.loadMedia"
+
+	attachmentID, err := primitive.ObjectIDFromHex(token)
+
+	if err != nil {
+		return model.Attachment{}, derp.Wrap(err, location, "Invalid media ID", token, derp.WithNotFound())
+	}
+
+	attachment := model.NewAttachment(model.AttachmentObjectTypeUser, authorization.UserID)
+
+	if err := factory.Attachment().LoadByID(model.AttachmentObjectTypeUser, authorization.UserID, attachmentID, &attachment); err != nil {
+		return model.Attachment{}, derp.Wrap(err, location, "Error loading attachment", token)
+	}
+
+	// RULE: Only Mastodon media can be read or edited here (not avatars, banners, etc)
+	if attachment.Category != model.AttachmentCategoryMedia {
+		return model.Attachment{}, derp.NewNotFoundError(location, "Media not found", token)
+	}
+
+	return attachment, nil
+}
+
+// writeMedia writes a MediaAttachment to the response.  Media that is ready to use is always
+// returned with 200 OK.  Media that is still processing is returned with the provided status code.
+func writeMedia(ctx echo.Context, attachment model.Attachment, processingStatus int) error {
+
+	status := http.StatusOK
+
+	if !attachment.IsReady() {
+		status = processingStatus
 	}
+
+	ctx.Response().Header().Set("Access-Control-Allow-Origin", "*")
+	return ctx.JSON(status, attachment.Toot())
 }
@@ -3,14 +3,56 @@ package mastodon
 import (
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/derp"
 	"github.com/benpate/toot/object"
 	"github.com/benpate/toot/txn"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // https://docs.joinmastodon.org/methods/reports/
 func PostReport(serverFactory *server.Factory) func(model.Authorization, txn.PostReport) (object.Report, error) {
 
-	return func(model.Authorization, txn.PostReport) (object.Report, error) {
-		return object.Report{}, nil
+	const location = "handler.mastodon.PostReport"
+
+	return func(authorization model.Authorization, t txn.PostReport) (object.Report, error) {
+
+		// Get the Domain factory for this request
+		factory, err := serverFactory.ByDomainName(t.Host)
+
+		if err != nil {
+			return object.Report{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Load the User who is filing the Report
+		user := model.NewUser()
+		if err := factory.User().LoadByID(authorization.UserID, &user); err != nil {
+			return object.Report{}, derp.Wrap(err, location, "Error loading User", authorization.UserID)
+		}
+
+		// Statuses may be identified by URL, or by the ID of a Message in the User's inbox
+		inboxService := factory.Inbox()
+		objectURLs := make([]string, 0, len(t.StatusIDs))
+
+		for _, statusID := range t.StatusIDs {
+
+			if messageID, err := primitive.ObjectIDFromHex(statusID); err == nil {
+				message := model.NewMessage()
+				if err := inboxService.LoadByID(user.UserID, messageID, &message); err == nil {
+					objectURLs = append(objectURLs, message.URL)
+					continue
+				}
+			}
+
+			objectURLs = append(objectURLs, statusID)
+		}
+
+		// File the Report
+		report, err := factory.Report().File(&user, t.AccountID, objectURLs, t.Category, t.Comment, t.Forward)
+
+		if err != nil {
+			return object.Report{}, derp.Wrap(err, location, "Error filing Report")
+		}
+
+		return report.Toot(), nil
 	}
 }
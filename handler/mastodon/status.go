@@ -45,9 +45,12 @@ func PostStatus(serverFactory *server.Factory) func(model.Authorization, txn.Pos
 			stream.PublishDate = scheduledAt.Unix()
 		}
 
-		// Add the content into the stream
+		// Add the content (and optional quote) into the stream
+		status, quoteURL := splitQuote(transaction.Status)
+		stream.QuoteURL = quoteURL
+
 		contentService := factory.Content()
-		stream.Content = contentService.New(model.ContentFormatHTML, transaction.Status)
+		stream.Content = contentService.New(model.ContentFormatHTML, status)
 
 		// Verify user permissions
 		streamService := factory.Stream()
@@ -55,17 +58,28 @@ func PostStatus(serverFactory *server.Factory) func(model.Authorization, txn.Pos
 			return object.Status{}, derp.NewForbiddenError(location, "User is not authorized to create this stream", stream, authorization)
 		}
 
+		// Verify that all media files exist before saving anything
+		attachmentService := factory.Attachment()
+		if _, err := attachmentService.QueryMedia(authorization.UserID, transaction.MediaIDs); err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Invalid media_ids")
+		}
+
 		// Save the stream
 		if err := streamService.Save(&stream, "Created via Mastodon API"); err != nil {
 			return object.Status{}, derp.Wrap(err, location, "Error saving stream")
 		}
 
+		// Move uploaded media files onto the new stream
+		if _, err := attachmentService.AttachMedia(authorization.UserID, transaction.MediaIDs, stream.StreamID); err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Error attaching media")
+		}
+
 		// Publish the Stream to the User's outbox
 		if err := streamService.Publish(&user, &stream, true); err != nil {
 			return object.Status{}, derp.Wrap(err, location, "Error publishing stream")
 		}
 
-		return stream.Toot(), nil
+		return getStreamToot(factory, stream)
 	}
 }
 
@@ -88,8 +102,15 @@ func GetStatus(serverFactory *server.Factory) func(model.Authorization, txn.GetS
 			return object.Status{}, derp.NewForbiddenError(location, "User is not authorized to delete this stream")
 		}
 
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByDomainName(transaction.Host)
+
+		if err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Invalid Domain")
+		}
+
 		// Return the value
-		return stream.Toot(), nil
+		return getStreamToot(factory, stream)
 	}
 }
 
@@ -394,7 +415,6 @@ func PutStatus(serverFactory *server.Factory) func(model.Authorization, txn.PutS
 		// t.Sensitive
 		// t.Language
 
-		// t.MediaIDs
 		// t.Poll info...
 
 		// Save the stream to the database
@@ -402,7 +422,12 @@ func PutStatus(serverFactory *server.Factory) func(model.Authorization, txn.PutS
 			return object.Status{}, derp.Wrap(err, location, "Error saving stream")
 		}
 
-		return stream.Toot(), nil
+		// Reconcile the stream's attachments with the client's media list
+		if _, err := factory.Attachment().SetStreamMedia(auth.UserID, t.MediaIDs, stream.StreamID); err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Error attaching media")
+		}
+
+		return getStreamToot(factory, stream)
 	}
 }
 
@@ -1,8 +1,10 @@
 package mastodon
 
 import (
+	"github.com/EmissarySocial/emissary/domain"
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/derp"
 	"github.com/benpate/toot/object"
 	"github.com/benpate/toot/txn"
 )
@@ -10,21 +12,98 @@ import (
 // https://docs.joinmastodon.org/methods/tags/
 func GetTag(serverFactory *server.Factory) func(model.Authorization, txn.GetTag) (object.Tag, error) {
 
-	return func(model.Authorization, txn.GetTag) (object.Tag, error) {
-		return object.Tag{}, nil
+	const location = "handler.mastodon.GetTag"
+
+	return func(auth model.Authorization, t txn.GetTag) (object.Tag, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByDomainName(t.Host)
+
+		if err != nil {
+			return object.Tag{}, derp.Wrap(err, location, "Invalid Domain")
+		}
+
+		name := model.NormalizeHashtag(t.ID)
+
+		if name == "" {
+			return object.Tag{}, derp.NewNotFoundError(location, "Hashtag not found", t.ID)
+		}
+
+		// Anonymous visitors do not follow any hashtags
+		if auth.UserID.IsZero() {
+			return getTagToot(factory, name, false), nil
+		}
+
+		// Report whether the signed-in User follows this hashtag
+		followedTag := model.NewFollowedTag()
+
+		if err := factory.FollowedTag().LoadByName(auth.UserID, name, &followedTag); err == nil {
+			return getTagToot(factory, name, true), nil
+		} else if !derp.NotFound(err) {
+			return object.Tag{}, derp.Wrap(err, location, "Error loading followed hashtag", name)
+		}
+
+		return getTagToot(factory, name, false), nil
 	}
 }
 
 func PostTag_Follow(serverFactory *server.Factory) func(model.Authorization, txn.PostTag_Follow) (object.Tag, error) {
 
-	return func(model.Authorization, txn.PostTag_Follow) (object.Tag, error) {
-		return object.Tag{}, nil
+	const location = "handler.mastodon.PostTag_Follow"
+
+	return func(auth model.Authorization, t txn.PostTag_Follow) (object.Tag, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByDomainName(t.Host)
+
+		if err != nil {
+			return object.Tag{}, derp.Wrap(err, location, "Invalid Domain")
+		}
+
+		// Follow the hashtag
+		followedTag, err := factory.FollowedTag().Follow(auth.UserID, t.ID)
+
+		if err != nil {
+			return object.Tag{}, derp.Wrap(err, location, "Error following hashtag", t.ID)
+		}
+
+		return getTagToot(factory, followedTag.Name, true), nil
 	}
 }
 
 func PostTag_Unfollow(serverFactory *server.Factory) func(model.Authorization, txn.PostTag_Unfollow) (object.Tag, error) {
 
-	return func(model.Authorization, txn.PostTag_Unfollow) (object.Tag, error) {
-		return object.Tag{}, nil
+	const location = "handler.mastodon.PostTag_Unfollow"
+
+	return func(auth model.Authorization, t txn.PostTag_Unfollow) (object.Tag, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByDomainName(t.Host)
+
+		if err != nil {
+			return object.Tag{}, derp.Wrap(err, location, "Invalid Domain")
+		}
+
+		// Unfollow the hashtag
+		if err := factory.FollowedTag().Unfollow(auth.UserID, t.ID); err != nil {
+			return object.Tag{}, derp.Wrap(err, location, "Error unfollowing hashtag", t.ID)
+		}
+
+		return getTagToot(factory, model.NormalizeHashtag(t.ID), false), nil
 	}
 }
+
+// getTagToot returns a Mastodon Tag that links to the hashtag page on this Domain.
+// Usage history is only included for approved trends.
+func getTagToot(factory *domain.Factory, name string, following bool) object.Tag {
+
+	trend := model.NewTrend()
+
+	if err := factory.Trend().LoadApprovedTag(name, &trend); err != nil {
+		trend.Key = name
+	}
+
+	result := trend.Tag(factory.Host())
+	result.Following = following
+	return result
+}
@@ -1,13 +1,17 @@
 package mastodon
 
 import (
+	"slices"
+	"sort"
+
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/server"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data/option"
 	"github.com/benpate/derp"
 	"github.com/benpate/toot"
 	"github.com/benpate/toot/object"
 	"github.com/benpate/toot/txn"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // https://docs.joinmastodon.org/methods/timelines/
@@ -15,16 +19,128 @@ import (
 // https://docs.joinmastodon.org/methods/timelines/#public
 func GetTimeline_Public(serverFactory *server.Factory) func(model.Authorization, txn.GetTimeline_Public) ([]object.Status, toot.PageInfo, error) {
 
+	const location = "handler.mastodon.GetTimeline_Public"
+
 	return func(auth model.Authorization, t txn.GetTimeline_Public) ([]object.Status, toot.PageInfo, error) {
-		return []object.Status{}, toot.PageInfo{}, nil
+
+		// The public timeline is made of posts forwarded by relays, so there are no local posts
+		if t.Local {
+			return []object.Status{}, toot.PageInfo{}, nil
+		}
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByDomainName(t.Host)
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Invalid Domain")
+		}
+
+		// Get relayed messages from the database
+		criteria := queryExpression(queryPage{
+			MaxID:   t.MaxID,
+			SinceID: t.SinceID,
+			MinID:   t.MinID,
+			Limit:   t.Limit,
+		})
+
+		messages, err := factory.RelayMessage().QueryTimeline(criteria, t.Limit)
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Error retrieving messages")
+		}
+
+		return getSliceOfToots[model.RelayMessage, object.Status](messages), getPageInfo(messages), nil
 	}
 }
 
 // https://docs.joinmastodon.org/methods/timelines/#tag
 func GetTimeline_Hashtag(serverFactory *server.Factory) func(model.Authorization, txn.GetTimeline_Hashtag) ([]object.Status, toot.PageInfo, error) {
 
+	const location = "handler.mastodon.GetTimeline_Hashtag"
+
 	return func(auth model.Authorization, t txn.GetTimeline_Hashtag) ([]object.Status, toot.PageInfo, error) {
-		return []object.Status{}, toot.PageInfo{}, nil
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByDomainName(t.Host)
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Invalid Domain")
+		}
+
+		// Posts may match the primary hashtag or any of the "any" hashtags
+		names := normalizeHashtags(append([]string{t.Hashtag}, t.Any...))
+
+		if len(names) == 0 {
+			return []object.Status{}, toot.PageInfo{}, nil
+		}
+
+		limit := getLimit(t.Limit)
+		result := make([]hashtagStatus, 0)
+		localURIs := make(map[string]bool)
+
+		// Local Streams are included unless the caller asks for remote posts (or
+		// media posts, which are not indexed on the Stream) only
+		if !t.Remote && !t.OnlyMedia {
+
+			streams, err := factory.Stream().QueryByHashtags(names, queryExpressionByField(t, "publishDate"), option.SortDesc("publishDate"), option.MaxRows(limit))
+
+			if err != nil {
+				return nil, toot.PageInfo{}, derp.Wrap(err, location, "Error retrieving local posts")
+			}
+
+			streams = slices.DeleteFunc(streams, func(stream model.Stream) bool {
+				return !matchHashtags(stream.Hashtags(), t.All, t.None)
+			})
+
+			statuses, err := getStreamToots(factory, streams)
+
+			if err != nil {
+				return nil, toot.PageInfo{}, derp.Wrap(err, location, "Error retrieving local media")
+			}
+
+			for index, status := range statuses {
+				localURIs[status.URI] = true
+				result = append(result, hashtagStatus{status: status, rank: streams[index].PublishDate})
+			}
+		}
+
+		// Remote posts are the documents that this Domain has received
+		if !t.Local {
+
+			documents, err := factory.FollowedTag().QueryDocuments(names, queryExpressionByField(t, "published"), option.SortDesc("published"), option.MaxRows(limit))
+
+			if err != nil {
+				return nil, toot.PageInfo{}, derp.Wrap(err, location, "Error retrieving remote posts")
+			}
+
+			for _, document := range documents {
+
+				if localURIs[document.ID()] {
+					continue
+				}
+
+				if t.OnlyMedia && document.Attachment().IsNil() {
+					continue
+				}
+
+				if !matchHashtags(service.DocumentHashtags(document), t.All, t.None) {
+					continue
+				}
+
+				result = append(result, hashtagStatus{status: getDocumentToot(document), rank: document.Published().Unix()})
+			}
+		}
+
+		// Merge local and remote posts, newest first
+		sort.SliceStable(result, func(i int, j int) bool {
+			return result[i].rank > result[j].rank
+		})
+
+		if int64(len(result)) > limit {
+			result = result[:limit]
+		}
+
+		return getSliceOfToots[hashtagStatus, object.Status](result), getPageInfo(result), nil
 	}
 }
 
@@ -42,15 +158,27 @@ func GetTimeline_Home(serverFactory *server.Factory) func(model.Authorization, t
 			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Invalid Domain")
 		}
 
+		// Members of "exclusive" lists are removed from the home timeline
+		criteria := queryExpression(t)
+		exclusiveIDs, err := factory.Following().QueryExclusiveListMemberIDs(auth.UserID)
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Error retrieving exclusive lists")
+		}
+
+		if len(exclusiveIDs) > 0 {
+			criteria = criteria.AndNotIn("origin.followingId", exclusiveIDs)
+		}
+
 		// Get Inbox items from the database
 		inboxService := factory.Inbox()
-		messages, err := inboxService.QueryByUserID(auth.UserID, queryExpression(t))
+		messages, err := inboxService.QueryByUserID(auth.UserID, criteria)
 
 		if err != nil {
 			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Error retrieving messages")
 		}
 
-		return getSliceOfToots[model.Message, object.Status](messages), getPageInfo(messages), nil
+		return getMessageToots(factory, messages), getPageInfo(messages), nil
 	}
 }
 
@@ -61,13 +189,6 @@ func GetTimeline_List(serverFactory *server.Factory) func(model.Authorization, t
 
 	return func(auth model.Authorization, t txn.GetTimeline_List) ([]object.Status, toot.PageInfo, error) {
 
-		// Parse arguments
-		folderID, err := primitive.ObjectIDFromHex(t.ListID)
-
-		if err != nil {
-			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Invalid ListID")
-		}
-
 		// Get the factory for this Domain
 		factory, err := serverFactory.ByDomainName(t.Host)
 
@@ -75,16 +196,71 @@ func GetTimeline_List(serverFactory *server.Factory) func(model.Authorization, t
 			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Invalid Domain")
 		}
 
-		// Get Inbox items from the database
-		inboxService := factory.Inbox()
-		criteria := queryExpression(t).AndEqual("folderId", folderID)
+		// Load the list
+		folder, err := loadList(factory, auth.UserID, t.ListID)
 
-		messages, err := inboxService.QueryByUserID(auth.UserID, criteria)
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Error loading list", t.ListID)
+		}
+
+		// Get posts from list members
+		followingService := factory.Following()
+		messages, err := followingService.QueryListTimeline(auth.UserID, &folder, queryExpression(t))
 
 		if err != nil {
 			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Error retrieving messages")
 		}
 
-		return getSliceOfToots[model.Message, object.Status](messages), getPageInfo(messages), nil
+		return getMessageToots(factory, messages), getPageInfo(messages), nil
 	}
 }
+
+// hashtagStatus is a single post in a hashtag timeline.  Local and remote posts are
+// merged together, so they are ranked by their publish date.
+type hashtagStatus struct {
+	status object.Status
+	rank   int64
+}
+
+// Toot implements the tootGetter interface
+func (h hashtagStatus) Toot() object.Status {
+	return h.status
+}
+
+// GetRank implements the rankGetter interface
+func (h hashtagStatus) GetRank() int64 {
+	return h.rank
+}
+
+// normalizeHashtags returns the normalized, non-empty names of the provided hashtags
+func normalizeHashtags(values []string) []string {
+
+	result := make([]string, 0, len(values))
+
+	for _, value := range values {
+		if name := model.NormalizeHashtag(value); name != "" {
+			result = append(result, name)
+		}
+	}
+
+	return result
+}
+
+// matchHashtags returns TRUE if a post's hashtags include ALL of the "all" hashtags
+// and NONE of the "none" hashtags
+func matchHashtags(hashtags []string, all []string, none []string) bool {
+
+	for _, name := range normalizeHashtags(all) {
+		if !slices.Contains(hashtags, name) {
+			return false
+		}
+	}
+
+	for _, name := range normalizeHashtags(none) {
+		if slices.Contains(hashtags, name) {
+			return false
+		}
+	}
+
+	return true
+}
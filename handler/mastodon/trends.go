@@ -1,8 +1,11 @@
 package mastodon
 
 import (
+	"github.com/EmissarySocial/emissary/domain"
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
 	"github.com/benpate/toot"
 	"github.com/benpate/toot/object"
 	"github.com/benpate/toot/txn"
@@ -11,21 +14,130 @@ import (
 // https://docs.joinmastodon.org/methods/trends/
 func GetTrends(serverFactory *server.Factory) func(model.Authorization, txn.GetTrends) ([]object.Tag, toot.PageInfo, error) {
 
-	return func(model.Authorization, txn.GetTrends) ([]object.Tag, toot.PageInfo, error) {
-		return []object.Tag{}, toot.PageInfo{}, nil
+	const location = "handler.mastodon.GetTrends"
+
+	return func(_ model.Authorization, t txn.GetTrends) ([]object.Tag, toot.PageInfo, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByDomainName(t.Host)
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Invalid Domain")
+		}
+
+		// Query approved hashtag trends
+		trends, err := queryApprovedTrends(factory, model.TrendTypeTag, t.Limit, t.Offset)
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Error loading trending hashtags")
+		}
+
+		result := make([]object.Tag, len(trends))
+
+		for index, trend := range trends {
+			result[index] = trend.Tag(factory.Host())
+		}
+
+		return result, toot.PageInfo{}, nil
 	}
 }
 
 func GetTrends_Statuses(serverFactory *server.Factory) func(model.Authorization, txn.GetTrends_Statuses) ([]object.Status, toot.PageInfo, error) {
 
-	return func(model.Authorization, txn.GetTrends_Statuses) ([]object.Status, toot.PageInfo, error) {
-		return []object.Status{}, toot.PageInfo{}, nil
+	const location = "handler.mastodon.GetTrends_Statuses"
+
+	return func(_ model.Authorization, t txn.GetTrends_Statuses) ([]object.Status, toot.PageInfo, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByDomainName(t.Host)
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Invalid Domain")
+		}
+
+		// Query approved status trends
+		trends, err := queryApprovedTrends(factory, model.TrendTypeStatus, t.Limit, t.Offset)
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Error loading trending statuses")
+		}
+
+		// Load each status from the ActivityStream cache
+		activityService := factory.ActivityStream()
+		domainPolicyService := factory.DomainPolicy()
+		result := make([]object.Status, 0, len(trends))
+
+		for _, trend := range trends {
+
+			document, err := activityService.Load(trend.Key)
+
+			if err != nil {
+				derp.Report(derp.Wrap(err, location, "Error loading trending status", trend.Key))
+				continue
+			}
+
+			if !domainPolicyService.Allow(&document, true) {
+				continue
+			}
+
+			result = append(result, getDocumentToot(document))
+		}
+
+		return result, toot.PageInfo{}, nil
 	}
 }
 
 func GetTrends_Links(serverFactory *server.Factory) func(model.Authorization, txn.GetTrends_Links) ([]object.PreviewCard, toot.PageInfo, error) {
 
-	return func(model.Authorization, txn.GetTrends_Links) ([]object.PreviewCard, toot.PageInfo, error) {
-		return []object.PreviewCard{}, toot.PageInfo{}, nil
+	const location = "handler.mastodon.GetTrends_Links"
+
+	return func(_ model.Authorization, t txn.GetTrends_Links) ([]object.PreviewCard, toot.PageInfo, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByDomainName(t.Host)
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Invalid Domain")
+		}
+
+		// Query approved link trends
+		trends, err := queryApprovedTrends(factory, model.TrendTypeLink, t.Limit, t.Offset)
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Error loading trending links")
+		}
+
+		result := make([]object.PreviewCard, len(trends))
+
+		for index, trend := range trends {
+			result[index] = trend.PreviewCard()
+		}
+
+		return result, toot.PageInfo{}, nil
+	}
+}
+
+// queryApprovedTrends returns a single page of approved trends.  Trends are paged by offset
+// instead of by ID, and return 10 results by default (and no more than 20).
+func queryApprovedTrends(factory *domain.Factory, trendType string, limit int, offset int) ([]model.Trend, error) {
+
+	if (limit <= 0) || (limit > 20) {
+		limit = 10
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+
+	trends, err := factory.Trend().QueryApproved(trendType, option.MaxRows(int64(offset+limit)))
+
+	if err != nil {
+		return nil, err
 	}
+
+	if offset >= len(trends) {
+		return make([]model.Trend, 0), nil
+	}
+
+	return trends[offset:], nil
 }
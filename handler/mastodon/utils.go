@@ -3,14 +3,22 @@ package mastodon
 import (
 	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/EmissarySocial/emissary/domain"
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/server"
 	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data/option"
 	"github.com/benpate/derp"
 	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/rosetta/list"
 	"github.com/benpate/toot"
+	"github.com/benpate/toot/object"
 	"github.com/benpate/toot/txn"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type tootGetter[Result any] interface {
@@ -51,9 +59,35 @@ func getPageInfo[In rankGetter](slice []In) toot.PageInfo {
 	return result
 }
 
+// queryPage adapts txn.QueryPage to the txn.QueryPager interface, for
+// transactions (like GetTimeline_Public) that do not implement it themselves.
+type queryPage txn.QueryPage
+
+// QueryPage implements the txn.QueryPager interface
+func (q queryPage) QueryPage() txn.QueryPage {
+	return txn.QueryPage(q)
+}
+
+// getLimit returns the number of records to return in a single page of results,
+// using the Mastodon defaults when the requested limit is missing or too large.
+func getLimit(limit int64) int64 {
+
+	if (limit <= 0) || (limit > 40) {
+		return 20
+	}
+
+	return limit
+}
+
 // queryExpression converts data from a txn.QueryPager into an exp.Expression
 // that can be used to filter database queries.
 func queryExpression(queryPager txn.QueryPager) exp.Expression {
+	return queryExpressionByField(queryPager, "createDate")
+}
+
+// queryExpressionByField converts data from a txn.QueryPager into an exp.Expression
+// that pages through a database query using the provided field.
+func queryExpressionByField(queryPager txn.QueryPager, field string) exp.Expression {
 
 	result := exp.All()
 
@@ -61,19 +95,19 @@ func queryExpression(queryPager txn.QueryPager) exp.Expression {
 
 	if params.MinID != "" {
 		if minID, err := strconv.ParseInt(params.MinID, 10, 64); err == nil {
-			result = result.AndLessThan("createDate", minID)
+			result = result.AndLessThan(field, minID)
 		}
 	}
 
 	if params.MaxID != "" {
 		if maxID, err := strconv.ParseInt(params.MaxID, 10, 64); err == nil {
-			result = result.AndLessThan("createDate", maxID)
+			result = result.AndLessThan(field, maxID)
 		}
 	}
 
 	if params.SinceID != "" {
 		if sinceID, err := strconv.ParseInt(params.SinceID, 10, 64); err == nil {
-			result = result.AndGreaterThan("createDate", sinceID)
+			result = result.AndGreaterThan(field, sinceID)
 		}
 	}
 
@@ -114,3 +148,162 @@ func getStreamFromURL(serverFactory *server.Factory, streamURL string) (model.St
 	return stream, streamService, nil
 
 }
+
+// splitQuote removes a trailing "RE: <url>" line from a status, which is
+// how Mastodon clients without quote support ask for a quote post.
+// It returns the remaining status text and the quoted URL (if any).
+func splitQuote(status string) (string, string) {
+
+	status = strings.TrimSpace(status)
+	index := strings.LastIndex(status, "\n")
+	lastLine := strings.TrimSpace(status[index+1:])
+
+	quoteURL, found := strings.CutPrefix(lastLine, "RE:")
+
+	if !found {
+		return status, ""
+	}
+
+	quoteURL = strings.TrimSpace(quoteURL)
+
+	if parsed, err := url.Parse(quoteURL); (err != nil) || (parsed.Host == "") || strings.ContainsAny(quoteURL, " \t") {
+		return status, ""
+	}
+
+	return strings.TrimSpace(status[:max(index, 0)]), quoteURL
+}
+
+// getDocumentToot converts a remote ActivityStream document into a Mastodon Status
+func getDocumentToot(document streams.Document) object.Status {
+
+	author := document.AttributedTo()
+
+	attributedTo := model.PersonLink{
+		Name:       author.Name(),
+		ProfileURL: author.ID(),
+		IconURL:    author.IconOrImage().URL(),
+	}
+
+	return object.Status{
+		ID:          document.ID(),
+		URI:         document.ID(),
+		URL:         document.URLOrID(),
+		CreatedAt:   document.Published().Format(time.RFC3339),
+		Account:     attributedTo.Toot(),
+		Content:     document.Content(),
+		SpoilerText: document.Summary(),
+		Sensitive:   document.Get("sensitive").Bool(),
+		Visibility:  "public",
+
+		MediaAttachments: getDocumentMedia(document),
+	}
+}
+
+// getStreamToot converts a Stream into a Mastodon Status, including its media attachments
+func getStreamToot(factory *domain.Factory, stream model.Stream) (object.Status, error) {
+
+	result, err := getStreamToots(factory, []model.Stream{stream})
+
+	if err != nil {
+		return object.Status{}, err
+	}
+
+	return result[0], nil
+}
+
+// getStreamToots converts a slice of Streams into Mastodon Statuses, including their
+// media attachments.  Attachments for all Streams are loaded in a single query.
+func getStreamToots(factory *domain.Factory, streams []model.Stream) ([]object.Status, error) {
+
+	const location = "handler.mastodon.getStreamToots"
+
+	result := make([]object.Status, len(streams))
+
+	if len(streams) == 0 {
+		return result, nil
+	}
+
+	streamIDs := make([]primitive.ObjectID, len(streams))
+
+	for index, stream := range streams {
+		streamIDs[index] = stream.StreamID
+	}
+
+	criteria := exp.Equal("objectType", model.AttachmentObjectTypeStream).AndIn("objectId", streamIDs)
+	attachments, err := factory.Attachment().Query(criteria, option.SortAsc("rank"))
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error loading attachments")
+	}
+
+	for index, stream := range streams {
+
+		result[index] = stream.Toot()
+		result[index].MediaAttachments = make([]object.MediaAttachment, 0)
+
+		for _, attachment := range attachments {
+			if attachment.ObjectID == stream.StreamID {
+				result[index].MediaAttachments = append(result[index].MediaAttachments, attachment.Toot())
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// getMessageToots converts a slice of inbox Messages into Mastodon Statuses.  Media
+// attachments are read from the cached documents of Messages that have attachments.
+func getMessageToots(factory *domain.Factory, messages []model.Message) []object.Status {
+
+	result := getSliceOfToots[model.Message, object.Status](messages)
+	activityService := factory.ActivityStream()
+
+	for index, message := range messages {
+
+		if !message.HasAttachment {
+			continue
+		}
+
+		if document, err := activityService.Load(message.URL); err == nil {
+			result[index].MediaAttachments = getDocumentMedia(document)
+		}
+	}
+
+	return result
+}
+
+// getDocumentMedia converts the attachments of a remote ActivityStream document into Mastodon MediaAttachments
+func getDocumentMedia(document streams.Document) []object.MediaAttachment {
+
+	result := make([]object.MediaAttachment, 0)
+
+	for attachment := document.Attachment(); attachment.NotNil(); attachment = attachment.Tail() {
+
+		head := attachment.Head()
+		url := head.URL()
+
+		if url == "" {
+			continue
+		}
+
+		mediaType := "unknown"
+
+		switch category := list.Slash(head.MediaType()).First(); category {
+		case model.AttachmentMediaTypeAudio, model.AttachmentMediaTypeImage, model.AttachmentMediaTypeVideo:
+			mediaType = category
+		}
+
+		result = append(result, object.MediaAttachment{
+			ID:          url,
+			Type:        mediaType,
+			URL:         url,
+			PreviewURL:  url,
+			RemoteURL:   url,
+			Description: head.Name(),
+			Blurhash:    head.Get("blurhash").String(),
+			Meta:        map[string]any{},
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,70 @@
+package mastodon
+
+import (
+	"testing"
+
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitQuote(t *testing.T) {
+
+	tests := []struct {
+		status   string
+		expected string
+		quoteURL string
+	}{
+		// Trailing "RE:" lines become quotes
+		{"Look at this\nRE: https://remote.social/notes/1", "Look at this", "https://remote.social/notes/1"},
+		{"Look at this\n\nRE:https://remote.social/notes/1  ", "Look at this", "https://remote.social/notes/1"},
+		{"RE: https://remote.social/notes/1", "", "https://remote.social/notes/1"},
+
+		// Everything else is left alone
+		{"Look at this", "Look at this", ""},
+		{"RE: https://remote.social/notes/1\nLook at this", "RE: https://remote.social/notes/1\nLook at this", ""},
+		{"Look at this\nRE: the meeting", "Look at this\nRE: the meeting", ""},
+		{"Look at this\nRE: /notes/1", "Look at this\nRE: /notes/1", ""},
+		{"Look at this\nRE: https://remote.social/notes/1 and more", "Look at this\nRE: https://remote.social/notes/1 and more", ""},
+		{"", "", ""},
+	}
+
+	for _, test := range tests {
+		status, quoteURL := splitQuote(test.status)
+		require.Equal(t, test.expected, status, test.status)
+		require.Equal(t, test.quoteURL, quoteURL, test.status)
+	}
+}
+
+func TestGetDocumentMedia(t *testing.T) {
+
+	document := streams.NewDocument(mapof.Any{
+		vocab.PropertyAttachment: []any{
+			mapof.Any{
+				vocab.PropertyType:      vocab.ObjectTypeDocument,
+				vocab.PropertyMediaType: "image/jpeg",
+				vocab.PropertyURL:       "https://remote.social/media/1.jpg",
+				vocab.PropertyName:      "A cat sitting on a keyboard",
+				"blurhash":              "LEHV6nWB2yk8pyo0adR*.7kCMdnj",
+			},
+			mapof.Any{
+				vocab.PropertyType:      vocab.ObjectTypeDocument,
+				vocab.PropertyMediaType: "application/pdf",
+				vocab.PropertyURL:       "https://remote.social/media/2.pdf",
+			},
+			mapof.Any{
+				vocab.PropertyType: vocab.ObjectTypeDocument,
+			},
+		},
+	})
+
+	result := getDocumentMedia(document)
+
+	require.Equal(t, 2, len(result))
+	require.Equal(t, "image", result[0].Type)
+	require.Equal(t, "https://remote.social/media/1.jpg", result[0].URL)
+	require.Equal(t, "A cat sitting on a keyboard", result[0].Description)
+	require.Equal(t, "LEHV6nWB2yk8pyo0adR*.7kCMdnj", result[0].Blurhash)
+	require.Equal(t, "unknown", result[1].Type)
+}
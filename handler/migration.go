@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+
+	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/derp"
+	"github.com/benpate/steranko"
+	"github.com/labstack/echo/v4"
+)
+
+// GetFollowingCSV exports the signed-in User's Following records as a CSV file
+func GetFollowingCSV(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.GetFollowingCSV"
+
+	return func(ctx echo.Context) error {
+
+		sterankoContext := ctx.(*steranko.Context)
+		factory, err := serverFactory.ByContext(sterankoContext)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error loading domain factory")
+		}
+
+		authorization := getAuthorization(sterankoContext)
+
+		if !authorization.IsAuthenticated() {
+			return derp.NewUnauthorizedError(location, "Not Authorized")
+		}
+
+		// Buffer the file so that errors can still be reported with the correct status code
+		var buffer bytes.Buffer
+
+		if err := factory.Following().ExportCSV(authorization.UserID, &buffer); err != nil {
+			return derp.Wrap(err, location, "Error exporting Following records", authorization.UserID)
+		}
+
+		return writeCSV(ctx, "following.csv", buffer.Bytes())
+	}
+}
+
+// GetFollowersCSV exports the signed-in User's ActivityPub Followers as a CSV file
+func GetFollowersCSV(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.GetFollowersCSV"
+
+	return func(ctx echo.Context) error {
+
+		sterankoContext := ctx.(*steranko.Context)
+		factory, err := serverFactory.ByContext(sterankoContext)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error loading domain factory")
+		}
+
+		authorization := getAuthorization(sterankoContext)
+
+		if !authorization.IsAuthenticated() {
+			return derp.NewUnauthorizedError(location, "Not Authorized")
+		}
+
+		// Buffer the file so that errors can still be reported with the correct status code
+		var buffer bytes.Buffer
+
+		if err := factory.Follower().ExportCSV(authorization.UserID, &buffer); err != nil {
+			return derp.Wrap(err, location, "Error exporting Followers", authorization.UserID)
+		}
+
+		return writeCSV(ctx, "followers.csv", buffer.Bytes())
+	}
+}
+
+// PostFollowingCSV imports a CSV file of accounts, and follows each one that
+// the signed-in User is not already following.
+func PostFollowingCSV(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.PostFollowingCSV"
+
+	return func(ctx echo.Context) error {
+
+		sterankoContext := ctx.(*steranko.Context)
+		factory, err := serverFactory.ByContext(sterankoContext)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error loading domain factory")
+		}
+
+		authorization := getAuthorization(sterankoContext)
+
+		if !authorization.IsAuthenticated() {
+			return derp.NewUnauthorizedError(location, "Not Authorized")
+		}
+
+		// Read the uploaded file
+		fileHeader, err := ctx.FormFile("file")
+
+		if err != nil {
+			return derp.Wrap(err, location, "Missing CSV file", derp.WithBadRequest())
+		}
+
+		file, err := fileHeader.Open()
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error opening CSV file")
+		}
+
+		defer file.Close()
+
+		// Import the file into the User's Following records
+		count, err := factory.Following().ImportCSV(authorization.UserID, file)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error importing CSV file", authorization.UserID)
+		}
+
+		message := "Importing " + strconv.Itoa(count) + " accounts"
+
+		// Close the modal and refresh the page
+		ctx.Response().Header().Set("HX-Trigger", `{"closeModal":true, "refreshPage":true}`)
+		return ctx.String(http.StatusOK, message)
+	}
+}
+
+// writeCSV writes a downloadable CSV file to the response
+func writeCSV(ctx echo.Context, filename string, content []byte) error {
+	ctx.Response().Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	return ctx.Blob(http.StatusOK, "text/csv; charset=utf-8", content)
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/derp"
+	"github.com/benpate/steranko"
+	"github.com/labstack/echo/v4"
+)
+
+// GetFollowingOPML exports the signed-in User's Following records as an OPML file
+func GetFollowingOPML(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.GetFollowingOPML"
+
+	return func(ctx echo.Context) error {
+
+		sterankoContext := ctx.(*steranko.Context)
+		factory, err := serverFactory.ByContext(sterankoContext)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error loading domain factory")
+		}
+
+		authorization := getAuthorization(sterankoContext)
+
+		if !authorization.IsAuthenticated() {
+			return derp.NewUnauthorizedError(location, "Not Authorized")
+		}
+
+		user := model.NewUser()
+		if err := factory.User().LoadByID(authorization.UserID, &user); err != nil {
+			return derp.Wrap(err, location, "Error loading User", authorization.UserID)
+		}
+
+		writeOPMLHeaders(ctx, "following.opml", true)
+
+		if err := factory.Following().ExportOPML(&user, false, ctx.Response().Writer); err != nil {
+			return derp.Wrap(err, location, "Error exporting Following records", authorization.UserID)
+		}
+
+		return nil
+	}
+}
+
+// PostFollowingOPML imports an OPML file of feeds, and follows each one that
+// the signed-in User is not already following.
+func PostFollowingOPML(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.PostFollowingOPML"
+
+	return func(ctx echo.Context) error {
+
+		sterankoContext := ctx.(*steranko.Context)
+		factory, err := serverFactory.ByContext(sterankoContext)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error loading domain factory")
+		}
+
+		authorization := getAuthorization(sterankoContext)
+
+		if !authorization.IsAuthenticated() {
+			return derp.NewUnauthorizedError(location, "Not Authorized")
+		}
+
+		// Read the uploaded file
+		fileHeader, err := ctx.FormFile("file")
+
+		if err != nil {
+			return derp.Wrap(err, location, "Missing OPML file", derp.WithBadRequest())
+		}
+
+		file, err := fileHeader.Open()
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error opening OPML file")
+		}
+
+		defer file.Close()
+
+		// Import the file into the User's Following records
+		count, err := factory.Following().ImportOPML(authorization.UserID, file)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error importing OPML file", authorization.UserID)
+		}
+
+		// Close the modal and refresh the page
+		ctx.Response().Header().Set("HX-Trigger", `{"closeModal":true, "refreshPage":true}`)
+		return ctx.String(http.StatusOK, "Importing "+strconv.Itoa(count)+" feeds")
+	}
+}
+
+// GetBlogrollOPML publishes a User's public Following records as an OPML blogroll
+func GetBlogrollOPML(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.GetBlogrollOPML"
+
+	return func(ctx echo.Context) error {
+
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error loading domain factory")
+		}
+
+		user := model.NewUser()
+		if err := factory.User().LoadByToken(ctx.Param("userId"), &user); err != nil {
+			return derp.Wrap(err, location, "Error loading User", ctx.Param("userId"))
+		}
+
+		// RULE: Private profiles do not publish a blogroll
+		if !user.IsPublic {
+			return derp.NewNotFoundError(location, "User not found", ctx.Param("userId"))
+		}
+
+		writeOPMLHeaders(ctx, "blogroll.opml", false)
+
+		if err := factory.Following().ExportOPML(&user, true, ctx.Response().Writer); err != nil {
+			return derp.Wrap(err, location, "Error exporting blogroll", user.UserID)
+		}
+
+		return nil
+	}
+}
+
+// writeOPMLHeaders sets the HTTP headers for an OPML file.
+// Downloads are sent as attachments, while blogrolls are displayed inline.
+func writeOPMLHeaders(ctx echo.Context, filename string, download bool) {
+	header := ctx.Response().Header()
+	header.Set("Content-Type", "text/x-opml; charset=utf-8")
+
+	if download {
+		header.Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	}
+
+	ctx.Response().WriteHeader(http.StatusOK)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/benpate/derp"
 	"github.com/benpate/mediaserver"
 	"github.com/benpate/rosetta/first"
+	"github.com/benpate/rosetta/list"
 	"github.com/benpate/steranko"
 	"github.com/labstack/echo/v4"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -48,6 +49,79 @@ func GetProfileImage(serverFactory *server.Factory) echo.HandlerFunc {
 	return getProfileAttachment(serverFactory, "imageId", filespec)
 }
 
+// GetProfileMedia serves files that a User has uploaded through the Mastodon media API
+// but has not yet attached to a Stream.
+func GetProfileMedia(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.outbox.GetProfileMedia"
+
+	return func(ctx echo.Context) error {
+
+		// Cast the context into a steranko context (which includes authentication data)
+		sterankoContext := ctx.(*steranko.Context)
+
+		// Get the Domain factory from the context
+		factory, err := serverFactory.ByContext(sterankoContext)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error loading domain factory")
+		}
+
+		// Load the User from the database
+		userService := factory.User()
+		user := model.NewUser()
+
+		username, err := profileUsername(sterankoContext)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error loading user ID")
+		}
+
+		if err := userService.LoadByToken(username, &user); err != nil {
+			return derp.Wrap(err, location, "Error loading user", username)
+		}
+
+		if !isUserVisible(sterankoContext, &user) {
+			return derp.NewNotFoundError(location, "User not found")
+		}
+
+		// Load the Attachment from the database
+		attachmentIDString := list.Dot(ctx.Param("attachment")).First()
+		attachmentID, err := primitive.ObjectIDFromHex(attachmentIDString)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Invalid attachmentID", attachmentIDString, derp.WithNotFound())
+		}
+
+		attachment := model.NewAttachment(model.AttachmentObjectTypeUser, user.UserID)
+		if err := factory.Attachment().LoadByID(model.AttachmentObjectTypeUser, user.UserID, attachmentID, &attachment); err != nil {
+			return derp.Wrap(err, location, "Error loading attachment")
+		}
+
+		if attachment.Category != model.AttachmentCategoryMedia {
+			return derp.NewNotFoundError(location, "Attachment not found", attachmentID)
+		}
+
+		if !attachment.IsReady() {
+			return derp.NewNotFoundError(location, "Attachment is still being processed", attachmentID)
+		}
+
+		// Retrieve the file from the mediaserver
+		ms := factory.MediaServer()
+		filespec := ms.FileSpec(ctx.Request().URL, attachment.DownloadExtension())
+
+		header := ctx.Response().Header()
+		header.Set("Mime-Type", filespec.MimeType)
+		header.Set("Cache-Control", "private")
+
+		if err := ms.Get(filespec, ctx.Response().Writer); err != nil {
+			return derp.Wrap(err, location, "Error accessing media file")
+		}
+
+		return nil
+	}
+}
+
 func getProfileAttachment(serverFactory *server.Factory, field string, filespec mediaserver.FileSpec) echo.HandlerFunc {
 
 	const location = "handler.outbox.getProfileAttachment"
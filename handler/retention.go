@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"html"
+	"net/http"
+	"strconv"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/derp"
+	"github.com/benpate/steranko"
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetRetentionReport returns a dry-run report of the inbox Messages that the current
+// retention policies would purge from a single Following (followingId) or a whole Folder (folderId).
+func GetRetentionReport(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.GetRetentionReport"
+
+	return func(ctx echo.Context) error {
+
+		sterankoContext := ctx.(*steranko.Context)
+		factory, err := serverFactory.ByContext(sterankoContext)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error loading domain factory")
+		}
+
+		authorization := getAuthorization(sterankoContext)
+
+		if !authorization.IsAuthenticated() {
+			return derp.NewUnauthorizedError(location, "Not Authorized")
+		}
+
+		followingService := factory.Following()
+		report := model.RetentionReport{}
+
+		if token := ctx.QueryParam("followingId"); token != "" {
+
+			following := model.NewFollowing()
+			if err := followingService.LoadByToken(authorization.UserID, token, &following); err != nil {
+				return derp.Wrap(err, location, "Error loading Following", token)
+			}
+
+			if report, err = followingService.PurgeInbox(following, true); err != nil {
+				return derp.Wrap(err, location, "Error calculating retention report", token)
+			}
+
+		} else {
+
+			folderID, err := primitive.ObjectIDFromHex(ctx.QueryParam("folderId"))
+
+			if err != nil {
+				return derp.Wrap(err, location, "Invalid folderId", ctx.QueryParam("folderId"), derp.WithBadRequest())
+			}
+
+			if report, err = followingService.RetentionReportByFolder(authorization.UserID, folderID); err != nil {
+				return derp.Wrap(err, location, "Error calculating retention report", folderID)
+			}
+		}
+
+		return ctx.HTML(http.StatusOK, retentionReportHTML(report))
+	}
+}
+
+// retentionReportHTML formats a RetentionReport as a short, human-readable summary
+func retentionReportHTML(report model.RetentionReport) string {
+
+	if report.Total() == 0 {
+		return html.EscapeString("No messages are ready to be purged.")
+	}
+
+	result := strconv.Itoa(report.Total()) + " messages will be purged soon (" +
+		strconv.Itoa(report.Read) + " read, " +
+		strconv.Itoa(report.Unread) + " unread)."
+
+	if report.Kept > 0 {
+		result += " " + strconv.Itoa(report.Kept) + " older messages are kept because you responded to them."
+	}
+
+	return html.EscapeString(result)
+}
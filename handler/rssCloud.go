@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/server"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/derp"
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetRSSCloudClient is called by an external RSSCloud server to verify a registration request.
+// https://www.rssboard.org/rsscloud-interface
+func GetRSSCloudClient(serverFactory *server.Factory) echo.HandlerFunc {
+
+	return func(ctx echo.Context) error {
+
+		const location = "handler.GetRSSCloudClient"
+
+		following, _, err := getRSSCloudFollowing(serverFactory, ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error loading following record")
+		}
+
+		// RULE: Require that the feed URL matches this Following
+		if ctx.QueryParam("url") != following.FeedURL {
+			return derp.NewNotFoundError(location, "Invalid RSSCloud feed URL", following, ctx.QueryParam("url"))
+		}
+
+		// Win!
+		return ctx.String(http.StatusOK, ctx.QueryParam("challenge"))
+	}
+}
+
+// PostRSSCloudClient is called by an external RSSCloud server to notify us that a feed has changed.
+func PostRSSCloudClient(serverFactory *server.Factory) echo.HandlerFunc {
+
+	return func(ctx echo.Context) error {
+
+		const location = "handler.PostRSSCloudClient"
+
+		following, followingService, err := getRSSCloudFollowing(serverFactory, ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error loading following record")
+		}
+
+		// RULE: Require that the feed URL matches this Following
+		if ctx.FormValue("url") != following.FeedURL {
+			return derp.NewNotFoundError(location, "Invalid RSSCloud feed URL", following, ctx.FormValue("url"))
+		}
+
+		// RULE: Registrations are verified with a test notification before they are saved,
+		// so only reload the feed for Following records that are already using RSSCloud.
+		if following.Method == model.FollowingMethodRSSCloud {
+
+			// RSSCloud notifications only include the feed URL, so reload the feed
+			if err := followingService.Connect(following); err != nil {
+				return derp.Wrap(err, location, "Error connecting to following", following)
+			}
+		}
+
+		// Woot woot!
+		return ctx.NoContent(http.StatusOK)
+	}
+}
+
+// getRSSCloudFollowing loads the Following record identified in an RSSCloud callback URL
+func getRSSCloudFollowing(serverFactory *server.Factory, ctx echo.Context) (model.Following, *service.Following, error) {
+
+	const location = "handler.getRSSCloudFollowing"
+
+	following := model.NewFollowing()
+
+	// Get the factory for this domain
+	factory, err := serverFactory.ByContext(ctx)
+
+	if err != nil {
+		return following, nil, derp.Wrap(err, location, "Error loading server factory")
+	}
+
+	// Parse the UserID from the URL
+	userID, err := primitive.ObjectIDFromHex(ctx.Param("userId"))
+
+	if err != nil {
+		return following, nil, derp.Wrap(err, location, "Invalid UserID", ctx.Param("userId"))
+	}
+
+	// Parse the FollowingID from the URL
+	followingID, err := primitive.ObjectIDFromHex(ctx.Param("followingId"))
+
+	if err != nil {
+		return following, nil, derp.Wrap(err, location, "Invalid FollowingID", ctx.Param("followingId"))
+	}
+
+	// Load the following record from the database
+	followingService := factory.Following()
+
+	if err := followingService.LoadByID(userID, followingID, &following); err != nil {
+		return following, nil, derp.Wrap(err, location, "Error loading following record", userID, followingID)
+	}
+
+	return following, followingService, nil
+}
@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/EmissarySocial/emissary/domain"
+	ap_stream "github.com/EmissarySocial/emissary/handler/activitypub_stream"
+	ap_user "github.com/EmissarySocial/emissary/handler/activitypub_user"
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/inbox"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PostSharedInbox receives ActivityPub activities that are addressed to any number of
+// local Users and Streams.  The request signature is verified once, then the activity is
+// delivered to every local actor that it addresses, and every User who follows the sender.
+func PostSharedInbox(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.PostSharedInbox"
+
+	return func(ctx echo.Context) error {
+
+		// Find the factory for this hostname
+		factory, err := serverFactory.ByContext(ctx)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Invalid Domain")
+		}
+
+		// RULE: Reject requests from suspended domains before verifying signatures
+		domainPolicyService := factory.DomainPolicy()
+
+		if err := domainPolicyService.AllowRequest(ctx.Request()); err != nil {
+			return derp.Wrap(err, location, "Request Not Accepted")
+		}
+
+		// Retrieve the activity from the request body (this also validates the HTTP signature)
+		activity, err := inbox.ReceiveRequest(ctx.Request(), factory.ActivityStream())
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error parsing ActivityPub request")
+		}
+
+		// RULE: Reject activities whose actor or object is on a suspended domain
+		if err := domainPolicyService.AllowActivity(activity); err != nil {
+			return derp.Wrap(err, location, "Activity Not Accepted")
+		}
+
+		log.Info().Str("host", factory.Host()).Str("activity", activity.ID()).Msg("Shared Inbox: Received new activity")
+
+		userIDs, streamIDs := sharedInboxRecipients(factory, activity)
+
+		// Deliver the activity to each User
+		for _, userID := range userIDs {
+			if err := sharedInboxDeliverUser(factory, userID, activity); err != nil {
+				derp.Report(derp.Wrap(err, location, "Error delivering activity to User", userID.Hex(), activity.ID()))
+			}
+		}
+
+		// Deliver the activity to each Stream
+		for _, streamID := range streamIDs {
+			if err := sharedInboxDeliverStream(factory, streamID, activity); err != nil {
+				derp.Report(derp.Wrap(err, location, "Error delivering activity to Stream", streamID.Hex(), activity.ID()))
+			}
+		}
+
+		// Send the response to the client
+		return ctx.String(http.StatusOK, "")
+	}
+}
+
+// sharedInboxRecipients returns the unique IDs of all local Users and Streams that should
+// receive the provided activity.  This includes every local actor that is addressed by the
+// activity (or its object) and every User who follows the activity's actor.
+func sharedInboxRecipients(factory *domain.Factory, activity streams.Document) ([]primitive.ObjectID, []primitive.ObjectID) {
+
+	const location = "handler.sharedInboxRecipients"
+
+	userService := factory.User()
+	streamService := factory.Stream()
+	host := factory.Host()
+
+	recipients := newSharedInboxRecipientSet()
+
+	// Map every local address onto a User or a Stream
+	for _, address := range sharedInboxAddresses(activity) {
+
+		if !strings.HasPrefix(address, host+"/") {
+			continue
+		}
+
+		if userID, err := userService.ParseProfileURL(address); err == nil {
+			recipients.AddUser(userID)
+			continue
+		}
+
+		// Local Streams receive the activity, along with the User who wrote them
+		stream := model.NewStream()
+		if err := streamService.LoadByURL(address, &stream); err == nil {
+			recipients.AddStream(stream.StreamID)
+
+			recipients.AddUser(stream.AttributedTo.UserID)
+		}
+	}
+
+	// RULE: Only broadcast activities are delivered to the sender's followers.
+	// Targeted activities (Follow, Accept, Block, etc.) only go to the actors they address.
+	switch activity.Type() {
+	case vocab.ActivityTypeCreate, vocab.ActivityTypeUpdate, vocab.ActivityTypeDelete, vocab.ActivityTypeAnnounce, vocab.ActivityTypeMove:
+	default:
+		return recipients.userIDs, recipients.streamIDs
+	}
+
+	// Add every User who follows the sender
+	if actorID := activity.Actor().ID(); actorID != "" {
+
+		followings, err := factory.Following().QueryActivityPubByProfileURL(actorID)
+
+		if err != nil {
+			derp.Report(derp.Wrap(err, location, "Error loading followers of actor", actorID))
+		}
+
+		for _, following := range followings {
+			recipients.AddUser(following.UserID)
+		}
+	}
+
+	return recipients.userIDs, recipients.streamIDs
+}
+
+// sharedInboxRecipientSet collects the unique Users and Streams that receive an activity
+type sharedInboxRecipientSet struct {
+	userIDs   []primitive.ObjectID
+	streamIDs []primitive.ObjectID
+	seen      map[primitive.ObjectID]bool
+}
+
+func newSharedInboxRecipientSet() sharedInboxRecipientSet {
+	return sharedInboxRecipientSet{
+		userIDs:   make([]primitive.ObjectID, 0),
+		streamIDs: make([]primitive.ObjectID, 0),
+		seen:      make(map[primitive.ObjectID]bool),
+	}
+}
+
+// AddUser adds a User to the set, unless it has already been added
+func (set *sharedInboxRecipientSet) AddUser(userID primitive.ObjectID) {
+	if set.add(userID) {
+		set.userIDs = append(set.userIDs, userID)
+	}
+}
+
+// AddStream adds a Stream to the set, unless it has already been added
+func (set *sharedInboxRecipientSet) AddStream(streamID primitive.ObjectID) {
+	if set.add(streamID) {
+		set.streamIDs = append(set.streamIDs, streamID)
+	}
+}
+
+// add returns TRUE if the ID has not been seen before
+func (set *sharedInboxRecipientSet) add(id primitive.ObjectID) bool {
+
+	if id.IsZero() || set.seen[id] {
+		return false
+	}
+
+	set.seen[id] = true
+	return true
+}
+
+// sharedInboxAddresses returns all of the actor IDs that are addressed by an activity,
+// including the addresses and authors of its embedded object.
+func sharedInboxAddresses(activity streams.Document) []string {
+
+	result := make([]string, 0)
+
+	collect := func(document streams.Document) {
+		for item := range document.Channel() {
+			if id := item.ID(); (id != "") && (id != vocab.NamespaceActivityStreamsPublic) {
+				result = append(result, id)
+			}
+		}
+	}
+
+	collectAddresses := func(document streams.Document) {
+		collect(document.To())
+		collect(document.CC())
+		collect(document.BTo())
+		collect(document.BCC())
+		collect(document.Audience())
+	}
+
+	collectAddresses(activity)
+
+	// The object itself may be a local actor (Follow) or a local document (Like, Announce)
+	object := activity.Object()
+	collect(object)
+
+	// Only inspect embedded objects, so that we don't make network requests for linked ones.
+	if object.IsMap() {
+		collectAddresses(object)
+		collect(object.AttributedTo())
+		collect(object.Object()) // e.g. Undo{Follow{Actor}}
+	}
+
+	return result
+}
+
+// sharedInboxDeliverUser routes an activity into the inbox of a single local User
+func sharedInboxDeliverUser(factory *domain.Factory, userID primitive.ObjectID, activity streams.Document) error {
+
+	const location = "handler.sharedInboxDeliverUser"
+
+	user := model.NewUser()
+	if err := factory.User().LoadByID(userID, &user); err != nil {
+		return derp.Wrap(err, location, "Error loading User", userID)
+	}
+
+	// RULE: Only public users have ActivityPub inboxes
+	if !user.IsPublic {
+		return nil
+	}
+
+	return ap_user.HandleActivity(factory, &user, activity)
+}
+
+// sharedInboxDeliverStream routes an activity into the inbox of a single local Stream
+func sharedInboxDeliverStream(factory *domain.Factory, streamID primitive.ObjectID, activity streams.Document) error {
+
+	const location = "handler.sharedInboxDeliverStream"
+
+	stream := model.NewStream()
+	if err := factory.Stream().LoadByID(streamID, &stream); err != nil {
+		return derp.Wrap(err, location, "Error loading Stream", streamID)
+	}
+
+	template, err := factory.Template().Load(stream.TemplateID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Invalid Template", stream.TemplateID)
+	}
+
+	// RULE: Only Streams with an Actor have ActivityPub inboxes
+	actor := template.Actor
+
+	if actor.IsNil() {
+		return nil
+	}
+
+	return ap_stream.HandleActivity(factory, &stream, &actor, activity)
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSharedInboxRecipientSet(t *testing.T) {
+
+	alice := primitive.NewObjectID()
+	bob := primitive.NewObjectID()
+	stream := primitive.NewObjectID()
+
+	recipients := newSharedInboxRecipientSet()
+
+	// Alice is addressed directly, and also writes the addressed Stream
+	recipients.AddUser(alice)
+	recipients.AddStream(stream)
+	recipients.AddUser(alice)
+
+	// Alice and Bob both follow the sender
+	recipients.AddUser(bob)
+	recipients.AddUser(alice)
+	recipients.AddUser(bob)
+
+	// Streams without authors and duplicate Streams are ignored
+	recipients.AddUser(primitive.NilObjectID)
+	recipients.AddStream(stream)
+
+	require.Equal(t, []primitive.ObjectID{alice, bob}, recipients.userIDs)
+	require.Equal(t, []primitive.ObjectID{stream}, recipients.streamIDs)
+}
+
+func TestSharedInboxAddresses(t *testing.T) {
+
+	activity := streams.NewDocument(mapof.Any{
+		vocab.PropertyType:  vocab.ActivityTypeCreate,
+		vocab.PropertyActor: "https://remote.social/users/sender",
+		vocab.PropertyTo:    []any{vocab.NamespaceActivityStreamsPublic, "https://local.social/@alice"},
+		vocab.PropertyCC:    "https://remote.social/users/sender/followers",
+		vocab.PropertyObject: mapof.Any{
+			vocab.PropertyID:           "https://remote.social/notes/1",
+			vocab.PropertyType:         vocab.ObjectTypeNote,
+			vocab.PropertyAttributedTo: "https://remote.social/users/sender",
+			vocab.PropertyTo:           "https://local.social/@alice",
+			vocab.PropertyCC:           "https://local.social/@bob",
+		},
+	})
+
+	require.Equal(t, []string{
+		"https://local.social/@alice",
+		"https://remote.social/users/sender/followers",
+		"https://remote.social/notes/1",
+		"https://local.social/@alice",
+		"https://local.social/@bob",
+		"https://remote.social/users/sender",
+	}, sharedInboxAddresses(activity))
+}
+
+func TestSharedInboxAddresses_LinkedObject(t *testing.T) {
+
+	// Linked objects are only addressed by their ID, and are never loaded
+	activity := streams.NewDocument(mapof.Any{
+		vocab.PropertyType:   vocab.ActivityTypeLike,
+		vocab.PropertyActor:  "https://remote.social/users/sender",
+		vocab.PropertyObject: "https://local.social/@alice/pub/1",
+	})
+
+	require.Equal(t, []string{"https://local.social/@alice/pub/1"}, sharedInboxAddresses(activity))
+}
@@ -27,7 +27,12 @@ func GetWebfinger(fm *server.Factory) echo.HandlerFunc {
 
 		resourceID := ctx.QueryParam("resource")
 
-		// Look for Users first
+		// Look for the Instance Actor first
+		if resource, err := factory.InstanceActor().LoadWebFinger(resourceID); err == nil {
+			return writeResource(ctx, resource)
+		}
+
+		// Next, look for Users
 		if resource, err := factory.User().LoadWebFinger(resourceID); err == nil {
 			return writeResource(ctx, resource)
 		}
@@ -1,7 +1,8 @@
 package handler
 
 import (
-	"bytes"
+	"encoding/hex"
+	"io"
 	"net/http"
 
 	"github.com/EmissarySocial/emissary/model"
@@ -97,11 +98,11 @@ func PostWebSubClient(serverFactory *server.Factory) echo.HandlerFunc {
 
 	return func(ctx echo.Context) error {
 
-		const location = "handler.GetWebSubClient"
+		const location = "handler.PostWebSubClient"
 
-		var body bytes.Buffer
+		body, err := io.ReadAll(ctx.Request().Body)
 
-		if err := ctx.Bind(&body); err != nil {
+		if err != nil {
 			return derp.Wrap(err, location, "Error reading request body")
 		}
 
@@ -139,19 +140,31 @@ func PostWebSubClient(serverFactory *server.Factory) echo.HandlerFunc {
 			return derp.NewBadRequestError(location, "Not a WebSub follow", following)
 		}
 
-		// Validate the HMAC signature
-		if following.Secret != "" {
-			header := ctx.Request().Header.Get("X-Hub-Signature")
-			method, signature := list.Equal(header).Split()
+		// Validate the HMAC signature.  Unsigned pushes cannot be trusted, so they are rejected
+		// too. Per the WebSub spec, invalid messages are acknowledged (so that the hub does not
+		// retry) but otherwise ignored.
+		// https://www.w3.org/TR/websub/#signature-validation
+		if following.Secret == "" {
+			return ctx.NoContent(http.StatusOK)
+		}
 
-			hmac.Validate(method, following.Secret, body.Bytes(), signature.Bytes())
+		header := ctx.Request().Header.Get("X-Hub-Signature")
+		method, signature := list.Equal(header).Split()
+		signatureBytes, err := hex.DecodeString(signature.String())
+
+		if (err != nil) || !hmac.Validate(method, following.Secret, body, signatureBytes) {
+			return ctx.NoContent(http.StatusOK)
 		}
 
-		// TODO: MEDIUM: WebSub - Handle FatPings.
-		// Right now, we re-poll the entire feed. But we could save a round-trip by
-		// inspecting the body and parsing any additional data that's been "Fat Ping-ed" to us.
+		// Import "fat pings" directly from the request body
+		if ok, err := followingService.ReceiveFatPing(following, body); ok {
+			if err != nil {
+				return derp.Wrap(err, location, "Error receiving WebSub message", following)
+			}
+			return ctx.NoContent(http.StatusOK)
+		}
 
-		// Connect to the the WebSub server
+		// Otherwise, this is a "thin ping" so reload the feed from the WebSub server
 		if err := followingService.Connect(following); err != nil {
 			return derp.Wrap(err, location, "Error connecting to following", following)
 		}
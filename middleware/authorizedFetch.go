@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/derp"
+	"github.com/labstack/echo/v4"
+)
+
+// AuthorizedFetch middleware requires a valid HTTP signature from an allowed
+// actor when the requested domain has enabled "authorized fetch".
+func AuthorizedFetch(factory *server.Factory) echo.MiddlewareFunc {
+
+	const location = "middleware.AuthorizedFetch"
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+
+		return func(ctx echo.Context) error {
+
+			domainFactory, err := factory.ByContext(ctx)
+
+			if err != nil {
+				return derp.Wrap(err, location, "Unrecognized domain name")
+			}
+
+			if err := domainFactory.AuthorizedFetch().Require(ctx.Request()); err != nil {
+				return derp.Wrap(err, location, "Request is not authorized")
+			}
+
+			return next(ctx)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package model
 
-import "github.com/benpate/domain"
+import (
+	"net/url"
+
+	"github.com/benpate/domain"
+)
 
 // ActorSummary is a record returned by the ActivityStream directory
 type ActorSummary struct {
@@ -18,3 +22,15 @@ func (actor ActorSummary) UsernameOrID() string {
 
 	return actor.ID
 }
+
+// ActivityPubSharedInboxURL returns the URL of the shared inbox for the server that hosts the provided actor
+func ActivityPubSharedInboxURL(actorURL string) string {
+
+	parsed, err := url.Parse(actorURL)
+
+	if (err != nil) || (parsed.Host == "") {
+		return ""
+	}
+
+	return parsed.Scheme + "://" + parsed.Host + "/pub/shared-inbox"
+}
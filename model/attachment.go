@@ -8,25 +8,35 @@ import (
 	"github.com/benpate/data/journal"
 	"github.com/benpate/hannibal/vocab"
 	"github.com/benpate/rosetta/list"
+	"github.com/benpate/toot/object"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // Attachment represents a file that has been uploaded to the software
 type Attachment struct {
-	AttachmentID primitive.ObjectID `bson:"_id"`         // ID of this Attachment
-	ObjectID     primitive.ObjectID `bson:"objectId"`    // ID of the Stream that owns this Attachment
-	ObjectType   string             `bson:"objectType"`  // Type of object that owns this Attachment
-	Original     string             `bson:"original"`    // Original filename uploaded by user
-	MediaType    string             `bson:"mediaType"`   // MIME type of the file
-	Category     string             `bson:"category"`    // Category of the file (defined by the Template)
-	Label        string             `bson:"label"`       // User-defined label for the attachment
-	Description  string             `bson:"description"` // User-defined description for the attachment
-	URL          string             `bson:"url"`         // URL where the file is stored
-	Status       string             `bson:"status"`      // Status of the attachment (READY, WORKING)
-	Height       int                `bson:"height"`      // Height of the media file (if applicable)
-	Width        int                `bson:"width"`       // Width of the media file (if applicable)
-	Duration     int                `bbson:"duration"`   // Duration of the media file (if applicable)
-	Rank         int                `bson:"rank"`        // The sort order to display the attachments in.
+	AttachmentID  primitive.ObjectID `bson:"_id"`           // ID of this Attachment
+	UserID        primitive.ObjectID `bson:"userId"`        // ID of the User who uploaded this Attachment (counts against their storage quota)
+	ObjectID      primitive.ObjectID `bson:"objectId"`      // ID of the Stream that owns this Attachment
+	ObjectType    string             `bson:"objectType"`    // Type of object that owns this Attachment
+	Original      string             `bson:"original"`      // Original filename uploaded by user
+	MediaType     string             `bson:"mediaType"`     // MIME type of the file
+	Category      string             `bson:"category"`      // Category of the file (defined by the Template)
+	Label         string             `bson:"label"`         // User-defined label for the attachment
+	Description   string             `bson:"description"`   // User-defined description for the attachment
+	AltText       string             `bson:"altText"`       // Alternate text that describes the attachment for screen readers
+	Blurhash      string             `bson:"blurhash"`      // BlurHash placeholder for images and video posters
+	URL           string             `bson:"url"`           // URL where the file is stored
+	Status        string             `bson:"status"`        // Status of the attachment (READY, WORKING)
+	Height        int                `bson:"height"`        // Height of the media file (if applicable)
+	Width         int                `bson:"width"`         // Width of the media file (if applicable)
+	Duration      int                `bson:"duration"`      // Duration of the media file in seconds (if applicable)
+	PosterURL     string             `bson:"posterUrl"`     // URL of a poster image for video files (if applicable)
+	Rendition     string             `bson:"rendition"`     // File extension of the web-safe rendition created by the transcoder (if applicable)
+	FocusX        float64            `bson:"focusX"`        // Horizontal focal point used when cropping previews (-1.0 to 1.0)
+	FocusY        float64            `bson:"focusY"`        // Vertical focal point used when cropping previews (-1.0 to 1.0)
+	Rank          int                `bson:"rank"`          // The sort order to display the attachments in.
+	Size          int64              `bson:"size"`          // Size of the original file (in bytes)
+	RenditionSize int64              `bson:"renditionSize"` // Size of the web-safe rendition (in bytes) if applicable
 
 	journal.Journal `json:"-" bson:",inline"` // Journal entry for fetch compatability
 }
@@ -56,6 +66,11 @@ func (attachment *Attachment) ID() string {
 func (attachment *Attachment) CalcURL(host string) string {
 
 	if attachment.ObjectType == AttachmentObjectTypeUser {
+
+		if attachment.Category == AttachmentCategoryMedia {
+			return host + "/@" + attachment.ObjectID.Hex() + "/media/" + attachment.AttachmentID.Hex()
+		}
+
 		return host + "/@" + attachment.ObjectID.Hex() + "/pub/icon/" + attachment.AttachmentID.Hex()
 	}
 
@@ -64,6 +79,11 @@ func (attachment *Attachment) CalcURL(host string) string {
 
 func (attachment *Attachment) SetURL(host string) {
 	attachment.URL = attachment.CalcURL(host)
+
+	// Keep the poster frame in sync with the file's current location
+	if attachment.PosterURL != "" {
+		attachment.PosterURL = attachment.URL + ".webp"
+	}
 }
 
 func (attachment *Attachment) DownloadExtension() string {
@@ -75,6 +95,12 @@ func (attachment *Attachment) DownloadExtension() string {
 		return ".webp"
 	}
 
+	// Video and audio files are downloaded in whatever format the
+	// transcoder produced, or in their original format if it did not run.
+	if attachment.Rendition != "" {
+		return attachment.Rendition
+	}
+
 	return ext
 }
 
@@ -82,6 +108,17 @@ func (attachment *Attachment) DownloadMimeType() string {
 	return mime.TypeByExtension(attachment.DownloadExtension())
 }
 
+// DownloadSize returns the size (in bytes) of the file that is downloaded, which
+// is the transcoded rendition if one exists, or the original file otherwise.
+func (attachment *Attachment) DownloadSize() int64 {
+
+	if (attachment.Rendition != "") && (attachment.RenditionSize > 0) {
+		return attachment.RenditionSize
+	}
+
+	return attachment.Size
+}
+
 // OriginalExtension returns the file extension of the original filename
 func (attachment *Attachment) OriginalExtension() string {
 	return "." + list.Dot(attachment.Original).Last()
@@ -96,6 +133,60 @@ func (attachment *Attachment) MimeCategory() string {
 	return list.Slash(attachment.MimeType()).First()
 }
 
+// CalcMediaType returns the AttachmentMediaType that matches the original file
+func (attachment *Attachment) CalcMediaType() string {
+
+	switch category := attachment.MimeCategory(); category {
+	case AttachmentMediaTypeAudio, AttachmentMediaTypeImage, AttachmentMediaTypeVideo:
+		return category
+	}
+
+	return AttachmentMediaTypeDocument
+}
+
+// IsVideo returns TRUE if this Attachment is a video file
+func (attachment *Attachment) IsVideo() bool {
+	return attachment.MimeCategory() == AttachmentMediaTypeVideo
+}
+
+// IsAudio returns TRUE if this Attachment is an audio file
+func (attachment *Attachment) IsAudio() bool {
+	return attachment.MimeCategory() == AttachmentMediaTypeAudio
+}
+
+// SetFocus parses a Mastodon focal point ("x,y") and applies it to this Attachment.
+// Values outside of the -1.0 to 1.0 range are ignored.
+func (attachment *Attachment) SetFocus(value string) bool {
+
+	x, y, found := strings.Cut(value, ",")
+
+	if !found {
+		return false
+	}
+
+	focusX, err := strconv.ParseFloat(strings.TrimSpace(x), 64)
+
+	if (err != nil) || (focusX < -1) || (focusX > 1) {
+		return false
+	}
+
+	focusY, err := strconv.ParseFloat(strings.TrimSpace(y), 64)
+
+	if (err != nil) || (focusY < -1) || (focusY > 1) {
+		return false
+	}
+
+	attachment.FocusX = focusX
+	attachment.FocusY = focusY
+	return true
+}
+
+// IsReady returns TRUE if this Attachment has finished processing
+// and can be downloaded.
+func (attachment *Attachment) IsReady() bool {
+	return attachment.Status != AttachmentStatusWorking
+}
+
 func (attachment *Attachment) AspectRatio() string {
 
 	if attachment.Width == 0 {
@@ -120,7 +211,7 @@ func (attachment Attachment) HasDimensions() bool {
 func (attachment Attachment) JSONLD() map[string]any {
 
 	result := map[string]any{
-		vocab.PropertyType:      vocab.ObjectTypeImage, // TODO: Expand this to videos, audios, etc.
+		vocab.PropertyType:      attachment.ActivityPubType(),
 		vocab.PropertyMediaType: attachment.DownloadMimeType(),
 		vocab.PropertyURL:       attachment.URL,
 	}
@@ -130,8 +221,93 @@ func (attachment Attachment) JSONLD() map[string]any {
 		result["height"] = attachment.Height
 	}
 
-	// TODO: Alt Text
-	// TODO: Blurhash
+	if attachment.Duration > 0 {
+		result[vocab.PropertyDuration] = "PT" + strconv.Itoa(attachment.Duration) + "S"
+	}
+
+	if attachment.PosterURL != "" {
+		result[vocab.PropertyIcon] = map[string]any{
+			vocab.PropertyType:      vocab.ObjectTypeImage,
+			vocab.PropertyMediaType: "image/webp",
+			vocab.PropertyURL:       attachment.PosterURL,
+		}
+	}
+
+	if attachment.AltText != "" {
+		result[vocab.PropertyName] = attachment.AltText
+	}
+
+	if attachment.Blurhash != "" {
+		result["blurhash"] = attachment.Blurhash
+	}
+
+	return result
+}
+
+// ActivityPubType returns the ActivityPub object type that best describes this Attachment
+func (attachment Attachment) ActivityPubType() string {
+
+	switch attachment.MimeCategory() {
+	case AttachmentMediaTypeVideo:
+		return vocab.ObjectTypeVideo
+	case AttachmentMediaTypeAudio:
+		return vocab.ObjectTypeAudio
+	case AttachmentMediaTypeImage:
+		return vocab.ObjectTypeImage
+	}
+
+	return vocab.ObjectTypeDocument
+}
+
+/******************************************
+ * Mastodon API Methods
+ ******************************************/
+
+// Toot returns this Attachment represented as a Mastodon MediaAttachment
+func (attachment Attachment) Toot() object.MediaAttachment {
+
+	result := object.MediaAttachment{
+		ID:          attachment.AttachmentID.Hex(),
+		Type:        attachment.TootType(),
+		URL:         attachment.URL,
+		PreviewURL:  attachment.URL,
+		Description: attachment.AltText,
+		Blurhash:    attachment.Blurhash,
+		Meta:        map[string]any{},
+	}
+
+	if attachment.PosterURL != "" {
+		result.PreviewURL = attachment.PosterURL
+	}
+
+	if attachment.HasDimensions() {
+		result.Meta["original"] = map[string]any{
+			"width":  attachment.Width,
+			"height": attachment.Height,
+		}
+	}
+
+	if attachment.Duration > 0 {
+		result.Meta["duration"] = attachment.Duration
+	}
+
+	if (attachment.FocusX != 0) || (attachment.FocusY != 0) {
+		result.Meta["focus"] = map[string]any{
+			"x": attachment.FocusX,
+			"y": attachment.FocusY,
+		}
+	}
 
 	return result
 }
+
+// TootType returns the Mastodon media type (image, video, audio, or unknown) for this Attachment
+func (attachment Attachment) TootType() string {
+
+	switch category := attachment.MimeCategory(); category {
+	case AttachmentMediaTypeAudio, AttachmentMediaTypeImage, AttachmentMediaTypeVideo:
+		return category
+	}
+
+	return "unknown"
+}
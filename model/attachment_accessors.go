@@ -1,6 +1,7 @@
 package model
 
 import (
+	"github.com/benpate/rosetta/null"
 	"github.com/benpate/rosetta/schema"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -8,20 +9,29 @@ import (
 func AttachmentSchema() schema.Element {
 	return schema.Object{
 		Properties: schema.ElementMap{
-			"attachmentId": schema.String{Format: "objectId"},
-			"objectId":     schema.String{Format: "objectId"},
-			"objectType":   schema.String{Enum: []string{AttachmentObjectTypeStream, AttachmentObjectTypeUser}},
-			"mediaType":    schema.String{Enum: []string{AttachmentMediaTypeAny, AttachmentMediaTypeAudio, AttachmentMediaTypeDocument, AttachmentMediaTypeImage, AttachmentMediaTypeVideo}},
-			"category":     schema.String{},
-			"label":        schema.String{},
-			"description":  schema.String{},
-			"url":          schema.String{Format: "url"},
-			"original":     schema.String{},
-			"status":       schema.String{Enum: []string{AttachmentStatusReady, AttachmentStatusWorking}},
-			"height":       schema.Integer{},
-			"width":        schema.Integer{},
-			"duration":     schema.Integer{},
-			"rank":         schema.Integer{},
+			"attachmentId":  schema.String{Format: "objectId"},
+			"objectId":      schema.String{Format: "objectId"},
+			"userId":        schema.String{Format: "objectId"},
+			"objectType":    schema.String{Enum: []string{AttachmentObjectTypeStream, AttachmentObjectTypeUser}},
+			"mediaType":     schema.String{Enum: []string{AttachmentMediaTypeAny, AttachmentMediaTypeAudio, AttachmentMediaTypeDocument, AttachmentMediaTypeImage, AttachmentMediaTypeVideo}},
+			"category":      schema.String{},
+			"label":         schema.String{},
+			"description":   schema.String{},
+			"altText":       schema.String{MaxLength: 1500},
+			"blurhash":      schema.String{MaxLength: 100},
+			"url":           schema.String{Format: "url"},
+			"original":      schema.String{},
+			"status":        schema.String{Enum: []string{AttachmentStatusReady, AttachmentStatusWorking}},
+			"height":        schema.Integer{},
+			"width":         schema.Integer{},
+			"duration":      schema.Integer{},
+			"posterUrl":     schema.String{Format: "url"},
+			"rendition":     schema.String{Enum: []string{"", ".mp3", ".mp4"}},
+			"focusX":        schema.Number{Minimum: null.NewFloat(-1), Maximum: null.NewFloat(1)},
+			"focusY":        schema.Number{Minimum: null.NewFloat(-1), Maximum: null.NewFloat(1)},
+			"rank":          schema.Integer{},
+			"size":          schema.Integer{Minimum: null.NewInt64(0), BitSize: 64},
+			"renditionSize": schema.Integer{Minimum: null.NewInt64(0), BitSize: 64},
 		},
 	}
 }
@@ -49,6 +59,12 @@ func (attachment *Attachment) GetPointer(name string) (any, bool) {
 	case "description":
 		return &attachment.Description, true
 
+	case "altText":
+		return &attachment.AltText, true
+
+	case "blurhash":
+		return &attachment.Blurhash, true
+
 	case "url":
 		return &attachment.URL, true
 
@@ -69,6 +85,24 @@ func (attachment *Attachment) GetPointer(name string) (any, bool) {
 
 	case "duration":
 		return &attachment.Duration, true
+
+	case "size":
+		return &attachment.Size, true
+
+	case "renditionSize":
+		return &attachment.RenditionSize, true
+
+	case "posterUrl":
+		return &attachment.PosterURL, true
+
+	case "rendition":
+		return &attachment.Rendition, true
+
+	case "focusX":
+		return &attachment.FocusX, true
+
+	case "focusY":
+		return &attachment.FocusY, true
 	}
 
 	return "", false
@@ -83,6 +117,9 @@ func (attachment *Attachment) GetStringOK(name string) (string, bool) {
 
 	case "objectId":
 		return attachment.ObjectID.Hex(), true
+
+	case "userId":
+		return attachment.UserID.Hex(), true
 	}
 
 	return "", false
@@ -107,6 +144,12 @@ func (attachment *Attachment) SetString(name string, value string) bool {
 			attachment.ObjectID = objectID
 			return true
 		}
+
+	case "userId":
+		if userID, err := primitive.ObjectIDFromHex(value); err == nil {
+			attachment.UserID = userID
+			return true
+		}
 	}
 
 	return false
@@ -28,3 +28,7 @@ const AttachmentStatusReady = "READY"
 // AttachmentStatusWorking represents an attachment that is currently
 // being processed and cannot be downloaded yet.
 const AttachmentStatusWorking = "WORKING"
+
+// AttachmentCategoryMedia represents a file uploaded through the Mastodon media API
+// that has not (yet) been attached to a Stream
+const AttachmentCategoryMedia = "media"
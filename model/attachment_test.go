@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -15,19 +16,142 @@ func TestAttachmentSchema(t *testing.T) {
 	table := []tableTestItem{
 		{"attachmentId", "123456781234567812345678", nil},
 		{"objectId", "876543218765432187654321", nil},
+		{"userId", "123412341234123412341234", nil},
 		{"objectType", "Stream", nil},
 		{"original", "ORIGINAL", nil},
 		{"mediaType", "image", nil},
 		{"category", "CATEGORY", nil},
 		{"label", "LABEL", nil},
 		{"description", "DESCRIPTION", nil},
+		{"altText", "ALT TEXT", nil},
+		{"blurhash", "LEHV6nWB2yk8pyo0adR*.7kCMdnj", nil},
 		{"url", "http://example.com", nil},
 		{"status", "READY", nil},
 		{"height", "100", 100},
 		{"width", "200", 200},
 		{"duration", "100", 100},
+		{"posterUrl", "http://example.com/poster.webp", nil},
+		{"rendition", ".mp4", nil},
 		{"rank", "1", 1},
+		{"size", "1048576", int64(1048576)},
+		{"renditionSize", "524288", int64(524288)},
+		{"focusX", "0.5", 0.5},
+		{"focusY", "-0.25", -0.25},
 	}
 
 	tableTest_Schema(t, &s, &attachment, table)
 }
+
+func TestAttachment_SetFocus(t *testing.T) {
+
+	attachment := NewAttachment("TEMP", primitive.NewObjectID())
+
+	require.True(t, attachment.SetFocus("0.5,-0.25"))
+	require.Equal(t, 0.5, attachment.FocusX)
+	require.Equal(t, -0.25, attachment.FocusY)
+
+	require.False(t, attachment.SetFocus("2,0"))
+	require.False(t, attachment.SetFocus("nonsense"))
+	require.Equal(t, 0.5, attachment.FocusX)
+}
+
+func TestAttachment_DownloadExtension(t *testing.T) {
+
+	attachment := NewAttachment(AttachmentObjectTypeStream, primitive.NewObjectID())
+
+	// Images are always converted to WebP
+	attachment.Original = "photo.JPG"
+	require.Equal(t, ".webp", attachment.DownloadExtension())
+
+	// Videos are downloaded in their original format until they are transcoded
+	attachment.Original = "movie.mov"
+	require.Equal(t, ".mov", attachment.DownloadExtension())
+	require.Equal(t, "video/quicktime", attachment.DownloadMimeType())
+
+	attachment.Rendition = ".mp4"
+	require.Equal(t, ".mp4", attachment.DownloadExtension())
+	require.Equal(t, "video/mp4", attachment.DownloadMimeType())
+
+	// Other documents are downloaded as-is
+	attachment = NewAttachment(AttachmentObjectTypeStream, primitive.NewObjectID())
+	attachment.Original = "paper.pdf"
+	require.Equal(t, ".pdf", attachment.DownloadExtension())
+}
+
+func TestAttachment_DownloadSize(t *testing.T) {
+
+	attachment := NewAttachment(AttachmentObjectTypeStream, primitive.NewObjectID())
+	attachment.Original = "episode.wav"
+	attachment.Size = 50_000_000
+
+	// Originals are downloaded until they are transcoded
+	require.Equal(t, int64(50_000_000), attachment.DownloadSize())
+
+	// Renditions report their own size, so that it matches DownloadMimeType
+	attachment.Rendition = ".mp3"
+	attachment.RenditionSize = 5_000_000
+	require.Equal(t, int64(5_000_000), attachment.DownloadSize())
+	require.Equal(t, "audio/mpeg", attachment.DownloadMimeType())
+}
+
+func TestAttachment_JSONLD_Video(t *testing.T) {
+
+	attachment := NewAttachment(AttachmentObjectTypeStream, primitive.NewObjectID())
+	attachment.Original = "movie.mov"
+	attachment.URL = "https://example.com/123/attachments/456"
+	attachment.AltText = "A short film"
+
+	// Before transcoding, the original format is federated
+	result := attachment.JSONLD()
+	require.Equal(t, "Video", result["type"])
+	require.Equal(t, "video/quicktime", result["mediaType"])
+	require.Equal(t, "A short film", result["name"])
+	require.Nil(t, result["width"])
+	require.Nil(t, result["duration"])
+	require.Nil(t, result["icon"])
+
+	// After transcoding, the rendition and its metadata are federated
+	attachment.Rendition = ".mp4"
+	attachment.Width = 1920
+	attachment.Height = 1080
+	attachment.Duration = 13
+	attachment.PosterURL = attachment.URL + ".webp"
+	attachment.Blurhash = "LEHV6nWB2yk8pyo0adR*.7kCMdnj"
+
+	result = attachment.JSONLD()
+	require.Equal(t, "Video", result["type"])
+	require.Equal(t, "video/mp4", result["mediaType"])
+	require.Equal(t, "https://example.com/123/attachments/456", result["url"])
+	require.Equal(t, 1920, result["width"])
+	require.Equal(t, 1080, result["height"])
+	require.Equal(t, "PT13S", result["duration"])
+	require.Equal(t, "LEHV6nWB2yk8pyo0adR*.7kCMdnj", result["blurhash"])
+	require.Equal(t, map[string]any{
+		"type":      "Image",
+		"mediaType": "image/webp",
+		"url":       "https://example.com/123/attachments/456.webp",
+	}, result["icon"])
+}
+
+func TestAttachment_JSONLD_Audio(t *testing.T) {
+
+	attachment := NewAttachment(AttachmentObjectTypeStream, primitive.NewObjectID())
+	attachment.Original = "episode.flac"
+	attachment.URL = "https://example.com/123/attachments/789"
+
+	// Before transcoding, the original format is federated
+	result := attachment.JSONLD()
+	require.Equal(t, "Audio", result["type"])
+	require.Equal(t, "audio/flac", result["mediaType"])
+
+	// After transcoding, the rendition is federated.  Audio has no dimensions or poster
+	attachment.Rendition = ".mp3"
+	attachment.Duration = 185
+
+	result = attachment.JSONLD()
+	require.Equal(t, "Audio", result["type"])
+	require.Equal(t, "audio/mpeg", result["mediaType"])
+	require.Equal(t, "PT185S", result["duration"])
+	require.Nil(t, result["width"])
+	require.Nil(t, result["icon"])
+}
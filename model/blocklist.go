@@ -0,0 +1,110 @@
+package model
+
+import (
+	"time"
+
+	"github.com/benpate/data/journal"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Blocklist is a subscription to a shared domain blocklist that is published at a remote URL.
+// Blocklists are re-fetched on a schedule, and their entries are synchronized into
+// server-wide (admin-origin) DOMAIN Rules.
+type Blocklist struct {
+	BlocklistID   primitive.ObjectID `json:"blocklistId"   bson:"_id"`                     // Unique identifier for this Blocklist
+	Label         string             `json:"label"         bson:"label"`                   // Human-friendly label for this Blocklist
+	URL           string             `json:"url"           bson:"url"`                     // URL of the remote blocklist (CSV or JSON)
+	PollDuration  int                `json:"pollDuration"  bson:"pollDuration"`            // Number of hours to wait between fetches
+	LastPolled    int64              `json:"lastPolled"    bson:"lastPolled"`              // Unix timestamp of the last time this Blocklist was fetched
+	NextPoll      int64              `json:"nextPoll"      bson:"nextPoll"`                // Unix timestamp of the next time this Blocklist should be fetched
+	StatusID      string             `json:"statusId"      bson:"statusId"`                // Result of the last fetch (NEW, SUCCESS, FAILURE)
+	StatusMessage string             `json:"statusMessage" bson:"statusMessage,omitempty"` // Error message from the last fetch, if any
+	RuleCount     int                `json:"ruleCount"     bson:"ruleCount"`               // Number of Rules imported from this Blocklist
+
+	journal.Journal `json:"-" bson:",inline"`
+}
+
+// BlocklistEntry is a single domain read from a blocklist file
+type BlocklistEntry struct {
+	Domain   string // Domain name that is blocked
+	Severity string // Mastodon severity (suspend, silence, noop)
+	Comment  string // Public comment explaining why this domain is blocked
+}
+
+// NewBlocklist returns a fully initialized Blocklist object
+func NewBlocklist() Blocklist {
+	return Blocklist{
+		BlocklistID:  primitive.NewObjectID(),
+		PollDuration: 24,
+		StatusID:     BlocklistStatusNew,
+	}
+}
+
+/******************************************
+ * data.Object Interface
+ ******************************************/
+
+// ID returns the primary key of this object
+func (blocklist Blocklist) ID() string {
+	return blocklist.BlocklistID.Hex()
+}
+
+// Fields returns the subset of fields that are queried when listing Blocklists
+func (blocklist Blocklist) Fields() []string {
+	return []string{
+		"_id",
+		"label",
+		"url",
+		"pollDuration",
+		"lastPolled",
+		"nextPoll",
+		"statusId",
+		"statusMessage",
+		"ruleCount",
+	}
+}
+
+/******************************************
+ * Other Data Accessors
+ ******************************************/
+
+// IsFailure returns TRUE if the last fetch of this Blocklist failed
+func (blocklist Blocklist) IsFailure() bool {
+	return blocklist.StatusID == BlocklistStatusFailure
+}
+
+// SetPolled updates the polling timestamps for this Blocklist
+func (blocklist *Blocklist) SetPolled(statusID string, statusMessage string) {
+
+	pollDuration := blocklist.PollDuration
+
+	if pollDuration < 1 {
+		pollDuration = 24
+	}
+
+	now := time.Now()
+	blocklist.StatusID = statusID
+	blocklist.StatusMessage = statusMessage
+	blocklist.LastPolled = now.Unix()
+	blocklist.NextPoll = now.Add(time.Duration(pollDuration) * time.Hour).Unix()
+}
+
+/******************************************
+ * BlocklistEntry Methods
+ ******************************************/
+
+// RuleAction returns the Rule action that matches this entry's severity.
+// Entries that should not create a Rule return an empty string.
+func (entry BlocklistEntry) RuleAction() string {
+
+	switch entry.Severity {
+
+	case BlocklistSeveritySuspend:
+		return RuleActionBlock
+
+	case BlocklistSeveritySilence:
+		return RuleActionMute
+	}
+
+	return ""
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"github.com/benpate/rosetta/null"
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BlocklistSchema returns a Rosetta Schema for the Blocklist object
+func BlocklistSchema() schema.Element {
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"blocklistId":   schema.String{Format: "objectId"},
+			"label":         schema.String{MaxLength: 128, Required: true},
+			"url":           schema.String{Format: "url", Required: true},
+			"pollDuration":  schema.Integer{Minimum: null.NewInt64(1), Maximum: null.NewInt64(720)},
+			"lastPolled":    schema.Integer{BitSize: 64},
+			"nextPoll":      schema.Integer{BitSize: 64},
+			"statusId":      schema.String{Enum: []string{BlocklistStatusNew, BlocklistStatusSuccess, BlocklistStatusFailure}},
+			"statusMessage": schema.String{MaxLength: 1024},
+			"ruleCount":     schema.Integer{},
+		},
+	}
+}
+
+/******************************************
+ * Getter/Setter Interfaces
+ ******************************************/
+
+func (blocklist *Blocklist) GetPointer(name string) (any, bool) {
+
+	switch name {
+
+	case "label":
+		return &blocklist.Label, true
+
+	case "url":
+		return &blocklist.URL, true
+
+	case "pollDuration":
+		return &blocklist.PollDuration, true
+
+	case "lastPolled":
+		return &blocklist.LastPolled, true
+
+	case "nextPoll":
+		return &blocklist.NextPoll, true
+
+	case "statusId":
+		return &blocklist.StatusID, true
+
+	case "statusMessage":
+		return &blocklist.StatusMessage, true
+
+	case "ruleCount":
+		return &blocklist.RuleCount, true
+	}
+
+	return nil, false
+}
+
+func (blocklist *Blocklist) GetStringOK(name string) (string, bool) {
+
+	switch name {
+
+	case "blocklistId":
+		return blocklist.BlocklistID.Hex(), true
+	}
+
+	return "", false
+}
+
+func (blocklist *Blocklist) SetString(name string, value string) bool {
+
+	switch name {
+
+	case "blocklistId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			blocklist.BlocklistID = objectID
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,19 @@
+package model
+
+// BlocklistStatusNew signifies a Blocklist that has not yet been fetched
+const BlocklistStatusNew = "NEW"
+
+// BlocklistStatusSuccess signifies a Blocklist that was fetched and imported successfully
+const BlocklistStatusSuccess = "SUCCESS"
+
+// BlocklistStatusFailure signifies a Blocklist that could not be fetched or parsed
+const BlocklistStatusFailure = "FAILURE"
+
+// BlocklistSeveritySuspend is the Mastodon severity for domains that are blocked entirely
+const BlocklistSeveritySuspend = "suspend"
+
+// BlocklistSeveritySilence is the Mastodon severity for domains that are hidden from public views
+const BlocklistSeveritySilence = "silence"
+
+// BlocklistSeverityNoop is the Mastodon severity for domains that are listed, but not limited
+const BlocklistSeverityNoop = "noop"
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlocklistSchema(t *testing.T) {
+
+	blocklist := NewBlocklist()
+	s := schema.New(BlocklistSchema())
+
+	table := []tableTestItem{
+		{"blocklistId", "123456781234567812345678", nil},
+		{"label", "Shared Denylist", nil},
+		{"url", "https://example.com/blocklist.csv", nil},
+		{"pollDuration", 12, nil},
+		{"lastPolled", int64(1234567890), nil},
+		{"nextPoll", int64(1234567890), nil},
+		{"statusId", "FAILURE", nil},
+		{"statusMessage", "Not Found", nil},
+		{"ruleCount", 42, nil},
+	}
+
+	tableTest_Schema(t, &s, &blocklist, table)
+}
+
+func TestBlocklistEntryRuleAction(t *testing.T) {
+	require.Equal(t, RuleActionBlock, BlocklistEntry{Severity: BlocklistSeveritySuspend}.RuleAction())
+	require.Equal(t, RuleActionMute, BlocklistEntry{Severity: BlocklistSeveritySilence}.RuleAction())
+	require.Equal(t, "", BlocklistEntry{Severity: BlocklistSeverityNoop}.RuleAction())
+}
@@ -21,6 +21,8 @@ type Domain struct {
 	ColorMode        string             `bson:"colorMode"`        // Color mode for this domain (e.g. "LIGHT", "DARK", or "AUTO")
 	Data             mapof.String       `bson:"data"`             // Custom data stored in this domain
 	DatabaseVersion  uint               `bson:"databaseVersion"`  // Version of the database schema
+	StorageQuota     int64              `bson:"storageQuota"`     // Default amount of storage (in megabytes) that each User can upload.  Zero means unlimited.
+	AuthorizedFetch  bool               `bson:"authorizedFetch"`  // If TRUE, then ActivityPub GET requests must be signed by a remote actor ("secure mode")
 	journal.Journal  `json:"-" bson:",inline"`
 }
 
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/benpate/data/journal"
+	"github.com/benpate/toot/object"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DomainPolicy is a server-wide federation policy for a remote domain (and all of its
+// subdomains).  Policies are set by Domain Owners, and are enforced when receiving
+// activities, when delivering activities, and when displaying remote content.
+type DomainPolicy struct {
+	DomainPolicyID primitive.ObjectID `json:"domainPolicyId" bson:"_id"`                      // Unique identifier for this DomainPolicy
+	Hostname       string             `json:"hostname"       bson:"hostname"`                 // Remote hostname that this policy applies to (includes subdomains)
+	Severity       string             `json:"severity"       bson:"severity"`                 // Federation level for this domain (NONE, SILENCE, SUSPEND)
+	RejectMedia    bool               `json:"rejectMedia"    bson:"rejectMedia,omitempty"`    // If TRUE, then attachments and images from this domain are never fetched or displayed
+	ContentWarning string             `json:"contentWarning" bson:"contentWarning,omitempty"` // If present, this content warning is added to every document from this domain
+	PublicComment  string             `json:"publicComment"  bson:"publicComment,omitempty"`  // Reason for this policy, published in the Mastodon API
+	PrivateComment string             `json:"privateComment" bson:"privateComment,omitempty"` // Notes for other Domain Owners, never published
+	IsPublic       bool               `json:"isPublic"       bson:"isPublic,omitempty"`       // If TRUE, then this policy is published in the Mastodon API
+
+	journal.Journal `json:"-" bson:",inline"`
+}
+
+// NewDomainPolicy returns a fully initialized DomainPolicy object
+func NewDomainPolicy() DomainPolicy {
+	return DomainPolicy{
+		DomainPolicyID: primitive.NewObjectID(),
+		Severity:       DomainPolicySeveritySuspend,
+		IsPublic:       true,
+	}
+}
+
+/******************************************
+ * data.Object Interface
+ ******************************************/
+
+// ID returns the primary key of this object
+func (policy DomainPolicy) ID() string {
+	return policy.DomainPolicyID.Hex()
+}
+
+// Fields returns the subset of fields that are queried when listing DomainPolicies
+func (policy DomainPolicy) Fields() []string {
+	return []string{
+		"_id",
+		"hostname",
+		"severity",
+		"rejectMedia",
+		"contentWarning",
+		"publicComment",
+		"isPublic",
+	}
+}
+
+/******************************************
+ * Other Data Accessors
+ ******************************************/
+
+// IsSuspended returns TRUE if all federation with this domain is blocked
+func (policy DomainPolicy) IsSuspended() bool {
+	return policy.Severity == DomainPolicySeveritySuspend
+}
+
+// IsSilenced returns TRUE if this domain is hidden from public views
+func (policy DomainPolicy) IsSilenced() bool {
+	return policy.Severity == DomainPolicySeveritySilence
+}
+
+// IsContentWarning returns TRUE if a content warning is added to documents from this domain
+func (policy DomainPolicy) IsContentWarning() bool {
+	return policy.ContentWarning != ""
+}
+
+// MatchHostname returns TRUE if this policy applies to the provided hostname,
+// which may be the policy's own hostname, or any of its subdomains.
+func (policy DomainPolicy) MatchHostname(hostname string) bool {
+
+	hostname = strings.ToLower(hostname)
+
+	if hostname == policy.Hostname {
+		return true
+	}
+
+	return strings.HasSuffix(hostname, "."+policy.Hostname)
+}
+
+// SeverityLabel returns a human-friendly label for this policy's severity
+func (policy DomainPolicy) SeverityLabel() string {
+
+	switch policy.Severity {
+
+	case DomainPolicySeveritySuspend:
+		return "Suspend"
+
+	case DomainPolicySeveritySilence:
+		return "Silence"
+	}
+
+	return "Limit"
+}
+
+/******************************************
+ * Mastodon API
+ ******************************************/
+
+// Toot returns this DomainPolicy in the format used by the Mastodon API
+func (policy DomainPolicy) Toot() object.DomainBlock {
+
+	digest := sha256.Sum256([]byte(policy.Hostname))
+
+	result := object.DomainBlock{
+		Domain:   policy.Hostname,
+		Digest:   hex.EncodeToString(digest[:]),
+		Severity: object.DomainBlockSeveritySuspend,
+		Comment:  policy.PublicComment,
+	}
+
+	if !policy.IsSuspended() {
+		result.Severity = object.DomainBlockSeveritySilence
+	}
+
+	return result
+}
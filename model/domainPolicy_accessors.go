@@ -0,0 +1,80 @@
+package model
+
+import (
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DomainPolicySchema returns a Rosetta Schema for the DomainPolicy object
+func DomainPolicySchema() schema.Element {
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"domainPolicyId": schema.String{Format: "objectId"},
+			"hostname":       schema.String{MaxLength: 256, Required: true},
+			"severity":       schema.String{Enum: []string{DomainPolicySeverityNone, DomainPolicySeveritySilence, DomainPolicySeveritySuspend}, Required: true},
+			"rejectMedia":    schema.Boolean{},
+			"contentWarning": schema.String{MaxLength: 256},
+			"publicComment":  schema.String{MaxLength: 1024},
+			"privateComment": schema.String{MaxLength: 1024},
+			"isPublic":       schema.Boolean{},
+		},
+	}
+}
+
+/******************************************
+ * Getter/Setter Interfaces
+ ******************************************/
+
+func (policy *DomainPolicy) GetPointer(name string) (any, bool) {
+
+	switch name {
+
+	case "hostname":
+		return &policy.Hostname, true
+
+	case "severity":
+		return &policy.Severity, true
+
+	case "rejectMedia":
+		return &policy.RejectMedia, true
+
+	case "contentWarning":
+		return &policy.ContentWarning, true
+
+	case "publicComment":
+		return &policy.PublicComment, true
+
+	case "privateComment":
+		return &policy.PrivateComment, true
+
+	case "isPublic":
+		return &policy.IsPublic, true
+	}
+
+	return nil, false
+}
+
+func (policy *DomainPolicy) GetStringOK(name string) (string, bool) {
+
+	switch name {
+
+	case "domainPolicyId":
+		return policy.DomainPolicyID.Hex(), true
+	}
+
+	return "", false
+}
+
+func (policy *DomainPolicy) SetString(name string, value string) bool {
+
+	switch name {
+
+	case "domainPolicyId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			policy.DomainPolicyID = objectID
+			return true
+		}
+	}
+
+	return false
+}
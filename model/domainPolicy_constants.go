@@ -0,0 +1,10 @@
+package model
+
+// DomainPolicySeverityNone signifies a DomainPolicy that does not limit federation (but may still reject media or force content warnings)
+const DomainPolicySeverityNone = "NONE"
+
+// DomainPolicySeveritySilence signifies a DomainPolicy that hides the remote domain from public views
+const DomainPolicySeveritySilence = "SILENCE"
+
+// DomainPolicySeveritySuspend signifies a DomainPolicy that rejects all inbound activities and stops all outbound delivery
+const DomainPolicySeveritySuspend = "SUSPEND"
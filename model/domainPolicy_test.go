@@ -0,0 +1,39 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDomainPolicySchema(t *testing.T) {
+
+	policy := NewDomainPolicy()
+	s := schema.New(DomainPolicySchema())
+
+	table := []tableTestItem{
+		{"domainPolicyId", "123456781234567812345678", nil},
+		{"hostname", "remote.social", nil},
+		{"severity", "SILENCE", nil},
+		{"rejectMedia", true, nil},
+		{"contentWarning", "Unmoderated server", nil},
+		{"publicComment", "Spam", nil},
+		{"privateComment", "Reported by several users", nil},
+		{"isPublic", true, nil},
+	}
+
+	tableTest_Schema(t, &s, &policy, table)
+}
+
+func TestDomainPolicyMatchHostname(t *testing.T) {
+
+	policy := NewDomainPolicy()
+	policy.Hostname = "remote.social"
+
+	require.True(t, policy.MatchHostname("remote.social"))
+	require.True(t, policy.MatchHostname("media.remote.social"))
+	require.True(t, policy.MatchHostname("REMOTE.social"))
+	require.False(t, policy.MatchHostname("notremote.social"))
+	require.False(t, policy.MatchHostname("remote.social.example"))
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"github.com/benpate/rosetta/null"
 	"github.com/benpate/rosetta/schema"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -19,6 +20,8 @@ func DomainSchema() schema.Element {
 			"data":             schema.Object{Wildcard: schema.String{}},
 			"colorMode":        schema.String{Enum: []string{DomainColorModeAuto, DomainColorModeLight, DomainColorModeDark}},
 			"registrationData": schema.Object{Wildcard: schema.String{}},
+			"storageQuota":     schema.Integer{Minimum: null.NewInt64(0), BitSize: 64},
+			"authorizedFetch":  schema.Boolean{},
 		},
 	}
 }
@@ -60,6 +63,12 @@ func (domain *Domain) GetPointer(name string) (any, bool) {
 
 	case "data":
 		return &domain.Data, true
+
+	case "storageQuota":
+		return &domain.StorageQuota, true
+
+	case "authorizedFetch":
+		return &domain.AuthorizedFetch, true
 	}
 
 	return nil, false
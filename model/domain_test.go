@@ -28,6 +28,8 @@ func TestDomainSchema(t *testing.T) {
 		{"colorMode", "LIGHT", nil},
 		{"registrationData.custom", "CUSTOM", nil},
 		{"registrationData.value", "VALUE", nil},
+		{"storageQuota", "1024", int64(1024)},
+		{"authorizedFetch", "true", true},
 	}
 
 	tableTest_Schema(t, &s, &domain, table)
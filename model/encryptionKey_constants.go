@@ -5,3 +5,6 @@ const EncryptionKeyTypeUser = "User"
 
 // EncryptionKeyTypeStream identifies an EncryptionKey that is owned by a Stream/Actor
 const EncryptionKeyTypeStream = "Stream"
+
+// EncryptionKeyTypeInstance identifies an EncryptionKey that is owned by the Domain's instance actor
+const EncryptionKeyTypeInstance = "Instance"
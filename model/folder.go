@@ -3,20 +3,27 @@ package model
 import (
 	"github.com/benpate/data/journal"
 	"github.com/benpate/form"
+	"github.com/benpate/rosetta/first"
 	"github.com/benpate/toot/object"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // Folder represents a custom folder that organizes incoming messages
 type Folder struct {
-	FolderID    primitive.ObjectID `json:"folderId"    bson:"_id"`         // Unique ID for this folder
-	UserID      primitive.ObjectID `json:"userId"      bson:"userId"`      // ID of the User who owns this folder
-	Label       string             `json:"label"       bson:"label"`       // Label of the folder
-	Icon        string             `json:"icon"        bson:"icon"`        // Icon of the folder
-	Layout      string             `json:"layout"      bson:"layout"`      // Layout type of the folder
-	Group       int                `json:"group"       bson:"group"`       // Group number of the folder (starting with 1)
-	Rank        int                `json:"rank"        bson:"rank"`        // Sort order of the folder
-	UnreadCount int                `json:"unreadCount" bson:"unreadCount"` // Number of unread messages in this folder
+	FolderID      primitive.ObjectID `json:"folderId"      bson:"_id"`                     // Unique ID for this folder
+	UserID        primitive.ObjectID `json:"userId"        bson:"userId"`                  // ID of the User who owns this folder
+	Label         string             `json:"label"         bson:"label"`                   // Label of the folder
+	Icon          string             `json:"icon"          bson:"icon"`                    // Icon of the folder
+	Layout        string             `json:"layout"        bson:"layout"`                  // Layout type of the folder
+	Type          string             `json:"type"          bson:"type"`                    // Type of folder (STANDARD, SMART, SEARCH)
+	Query         FolderQuery        `json:"query"         bson:"query"`                   // Query that selects the Messages in SMART and SEARCH folders
+	Group         int                `json:"group"         bson:"group"`                   // Group number of the folder (starting with 1)
+	Rank          int                `json:"rank"          bson:"rank"`                    // Sort order of the folder
+	UnreadCount   int                `json:"unreadCount"   bson:"unreadCount"`             // Number of unread messages in this folder
+	AlertDate     int64              `json:"alertDate"     bson:"alertDate"`               // Unix timestamp of the most recent alert sent for a SEARCH folder
+	Retention     RetentionPolicy    `json:"retention"     bson:"retention"`               // Rules for purging old messages from this folder
+	RepliesPolicy string             `json:"repliesPolicy" bson:"repliesPolicy,omitempty"` // Replies shown in this folder's Mastodon list timeline (followed, list, none)
+	Exclusive     bool               `json:"exclusive"     bson:"exclusive,omitempty"`     // If TRUE, list members are removed from the Mastodon home timeline
 
 	journal.Journal `json:"-" bson:",inline"`
 }
@@ -24,8 +31,11 @@ type Folder struct {
 // NewFolder returns a fully initialized Folder object
 func NewFolder() Folder {
 	return Folder{
-		FolderID: primitive.NewObjectID(),
-		Icon:     "folder",
+		FolderID:  primitive.NewObjectID(),
+		Icon:      "folder",
+		Type:      FolderTypeStandard,
+		Query:     NewFolderQuery(),
+		Retention: NewRetentionPolicy(),
 	}
 }
 
@@ -41,6 +51,17 @@ func (folder Folder) ID() string {
  * Other Data Accessors
  ******************************************/
 
+// IsSmart returns TRUE if this Folder displays the results of a query
+// (instead of the Messages delivered into it by Following records)
+func (folder Folder) IsSmart() bool {
+	return (folder.Type == FolderTypeSmart) || (folder.Type == FolderTypeSearch)
+}
+
+// IsSearch returns TRUE if this Folder is a saved search that alerts the User about new Messages
+func (folder Folder) IsSearch() bool {
+	return folder.Type == FolderTypeSearch
+}
+
 func (folder Folder) LookupCode() form.LookupCode {
 	return form.LookupCode{
 		Value: folder.FolderID.Hex(),
@@ -81,7 +102,7 @@ func (folder Folder) Toot() object.List {
 	return object.List{
 		ID:            folder.FolderID.Hex(),
 		Title:         folder.Label,
-		RepliesPolicy: object.ListRepliesPolicyFollowed,
+		RepliesPolicy: first.String(folder.RepliesPolicy, object.ListRepliesPolicyFollowed),
 	}
 }
 
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/benpate/exp"
+)
+
+// FolderQuery defines the Messages that appear in a SMART or SEARCH Folder.
+// Empty values are ignored, so an empty FolderQuery matches every Message.
+type FolderQuery struct {
+	Keyword       string `json:"keyword"       bson:"keyword,omitempty"`       // Text to search for in the Message name, summary, and content
+	Author        string `json:"author"        bson:"author,omitempty"`        // Name or profile URL of the Message author
+	Domain        string `json:"domain"        bson:"domain,omitempty"`        // Domain (including subdomains) where the Message was published
+	SocialRole    string `json:"socialRole"    bson:"socialRole,omitempty"`    // Role of the Message ("Article", "Note", etc)
+	HasAttachment bool   `json:"hasAttachment" bson:"hasAttachment,omitempty"` // If TRUE, only include Messages with attachments
+	Unread        bool   `json:"unread"        bson:"unread,omitempty"`        // If TRUE, only include unread Messages
+	StartDate     string `json:"startDate"     bson:"startDate,omitempty"`     // First publish date to include (YYYY-MM-DD)
+	EndDate       string `json:"endDate"       bson:"endDate,omitempty"`       // Last publish date to include (YYYY-MM-DD)
+}
+
+// NewFolderQuery returns a fully initialized FolderQuery
+func NewFolderQuery() FolderQuery {
+	return FolderQuery{}
+}
+
+// IsEmpty returns TRUE if this query does not filter any Messages
+func (query FolderQuery) IsEmpty() bool {
+	return query == FolderQuery{}
+}
+
+// Expression returns the criteria that select Messages matching this query.
+// The result does not include the UserID, which must be added by the caller.
+func (query FolderQuery) Expression() exp.Expression {
+
+	result := make([]exp.Expression, 0)
+
+	if keyword := strings.TrimSpace(query.Keyword); keyword != "" {
+		result = append(result, exp.Contains("searchText", regexp.QuoteMeta(keyword)))
+	}
+
+	if author := strings.TrimSpace(query.Author); author != "" {
+		author = regexp.QuoteMeta(strings.TrimPrefix(author, "@"))
+		result = append(result, exp.Or(
+			exp.Contains("attributedTo.name", author),
+			exp.Contains("attributedTo.profileUrl", author),
+		))
+	}
+
+	if domain := strings.ToLower(strings.TrimSpace(query.Domain)); domain != "" {
+		result = append(result, exp.Or(
+			exp.Equal("domain", domain),
+			exp.EndsWith("domain", regexp.QuoteMeta("."+domain)),
+		))
+	}
+
+	if query.SocialRole != "" {
+		result = append(result, exp.Equal("socialRole", query.SocialRole))
+	}
+
+	if query.HasAttachment {
+		result = append(result, exp.Equal("hasAttachment", true))
+	}
+
+	if query.Unread {
+		result = append(result, exp.Equal("readDate", int64(math.MaxInt64)))
+	}
+
+	if startDate, ok := parseFolderQueryDate(query.StartDate); ok {
+		result = append(result, exp.GreaterOrEqual("publishDate", startDate.Unix()))
+	}
+
+	if endDate, ok := parseFolderQueryDate(query.EndDate); ok {
+		result = append(result, exp.LessThan("publishDate", endDate.AddDate(0, 0, 1).Unix()))
+	}
+
+	return exp.And(result...)
+}
+
+// parseFolderQueryDate parses a YYYY-MM-DD date (in UTC)
+func parseFolderQueryDate(value string) (time.Time, bool) {
+
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	result, err := time.Parse(time.DateOnly, value)
+	return result, err == nil
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"github.com/benpate/rosetta/schema"
+)
+
+// FolderQuerySchema returns a JSON Schema for FolderQuery structures
+func FolderQuerySchema() schema.Element {
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"keyword":       schema.String{MaxLength: 256},
+			"author":        schema.String{MaxLength: 256},
+			"domain":        schema.String{MaxLength: 256},
+			"socialRole":    schema.String{MaxLength: 64},
+			"hasAttachment": schema.Boolean{},
+			"unread":        schema.Boolean{},
+			"startDate":     schema.String{MaxLength: 10},
+			"endDate":       schema.String{MaxLength: 10},
+		},
+	}
+}
+
+/*********************************
+ * Getter Interfaces
+ *********************************/
+
+func (query *FolderQuery) GetPointer(name string) (any, bool) {
+	switch name {
+
+	case "keyword":
+		return &query.Keyword, true
+
+	case "author":
+		return &query.Author, true
+
+	case "domain":
+		return &query.Domain, true
+
+	case "socialRole":
+		return &query.SocialRole, true
+
+	case "hasAttachment":
+		return &query.HasAttachment, true
+
+	case "unread":
+		return &query.Unread, true
+
+	case "startDate":
+		return &query.StartDate, true
+
+	case "endDate":
+		return &query.EndDate, true
+	}
+
+	return nil, false
+}
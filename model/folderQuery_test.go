@@ -0,0 +1,74 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFolderQuerySchema(t *testing.T) {
+
+	query := NewFolderQuery()
+	s := schema.New(FolderQuerySchema())
+
+	table := []tableTestItem{
+		{"keyword", "emissary", nil},
+		{"author", "@benpate", nil},
+		{"domain", "example.com", nil},
+		{"socialRole", "Article", nil},
+		{"hasAttachment", true, nil},
+		{"unread", true, nil},
+		{"startDate", "2024-01-01", nil},
+		{"endDate", "2024-01-31", nil},
+	}
+
+	tableTest_Schema(t, &s, &query, table)
+}
+
+func TestFolderQueryExpression_Empty(t *testing.T) {
+	query := NewFolderQuery()
+	require.True(t, query.IsEmpty())
+	require.True(t, query.Expression().IsEmpty())
+}
+
+func TestFolderQueryExpression(t *testing.T) {
+
+	query := FolderQuery{
+		Keyword:       "c++",
+		Domain:        "Example.com",
+		SocialRole:    "Article",
+		HasAttachment: true,
+		StartDate:     "2024-01-01",
+		EndDate:       "2024-01-31",
+	}
+
+	require.False(t, query.IsEmpty())
+
+	// Collect every predicate in the expression.  Exact domain matches "fail" so
+	// that the subdomain predicate in the same OR expression is also visited.
+	predicates := make(map[string][]exp.Predicate)
+	query.Expression().Match(func(predicate exp.Predicate) bool {
+		predicates[predicate.Field] = append(predicates[predicate.Field], predicate)
+		return (predicate.Field != "domain") || (predicate.Operator != exp.OperatorEqual)
+	})
+
+	require.Equal(t, `c\+\+`, predicates["searchText"][0].Value)
+	require.Equal(t, exp.OperatorContains, predicates["searchText"][0].Operator)
+
+	require.Equal(t, "example.com", predicates["domain"][0].Value)
+	require.Equal(t, `\.example\.com`, predicates["domain"][1].Value)
+	require.Equal(t, exp.OperatorEndsWith, predicates["domain"][1].Operator)
+
+	require.Equal(t, "Article", predicates["socialRole"][0].Value)
+	require.Equal(t, true, predicates["hasAttachment"][0].Value)
+
+	require.Equal(t, int64(1704067200), predicates["publishDate"][0].Value)
+	require.Equal(t, exp.OperatorGreaterOrEqual, predicates["publishDate"][0].Operator)
+	require.Equal(t, int64(1706745600), predicates["publishDate"][1].Value)
+	require.Equal(t, exp.OperatorLessThan, predicates["publishDate"][1].Operator)
+
+	require.Nil(t, predicates["readDate"])
+	require.Nil(t, predicates["attributedTo.name"])
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"github.com/benpate/rosetta/schema"
+	"github.com/benpate/toot/object"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -9,12 +10,17 @@ import (
 func FolderSchema() schema.Element {
 	return schema.Object{
 		Properties: schema.ElementMap{
-			"folderId": schema.String{Format: "objectId"},
-			"userId":   schema.String{Format: "objectId"},
-			"label":    schema.String{MaxLength: 100, Required: true},
-			"layout":   schema.String{MaxLength: 100, Required: true},
-			"icon":     schema.String{MaxLength: 100},
-			"rank":     schema.Integer{},
+			"folderId":      schema.String{Format: "objectId"},
+			"userId":        schema.String{Format: "objectId"},
+			"label":         schema.String{MaxLength: 100, Required: true},
+			"layout":        schema.String{MaxLength: 100, Required: true},
+			"icon":          schema.String{MaxLength: 100},
+			"type":          schema.String{Enum: []string{FolderTypeStandard, FolderTypeSmart, FolderTypeSearch}},
+			"query":         FolderQuerySchema(),
+			"rank":          schema.Integer{},
+			"retention":     RetentionPolicySchema(),
+			"repliesPolicy": schema.String{Enum: []string{object.ListRepliesPolicyFollowed, object.ListRepliesPolicyList, object.ListRepliesPolicyNone}},
+			"exclusive":     schema.Boolean{},
 		},
 	}
 }
@@ -23,6 +29,25 @@ func FolderSchema() schema.Element {
  * Getter Interfaces
  ******************************************/
 
+func (folder *Folder) GetPointer(name string) (any, bool) {
+	switch name {
+
+	case "query":
+		return &folder.Query, true
+
+	case "retention":
+		return &folder.Retention, true
+
+	case "repliesPolicy":
+		return &folder.RepliesPolicy, true
+
+	case "exclusive":
+		return &folder.Exclusive, true
+	}
+
+	return nil, false
+}
+
 func (folder *Folder) GetIntOK(name string) (int, bool) {
 	switch name {
 
@@ -48,6 +73,9 @@ func (folder *Folder) GetStringOK(name string) (string, bool) {
 	case "layout":
 		return folder.Layout, true
 
+	case "type":
+		return folder.Type, true
+
 	case "userId":
 		return folder.UserID.Hex(), true
 	}
@@ -91,6 +119,10 @@ func (folder *Folder) SetString(name string, value string) bool {
 		folder.Layout = value
 		return true
 
+	case "type":
+		folder.Type = value
+		return true
+
 	case "userId":
 		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
 			folder.UserID = objectID
@@ -11,3 +11,15 @@ const FolderLayoutMagazine = "MAGAZINE"
 const FolderFilterAll = "ALL"
 
 const FolderFilterUnread = "UNREAD"
+
+// FolderTypeStandard is a Folder that receives Messages from the Following records that deliver into it
+const FolderTypeStandard = "STANDARD"
+
+// FolderTypeSmart is a Folder that displays all Messages matching a FolderQuery
+const FolderTypeSmart = "SMART"
+
+// FolderTypeSearch is a SMART Folder that also alerts the User when new matching Messages arrive
+const FolderTypeSearch = "SEARCH"
+
+// FolderSearchAlertInterval is the minimum time between alerts for a single saved search
+const FolderSearchAlertInterval = 60 * 60
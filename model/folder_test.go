@@ -17,6 +17,15 @@ func TestFolderSchema(t *testing.T) {
 		{"label", "LABEL", nil},
 		{"rank", 1.0, 1},
 		{"layout", "MAGAZINE", nil},
+		{"type", "SMART", nil},
+		{"query.keyword", "emissary", nil},
+		{"query.hasAttachment", true, nil},
+		{"query.startDate", "2024-01-01", nil},
+		{"retention.readDays", 7, nil},
+		{"retention.unreadDays", -1, nil},
+		{"retention.purgeResponses", true, nil},
+		{"repliesPolicy", "list", nil},
+		{"exclusive", true, nil},
 	}
 
 	tableTest_Schema(t, &s, &folder, table)
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"github.com/benpate/data/journal"
+	"github.com/benpate/toot/object"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FollowedTag is a hashtag that a User follows.  Public posts that include
+// this hashtag are delivered into the User's hashtag Folder.
+type FollowedTag struct {
+	FollowedTagID primitive.ObjectID `json:"followedTagId" bson:"_id"`      // Unique identifier for this FollowedTag
+	UserID        primitive.ObjectID `json:"userId"        bson:"userId"`   // ID of the User who follows this hashtag
+	Name          string             `json:"name"          bson:"name"`     // Normalized name of the hashtag (lowercase, without the leading "#")
+	FolderID      primitive.ObjectID `json:"folderId"      bson:"folderId"` // ID of the Folder where matching posts are delivered
+
+	journal.Journal `json:"-" bson:",inline"`
+}
+
+// NewFollowedTag returns a fully initialized FollowedTag object
+func NewFollowedTag() FollowedTag {
+	return FollowedTag{
+		FollowedTagID: primitive.NewObjectID(),
+	}
+}
+
+/******************************************
+ * data.Object Interface
+ ******************************************/
+
+// ID returns the primary key of this object
+func (followedTag FollowedTag) ID() string {
+	return followedTag.FollowedTagID.Hex()
+}
+
+/******************************************
+ * Other Data Accessors
+ ******************************************/
+
+// Hashtag returns the display value of this FollowedTag (including the leading "#")
+func (followedTag FollowedTag) Hashtag() string {
+	return "#" + followedTag.Name
+}
+
+// Origin returns an OriginLink for Messages that were delivered because of this FollowedTag
+func (followedTag FollowedTag) Origin(url string) OriginLink {
+	return OriginLink{
+		Type:  OriginTypeHashtag,
+		Label: followedTag.Hashtag(),
+		URL:   url,
+	}
+}
+
+/******************************************
+ * Mastodon API
+ ******************************************/
+
+// Toot returns this FollowedTag in the format used by the Mastodon API
+func (followedTag FollowedTag) Toot() object.Tag {
+	return object.Tag{
+		Name:      followedTag.Name,
+		History:   []object.TagHistory{},
+		Following: true,
+	}
+}
+
+// GetRank returns the value used to page through a User's followed hashtags
+func (followedTag FollowedTag) GetRank() int64 {
+	return followedTag.CreateDate
+}
@@ -0,0 +1,7 @@
+package model
+
+// FollowedTagFolderLabel is the label of the Folder that receives posts for a User's followed hashtags
+const FollowedTagFolderLabel = "Hashtags"
+
+// FollowedTagFolderIcon is the icon of the Folder that receives posts for a User's followed hashtags
+const FollowedTagFolderIcon = "hashtag"
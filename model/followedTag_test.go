@@ -0,0 +1,35 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollowedTagSchema(t *testing.T) {
+
+	followedTag := NewFollowedTag()
+	s := schema.New(FollowedTagSchema())
+
+	table := []tableTestItem{
+		{"followedTagId", "123456781234567812345678", nil},
+		{"userId", "876543218765432187654321", nil},
+		{"name", "emissary", nil},
+		{"folderId", "123412341234123412341234", nil},
+	}
+
+	tableTest_Schema(t, &s, &followedTag, table)
+}
+
+func TestFollowedTagToot(t *testing.T) {
+
+	followedTag := NewFollowedTag()
+	followedTag.Name = "emissary"
+
+	require.Equal(t, "#emissary", followedTag.Hashtag())
+
+	result := followedTag.Toot()
+	require.Equal(t, "emissary", result.Name)
+	require.True(t, result.Following)
+}
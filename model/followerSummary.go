@@ -30,6 +30,8 @@ func (summary FollowerSummary) MethodIcon() string {
 		return "email"
 	case FollowerMethodWebSub:
 		return "websub"
+	case FollowerMethodRSSCloud:
+		return "rss"
 	case FollowerMethodActivityPub:
 		return "activitypub"
 	}
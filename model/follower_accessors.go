@@ -12,7 +12,7 @@ func FollowerSchema() schema.Element {
 			"followerId": schema.String{Format: "objectId"},
 			"parentId":   schema.String{Format: "objectId"},
 			"type":       schema.String{Enum: []string{FollowerTypeStream, FollowerTypeUser}},
-			"method":     schema.String{Enum: []string{FollowerMethodActivityPub, FollowerMethodEmail, FollowerMethodWebSub}},
+			"method":     schema.String{Enum: []string{FollowerMethodActivityPub, FollowerMethodEmail, FollowerMethodWebSub, FollowerMethodRSSCloud}},
 			"format":     schema.String{Enum: []string{MimeTypeActivityPub, MimeTypeAtom, MimeTypeHTML, MimeTypeJSONFeed, MimeTypeRSS, MimeTypeXML}},
 			"stateId":    schema.String{Enum: []string{FollowerStateActive, FollowerStatePending}},
 			"actor":      PersonLinkSchema(),
@@ -20,6 +20,11 @@ const FollowerMethodEmail = "EMAIL"
 // https://websub.rocks
 const FollowerMethodWebSub = "WEBSUB"
 
+// FollowerMethodRSSCloud represents a Follower subscription that
+// receives real-time updates via RSSCloud
+// https://www.rssboard.org/rsscloud-interface
+const FollowerMethodRSSCloud = "RSSCLOUD"
+
 // FollowerStateActive represents an active Follower who is currently
 // receiving updates from the Stream
 const FollowerStateActive = "ACTIVE"
@@ -1,8 +1,10 @@
 package model
 
 import (
+	"github.com/EmissarySocial/emissary/tools/id"
 	"github.com/benpate/data/journal"
 	"github.com/benpate/digit"
+	"github.com/benpate/rosetta/sliceof"
 	"github.com/benpate/toot/object"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -10,29 +12,38 @@ import (
 // Following is a model object that represents a user's following to an external data feed.
 // Currently, the only supported feed types are: RSS, Atom, and JSON Feed.  Others may be added in the future.
 type Following struct {
-	FollowingID     primitive.ObjectID `json:"followingId"     bson:"_id"`             // Unique Identifier of this record
-	UserID          primitive.ObjectID `json:"userId"          bson:"userId"`          // ID of the stream that owns this "following"
-	FolderID        primitive.ObjectID `json:"folderId"        bson:"folderId"`        // ID of the folder to put new messages into
-	Folder          string             `json:"folder"          bson:"folder"`          // Name of the folder to put new messages into
-	Label           string             `json:"label"           bson:"label"`           // Label of this "following" record
-	Notes           string             `json:"notes"           bson:"notes"`           // Notes about this "following" record, entered by the user.
-	URL             string             `json:"url"             bson:"url"`             // Human-Facing URL that is being followed.
-	ProfileURL      string             `json:"profileUrl"      bson:"profileUrl"`      // Updated, computer-facing URL that is being followed.
-	IconURL         string             `json:"iconUrl"         bson:"iconUrl"`         // URL of an the avatar/icon image that represents this "following"
-	Behavior        string             `json:"behavior"        bson:"behavior"`        // Behavior determines the types of records to import from this Actor [POSTS+REPLIES]
-	RuleAction      string             `json:"ruleAction"      bson:"ruleAction"`      // RuleAction determines the types of records to rule from this Actor [IGNORE, LABEL, MUTE, BLOCK ]
-	CollapseThreads bool               `json:"collapseThreads" bson:"collapseThreads"` // If TRUE, traverse responses and import the initial post that initiated a thread
-	IsPublic        bool               `json:"isPublic"        bson:"isPublic"`        // If TRUE, this following is visible to the public
-	Links           digit.LinkSet      `json:"links"           bson:"links"`           // List of links can be used to update this following.
-	Method          string             `json:"method"          bson:"method"`          // Method used to update this feed (POLL, WEBSUB, RSS-CLOUD, ACTIVITYPUB)
-	Secret          string             `json:"secret"          bson:"secret"`          // Secret used to authenticate this feed (if required)
-	Status          string             `json:"status"          bson:"status"`          // Status of the last poll of Following (NEW, CONNECTING, POLLING, SUCCESS, FAILURE)
-	StatusMessage   string             `json:"statusMessage"   bson:"statusMessage"`   // Optional message describing the status of the last poll
-	LastPolled      int64              `json:"lastPolled"      bson:"lastPolled"`      // Unix Timestamp of the last date that this resource was retrieved.
-	PollDuration    int                `json:"pollDuration"    bson:"pollDuration"`    // Time (in hours) to wait between polling this resource.
-	NextPoll        int64              `json:"nextPoll"        bson:"nextPoll"`        // Unix Timestamp of the next time that this resource should be polled.
-	PurgeDuration   int                `json:"purgeDuration"   bson:"purgeDuration"`   // Time (in days) to wait before purging old messages
-	ErrorCount      int                `json:"errorCount"      bson:"errorCount"`      // Number of times that this "following" has failed to load (for exponential backoff)
+	FollowingID      primitive.ObjectID `json:"followingId"     bson:"_id"`                         // Unique Identifier of this record
+	UserID           primitive.ObjectID `json:"userId"          bson:"userId"`                      // ID of the stream that owns this "following"
+	FolderID         primitive.ObjectID `json:"folderId"        bson:"folderId"`                    // ID of the folder to put new messages into
+	Folder           string             `json:"folder"          bson:"folder"`                      // Name of the folder to put new messages into
+	Label            string             `json:"label"           bson:"label"`                       // Label of this "following" record
+	Notes            string             `json:"notes"           bson:"notes"`                       // Notes about this "following" record, entered by the user.
+	URL              string             `json:"url"             bson:"url"`                         // Human-Facing URL that is being followed.
+	ProfileURL       string             `json:"profileUrl"      bson:"profileUrl"`                  // Updated, computer-facing URL that is being followed.
+	IconURL          string             `json:"iconUrl"         bson:"iconUrl"`                     // URL of an the avatar/icon image that represents this "following"
+	Behavior         string             `json:"behavior"        bson:"behavior"`                    // Behavior determines the types of records to import from this Actor [POSTS+REPLIES]
+	RuleAction       string             `json:"ruleAction"      bson:"ruleAction"`                  // RuleAction determines the types of records to rule from this Actor [IGNORE, LABEL, MUTE, BLOCK ]
+	CollapseThreads  bool               `json:"collapseThreads" bson:"collapseThreads"`             // If TRUE, traverse responses and import the initial post that initiated a thread
+	IsPublic         bool               `json:"isPublic"        bson:"isPublic"`                    // If TRUE, this following is visible to the public
+	FetchFullContent bool               `json:"fetchFullContent" bson:"fetchFullContent"`           // If TRUE, download and extract the full article for feeds that only publish summaries
+	Links            digit.LinkSet      `json:"links"           bson:"links"`                       // List of links can be used to update this following.
+	Method           string             `json:"method"          bson:"method"`                      // Method used to update this feed (POLL, WEBSUB, RSS-CLOUD, ACTIVITYPUB)
+	Secret           string             `json:"secret"          bson:"secret"`                      // Secret used to authenticate this feed (if required)
+	Status           string             `json:"status"          bson:"status"`                      // Status of the last poll of Following (NEW, CONNECTING, POLLING, SUCCESS, FAILURE)
+	StatusMessage    string             `json:"statusMessage"   bson:"statusMessage"`               // Optional message describing the status of the last poll
+	LastPolled       int64              `json:"lastPolled"      bson:"lastPolled"`                  // Unix Timestamp of the last date that this resource was retrieved.
+	PollDuration     int                `json:"pollDuration"    bson:"pollDuration"`                // Time (in hours) to wait between polling this resource.
+	NextPoll         int64              `json:"nextPoll"        bson:"nextPoll"`                    // Unix Timestamp of the next time that this resource should be polled.
+	Retention        RetentionPolicy    `json:"retention"       bson:"retention"`                   // Rules for purging old messages (overrides the Folder's rules)
+	ErrorCount       int                `json:"errorCount"      bson:"errorCount"`                  // Number of times that this "following" has failed to load (for exponential backoff)
+	FeedURL          string             `json:"feedUrl"         bson:"feedUrl,omitempty"`           // URL of the feed document that is polled for updates
+	FeedETag         string             `json:"feedEtag"         bson:"feedEtag,omitempty"`         // ETag validator returned by the last full poll
+	FeedLastModified string             `json:"feedLastModified" bson:"feedLastModified,omitempty"` // Last-Modified validator returned by the last full poll
+	LastStatusCode   int                `json:"lastStatusCode"  bson:"lastStatusCode,omitempty"`    // HTTP status code returned by the last poll
+	ContentLength    int64              `json:"contentLength"   bson:"contentLength,omitempty"`     // Size (in bytes) of the last full response
+	BytesSaved       int64              `json:"bytesSaved"      bson:"bytesSaved,omitempty"`        // Total bytes that were not downloaded thanks to conditional requests
+	SkipHours        sliceof.Int        `json:"skipHours"       bson:"skipHours,omitempty"`         // Hours (UTC) during which the feed asks not to be polled
+	ListIDs          id.Slice           `json:"listIds"         bson:"listIds,omitempty"`           // IDs of the Folders that this Following is a list member of (Mastodon lists)
 
 	journal.Journal `json:"-" bson:",inline"`
 }
@@ -46,9 +57,10 @@ func NewFollowing() Following {
 		Behavior:        FollowingBehaviorPostsAndReplies,
 		RuleAction:      RuleActionLabel,
 		Links:           make(digit.LinkSet, 0),
+		ListIDs:         make(id.Slice, 0),
 		CollapseThreads: true, // default behavior is to collapse threads
 		PollDuration:    24,   // default poll interval is 24 hours
-		PurgeDuration:   14,   // default purge interval is 14 days
+		Retention:       NewRetentionPolicy(),
 	}
 }
 
@@ -99,6 +111,48 @@ func (following Following) Toot() object.Relationship {
 	}
 }
 
+/******************************************
+ * List Membership
+ ******************************************/
+
+// IsListMember returns TRUE if this Following is a member of the provided list
+func (following *Following) IsListMember(listID primitive.ObjectID) bool {
+
+	for _, existingID := range following.ListIDs {
+		if existingID == listID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AddList adds this Following to a list, avoiding duplicates.
+// It returns TRUE if the Following was changed.
+func (following *Following) AddList(listID primitive.ObjectID) bool {
+
+	if following.IsListMember(listID) {
+		return false
+	}
+
+	following.ListIDs = append(following.ListIDs, listID)
+	return true
+}
+
+// RemoveList removes this Following from a list.
+// It returns TRUE if the Following was changed.
+func (following *Following) RemoveList(listID primitive.ObjectID) bool {
+
+	for index, existingID := range following.ListIDs {
+		if existingID == listID {
+			following.ListIDs = append(following.ListIDs[:index], following.ListIDs[index+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
 /******************************************
  * Other Methods
  ******************************************/
@@ -125,6 +179,12 @@ func (following *Following) SetLinks(newLinks ...digit.Link) {
 	}
 }
 
+// IsFeed returns TRUE if this Following receives updates by reading a feed
+// document (either by polling, or when notified via RSSCloud)
+func (following Following) IsFeed() bool {
+	return (following.Method == FollowingMethodPoll) || (following.Method == FollowingMethodRSSCloud)
+}
+
 func (following Following) IsZero() bool {
 	return (following.UserID == primitive.NilObjectID) && (following.FolderID == primitive.NilObjectID)
 }
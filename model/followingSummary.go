@@ -5,6 +5,7 @@ import "go.mongodb.org/mongo-driver/bson/primitive"
 type FollowingSummary struct {
 	FollowingID primitive.ObjectID `bson:"_id"`
 	URL         string             `bson:"url"`
+	ProfileURL  string             `bson:"profileUrl"`
 	Label       string             `bson:"label"`
 	Folder      string             `bson:"folder"`
 	FolderID    primitive.ObjectID `bson:"folderId"`
@@ -18,7 +19,7 @@ type FollowingSummary struct {
 
 // FollowingSummaryFields returns a slice of all BSON field names for a FollowingSummary
 func FollowingSummaryFields() []string {
-	return []string{"_id", "url", "label", "folder", "folderId", "iconUrl", "method", "status", "lastPolled", "nextPoll", "createDate"}
+	return []string{"_id", "url", "profileUrl", "label", "folder", "folderId", "iconUrl", "method", "status", "lastPolled", "nextPoll", "createDate"}
 }
 
 func (summary FollowingSummary) Fields() []string {
@@ -32,7 +33,7 @@ func (summary FollowingSummary) Icon() string {
 	switch summary.Method {
 	case FollowingMethodActivityPub:
 		icon = "activitypub"
-	case FollowingMethodPoll:
+	case FollowingMethodPoll, FollowingMethodRSSCloud:
 		icon = "rss"
 	case FollowingMethodWebSub:
 		icon = "websub"
@@ -53,7 +54,7 @@ func (summary FollowingSummary) StatusClass() string {
 	switch summary.Status {
 	case FollowingStatusLoading:
 		return "spin"
-	case FollowingStatusFailure:
+	case FollowingStatusFailure, FollowingStatusRejected:
 		return "red"
 	case FollowingStatusSuccess:
 		return "green"
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"github.com/EmissarySocial/emissary/tools/id"
 	"github.com/benpate/rosetta/null"
 	"github.com/benpate/rosetta/schema"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -10,26 +11,35 @@ import (
 func FollowingSchema() schema.Element {
 	return schema.Object{
 		Properties: schema.ElementMap{
-			"followingId":     schema.String{Format: "objectId"},
-			"userId":          schema.String{Format: "objectId"},
-			"folderId":        schema.String{Format: "objectId", Required: true},
-			"label":           schema.String{MaxLength: 128},
-			"notes":           schema.String{MaxLength: 1024},
-			"url":             schema.String{Required: true, MaxLength: 1024},
-			"profileUrl":      schema.String{Format: "url", MaxLength: 1024},
-			"iconUrl":         schema.String{Format: "url", MaxLength: 1024},
-			"behavior":        schema.String{Enum: []string{FollowingBehaviorPosts, FollowingBehaviorPostsAndReplies}, Default: FollowingBehaviorPostsAndReplies, Required: true},
-			"ruleAction":      schema.String{Enum: []string{FollowingRuleActionIgnore, RuleActionMute, RuleActionLabel, RuleActionBlock}, Default: RuleActionLabel, Required: true},
-			"collapseThreads": schema.Boolean{Default: null.NewBool(true)},
-			"isPublic":        schema.Boolean{Default: null.NewBool(false)},
-			"method":          schema.String{Enum: []string{FollowingMethodPoll, FollowingMethodWebSub, FollowingMethodActivityPub}},
-			"status":          schema.String{Enum: []string{FollowingStatusNew, FollowingStatusLoading, FollowingStatusSuccess, FollowingStatusFailure}},
-			"statusMessage":   schema.String{MaxLength: 1024},
-			"lastPolled":      schema.Integer{Minimum: null.NewInt64(0), BitSize: 64},
-			"pollDuration":    schema.Integer{Minimum: null.NewInt64(1)},
-			"purgeDuration":   schema.Integer{Minimum: null.NewInt64(0)},
-			"nextPoll":        schema.Integer{Minimum: null.NewInt64(0), BitSize: 64},
-			"errorCount":      schema.Integer{Minimum: null.NewInt64(0)},
+			"followingId":      schema.String{Format: "objectId"},
+			"userId":           schema.String{Format: "objectId"},
+			"folderId":         schema.String{Format: "objectId", Required: true},
+			"label":            schema.String{MaxLength: 128},
+			"notes":            schema.String{MaxLength: 1024},
+			"url":              schema.String{Required: true, MaxLength: 1024},
+			"profileUrl":       schema.String{Format: "url", MaxLength: 1024},
+			"iconUrl":          schema.String{Format: "url", MaxLength: 1024},
+			"behavior":         schema.String{Enum: []string{FollowingBehaviorPosts, FollowingBehaviorPostsAndReplies}, Default: FollowingBehaviorPostsAndReplies, Required: true},
+			"ruleAction":       schema.String{Enum: []string{FollowingRuleActionIgnore, RuleActionMute, RuleActionLabel, RuleActionBlock}, Default: RuleActionLabel, Required: true},
+			"collapseThreads":  schema.Boolean{Default: null.NewBool(true)},
+			"isPublic":         schema.Boolean{Default: null.NewBool(false)},
+			"fetchFullContent": schema.Boolean{Default: null.NewBool(false)},
+			"method":           schema.String{Enum: []string{FollowingMethodPoll, FollowingMethodWebSub, FollowingMethodRSSCloud, FollowingMethodActivityPub}},
+			"status":           schema.String{Enum: []string{FollowingStatusNew, FollowingStatusLoading, FollowingStatusSuccess, FollowingStatusFailure, FollowingStatusRejected}},
+			"statusMessage":    schema.String{MaxLength: 1024},
+			"lastPolled":       schema.Integer{Minimum: null.NewInt64(0), BitSize: 64},
+			"pollDuration":     schema.Integer{Minimum: null.NewInt64(1)},
+			"retention":        RetentionPolicySchema(),
+			"nextPoll":         schema.Integer{Minimum: null.NewInt64(0), BitSize: 64},
+			"errorCount":       schema.Integer{Minimum: null.NewInt64(0)},
+			"feedUrl":          schema.String{Format: "url", MaxLength: 1024},
+			"feedEtag":         schema.String{MaxLength: 1024},
+			"feedLastModified": schema.String{MaxLength: 128},
+			"lastStatusCode":   schema.Integer{Minimum: null.NewInt64(0)},
+			"contentLength":    schema.Integer{Minimum: null.NewInt64(0), BitSize: 64},
+			"bytesSaved":       schema.Integer{Minimum: null.NewInt64(0), BitSize: 64},
+			"skipHours":        schema.Array{Items: schema.Integer{Minimum: null.NewInt64(0), Maximum: null.NewInt64(23)}},
+			"listIds":          id.SliceSchema(),
 		},
 	}
 }
@@ -68,6 +78,9 @@ func (following *Following) GetPointer(name string) (any, bool) {
 	case "isPublic":
 		return &following.IsPublic, true
 
+	case "fetchFullContent":
+		return &following.FetchFullContent, true
+
 	case "method":
 		return &following.Method, true
 
@@ -88,14 +101,38 @@ func (following *Following) GetPointer(name string) (any, bool) {
 	case "pollDuration":
 		return &following.PollDuration, true
 
-	case "purgeDuration":
-		return &following.PurgeDuration, true
+	case "retention":
+		return &following.Retention, true
 
 	case "nextPoll":
 		return &following.NextPoll, true
 
 	case "errorCount":
 		return &following.ErrorCount, true
+
+	case "feedUrl":
+		return &following.FeedURL, true
+
+	case "feedEtag":
+		return &following.FeedETag, true
+
+	case "feedLastModified":
+		return &following.FeedLastModified, true
+
+	case "lastStatusCode":
+		return &following.LastStatusCode, true
+
+	case "contentLength":
+		return &following.ContentLength, true
+
+	case "bytesSaved":
+		return &following.BytesSaved, true
+
+	case "skipHours":
+		return &following.SkipHours, true
+
+	case "listIds":
+		return &following.ListIDs, true
 	}
 
 	return nil, false
@@ -11,6 +11,10 @@ const FollowingMethodPoll = "POLL"
 // https://websub.rocks
 const FollowingMethodWebSub = "WEBSUB"
 
+// FollowingMethodRSSCloud represents an RSSCloud subscription
+// https://www.rssboard.org/rsscloud-interface
+const FollowingMethodRSSCloud = "RSSCLOUD"
+
 // FollowingStatusNew represents a new following that has not yet been polled
 const FollowingStatusNew = "NEW"
 
@@ -23,6 +27,9 @@ const FollowingStatusSuccess = "SUCCESS"
 // FollowingStatusFailure represents a following that has failed to load
 const FollowingStatusFailure = "FAILURE"
 
+// FollowingStatusRejected represents a following whose follow request was rejected by the remote server
+const FollowingStatusRejected = "REJECTED"
+
 // FollowingRuleActionIgnore declares that Rules published by a followed account should be ignored
 const FollowingRuleActionIgnore = "IGNORE"
 
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func TestFollowingSchema(t *testing.T) {
@@ -23,15 +25,47 @@ func TestFollowingSchema(t *testing.T) {
 		{"ruleAction", RuleActionMute, nil},
 		{"collapseThreads", "false", false},
 		{"isPublic", "true", true},
+		{"fetchFullContent", "true", true},
 		{"method", FollowingMethodActivityPub, nil},
 		{"status", FollowingStatusSuccess, nil},
 		{"statusMessage", "STATUS-MESSAGE", nil},
 		{"lastPolled", "123", int64(123)},
 		{"pollDuration", "42", 42},
 		{"nextPoll", 424242, int64(424242)},
-		{"purgeDuration", "1", 1},
+		{"retention.readDays", "1", 1},
+		{"retention.unreadDays", "30", 30},
+		{"retention.purgeResponses", "true", true},
 		{"errorCount", int64(7), 7},
+		{"feedUrl", "https://other.url/feed.xml", nil},
+		{"feedEtag", `W/"123"`, nil},
+		{"feedLastModified", "Mon, 19 Oct 2026 08:00:00 GMT", nil},
+		{"lastStatusCode", "304", 304},
+		{"contentLength", "2048", int64(2048)},
+		{"bytesSaved", 4096, int64(4096)},
+		{"skipHours.0", "3", 3},
+		{"listIds.0", "000000000000000000000002", nil},
+		{"listIds.1", "000000000000000000000003", nil},
 	}
 
 	tableTest_Schema(t, &s, &following, table)
 }
+
+func TestFollowingLists(t *testing.T) {
+
+	following := NewFollowing()
+	first := primitive.NewObjectID()
+	second := primitive.NewObjectID()
+
+	// Adding lists avoids duplicates
+	require.True(t, following.AddList(first))
+	require.True(t, following.AddList(second))
+	require.False(t, following.AddList(first))
+	require.Equal(t, 2, len(following.ListIDs))
+	require.True(t, following.IsListMember(first))
+
+	// Removing lists only changes lists that are present
+	require.True(t, following.RemoveList(first))
+	require.False(t, following.RemoveList(first))
+	require.False(t, following.IsListMember(first))
+	require.True(t, following.IsListMember(second))
+}
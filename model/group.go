@@ -11,6 +11,8 @@ type Group struct {
 	Token   string             `json:"token"   bson:"token"` // Uniqe token chosen by the administrator
 	Label   string             `json:"label"   bson:"label"` // Human-readable label for this group.
 
+	StorageQuota int64 `json:"storageQuota" bson:"storageQuota"` // Amount of storage (in megabytes) that members of this group can upload.  Zero means use the domain default.
+
 	journal.Journal `json:"-" bson:",inline"`
 }
 
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"github.com/benpate/rosetta/null"
 	"github.com/benpate/rosetta/schema"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -11,6 +12,8 @@ func GroupSchema() schema.Element {
 			"groupId": schema.String{Format: "objectId"},
 			"token":   schema.String{MaxLength: 64},
 			"label":   schema.String{MaxLength: 64, Required: true},
+
+			"storageQuota": schema.Integer{Minimum: null.NewInt64(0), BitSize: 64},
 		},
 	}
 }
@@ -19,6 +22,17 @@ func GroupSchema() schema.Element {
  * Getter Interfaces
  ******************************************/
 
+func (group *Group) GetPointer(name string) (any, bool) {
+
+	switch name {
+
+	case "storageQuota":
+		return &group.StorageQuota, true
+	}
+
+	return nil, false
+}
+
 func (group *Group) GetStringOK(name string) (string, bool) {
 
 	switch name {
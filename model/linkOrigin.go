@@ -52,6 +52,9 @@ func (origin OriginLink) Icon() string {
 
 	case OriginTypeAnnounce:
 		return "star"
+
+	case OriginTypeHashtag:
+		return "hashtag"
 	}
 
 	return "question-square"
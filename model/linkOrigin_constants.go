@@ -14,3 +14,6 @@ const OriginTypeLike = "LIKE"
 
 // OriginTypeBoost identifies a link that was retrieved because of a "Dislike" of an existing post
 const OriginTypeDislike = "DISLIKE"
+
+// OriginTypeHashtag identifies a link that was retrieved because it includes a hashtag that the User follows
+const OriginTypeHashtag = "HASHTAG"
@@ -9,12 +9,13 @@ import (
 )
 
 type PersonLink struct {
-	UserID       primitive.ObjectID `json:"userId,omitempty"       bson:"userId,omitempty"`       // Internal ID of the person (if they exist in this database)
-	Name         string             `json:"name,omitempty"         bson:"name,omitempty"`         // Name of the person
-	ProfileURL   string             `json:"profileUrl,omitempty"   bson:"profileUrl,omitempty"`   // URL of the person's profile
-	InboxURL     string             `json:"inboxUrl,omitempty"     bson:"inboxUrl,omitempty"`     // URL of the person's inbox
-	EmailAddress string             `json:"emailAddress,omitempty" bson:"emailAddress,omitempty"` // Email address of the person
-	IconURL      string             `json:"iconUrl,omitempty"      bson:"iconUrl,omitempty"`      // URL of the person's avatar/icon image
+	UserID         primitive.ObjectID `json:"userId,omitempty"         bson:"userId,omitempty"`         // Internal ID of the person (if they exist in this database)
+	Name           string             `json:"name,omitempty"           bson:"name,omitempty"`           // Name of the person
+	ProfileURL     string             `json:"profileUrl,omitempty"     bson:"profileUrl,omitempty"`     // URL of the person's profile
+	InboxURL       string             `json:"inboxUrl,omitempty"       bson:"inboxUrl,omitempty"`       // URL of the person's inbox
+	SharedInboxURL string             `json:"sharedInboxUrl,omitempty" bson:"sharedInboxUrl,omitempty"` // URL of the shared inbox for the person's server (if available)
+	EmailAddress   string             `json:"emailAddress,omitempty"   bson:"emailAddress,omitempty"`   // Email address of the person
+	IconURL        string             `json:"iconUrl,omitempty"        bson:"iconUrl,omitempty"`        // URL of the person's avatar/icon image
 }
 
 func NewPersonLink() PersonLink {
@@ -62,7 +63,7 @@ func (person PersonLink) GetJSONLD() mapof.Any {
 
 // GetURL gets a named property value of this person,
 // then retuns it as a parsed URL.  Only "profileUrl"
-// "inboxUrl" and "iconUrl" should be passed to this
+// "inboxUrl", "sharedInboxUrl", and "iconUrl" should be passed to this
 // function. all others will return nil values
 func (person PersonLink) GetURL(name string) *url.URL {
 	value, _ := person.GetStringOK(name)
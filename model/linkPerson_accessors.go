@@ -9,12 +9,13 @@ func PersonLinkSchema() schema.Element {
 
 	return schema.Object{
 		Properties: schema.ElementMap{
-			"userId":       schema.String{Format: "objectId"},
-			"name":         schema.String{MaxLength: 128},
-			"profileUrl":   schema.String{Format: "url", MaxLength: 1024},
-			"inboxUrl":     schema.String{Format: "url", MaxLength: 1024},
-			"iconUrl":      schema.String{Format: "url", MaxLength: 1024},
-			"emailAddress": schema.String{Format: "email", MaxLength: 128},
+			"userId":         schema.String{Format: "objectId"},
+			"name":           schema.String{MaxLength: 128},
+			"profileUrl":     schema.String{Format: "url", MaxLength: 1024},
+			"inboxUrl":       schema.String{Format: "url", MaxLength: 1024},
+			"sharedInboxUrl": schema.String{Format: "url", MaxLength: 1024},
+			"iconUrl":        schema.String{Format: "url", MaxLength: 1024},
+			"emailAddress":   schema.String{Format: "email", MaxLength: 128},
 		},
 	}
 }
@@ -35,6 +36,9 @@ func (link *PersonLink) GetPointer(name string) (any, bool) {
 	case "inboxUrl":
 		return &link.InboxURL, true
 
+	case "sharedInboxUrl":
+		return &link.SharedInboxURL, true
+
 	case "emailAddress":
 		return &link.EmailAddress, true
 
@@ -16,6 +16,7 @@ func TestPersonLink(t *testing.T) {
 		{"name", "John Connor", nil},
 		{"profileUrl", "https://john.connor.mil", nil},
 		{"inboxUrl", "https://john.connor.mil/inbox", nil},
+		{"sharedInboxUrl", "https://john.connor.mil/shared-inbox", nil},
 		{"emailAddress", "john.connor@mil", nil},
 		{"iconUrl", "https://john.connor.mil/image", nil},
 	}
@@ -12,20 +12,27 @@ import (
 
 // Message represents a single item in a User's inbox.
 type Message struct {
-	MessageID   primitive.ObjectID         `json:"messageId"    bson:"_id"`                   // Unique ID of the Message
-	UserID      primitive.ObjectID         `json:"userId"       bson:"userId"`                // Unique ID of the User who owns this Message
-	FollowingID primitive.ObjectID         `json:"followingId"  bson:"followingId,omitempty"` // Unique ID of the Following record that generated this Message
-	FolderID    primitive.ObjectID         `json:"folderId"     bson:"folderId,omitempty"`    // Unique ID of the Folder where this Message is stored
-	SocialRole  string                     `json:"socialRole"   bson:"socialRole,omitempty"`  // Role this message plays in social integrations ("Article", "Note", etc)
-	Origin      OriginLink                 `json:"origin"       bson:"origin,omitempty"`      // Link to the original source of this Message (the following and website that originally published it)
-	References  sliceof.Object[OriginLink] `json:"references"   bson:"references,omitempty"`  // Links to other references to this Message - likes, reposts, or comments that informed us of its existence
-	URL         string                     `json:"url"          bson:"url"`                   // URL of this Message
-	InReplyTo   string                     `json:"inReplyTo"    bson:"inReplyTo,omitempty"`   // URL this message is in reply to
-	MyResponse  string                     `json:"myResponse"   bson:"myResponse,omitempty"`  // If the owner of this message has responded, then this field contains the responseType (Like, Dislike, Repost)
-	StateID     string                     `json:"stateId"      bson:"stateId"`               // StateID of this message (UNREAD,READ,MUTED,NEW-REPLIES)
-	PublishDate int64                      `json:"publishDate"  bson:"publishDate,omitempty"` // Unix timestamp of the date/time when this Message was published
-	ReadDate    int64                      `json:"readDate"     bson:"readDate"`              // Unix timestamp of the date/time when this Message was read.  If unread, this is MaxInt64.
-	Rank        int64                      `json:"rank"         bson:"rank"`                  // Sort rank for this message (publishDate * 1000 + sequence number)
+	MessageID       primitive.ObjectID         `json:"messageId"     bson:"_id"`                         // Unique ID of the Message
+	UserID          primitive.ObjectID         `json:"userId"        bson:"userId"`                      // Unique ID of the User who owns this Message
+	FollowingID     primitive.ObjectID         `json:"followingId"   bson:"followingId,omitempty"`       // Unique ID of the Following record that generated this Message
+	FolderID        primitive.ObjectID         `json:"folderId"      bson:"folderId,omitempty"`          // Unique ID of the Folder where this Message is stored
+	SocialRole      string                     `json:"socialRole"    bson:"socialRole,omitempty"`        // Role this message plays in social integrations ("Article", "Note", etc)
+	Origin          OriginLink                 `json:"origin"        bson:"origin,omitempty"`            // Link to the original source of this Message (the following and website that originally published it)
+	References      sliceof.Object[OriginLink] `json:"references"    bson:"references,omitempty"`        // Links to other references to this Message - likes, reposts, or comments that informed us of its existence
+	URL             string                     `json:"url"           bson:"url"`                         // URL of this Message
+	Domain          string                     `json:"domain"        bson:"domain,omitempty"`            // Hostname of the server that published this Message
+	AttributedTo    PersonLink                 `json:"attributedTo"  bson:"attributedTo,omitempty"`      // Author of this Message
+	SearchText      string                     `json:"searchText"    bson:"searchText,omitempty"`        // Plain text (name, summary, and content) used by smart folder keyword searches
+	Hashtags        sliceof.String             `json:"hashtags"      bson:"hashtags,omitempty"`          // Normalized hashtags of this Message (public Messages only), used by hashtag timelines and trends
+	Links           sliceof.String             `json:"links"         bson:"links,omitempty"`             // External links in this Message (public Messages only), used by trends
+	HasAttachment   bool                       `json:"hasAttachment" bson:"hasAttachment,omitempty"`     // TRUE if this Message includes attachments (images, audio, video, etc)
+	InReplyTo       string                     `json:"inReplyTo"     bson:"inReplyTo,omitempty"`         // URL this message is in reply to
+	InReplyToAuthor string                     `json:"inReplyToAuthor" bson:"inReplyToAuthor,omitempty"` // Profile URL of the author of the message that this message replies to
+	MyResponse      string                     `json:"myResponse"    bson:"myResponse,omitempty"`        // If the owner of this message has responded, then this field contains the responseType (Like, Dislike, Repost)
+	StateID         string                     `json:"stateId"       bson:"stateId"`                     // StateID of this message (UNREAD,READ,MUTED,NEW-REPLIES)
+	PublishDate     int64                      `json:"publishDate"   bson:"publishDate,omitempty"`       // Unix timestamp of the date/time when this Message was published
+	ReadDate        int64                      `json:"readDate"      bson:"readDate"`                    // Unix timestamp of the date/time when this Message was read.  If unread, this is MaxInt64.
+	Rank            int64                      `json:"rank"          bson:"rank"`                        // Sort rank for this message (publishDate * 1000 + sequence number)
 
 	journal.Journal `json:"-" bson:",inline"`
 }
@@ -268,6 +275,38 @@ func (message *Message) AddReference(reference OriginLink) bool {
 	return true
 }
 
+// RemoveReference removes a reference from this message.  If the reference was also
+// the message Origin, then the Origin is replaced by the next remaining reference.
+// It returns TRUE if the message has been updated.
+func (message *Message) RemoveReference(reference OriginLink) bool {
+
+	// Remove the reference from the list
+	references := sliceof.NewObject[OriginLink]()
+
+	for _, existing := range message.References {
+		if !existing.Equals(reference) {
+			references = append(references, existing)
+		}
+	}
+
+	if len(references) == len(message.References) {
+		return false
+	}
+
+	message.References = references
+
+	// If the reference was also the Origin, then replace it
+	if message.Origin.Equals(reference) {
+		if len(references) > 0 {
+			message.Origin = references[0]
+		} else {
+			message.Origin = NewOriginLink()
+		}
+	}
+
+	return true
+}
+
 // SetMyResponse
 func (message *Message) SetMyResponse(responseType string) {
 	message.MyResponse = responseType
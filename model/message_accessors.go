@@ -10,20 +10,27 @@ import (
 func MessageSchema() schema.Element {
 	return schema.Object{
 		Properties: schema.ElementMap{
-			"messageId":   schema.String{Format: "objectId"},
-			"userId":      schema.String{Format: "objectId"},
-			"followingId": schema.String{Format: "objectId"},
-			"folderId":    schema.String{Format: "objectId"},
-			"socialRole":  schema.String{MaxLength: 64},
-			"origin":      OriginLinkSchema(),
-			"references":  schema.Array{Items: OriginLinkSchema()},
-			"url":         schema.String{Format: "url"},
-			"inReplyTo":   schema.String{Format: "url"},
-			"myResponse":  schema.String{Enum: []string{vocab.ActivityTypeAnnounce, vocab.ActivityTypeLike, vocab.ActivityTypeDislike}},
-			"stateId":     schema.String{Enum: []string{MessageStateUnread, MessageStateRead, MessageStateMuted, MessageStateNewReplies}},
-			"publishDate": schema.Integer{BitSize: 64},
-			"readDate":    schema.Integer{BitSize: 64},
-			"rank":        schema.Integer{BitSize: 64},
+			"messageId":       schema.String{Format: "objectId"},
+			"userId":          schema.String{Format: "objectId"},
+			"followingId":     schema.String{Format: "objectId"},
+			"folderId":        schema.String{Format: "objectId"},
+			"socialRole":      schema.String{MaxLength: 64},
+			"origin":          OriginLinkSchema(),
+			"references":      schema.Array{Items: OriginLinkSchema()},
+			"url":             schema.String{Format: "url"},
+			"domain":          schema.String{MaxLength: 256},
+			"attributedTo":    PersonLinkSchema(),
+			"searchText":      schema.String{},
+			"hashtags":        schema.Array{Items: schema.String{MaxLength: 128}},
+			"links":           schema.Array{Items: schema.String{Format: "url"}},
+			"hasAttachment":   schema.Boolean{},
+			"inReplyTo":       schema.String{Format: "url"},
+			"inReplyToAuthor": schema.String{Format: "url"},
+			"myResponse":      schema.String{Enum: []string{vocab.ActivityTypeAnnounce, vocab.ActivityTypeLike, vocab.ActivityTypeDislike}},
+			"stateId":         schema.String{Enum: []string{MessageStateUnread, MessageStateRead, MessageStateMuted, MessageStateNewReplies}},
+			"publishDate":     schema.Integer{BitSize: 64},
+			"readDate":        schema.Integer{BitSize: 64},
+			"rank":            schema.Integer{BitSize: 64},
 		},
 	}
 }
@@ -47,9 +54,30 @@ func (message *Message) GetPointer(name string) (any, bool) {
 	case "url":
 		return &message.URL, true
 
+	case "domain":
+		return &message.Domain, true
+
+	case "attributedTo":
+		return &message.AttributedTo, true
+
+	case "searchText":
+		return &message.SearchText, true
+
+	case "hashtags":
+		return &message.Hashtags, true
+
+	case "links":
+		return &message.Links, true
+
+	case "hasAttachment":
+		return &message.HasAttachment, true
+
 	case "inReplyTo":
 		return &message.InReplyTo, true
 
+	case "inReplyToAuthor":
+		return &message.InReplyToAuthor, true
+
 	case "myResponse":
 		return &message.MyResponse, true
 
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMessageSchema(t *testing.T) {
@@ -21,7 +22,15 @@ func TestMessageSchema(t *testing.T) {
 		{"references.0.url", "https://first.reference.url", nil},
 		{"references.1.url", "https://another.reference.url", nil},
 		{"url", "https://message.url", nil},
+		{"domain", "message.url", nil},
+		{"attributedTo.name", "Author Name", nil},
+		{"attributedTo.profileUrl", "https://message.url/@author", nil},
+		{"searchText", "plain text of the message", nil},
+		{"hashtags.0", "emissary", nil},
+		{"links.0", "https://example.com/article", nil},
+		{"hasAttachment", true, nil},
 		{"inReplyTo", "https://url.com", nil},
+		{"inReplyToAuthor", "https://url.com/@author", nil},
 		{"myResponse", "Announce", nil},
 		{"stateId", "UNREAD", nil},
 		{"publishDate", "123", int64(123)},
@@ -31,3 +40,26 @@ func TestMessageSchema(t *testing.T) {
 
 	tableTest_Schema(t, &s, &activity, table)
 }
+
+func TestMessageRemoveReference(t *testing.T) {
+
+	first := OriginLink{Type: OriginTypeAnnounce, URL: "https://first.url"}
+	second := OriginLink{Type: OriginTypeAnnounce, URL: "https://second.url"}
+
+	message := NewMessage()
+	require.True(t, message.AddReference(first))
+	require.True(t, message.AddReference(second))
+
+	// Removing the Origin promotes the next reference
+	require.True(t, message.RemoveReference(first))
+	require.Equal(t, second, message.Origin)
+	require.Equal(t, 1, len(message.References))
+
+	// Removing a missing reference does nothing
+	require.False(t, message.RemoveReference(first))
+
+	// Removing the last reference empties the Origin
+	require.True(t, message.RemoveReference(second))
+	require.True(t, message.Origin.IsEmpty())
+	require.Zero(t, len(message.References))
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"github.com/benpate/data/journal"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Relay is a subscription to an ActivityPub relay.  The Domain's instance actor follows
+// the relay, which then forwards public posts from every other subscribed server into
+// this Domain's public timeline.  Local public posts can optionally be published back
+// to the relay.
+type Relay struct {
+	RelayID         primitive.ObjectID `json:"relayId"         bson:"_id"`                    // Unique identifier for this Relay
+	Label           string             `json:"label"           bson:"label"`                  // Human-friendly label for this Relay
+	URL             string             `json:"url"             bson:"url"`                    // URL entered by the administrator (either the relay's actor or its inbox)
+	Type            string             `json:"type"            bson:"type"`                   // Relay protocol (LITEPUB or MASTODON)
+	ActorID         string             `json:"actorId"         bson:"actorId,omitempty"`      // ActivityPub ID of the relay actor (if known)
+	InboxURL        string             `json:"inboxUrl"        bson:"inboxUrl"`               // Inbox where activities are delivered to the relay
+	FollowID        string             `json:"followId"        bson:"followId"`               // ID of the Follow activity that subscribed to this relay
+	StateID         string             `json:"stateId"         bson:"stateId"`                // Subscription state (PENDING, ACTIVE, REJECTED, FAILURE)
+	StateMessage    string             `json:"stateMessage"    bson:"stateMessage,omitempty"` // Error message from the last subscription attempt, if any
+	Publish         bool               `json:"publish"         bson:"publish"`                // If TRUE, then local public posts are published to this relay
+	MessageCount    int64              `json:"messageCount"    bson:"messageCount"`           // Number of messages received from this relay
+	LastMessageDate int64              `json:"lastMessageDate" bson:"lastMessageDate"`        // Unix timestamp of the last message received from this relay
+
+	journal.Journal `json:"-" bson:",inline"`
+}
+
+// NewRelay returns a fully initialized Relay object
+func NewRelay() Relay {
+	return Relay{
+		RelayID: primitive.NewObjectID(),
+		StateID: RelayStatePending,
+	}
+}
+
+/******************************************
+ * data.Object Interface
+ ******************************************/
+
+// ID returns the primary key of this object
+func (relay Relay) ID() string {
+	return relay.RelayID.Hex()
+}
+
+// Fields returns the subset of fields that are queried when listing Relays
+func (relay Relay) Fields() []string {
+	return []string{
+		"_id",
+		"label",
+		"url",
+		"type",
+		"actorId",
+		"inboxUrl",
+		"followId",
+		"stateId",
+		"stateMessage",
+		"publish",
+		"messageCount",
+		"lastMessageDate",
+	}
+}
+
+/******************************************
+ * Other Data Accessors
+ ******************************************/
+
+// IsActive returns TRUE if the relay has accepted our subscription
+func (relay Relay) IsActive() bool {
+	return relay.StateID == RelayStateActive
+}
+
+// IsLitePub returns TRUE if this relay uses the LitePub protocol
+func (relay Relay) IsLitePub() bool {
+	return relay.Type == RelayTypeLitePub
+}
+
+// FollowObject returns the object of the Follow activity sent to this relay.
+// LitePub relays are followed directly, while Mastodon relays are asked to
+// deliver the public collection.
+func (relay Relay) FollowObject() string {
+
+	if relay.IsLitePub() {
+		return relay.ActorID
+	}
+
+	return "https://www.w3.org/ns/activitystreams#Public"
+}
+
+// SetState updates the subscription state of this Relay
+func (relay *Relay) SetState(stateID string, stateMessage string) {
+	relay.StateID = stateID
+	relay.StateMessage = stateMessage
+}
+
+// StateLabel returns a human-friendly label for the subscription state
+func (relay Relay) StateLabel() string {
+
+	switch relay.StateID {
+
+	case RelayStateActive:
+		return "Active"
+
+	case RelayStateRejected:
+		return "Rejected"
+
+	case RelayStateFailure:
+		return "Failed"
+	}
+
+	return "Pending"
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"time"
+
+	"github.com/benpate/data/journal"
+	"github.com/benpate/rosetta/sliceof"
+	"github.com/benpate/toot/object"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RelayMessage is a public post that was forwarded to this Domain by an ActivityPub relay.
+// RelayMessages make up the Domain's public (federated) timeline, and are purged after
+// a short retention period.
+type RelayMessage struct {
+	RelayMessageID primitive.ObjectID `json:"relayMessageId" bson:"_id"`                   // Unique identifier for this RelayMessage
+	RelayID        primitive.ObjectID `json:"relayId"        bson:"relayId"`               // ID of the Relay that delivered this message
+	URL            string             `json:"url"            bson:"url"`                   // ActivityPub ID of the original document
+	AttributedTo   PersonLink         `json:"attributedTo"   bson:"attributedTo"`          // Author of the original document
+	Summary        string             `json:"summary"        bson:"summary,omitempty"`     // Summary / content warning of the original document
+	ContentHTML    string             `json:"contentHtml"    bson:"contentHtml,omitempty"` // HTML content of the original document
+	Hashtags       sliceof.String     `json:"hashtags"       bson:"hashtags,omitempty"`    // Normalized hashtags of the original document
+	Sensitive      bool               `json:"sensitive"      bson:"sensitive,omitempty"`   // If TRUE, then the original document is marked as sensitive
+	PublishDate    int64              `json:"publishDate"    bson:"publishDate"`           // Unix timestamp when the original document was published
+
+	journal.Journal `json:"-" bson:",inline"`
+}
+
+// NewRelayMessage returns a fully initialized RelayMessage object
+func NewRelayMessage() RelayMessage {
+	return RelayMessage{
+		RelayMessageID: primitive.NewObjectID(),
+		AttributedTo:   NewPersonLink(),
+	}
+}
+
+/******************************************
+ * data.Object Interface
+ ******************************************/
+
+// ID returns the primary key of this object
+func (message RelayMessage) ID() string {
+	return message.RelayMessageID.Hex()
+}
+
+/******************************************
+ * Mastodon API
+ ******************************************/
+
+// Toot returns this RelayMessage as a public Mastodon Status
+func (message RelayMessage) Toot() object.Status {
+
+	return object.Status{
+		ID:          message.RelayMessageID.Hex(),
+		URI:         message.URL,
+		URL:         message.URL,
+		CreatedAt:   time.Unix(message.PublishDate, 0).Format(time.RFC3339),
+		Account:     message.AttributedTo.Toot(),
+		Content:     message.ContentHTML,
+		SpoilerText: message.Summary,
+		Sensitive:   message.Sensitive,
+		Visibility:  "public",
+	}
+}
+
+// GetRank returns the value used to page through the public timeline
+func (message RelayMessage) GetRank() int64 {
+	return message.CreateDate
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RelayMessageSchema returns a Rosetta Schema for the RelayMessage object
+func RelayMessageSchema() schema.Element {
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"relayMessageId": schema.String{Format: "objectId"},
+			"relayId":        schema.String{Format: "objectId"},
+			"url":            schema.String{Format: "url", Required: true},
+			"attributedTo":   PersonLinkSchema(),
+			"summary":        schema.String{},
+			"contentHtml":    schema.String{Format: "html"},
+			"hashtags":       schema.Array{Items: schema.String{MaxLength: 128}},
+			"sensitive":      schema.Boolean{},
+			"publishDate":    schema.Integer{BitSize: 64},
+		},
+	}
+}
+
+/******************************************
+ * Getter/Setter Interfaces
+ ******************************************/
+
+func (message *RelayMessage) GetPointer(name string) (any, bool) {
+
+	switch name {
+
+	case "url":
+		return &message.URL, true
+
+	case "attributedTo":
+		return &message.AttributedTo, true
+
+	case "summary":
+		return &message.Summary, true
+
+	case "contentHtml":
+		return &message.ContentHTML, true
+
+	case "hashtags":
+		return &message.Hashtags, true
+
+	case "sensitive":
+		return &message.Sensitive, true
+
+	case "publishDate":
+		return &message.PublishDate, true
+	}
+
+	return nil, false
+}
+
+func (message *RelayMessage) GetStringOK(name string) (string, bool) {
+
+	switch name {
+
+	case "relayMessageId":
+		return message.RelayMessageID.Hex(), true
+
+	case "relayId":
+		return message.RelayID.Hex(), true
+	}
+
+	return "", false
+}
+
+func (message *RelayMessage) SetString(name string, value string) bool {
+
+	switch name {
+
+	case "relayMessageId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			message.RelayMessageID = objectID
+			return true
+		}
+
+	case "relayId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			message.RelayID = objectID
+			return true
+		}
+	}
+
+	return false
+}
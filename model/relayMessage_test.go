@@ -0,0 +1,46 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayMessageSchema(t *testing.T) {
+
+	message := NewRelayMessage()
+	s := schema.New(RelayMessageSchema())
+
+	table := []tableTestItem{
+		{"relayMessageId", "123456781234567812345678", nil},
+		{"relayId", "876543218765432187654321", nil},
+		{"url", "https://remote.social/notes/1", nil},
+		{"attributedTo.name", "Alice", nil},
+		{"attributedTo.profileUrl", "https://remote.social/@alice", nil},
+		{"summary", "Spoilers", nil},
+		{"contentHtml", "<p>Hello World</p>", nil},
+		{"hashtags.0", "emissary", nil},
+		{"sensitive", "true", true},
+		{"publishDate", int64(1234567890), nil},
+	}
+
+	tableTest_Schema(t, &s, &message, table)
+}
+
+func TestRelayMessageToot(t *testing.T) {
+
+	message := NewRelayMessage()
+	message.URL = "https://remote.social/notes/1"
+	message.AttributedTo.ProfileURL = "https://remote.social/@alice"
+	message.AttributedTo.Name = "Alice"
+	message.ContentHTML = "<p>Hello World</p>"
+
+	status := message.Toot()
+
+	require.Equal(t, "public", status.Visibility)
+	require.Equal(t, "https://remote.social/notes/1", status.URI)
+	require.Equal(t, "https://remote.social/@alice", status.Account.ID)
+	require.Equal(t, "Alice", status.Account.DisplayName)
+	require.Equal(t, "<p>Hello World</p>", status.Content)
+}
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RelaySchema returns a Rosetta Schema for the Relay object
+func RelaySchema() schema.Element {
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"relayId":         schema.String{Format: "objectId"},
+			"label":           schema.String{MaxLength: 128},
+			"url":             schema.String{Format: "url", Required: true},
+			"type":            schema.String{Enum: []string{RelayTypeLitePub, RelayTypeMastodon}},
+			"actorId":         schema.String{Format: "url"},
+			"inboxUrl":        schema.String{Format: "url"},
+			"followId":        schema.String{Format: "url"},
+			"stateId":         schema.String{Enum: []string{RelayStatePending, RelayStateActive, RelayStateRejected, RelayStateFailure}},
+			"stateMessage":    schema.String{MaxLength: 1024},
+			"publish":         schema.Boolean{},
+			"messageCount":    schema.Integer{BitSize: 64},
+			"lastMessageDate": schema.Integer{BitSize: 64},
+		},
+	}
+}
+
+/******************************************
+ * Getter/Setter Interfaces
+ ******************************************/
+
+func (relay *Relay) GetPointer(name string) (any, bool) {
+
+	switch name {
+
+	case "label":
+		return &relay.Label, true
+
+	case "url":
+		return &relay.URL, true
+
+	case "type":
+		return &relay.Type, true
+
+	case "actorId":
+		return &relay.ActorID, true
+
+	case "inboxUrl":
+		return &relay.InboxURL, true
+
+	case "followId":
+		return &relay.FollowID, true
+
+	case "stateId":
+		return &relay.StateID, true
+
+	case "stateMessage":
+		return &relay.StateMessage, true
+
+	case "publish":
+		return &relay.Publish, true
+
+	case "messageCount":
+		return &relay.MessageCount, true
+
+	case "lastMessageDate":
+		return &relay.LastMessageDate, true
+	}
+
+	return nil, false
+}
+
+func (relay *Relay) GetStringOK(name string) (string, bool) {
+
+	switch name {
+
+	case "relayId":
+		return relay.RelayID.Hex(), true
+	}
+
+	return "", false
+}
+
+func (relay *Relay) SetString(name string, value string) bool {
+
+	switch name {
+
+	case "relayId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			relay.RelayID = objectID
+			return true
+		}
+	}
+
+	return false
+}
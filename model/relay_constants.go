@@ -0,0 +1,19 @@
+package model
+
+// RelayStatePending signifies a Relay subscription that has been sent, but not yet accepted
+const RelayStatePending = "PENDING"
+
+// RelayStateActive signifies a Relay subscription that has been accepted by the relay
+const RelayStateActive = "ACTIVE"
+
+// RelayStateRejected signifies a Relay subscription that was rejected by the relay
+const RelayStateRejected = "REJECTED"
+
+// RelayStateFailure signifies a Relay subscription that could not be sent
+const RelayStateFailure = "FAILURE"
+
+// RelayTypeLitePub identifies a relay that is followed as an actor (Pleroma, Akkoma, and most relay servers)
+const RelayTypeLitePub = "LITEPUB"
+
+// RelayTypeMastodon identifies a relay that is sent a Follow of the public collection at its inbox
+const RelayTypeMastodon = "MASTODON"
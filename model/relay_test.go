@@ -0,0 +1,43 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelaySchema(t *testing.T) {
+
+	relay := NewRelay()
+	s := schema.New(RelaySchema())
+
+	table := []tableTestItem{
+		{"relayId", "123456781234567812345678", nil},
+		{"label", "Friendly Relay", nil},
+		{"url", "https://relay.example.com/actor", nil},
+		{"type", "LITEPUB", nil},
+		{"actorId", "https://relay.example.com/actor", nil},
+		{"inboxUrl", "https://relay.example.com/inbox", nil},
+		{"followId", "https://local.social/pub/actor/relays/123456781234567812345678", nil},
+		{"stateId", "ACTIVE", nil},
+		{"stateMessage", "Not Found", nil},
+		{"publish", "true", true},
+		{"messageCount", int64(42), nil},
+		{"lastMessageDate", int64(1234567890), nil},
+	}
+
+	tableTest_Schema(t, &s, &relay, table)
+}
+
+func TestRelayFollowObject(t *testing.T) {
+
+	relay := NewRelay()
+	relay.ActorID = "https://relay.example.com/actor"
+
+	relay.Type = RelayTypeLitePub
+	require.Equal(t, "https://relay.example.com/actor", relay.FollowObject())
+
+	relay.Type = RelayTypeMastodon
+	require.Equal(t, "https://www.w3.org/ns/activitystreams#Public", relay.FollowObject())
+}
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/benpate/data/journal"
+	"github.com/benpate/rosetta/sliceof"
+	"github.com/benpate/toot/object"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Report represents a moderation report about an account or its content.  Reports can be
+// filed by local Users, or received from other servers as ActivityPub "Flag" activities,
+// and are reviewed by Domain Owners in the admin report queue.
+type Report struct {
+	ReportID    primitive.ObjectID             `json:"reportId"    bson:"_id"`                   // Unique identifier for this Report
+	OriginID    string                         `json:"originId"    bson:"originId"`              // Where this Report came from (LOCAL or REMOTE)
+	ReporterID  primitive.ObjectID             `json:"reporterId"  bson:"reporterId,omitempty"`  // ID of the local User who filed this Report (if LOCAL)
+	ReporterURL string                         `json:"reporterUrl" bson:"reporterUrl"`           // ActivityPub actor ID of the account that filed this Report
+	TargetURL   string                         `json:"targetUrl"   bson:"targetUrl"`             // ActivityPub actor ID of the account being reported
+	ObjectURLs  sliceof.String                 `json:"objectUrls"  bson:"objectUrls,omitempty"`  // URLs of the documents being reported
+	Snapshots   sliceof.Object[ReportSnapshot] `json:"snapshots"   bson:"snapshots,omitempty"`   // Copies of the reported documents, taken when the Report was filed
+	Category    string                         `json:"category"    bson:"category"`              // Category of this Report (SPAM, VIOLATION, OTHER)
+	Comment     string                         `json:"comment"     bson:"comment,omitempty"`     // Explanation from the reporter
+	ActivityID  string                         `json:"activityId"  bson:"activityId,omitempty"`  // ID of the "Flag" activity that was received or sent for this Report
+	Forward     bool                           `json:"forward"     bson:"forward,omitempty"`     // TRUE if the reporter asked for this Report to be forwarded to the remote server
+	ForwardDate int64                          `json:"forwardDate" bson:"forwardDate,omitempty"` // Unix timestamp when this Report was forwarded to the remote server
+	StatusID    string                         `json:"statusId"    bson:"statusId"`              // Current status of this Report (PENDING, RESOLVED, DISMISSED)
+	ResolvedBy  primitive.ObjectID             `json:"resolvedBy"  bson:"resolvedBy,omitempty"`  // ID of the Domain Owner who closed this Report
+	ResolveDate int64                          `json:"resolveDate" bson:"resolveDate,omitempty"` // Unix timestamp when this Report was closed
+
+	journal.Journal `json:"-" bson:",inline"`
+}
+
+// ReportSnapshot is a copy of a reported document, so that moderators can review it
+// even if the original has been edited or deleted.
+type ReportSnapshot struct {
+	URL          string `json:"url"          bson:"url"`                    // URL of the reported document
+	Type         string `json:"type"         bson:"type,omitempty"`         // ActivityStreams type of the reported document
+	AttributedTo string `json:"attributedTo" bson:"attributedTo,omitempty"` // Actor ID of the document's author
+	Name         string `json:"name"         bson:"name,omitempty"`         // Name/title of the reported document
+	Summary      string `json:"summary"      bson:"summary,omitempty"`      // Summary (content warning) of the reported document
+	Content      string `json:"content"      bson:"content,omitempty"`      // Sanitized HTML content of the reported document
+	Published    int64  `json:"published"    bson:"published,omitempty"`    // Unix timestamp when the document was published
+}
+
+// NewReport returns a fully initialized Report object
+func NewReport() Report {
+	return Report{
+		ReportID:   primitive.NewObjectID(),
+		ObjectURLs: sliceof.NewString(),
+		Snapshots:  sliceof.NewObject[ReportSnapshot](),
+		Category:   ReportCategoryOther,
+		StatusID:   ReportStatusPending,
+	}
+}
+
+/******************************************
+ * data.Object Interface
+ ******************************************/
+
+// ID returns the primary key of this object
+func (report Report) ID() string {
+	return report.ReportID.Hex()
+}
+
+// Fields returns the subset of fields that are queried when listing Reports
+func (report Report) Fields() []string {
+	return []string{
+		"_id",
+		"originId",
+		"reporterUrl",
+		"targetUrl",
+		"category",
+		"comment",
+		"statusId",
+		"forward",
+		"forwardDate",
+		"createDate",
+	}
+}
+
+/******************************************
+ * Other Data Accessors
+ ******************************************/
+
+// IsPending returns TRUE if this Report has not yet been reviewed
+func (report Report) IsPending() bool {
+	return report.StatusID == ReportStatusPending
+}
+
+// IsLocal returns TRUE if this Report was filed by a local User
+func (report Report) IsLocal() bool {
+	return report.OriginID == ReportOriginLocal
+}
+
+// IsForwarded returns TRUE if this Report has been forwarded to the remote server
+func (report Report) IsForwarded() bool {
+	return report.ForwardDate > 0
+}
+
+// CategoryLabel returns a human-friendly label for this Report's category
+func (report Report) CategoryLabel() string {
+
+	switch report.Category {
+
+	case ReportCategorySpam:
+		return "Spam"
+
+	case ReportCategoryViolation:
+		return "Rule Violation"
+	}
+
+	return "Other"
+}
+
+/******************************************
+ * Mastodon API
+ ******************************************/
+
+// Toot returns this Report in the format used by the Mastodon API
+func (report Report) Toot() object.Report {
+
+	result := object.Report{
+		ID:           report.ReportID.Hex(),
+		ActionTaken:  !report.IsPending(),
+		Category:     strings.ToLower(report.Category),
+		Comment:      report.Comment,
+		Forwarded:    report.Forward,
+		CreatedAt:    time.Unix(report.CreateDate, 0).Format(time.RFC3339),
+		StatusIDs:    report.ObjectURLs,
+		TargetAcount: object.Account{ID: report.TargetURL, URL: report.TargetURL},
+	}
+
+	if report.ResolveDate > 0 {
+		result.ActionTakenAt = time.Unix(report.ResolveDate, 0).Format(time.RFC3339)
+	}
+
+	return result
+}
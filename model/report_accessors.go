@@ -0,0 +1,168 @@
+package model
+
+import (
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReportSchema returns a Rosetta Schema for the Report object
+func ReportSchema() schema.Element {
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"reportId":    schema.String{Format: "objectId"},
+			"originId":    schema.String{Enum: []string{ReportOriginLocal, ReportOriginRemote}, Required: true},
+			"reporterId":  schema.String{Format: "objectId"},
+			"reporterUrl": schema.String{Format: "url", Required: true},
+			"targetUrl":   schema.String{Format: "url", Required: true},
+			"objectUrls":  schema.Array{Items: schema.String{Format: "url"}, MaxLength: 32},
+			"snapshots":   schema.Array{Items: ReportSnapshotSchema(), MaxLength: 32},
+			"category":    schema.String{Enum: []string{ReportCategorySpam, ReportCategoryViolation, ReportCategoryOther}},
+			"comment":     schema.String{MaxLength: 2048},
+			"activityId":  schema.String{Format: "url"},
+			"forward":     schema.Boolean{},
+			"forwardDate": schema.Integer{BitSize: 64},
+			"statusId":    schema.String{Enum: []string{ReportStatusPending, ReportStatusResolved, ReportStatusDismissed}},
+			"resolvedBy":  schema.String{Format: "objectId"},
+			"resolveDate": schema.Integer{BitSize: 64},
+		},
+	}
+}
+
+// ReportSnapshotSchema returns a Rosetta Schema for the ReportSnapshot object
+func ReportSnapshotSchema() schema.Element {
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"url":          schema.String{Format: "url"},
+			"type":         schema.String{MaxLength: 64},
+			"attributedTo": schema.String{Format: "url"},
+			"name":         schema.String{MaxLength: 1024},
+			"summary":      schema.String{MaxLength: 1024},
+			"content":      schema.String{Format: "html"},
+			"published":    schema.Integer{BitSize: 64},
+		},
+	}
+}
+
+/******************************************
+ * Getter/Setter Interfaces
+ ******************************************/
+
+func (report *Report) GetPointer(name string) (any, bool) {
+
+	switch name {
+
+	case "originId":
+		return &report.OriginID, true
+
+	case "reporterUrl":
+		return &report.ReporterURL, true
+
+	case "targetUrl":
+		return &report.TargetURL, true
+
+	case "objectUrls":
+		return &report.ObjectURLs, true
+
+	case "snapshots":
+		return &report.Snapshots, true
+
+	case "category":
+		return &report.Category, true
+
+	case "comment":
+		return &report.Comment, true
+
+	case "activityId":
+		return &report.ActivityID, true
+
+	case "forward":
+		return &report.Forward, true
+
+	case "forwardDate":
+		return &report.ForwardDate, true
+
+	case "statusId":
+		return &report.StatusID, true
+
+	case "resolveDate":
+		return &report.ResolveDate, true
+	}
+
+	return nil, false
+}
+
+func (report *Report) GetStringOK(name string) (string, bool) {
+
+	switch name {
+
+	case "reportId":
+		return report.ReportID.Hex(), true
+
+	case "reporterId":
+		return report.ReporterID.Hex(), true
+
+	case "resolvedBy":
+		return report.ResolvedBy.Hex(), true
+	}
+
+	return "", false
+}
+
+func (report *Report) SetString(name string, value string) bool {
+
+	switch name {
+
+	case "reportId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			report.ReportID = objectID
+			return true
+		}
+
+	case "reporterId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			report.ReporterID = objectID
+			return true
+		}
+
+	case "resolvedBy":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			report.ResolvedBy = objectID
+			return true
+		}
+	}
+
+	return false
+}
+
+/******************************************
+ * ReportSnapshot Getter/Setter Interfaces
+ ******************************************/
+
+func (snapshot *ReportSnapshot) GetPointer(name string) (any, bool) {
+
+	switch name {
+
+	case "url":
+		return &snapshot.URL, true
+
+	case "type":
+		return &snapshot.Type, true
+
+	case "attributedTo":
+		return &snapshot.AttributedTo, true
+
+	case "name":
+		return &snapshot.Name, true
+
+	case "summary":
+		return &snapshot.Summary, true
+
+	case "content":
+		return &snapshot.Content, true
+
+	case "published":
+		return &snapshot.Published, true
+	}
+
+	return nil, false
+}
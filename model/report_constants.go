@@ -0,0 +1,25 @@
+package model
+
+// ReportOriginLocal signifies a Report that was filed by a local User
+const ReportOriginLocal = "LOCAL"
+
+// ReportOriginRemote signifies a Report that was received from another server as a "Flag" activity
+const ReportOriginRemote = "REMOTE"
+
+// ReportStatusPending signifies a Report that is waiting to be reviewed
+const ReportStatusPending = "PENDING"
+
+// ReportStatusResolved signifies a Report that has been reviewed and acted upon
+const ReportStatusResolved = "RESOLVED"
+
+// ReportStatusDismissed signifies a Report that has been reviewed and required no action
+const ReportStatusDismissed = "DISMISSED"
+
+// ReportCategorySpam signifies a Report about unsolicited or repetitive content
+const ReportCategorySpam = "SPAM"
+
+// ReportCategoryViolation signifies a Report about content that breaks the server rules
+const ReportCategoryViolation = "VIOLATION"
+
+// ReportCategoryOther signifies a Report that does not fit into another category
+const ReportCategoryOther = "OTHER"
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/schema"
+)
+
+func TestReportSchema(t *testing.T) {
+
+	report := NewReport()
+	s := schema.New(ReportSchema())
+
+	table := []tableTestItem{
+		{"reportId", "123456781234567812345678", nil},
+		{"originId", "REMOTE", nil},
+		{"reporterId", "876543218765432187654321", nil},
+		{"reporterUrl", "https://remote.social/users/reporter", nil},
+		{"targetUrl", "https://remote.social/users/target", nil},
+		{"objectUrls.0", "https://remote.social/notes/1", nil},
+		{"snapshots.0.url", "https://remote.social/notes/1", nil},
+		{"snapshots.0.type", "Note", nil},
+		{"snapshots.0.attributedTo", "https://remote.social/users/target", nil},
+		{"snapshots.0.content", "<p>Hello</p>", nil},
+		{"snapshots.0.published", int64(1234567890), nil},
+		{"category", "SPAM", nil},
+		{"comment", "This is spam", nil},
+		{"activityId", "https://remote.social/flags/1", nil},
+		{"forward", true, nil},
+		{"forwardDate", int64(1234567890), nil},
+		{"statusId", "RESOLVED", nil},
+		{"resolvedBy", "abcdef218765432187654321", nil},
+		{"resolveDate", int64(1234567890), nil},
+	}
+
+	tableTest_Schema(t, &s, &report, table)
+}
@@ -0,0 +1,92 @@
+package model
+
+import "time"
+
+// RetentionPolicy defines how long inbox Messages are kept before they are purged.
+// Policies can be set on a Folder and on each Following.  Values on a Following
+// override the values of its Folder, which override the system defaults.
+type RetentionPolicy struct {
+	ReadDays       int  `json:"readDays"       bson:"readDays,omitempty"`       // Days to keep Messages after they have been read (0 = inherit, -1 = forever)
+	UnreadDays     int  `json:"unreadDays"     bson:"unreadDays,omitempty"`     // Days to keep Messages that have not been read (0 = inherit, -1 = forever)
+	PurgeResponses bool `json:"purgeResponses" bson:"purgeResponses,omitempty"` // If TRUE, then Messages that the User has liked, bookmarked, or shared are also purged
+}
+
+// NewRetentionPolicy returns a fully initialized RetentionPolicy that inherits all values
+func NewRetentionPolicy() RetentionPolicy {
+	return RetentionPolicy{}
+}
+
+// DefaultRetentionPolicy returns the system-wide policy that is used when
+// neither the Following nor its Folder define a value.
+func DefaultRetentionPolicy() RetentionPolicy {
+	return RetentionPolicy{
+		ReadDays:   RetentionDefaultReadDays,
+		UnreadDays: RetentionForever,
+	}
+}
+
+// Inherit returns a new RetentionPolicy that fills any inherited (zero) values
+// in this policy with the values from the parent policy.
+func (policy RetentionPolicy) Inherit(parent RetentionPolicy) RetentionPolicy {
+
+	if policy.ReadDays == 0 {
+		policy.ReadDays = parent.ReadDays
+	}
+
+	if policy.UnreadDays == 0 {
+		policy.UnreadDays = parent.UnreadDays
+	}
+
+	policy.PurgeResponses = policy.PurgeResponses || parent.PurgeResponses
+
+	return policy
+}
+
+// ReadCutoff returns the Unix timestamp before which read Messages are purged.
+// It returns 0 if read Messages are kept forever.
+func (policy RetentionPolicy) ReadCutoff(now time.Time) int64 {
+	return retentionCutoff(now, policy.ReadDays)
+}
+
+// UnreadCutoff returns the Unix timestamp before which unread Messages are purged.
+// It returns 0 if unread Messages are kept forever.
+func (policy RetentionPolicy) UnreadCutoff(now time.Time) int64 {
+	return retentionCutoff(now, policy.UnreadDays)
+}
+
+// IsForever returns TRUE if this policy never purges any Messages
+func (policy RetentionPolicy) IsForever() bool {
+	return (policy.ReadDays <= 0) && (policy.UnreadDays <= 0)
+}
+
+func retentionCutoff(now time.Time, days int) int64 {
+
+	if days <= 0 {
+		return 0
+	}
+
+	return now.AddDate(0, 0, -days).Unix()
+}
+
+/******************************************
+ * Retention Reports
+ ******************************************/
+
+// RetentionReport summarizes the Messages that were (or would be) purged by a RetentionPolicy
+type RetentionReport struct {
+	Read   int `json:"read"`   // Number of read Messages that were purged
+	Unread int `json:"unread"` // Number of unread Messages that were purged
+	Kept   int `json:"kept"`   // Number of expired Messages that were kept because the User responded to them
+}
+
+// Add combines the values of another report into this one
+func (report *RetentionReport) Add(other RetentionReport) {
+	report.Read += other.Read
+	report.Unread += other.Unread
+	report.Kept += other.Kept
+}
+
+// Total returns the total number of Messages purged
+func (report RetentionReport) Total() int {
+	return report.Read + report.Unread
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"github.com/benpate/rosetta/null"
+	"github.com/benpate/rosetta/schema"
+)
+
+// RetentionPolicySchema returns a JSON Schema for RetentionPolicy structures
+func RetentionPolicySchema() schema.Element {
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"readDays":       schema.Integer{Minimum: null.NewInt64(RetentionForever)},
+			"unreadDays":     schema.Integer{Minimum: null.NewInt64(RetentionForever)},
+			"purgeResponses": schema.Boolean{},
+		},
+	}
+}
+
+/*********************************
+ * Getter Interfaces
+ *********************************/
+
+func (policy *RetentionPolicy) GetPointer(name string) (any, bool) {
+	switch name {
+
+	case "readDays":
+		return &policy.ReadDays, true
+
+	case "unreadDays":
+		return &policy.UnreadDays, true
+
+	case "purgeResponses":
+		return &policy.PurgeResponses, true
+	}
+
+	return nil, false
+}
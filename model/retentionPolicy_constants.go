@@ -0,0 +1,11 @@
+package model
+
+// RetentionInherit means that a RetentionPolicy value is inherited from its parent
+const RetentionInherit = 0
+
+// RetentionForever means that Messages are never purged
+const RetentionForever = -1
+
+// RetentionDefaultReadDays is the number of days that read Messages are kept
+// when no other policy has been defined.
+const RetentionDefaultReadDays = 14
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetentionPolicySchema(t *testing.T) {
+
+	policy := NewRetentionPolicy()
+	s := schema.New(RetentionPolicySchema())
+
+	table := []tableTestItem{
+		{"readDays", 7, nil},
+		{"unreadDays", -1, nil},
+		{"purgeResponses", true, nil},
+	}
+
+	tableTest_Schema(t, &s, &policy, table)
+}
+
+func TestRetentionPolicyInherit(t *testing.T) {
+
+	folder := RetentionPolicy{ReadDays: 30, PurgeResponses: true}
+	following := RetentionPolicy{UnreadDays: 60}
+
+	result := following.Inherit(folder).Inherit(DefaultRetentionPolicy())
+	require.Equal(t, 30, result.ReadDays)
+	require.Equal(t, 60, result.UnreadDays)
+	require.True(t, result.PurgeResponses)
+
+	// Empty policies use the system defaults
+	result = NewRetentionPolicy().Inherit(NewRetentionPolicy()).Inherit(DefaultRetentionPolicy())
+	require.Equal(t, RetentionDefaultReadDays, result.ReadDays)
+	require.Equal(t, RetentionForever, result.UnreadDays)
+	require.False(t, result.IsForever())
+}
+
+func TestRetentionPolicyCutoff(t *testing.T) {
+
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+	policy := RetentionPolicy{ReadDays: 7, UnreadDays: RetentionForever}
+
+	require.Equal(t, time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC).Unix(), policy.ReadCutoff(now))
+	require.Zero(t, policy.UnreadCutoff(now))
+
+	policy.ReadDays = RetentionForever
+	require.True(t, policy.IsForever())
+}
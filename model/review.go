@@ -0,0 +1,133 @@
+package model
+
+import (
+	"github.com/EmissarySocial/emissary/tools/id"
+	"github.com/benpate/data/journal"
+	"github.com/benpate/rosetta/slice"
+	"github.com/benpate/rosetta/sliceof"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Review represents a request to move a Stream through an editorial workflow Transition.
+// Reviews are assigned to one or more reviewers, and record each approval (or rejection)
+// until the Transition is complete.
+type Review struct {
+	ReviewID     primitive.ObjectID           `json:"reviewId"     bson:"_id"`          // Unique identifier for this Review
+	StreamID     primitive.ObjectID           `json:"streamId"     bson:"streamId"`     // ID of the Stream being reviewed
+	TransitionID string                       `json:"transitionId" bson:"transitionId"` // ID of the Template Transition being requested
+	FromStateID  string                       `json:"fromStateId"  bson:"fromStateId"`  // State of the Stream when this Review was requested
+	ToStateID    string                       `json:"toStateId"    bson:"toStateId"`    // State that the Stream will move into once this Review is approved
+	RequestedBy  PersonLink                   `json:"requestedBy"  bson:"requestedBy"`  // User who requested this Review
+	AssigneeIDs  id.Slice                     `json:"assigneeIds"  bson:"assigneeIds"`  // IDs of the Users who have been asked to review this Stream
+	Approvals    sliceof.Object[ReviewRecord] `json:"approvals"    bson:"approvals"`    // Decisions made by reviewers on this Review
+	Required     int                          `json:"required"     bson:"required"`     // Number of approvals required to complete this Review
+	Comment      string                       `json:"comment"      bson:"comment"`      // Note from the requester to the reviewers
+	StatusID     string                       `json:"statusId"     bson:"statusId"`     // Current status of this Review (PENDING, APPROVED, REJECTED, CANCELED)
+	CompleteDate int64                        `json:"completeDate" bson:"completeDate"` // Unix timestamp when this Review was completed
+
+	journal.Journal `json:"-" bson:",inline"`
+}
+
+// ReviewRecord records a single reviewer's decision on a Review
+type ReviewRecord struct {
+	Reviewer PersonLink `json:"reviewer" bson:"reviewer"` // User who made this decision
+	Decision string     `json:"decision" bson:"decision"` // Decision made by the reviewer (APPROVE or REJECT)
+	Comment  string     `json:"comment"  bson:"comment"`  // Optional comment explaining the decision
+	Date     int64      `json:"date"     bson:"date"`     // Unix timestamp when this decision was made
+}
+
+// NewReview returns a fully initialized Review object
+func NewReview() Review {
+	return Review{
+		ReviewID:    primitive.NewObjectID(),
+		AssigneeIDs: id.NewSlice(),
+		Approvals:   sliceof.NewObject[ReviewRecord](),
+		Required:    1,
+		StatusID:    ReviewStatusPending,
+	}
+}
+
+/******************************************
+ * data.Object Interface
+ ******************************************/
+
+// ID returns the primary key of this object
+func (review Review) ID() string {
+	return review.ReviewID.Hex()
+}
+
+/******************************************
+ * Other Data Accessors
+ ******************************************/
+
+// IsPending returns TRUE if this Review is still waiting on reviewers
+func (review Review) IsPending() bool {
+	return review.StatusID == ReviewStatusPending
+}
+
+// IsApproved returns TRUE if this Review has received all required approvals
+func (review Review) IsApproved() bool {
+	return review.StatusID == ReviewStatusApproved
+}
+
+// IsAssigned returns TRUE if the provided User has been asked to review this Stream
+func (review Review) IsAssigned(userID primitive.ObjectID) bool {
+	return slice.Contains(review.AssigneeIDs, userID)
+}
+
+// HasDecided returns TRUE if the provided User has already recorded a decision on this Review
+func (review Review) HasDecided(userID primitive.ObjectID) bool {
+	for _, record := range review.Approvals {
+		if record.Reviewer.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// ApprovalCount returns the number of reviewers who have approved this Review
+func (review Review) ApprovalCount() int {
+	result := 0
+	for _, record := range review.Approvals {
+		if record.Decision == ReviewDecisionApprove {
+			result++
+		}
+	}
+	return result
+}
+
+// RemainingApprovals returns the number of approvals still required to complete this Review
+func (review Review) RemainingApprovals() int {
+	return max(review.Required-review.ApprovalCount(), 0)
+}
+
+/******************************************
+ * RoleStateEnumerator Interface
+ ******************************************/
+
+// State returns the current state of this object.
+func (review Review) State() string {
+	return review.StatusID
+}
+
+// Roles returns a list of all roles that match the provided authorization.
+// Reviews are visible to the User who requested them (MagicRoleAuthor)
+// and to the Users who have been assigned to them (MagicRoleMyself).
+func (review Review) Roles(authorization *Authorization) []string {
+
+	result := []string{}
+
+	if !authorization.IsAuthenticated() {
+		return result
+	}
+
+	if review.RequestedBy.UserID == authorization.UserID {
+		result = append(result, MagicRoleAuthor)
+	}
+
+	if review.IsAssigned(authorization.UserID) {
+		result = append(result, MagicRoleMyself)
+	}
+
+	return result
+}
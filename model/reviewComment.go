@@ -0,0 +1,62 @@
+package model
+
+import (
+	"github.com/benpate/data/journal"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReviewComment is an inline editorial comment that a reviewer leaves on a Stream.
+// Comments are private to the editorial team, and are never published or federated.
+type ReviewComment struct {
+	ReviewCommentID primitive.ObjectID `json:"reviewCommentId" bson:"_id"`                // Unique identifier for this ReviewComment
+	StreamID        primitive.ObjectID `json:"streamId"        bson:"streamId"`           // ID of the Stream being commented on
+	ReviewID        primitive.ObjectID `json:"reviewId"        bson:"reviewId,omitempty"` // ID of the Review that this comment belongs to (if any)
+	Author          PersonLink         `json:"author"          bson:"author"`             // User who wrote this comment
+	Path            string             `json:"path"            bson:"path"`               // Path of the Stream field being commented on (e.g. "content", "label")
+	Selection       string             `json:"selection"       bson:"selection"`          // Excerpt of the text being commented on
+	Body            string             `json:"body"            bson:"body"`               // Text of the comment
+	IsResolved      bool               `json:"isResolved"      bson:"isResolved"`         // If TRUE, then this comment has been addressed
+	ResolvedBy      PersonLink         `json:"resolvedBy"      bson:"resolvedBy"`         // User who resolved this comment
+
+	journal.Journal `json:"-" bson:",inline"`
+}
+
+// NewReviewComment returns a fully initialized ReviewComment object
+func NewReviewComment() ReviewComment {
+	return ReviewComment{
+		ReviewCommentID: primitive.NewObjectID(),
+	}
+}
+
+/******************************************
+ * data.Object Interface
+ ******************************************/
+
+// ID returns the primary key of this object
+func (comment ReviewComment) ID() string {
+	return comment.ReviewCommentID.Hex()
+}
+
+/******************************************
+ * RoleStateEnumerator Interface
+ ******************************************/
+
+// State returns the current state of this object.
+func (comment ReviewComment) State() string {
+	if comment.IsResolved {
+		return "resolved"
+	}
+	return "open"
+}
+
+// Roles returns a list of all roles that match the provided authorization.
+// Only the comment's author receives MagicRoleAuthor.  Everyone else must be
+// granted access by the Stream that this comment belongs to.
+func (comment ReviewComment) Roles(authorization *Authorization) []string {
+
+	if authorization.IsAuthenticated() && (comment.Author.UserID == authorization.UserID) {
+		return []string{MagicRoleAuthor}
+	}
+
+	return []string{}
+}
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReviewCommentSchema returns a Rosetta Schema for the ReviewComment object
+func ReviewCommentSchema() schema.Element {
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"reviewCommentId": schema.String{Format: "objectId"},
+			"streamId":        schema.String{Format: "objectId", Required: true},
+			"reviewId":        schema.String{Format: "objectId"},
+			"author":          PersonLinkSchema(),
+			"path":            schema.String{MaxLength: 128},
+			"selection":       schema.String{MaxLength: 1024},
+			"body":            schema.String{MaxLength: 4096, Required: true},
+			"isResolved":      schema.Boolean{},
+			"resolvedBy":      PersonLinkSchema(),
+		},
+	}
+}
+
+/******************************************
+ * Getter/Setter Interfaces
+ ******************************************/
+
+func (comment *ReviewComment) GetPointer(name string) (any, bool) {
+
+	switch name {
+
+	case "author":
+		return &comment.Author, true
+
+	case "path":
+		return &comment.Path, true
+
+	case "selection":
+		return &comment.Selection, true
+
+	case "body":
+		return &comment.Body, true
+
+	case "isResolved":
+		return &comment.IsResolved, true
+
+	case "resolvedBy":
+		return &comment.ResolvedBy, true
+	}
+
+	return nil, false
+}
+
+func (comment *ReviewComment) GetStringOK(name string) (string, bool) {
+
+	switch name {
+
+	case "reviewCommentId":
+		return comment.ReviewCommentID.Hex(), true
+
+	case "streamId":
+		return comment.StreamID.Hex(), true
+
+	case "reviewId":
+		return comment.ReviewID.Hex(), true
+	}
+
+	return "", false
+}
+
+func (comment *ReviewComment) SetString(name string, value string) bool {
+
+	switch name {
+
+	case "reviewCommentId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			comment.ReviewCommentID = objectID
+			return true
+		}
+
+	case "streamId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			comment.StreamID = objectID
+			return true
+		}
+
+	case "reviewId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			comment.ReviewID = objectID
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/schema"
+)
+
+func TestReviewCommentSchema(t *testing.T) {
+
+	comment := NewReviewComment()
+	s := schema.New(ReviewCommentSchema())
+
+	table := []tableTestItem{
+		{"reviewCommentId", "123456781234567812345678", nil},
+		{"streamId", "876543218765432187654321", nil},
+		{"reviewId", "abcdef218765432187654321", nil},
+		{"author.name", "Sarah Connor", nil},
+		{"path", "content", nil},
+		{"selection", "Judgement Day", nil},
+		{"body", "Can we rephrase this?", nil},
+		{"isResolved", true, nil},
+		{"resolvedBy.name", "John Connor", nil},
+	}
+
+	tableTest_Schema(t, &s, &comment, table)
+}
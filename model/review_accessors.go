@@ -0,0 +1,138 @@
+package model
+
+import (
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReviewSchema returns a Rosetta Schema for the Review object
+func ReviewSchema() schema.Element {
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"reviewId":     schema.String{Format: "objectId"},
+			"streamId":     schema.String{Format: "objectId", Required: true},
+			"transitionId": schema.String{MaxLength: 128, Required: true},
+			"fromStateId":  schema.String{MaxLength: 128},
+			"toStateId":    schema.String{MaxLength: 128, Required: true},
+			"requestedBy":  PersonLinkSchema(),
+			"assigneeIds":  schema.Array{Items: schema.String{Format: "objectId"}},
+			"approvals":    schema.Array{Items: ReviewRecordSchema()},
+			"required":     schema.Integer{},
+			"comment":      schema.String{MaxLength: 2048},
+			"statusId":     schema.String{Enum: []string{ReviewStatusPending, ReviewStatusApproved, ReviewStatusRejected, ReviewStatusCanceled}},
+			"completeDate": schema.Integer{BitSize: 64},
+		},
+	}
+}
+
+// ReviewRecordSchema returns a Rosetta Schema for the ReviewRecord object
+func ReviewRecordSchema() schema.Element {
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"reviewer": PersonLinkSchema(),
+			"decision": schema.String{Enum: []string{ReviewDecisionApprove, ReviewDecisionReject}},
+			"comment":  schema.String{MaxLength: 2048},
+			"date":     schema.Integer{BitSize: 64},
+		},
+	}
+}
+
+/******************************************
+ * Getter/Setter Interfaces
+ ******************************************/
+
+func (review *Review) GetPointer(name string) (any, bool) {
+
+	switch name {
+
+	case "transitionId":
+		return &review.TransitionID, true
+
+	case "fromStateId":
+		return &review.FromStateID, true
+
+	case "toStateId":
+		return &review.ToStateID, true
+
+	case "requestedBy":
+		return &review.RequestedBy, true
+
+	case "assigneeIds":
+		return &review.AssigneeIDs, true
+
+	case "approvals":
+		return &review.Approvals, true
+
+	case "required":
+		return &review.Required, true
+
+	case "comment":
+		return &review.Comment, true
+
+	case "statusId":
+		return &review.StatusID, true
+
+	case "completeDate":
+		return &review.CompleteDate, true
+	}
+
+	return nil, false
+}
+
+func (review *Review) GetStringOK(name string) (string, bool) {
+
+	switch name {
+
+	case "reviewId":
+		return review.ReviewID.Hex(), true
+
+	case "streamId":
+		return review.StreamID.Hex(), true
+	}
+
+	return "", false
+}
+
+func (review *Review) SetString(name string, value string) bool {
+
+	switch name {
+
+	case "reviewId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			review.ReviewID = objectID
+			return true
+		}
+
+	case "streamId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			review.StreamID = objectID
+			return true
+		}
+	}
+
+	return false
+}
+
+/******************************************
+ * ReviewRecord Getter/Setter Interfaces
+ ******************************************/
+
+func (record *ReviewRecord) GetPointer(name string) (any, bool) {
+
+	switch name {
+
+	case "reviewer":
+		return &record.Reviewer, true
+
+	case "decision":
+		return &record.Decision, true
+
+	case "comment":
+		return &record.Comment, true
+
+	case "date":
+		return &record.Date, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,19 @@
+package model
+
+// ReviewStatusPending signifies a Review that is waiting on one or more reviewers
+const ReviewStatusPending = "PENDING"
+
+// ReviewStatusApproved signifies a Review that has received all required approvals
+const ReviewStatusApproved = "APPROVED"
+
+// ReviewStatusRejected signifies a Review that was rejected by a reviewer
+const ReviewStatusRejected = "REJECTED"
+
+// ReviewStatusCanceled signifies a Review that was withdrawn before it was completed
+const ReviewStatusCanceled = "CANCELED"
+
+// ReviewDecisionApprove signifies a reviewer who approved the requested Transition
+const ReviewDecisionApprove = "APPROVE"
+
+// ReviewDecisionReject signifies a reviewer who rejected the requested Transition
+const ReviewDecisionReject = "REJECT"
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestReviewSchema(t *testing.T) {
+
+	review := NewReview()
+	s := schema.New(ReviewSchema())
+
+	table := []tableTestItem{
+		{"reviewId", "123456781234567812345678", nil},
+		{"streamId", "876543218765432187654321", nil},
+		{"transitionId", "submit", nil},
+		{"fromStateId", "draft", nil},
+		{"toStateId", "in-review", nil},
+		{"requestedBy.name", "Sarah Connor", nil},
+		{"assigneeIds.0", "abcdef218765432187654321", nil},
+		{"approvals.0.decision", "APPROVE", nil},
+		{"approvals.0.comment", "Looks great", nil},
+		{"approvals.0.reviewer.name", "John Connor", nil},
+		{"required", 2, nil},
+		{"comment", "Please review", nil},
+		{"statusId", "APPROVED", nil},
+		{"completeDate", int64(1234567890), nil},
+	}
+
+	tableTest_Schema(t, &s, &review, table)
+}
+
+func TestReview_Approvals(t *testing.T) {
+
+	sarah := primitive.NewObjectID()
+	john := primitive.NewObjectID()
+
+	review := NewReview()
+	review.Required = 2
+	review.AssigneeIDs = append(review.AssigneeIDs, sarah, john)
+
+	require.True(t, review.IsAssigned(sarah))
+	require.False(t, review.IsAssigned(primitive.NewObjectID()))
+	require.Equal(t, 2, review.RemainingApprovals())
+
+	review.Approvals = append(review.Approvals, ReviewRecord{
+		Reviewer: PersonLink{UserID: sarah},
+		Decision: ReviewDecisionApprove,
+	})
+
+	require.True(t, review.HasDecided(sarah))
+	require.False(t, review.HasDecided(john))
+	require.Equal(t, 1, review.ApprovalCount())
+	require.Equal(t, 1, review.RemainingApprovals())
+}
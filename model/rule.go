@@ -8,17 +8,19 @@ import (
 
 // Rule represents many kinds of filters that are applied to messages before they are added into a User's inbox
 type Rule struct {
-	RuleID         primitive.ObjectID `json:"ruleId"         bson:"_id"`            // Unique identifier of this Rule
-	UserID         primitive.ObjectID `json:"userId"         bson:"userId"`         // Unique identifier of the User who owns this Rule
-	FollowingID    primitive.ObjectID `json:"followingId"    bson:"followingId"`    // Unique identifier of the Following record that created this Rule.  If Zero, then this rule was created by the user.
-	FollowingLabel string             `json:"followingLabel" bson:"followingLabel"` // Label of the Following record that created this Rule.
-	Type           string             `json:"type"           bson:"type"`           // Type of Rule (e.g. "ACTOR", "DOMAIN", "CONTENT")
-	Action         string             `json:"action"         bson:"action"`         // Action to take when this rule is triggered (e.g. "BLOCK", "MUTE", "LABEL")
-	Label          string             `json:"label"          bson:"label"`          // Human-friendly label to add to messages
-	Trigger        string             `json:"trigger"        bson:"trigger"`        // Parameter for this rule type)
-	Summary        string             `json:"summary"        bson:"summary"`        // Optional comment describing why this rule exists
-	IsPublic       bool               `json:"isPublic"       bson:"isPublic"`       // If TRUE, this record is visible publicly
-	PublishDate    int64              `json:"publishDate"    bson:"publishDate"`    // Unix timestamp when this rule was published to followers
+	RuleID         primitive.ObjectID `json:"ruleId"         bson:"_id"`                      // Unique identifier of this Rule
+	UserID         primitive.ObjectID `json:"userId"         bson:"userId"`                   // Unique identifier of the User who owns this Rule
+	FollowingID    primitive.ObjectID `json:"followingId"    bson:"followingId"`              // Unique identifier of the Following record that created this Rule.  If Zero, then this rule was created by the user.
+	FollowingLabel string             `json:"followingLabel" bson:"followingLabel"`           // Label of the Following record that created this Rule.
+	BlocklistID    primitive.ObjectID `json:"blocklistId"    bson:"blocklistId,omitempty"`    // Unique identifier of the Blocklist subscription that created this Rule.  If Zero, then this rule was not imported from a Blocklist.
+	BlocklistLabel string             `json:"blocklistLabel" bson:"blocklistLabel,omitempty"` // Label of the Blocklist subscription that created this Rule.
+	Type           string             `json:"type"           bson:"type"`                     // Type of Rule (e.g. "ACTOR", "DOMAIN", "CONTENT")
+	Action         string             `json:"action"         bson:"action"`                   // Action to take when this rule is triggered (e.g. "BLOCK", "MUTE", "LABEL")
+	Label          string             `json:"label"          bson:"label"`                    // Human-friendly label to add to messages
+	Trigger        string             `json:"trigger"        bson:"trigger"`                  // Parameter for this rule type)
+	Summary        string             `json:"summary"        bson:"summary"`                  // Optional comment describing why this rule exists
+	IsPublic       bool               `json:"isPublic"       bson:"isPublic"`                 // If TRUE, this record is visible publicly
+	PublishDate    int64              `json:"publishDate"    bson:"publishDate"`              // Unix timestamp when this rule was published to followers
 
 	journal.Journal `json:"-" bson:",inline"`
 }
@@ -45,6 +47,8 @@ func (rule Rule) Fields() []string {
 		"_id",
 		"userId",
 		"followingId",
+		"blocklistId",
+		"blocklistLabel",
 		"type",
 		"action",
 		"label",
@@ -120,7 +124,12 @@ func (rule Rule) OriginRemote() bool {
 	return !rule.OriginUser()
 }
 
+// OriginBlocklist returns TRUE if this Rule was imported from a Blocklist subscription.
+func (rule Rule) OriginBlocklist() bool {
+	return !rule.BlocklistID.IsZero()
+}
+
 // OriginUser returns TRUE if this Rule was created by the User.
 func (rule Rule) OriginUser() bool {
-	return rule.FollowingID.IsZero()
+	return rule.FollowingID.IsZero() && rule.BlocklistID.IsZero()
 }
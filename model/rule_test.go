@@ -17,6 +17,8 @@ func TestRuleSchema(t *testing.T) {
 		{"userId", "876543218765432187654321", nil},
 		{"followingId", "876543218765432187654321", nil},
 		{"followingLabel", "Hoo boy", nil},
+		{"blocklistId", "abcdef218765432187654321", nil},
+		{"blocklistLabel", "Shared Denylist", nil},
 		{"type", "ACTOR", nil},
 		{"action", "LABEL", nil},
 		{"label", "LABEL", nil},
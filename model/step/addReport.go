@@ -0,0 +1,20 @@
+package step
+
+import (
+	"github.com/benpate/rosetta/mapof"
+)
+
+// AddReport represents an action-step that lets a User report an account or document to the Domain Owners
+type AddReport struct {
+	Title string // Title to display at the top of the report form
+}
+
+// NewAddReport returns a fully initialized AddReport object
+func NewAddReport(stepInfo mapof.Any) (AddReport, error) {
+	return AddReport{
+		Title: first(stepInfo.GetString("title"), "Report to Moderators"),
+	}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step AddReport) AmStep() {}
@@ -0,0 +1,16 @@
+package step
+
+import (
+	"github.com/benpate/rosetta/mapof"
+)
+
+// AddReviewComment represents an action-step that adds an inline editorial comment to a Stream
+type AddReviewComment struct{}
+
+// NewAddReviewComment returns a fully initialized AddReviewComment object
+func NewAddReviewComment(stepInfo mapof.Any) (AddReviewComment, error) {
+	return AddReviewComment{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step AddReviewComment) AmStep() {}
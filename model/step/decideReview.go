@@ -0,0 +1,22 @@
+package step
+
+import (
+	"github.com/benpate/rosetta/mapof"
+)
+
+// DecideReview represents an action-step that records a reviewer's decision on the pending Review for a Stream
+type DecideReview struct {
+	Title   string // Title to display at the top of the decision form
+	Message string // Message to display at the top of the decision form
+}
+
+// NewDecideReview returns a fully initialized DecideReview object
+func NewDecideReview(stepInfo mapof.Any) (DecideReview, error) {
+	return DecideReview{
+		Title:   first(stepInfo.GetString("title"), "Review this Stream"),
+		Message: stepInfo.GetString("message"),
+	}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step DecideReview) AmStep() {}
@@ -0,0 +1,20 @@
+package step
+
+import (
+	"github.com/benpate/rosetta/mapof"
+)
+
+// EditAttachment represents an action-step that can edit the alt text of a single attachment
+type EditAttachment struct {
+	Title string // Title to display at the top of the form
+}
+
+// NewEditAttachment returns a fully initialized EditAttachment object
+func NewEditAttachment(stepInfo mapof.Any) (EditAttachment, error) {
+	return EditAttachment{
+		Title: first(stepInfo.GetString("title"), "Edit Attachment"),
+	}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step EditAttachment) AmStep() {}
@@ -0,0 +1,17 @@
+package step
+
+import (
+	"github.com/benpate/rosetta/mapof"
+)
+
+// EditAuthorizedFetch represents an action-step that turns "authorized fetch"
+// (signed ActivityPub GET requests) on or off for a Domain.
+type EditAuthorizedFetch struct{}
+
+// NewEditAuthorizedFetch returns a fully initialized EditAuthorizedFetch object
+func NewEditAuthorizedFetch(stepInfo mapof.Any) (EditAuthorizedFetch, error) {
+	return EditAuthorizedFetch{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step EditAuthorizedFetch) AmStep() {}
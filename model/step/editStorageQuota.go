@@ -0,0 +1,17 @@
+package step
+
+import (
+	"github.com/benpate/rosetta/mapof"
+)
+
+// EditStorageQuota represents an action-step that updates the default
+// storage quota for all Users in a Domain.
+type EditStorageQuota struct{}
+
+// NewEditStorageQuota returns a fully initialized EditStorageQuota object
+func NewEditStorageQuota(stepInfo mapof.Any) (EditStorageQuota, error) {
+	return EditStorageQuota{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step EditStorageQuota) AmStep() {}
@@ -0,0 +1,14 @@
+package step
+
+import "github.com/benpate/rosetta/mapof"
+
+// FollowHashtag represents an action-step that follows the current hashtag for the signed-in User
+type FollowHashtag struct{}
+
+// NewFollowHashtag returns a fully initialized FollowHashtag object
+func NewFollowHashtag(stepInfo mapof.Any) (FollowHashtag, error) {
+	return FollowHashtag{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step FollowHashtag) AmStep() {}
@@ -0,0 +1,17 @@
+package step
+
+import (
+	"github.com/benpate/rosetta/mapof"
+)
+
+// MigrateAccount represents an action-step that manages a User's account aliases,
+// and can move the User to another ActivityPub account.
+type MigrateAccount struct{}
+
+// NewMigrateAccount returns a fully initialized MigrateAccount object
+func NewMigrateAccount(stepInfo mapof.Any) (MigrateAccount, error) {
+	return MigrateAccount{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step MigrateAccount) AmStep() {}
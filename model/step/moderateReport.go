@@ -0,0 +1,32 @@
+package step
+
+import (
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/mapof"
+)
+
+// ModerateReport represents an action-step that forwards, resolves, or dismisses a moderation Report
+type ModerateReport struct {
+	Action string // Action to take on the Report (forward, resolve, dismiss)
+}
+
+// NewModerateReport returns a fully initialized ModerateReport object
+func NewModerateReport(stepInfo mapof.Any) (ModerateReport, error) {
+
+	const location = "model.step.NewModerateReport"
+
+	action := stepInfo.GetString("action")
+
+	switch action {
+	case "forward", "resolve", "dismiss":
+	default:
+		return ModerateReport{}, derp.New(derp.CodeBadRequestError, location, "Invalid action.  Only 'forward', 'resolve', and 'dismiss' are allowed", action)
+	}
+
+	return ModerateReport{
+		Action: action,
+	}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step ModerateReport) AmStep() {}
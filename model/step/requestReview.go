@@ -0,0 +1,24 @@
+package step
+
+import (
+	"github.com/benpate/rosetta/mapof"
+)
+
+// RequestReview represents an action-step that asks one or more reviewers to approve a workflow Transition
+type RequestReview struct {
+	Transition string // ID of the Template Transition being requested
+	Title      string // Title to display at the top of the request form
+	Message    string // Message to display at the top of the request form
+}
+
+// NewRequestReview returns a fully initialized RequestReview object
+func NewRequestReview(stepInfo mapof.Any) (RequestReview, error) {
+	return RequestReview{
+		Transition: stepInfo.GetString("transition"),
+		Title:      first(stepInfo.GetString("title"), "Request a Review"),
+		Message:    stepInfo.GetString("message"),
+	}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step RequestReview) AmStep() {}
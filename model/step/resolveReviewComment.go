@@ -0,0 +1,16 @@
+package step
+
+import (
+	"github.com/benpate/rosetta/mapof"
+)
+
+// ResolveReviewComment represents an action-step that marks an inline editorial comment as addressed
+type ResolveReviewComment struct{}
+
+// NewResolveReviewComment returns a fully initialized ResolveReviewComment object
+func NewResolveReviewComment(stepInfo mapof.Any) (ResolveReviewComment, error) {
+	return ResolveReviewComment{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step ResolveReviewComment) AmStep() {}
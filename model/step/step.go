@@ -38,6 +38,12 @@ func New(stepInfo mapof.Any) (Step, error) {
 	case "add":
 		return NewAddModelObject(stepInfo)
 
+	case "add-report":
+		return NewAddReport(stepInfo)
+
+	case "add-review-comment":
+		return NewAddReviewComment(stepInfo)
+
 	case "add-stream":
 		return NewAddStream(stepInfo)
 
@@ -50,6 +56,9 @@ func New(stepInfo mapof.Any) (Step, error) {
 	case "as-tooltip":
 		return NewAsTooltip(stepInfo)
 
+	case "decide-review":
+		return NewDecideReview(stepInfo)
+
 	case "delete":
 		return NewDelete(stepInfo)
 
@@ -62,12 +71,21 @@ func New(stepInfo mapof.Any) (Step, error) {
 	case "edit-connection":
 		return NewEditConnection(stepInfo)
 
+	case "edit-attachment":
+		return NewEditAttachment(stepInfo)
+
+	case "edit-authorized-fetch":
+		return NewEditAuthorizedFetch(stepInfo)
+
 	case "edit-content":
 		return NewEditContent(stepInfo)
 
 	case "edit-registration":
 		return NewEditRegistration(stepInfo)
 
+	case "edit-storage-quota":
+		return NewEditStorageQuota(stepInfo)
+
 	case "edit-table":
 		return NewTableEditor(stepInfo)
 
@@ -77,6 +95,9 @@ func New(stepInfo mapof.Any) (Step, error) {
 	case "edit-widget":
 		return NewEditWidget(stepInfo)
 
+	case "follow-hashtag":
+		return NewFollowHashtag(stepInfo)
+
 	case "forward-to":
 		return NewForwardTo(stepInfo)
 
@@ -98,6 +119,12 @@ func New(stepInfo mapof.Any) (Step, error) {
 	case "inline-success":
 		return NewInlineSuccess(stepInfo)
 
+	case "migrate-account":
+		return NewMigrateAccount(stepInfo)
+
+	case "moderate-report":
+		return NewModerateReport(stepInfo)
+
 	case "process-content":
 		return NewProcessContent(stepInfo)
 
@@ -122,6 +149,15 @@ func New(stepInfo mapof.Any) (Step, error) {
 	case "remove-event":
 		return NewRemoveEvent(stepInfo)
 
+	case "request-review":
+		return NewRequestReview(stepInfo)
+
+	case "resolve-review-comment":
+		return NewResolveReviewComment(stepInfo)
+
+	case "rsscloud":
+		return NewRSSCloud(stepInfo)
+
 	case "save":
 		return NewSave(stepInfo)
 
@@ -161,9 +197,18 @@ func New(stepInfo mapof.Any) (Step, error) {
 	case "sort-widgets":
 		return NewSortWidgets(stepInfo)
 
+	case "subscribe-relay":
+		return NewSubscribeRelay(stepInfo)
+
+	case "sync-blocklist":
+		return NewSyncBlocklist(stepInfo)
+
 	case "trigger-event":
 		return NewTriggerEvent(stepInfo)
 
+	case "unfollow-hashtag":
+		return NewUnfollowHashtag(stepInfo)
+
 	case "unpublish":
 		return NewUnPublish(stepInfo)
 
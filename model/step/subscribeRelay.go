@@ -0,0 +1,14 @@
+package step
+
+import "github.com/benpate/rosetta/mapof"
+
+// SubscribeRelay represents an action-step that sends a subscription (Follow) request to an ActivityPub Relay
+type SubscribeRelay struct{}
+
+// NewSubscribeRelay returns a fully initialized SubscribeRelay object
+func NewSubscribeRelay(stepInfo mapof.Any) (SubscribeRelay, error) {
+	return SubscribeRelay{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step SubscribeRelay) AmStep() {}
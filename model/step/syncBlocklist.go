@@ -0,0 +1,14 @@
+package step
+
+import "github.com/benpate/rosetta/mapof"
+
+// SyncBlocklist represents an action-step that re-fetches a Blocklist subscription immediately
+type SyncBlocklist struct{}
+
+// NewSyncBlocklist returns a fully initialized SyncBlocklist object
+func NewSyncBlocklist(stepInfo mapof.Any) (SyncBlocklist, error) {
+	return SyncBlocklist{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step SyncBlocklist) AmStep() {}
@@ -0,0 +1,14 @@
+package step
+
+import "github.com/benpate/rosetta/mapof"
+
+// UnfollowHashtag represents an action-step that unfollows the current hashtag for the signed-in User
+type UnfollowHashtag struct{}
+
+// NewUnfollowHashtag returns a fully initialized UnfollowHashtag object
+func NewUnfollowHashtag(stepInfo mapof.Any) (UnfollowHashtag, error) {
+	return UnfollowHashtag{}, nil
+}
+
+// AmStep is here only to verify that this struct is a build pipeline step
+func (step UnfollowHashtag) AmStep() {}
@@ -15,6 +15,8 @@ type UploadAttachments struct {
 	Category       string // Category to apply to the Attachment
 	Maximum        int    // Maximum number of uploads to allow (Default: 1)
 	JSONResult     bool   // If TRUE, return a JSON structure with result data. This forces Maximum=1
+	AltTextField   string // Name of the form field that contains alt text for each file (Default: "altText")
+	AltTextRequire bool   // If TRUE, then every uploaded file must include alt text
 }
 
 // NewUploadAttachments returns a fully parsed UploadAttachments object
@@ -37,6 +39,8 @@ func NewUploadAttachments(stepInfo mapof.Any) (UploadAttachments, error) {
 		Maximum:        max(stepInfo.GetInt("maximum"), 1),
 		Category:       stepInfo.GetString("category"),
 		JSONResult:     stepInfo.GetBool("json-result"),
+		AltTextField:   first(stepInfo.GetString("alt-text-field"), "altText"),
+		AltTextRequire: stepInfo.GetBool("alt-text-required"),
 	}, nil
 }
 
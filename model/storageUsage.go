@@ -0,0 +1,29 @@
+package model
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// StorageUsage summarizes the amount of attachment storage used by a single User
+type StorageUsage struct {
+	UserID      primitive.ObjectID `json:"userId"`      // ID of the User
+	DisplayName string             `json:"displayName"` // Display name of the User
+	Username    string             `json:"username"`    // Username of the User
+	Files       int                `json:"files"`       // Number of files uploaded by the User
+	Used        int64              `json:"used"`        // Total size (in bytes) of all files uploaded by the User
+	Quota       int64              `json:"quota"`       // Maximum size (in bytes) that the User may upload.  Zero means unlimited.
+}
+
+// IsOverQuota returns TRUE if this User has uploaded more than their quota allows
+func (usage StorageUsage) IsOverQuota() bool {
+	return (usage.Quota > 0) && (usage.Used > usage.Quota)
+}
+
+// PercentUsed returns the percentage (0-100) of the quota that has been used.
+// Unlimited quotas always return zero.
+func (usage StorageUsage) PercentUsed() int {
+
+	if usage.Quota <= 0 {
+		return 0
+	}
+
+	return int(min(usage.Used*100/usage.Quota, 100))
+}
@@ -41,6 +41,7 @@ type Stream struct {
 	AttributedTo     PersonLink                   `json:"attributedTo,omitempty" bson:"attributedTo,omitempty"` // List of people who are attributed to this document
 	Context          string                       `json:"context,omitempty"      bson:"context,omitempty"`      // Context of this document (usually a URL)
 	InReplyTo        string                       `json:"inReplyTo"              bson:"inReplyTo"`              // If this stream is a reply to another stream or web page, then this links to the original document.
+	QuoteURL         string                       `json:"quoteUrl,omitempty"     bson:"quoteUrl,omitempty"`     // If this stream quotes another stream or web page, then this links to the quoted document.
 	PublishDate      int64                        `json:"publishDate"            bson:"publishDate"`            // Unix timestamp of the date/time when this document is/was/will be first available on the domain.
 	UnPublishDate    int64                        `json:"unpublishDate"          bson:"unpublishDate"`          // Unix timestemp of the date/time when this document will no longer be available on the domain.
 	IsFeatured       bool                         `json:"isFeatured"             bson:"isFeatured,omitempty"`   // TRUE if this Stream is featured by its parent container.
@@ -112,6 +113,22 @@ func (stream *Stream) WidgetByID(streamWidgetID primitive.ObjectID) StreamWidget
 	return StreamWidget{}
 }
 
+// Hashtags returns the normalized names of all #hashtags in this Stream
+func (stream *Stream) Hashtags() []string {
+
+	result := make([]string, 0)
+
+	for _, tag := range stream.Tags {
+		if tag.IsHashtag() {
+			if name := NormalizeHashtag(tag.Name); name != "" {
+				result = append(result, name)
+			}
+		}
+	}
+
+	return result
+}
+
 // GetSort returns the sortable value for this stream, based onthe provided fieldName
 func (stream *Stream) GetSort(fieldName string) any {
 	switch fieldName {
@@ -293,6 +310,10 @@ func (stream *Stream) ActivityPubInboxURL() string {
 	return stream.URL + "/pub/inbox"
 }
 
+func (stream *Stream) ActivityPubSharedInboxURL() string {
+	return ActivityPubSharedInboxURL(stream.URL)
+}
+
 func (stream *Stream) ActivityPubOutboxURL() string {
 	return stream.URL + "/pub/outbox"
 }
@@ -364,7 +385,7 @@ func (stream *Stream) PublishActivity() string {
 
 func (stream Stream) Toot() object.Status {
 
-	return object.Status{
+	result := object.Status{
 		ID:          stream.StreamID.Hex(),
 		URI:         stream.ActivityPubURL(),
 		CreatedAt:   time.Unix(stream.PublishDate, 0).Format(time.RFC3339),
@@ -375,6 +396,16 @@ func (stream Stream) Toot() object.Status {
 		URL:         stream.URL,
 		InReplyToID: stream.InReplyTo,
 	}
+
+	// The Mastodon API does not (yet) have quotes, so show them as a link preview
+	if stream.QuoteURL != "" {
+		result.Card = &object.PreviewCard{
+			URL:  stream.QuoteURL,
+			Type: "link",
+		}
+	}
+
+	return result
 }
 
 func (stream Stream) GetRank() int64 {
@@ -38,6 +38,9 @@ func (actor StreamActor) JSONLD(stream *Stream) mapof.Any {
 		vocab.PropertyOutbox:            stream.ActivityPubOutboxURL(),
 		vocab.PropertyName:              stream.Label,
 		vocab.PropertyPreferredUsername: stream.Token,
+		vocab.PropertyEndpoints: mapof.Any{
+			"sharedInbox": stream.ActivityPubSharedInboxURL(),
+		},
 	}
 
 	if stream.Summary != "" {
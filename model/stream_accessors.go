@@ -29,6 +29,7 @@ func StreamSchema() schema.Element {
 			"attributedTo":     PersonLinkSchema(),
 			"context":          schema.String{Format: "url"},
 			"inReplyTo":        schema.String{Format: "url"},
+			"quoteUrl":         schema.String{Format: "url"},
 			"content":          ContentSchema(),
 			"widgets":          WidgetSchema(),
 			"tags":             schema.Object{Wildcard: schema.String{}},
@@ -104,6 +105,9 @@ func (stream *Stream) GetPointer(name string) (any, bool) {
 	case "inReplyTo":
 		return &stream.InReplyTo, true
 
+	case "quoteUrl":
+		return &stream.QuoteURL, true
+
 	case "rank":
 		return &stream.Rank, true
 
@@ -6,6 +6,7 @@ import (
 	"github.com/benpate/rosetta/mapof"
 	"github.com/benpate/rosetta/schema"
 	"github.com/benpate/rosetta/sliceof"
+	"github.com/stretchr/testify/require"
 )
 
 func TestStreamSchema(t *testing.T) {
@@ -43,6 +44,7 @@ func TestStreamSchema(t *testing.T) {
 		{"attributedTo.profileUrl", "https://example/author", nil},
 
 		{"inReplyTo", "https://in-reply-to.com", nil},
+		{"quoteUrl", "https://quoted.com", nil},
 		{"content.format", "HTML", nil},
 		{"content.raw", "TEST_RAWCONTENT", nil},
 		{"content.html", "TEST_HTML", nil},
@@ -81,3 +83,15 @@ func TestPermissionSchema(t *testing.T) {
 
 	tableTest_Schema(t, &s, &m, table)
 }
+
+func TestStreamHashtags(t *testing.T) {
+
+	stream := NewStream()
+	stream.Tags = append(stream.Tags,
+		Tag{Type: TagTypeHashtag, Name: "#Emissary"},
+		Tag{Type: "Mention", Name: "@someone@example.com"},
+		Tag{Type: TagTypeHashtag, Name: "#fediverse."},
+	)
+
+	require.Equal(t, []string{"emissary", "fediverse"}, stream.Hashtags())
+}
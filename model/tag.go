@@ -1,6 +1,10 @@
 package model
 
-import "github.com/benpate/rosetta/mapof"
+import (
+	"strings"
+
+	"github.com/benpate/rosetta/mapof"
+)
 
 type Tag struct {
 	Type string `json:"type"` // Type of Tag (e.g. "Hashtag", "Mention")
@@ -12,6 +16,11 @@ func NewTag() Tag {
 	return Tag{}
 }
 
+// IsHashtag returns TRUE if this Tag is a #hashtag
+func (tag Tag) IsHashtag() bool {
+	return tag.Type == TagTypeHashtag
+}
+
 func (tag Tag) JSONLD() mapof.Any {
 	return TagAsJSONLD(tag)
 }
@@ -23,3 +32,12 @@ func TagAsJSONLD(tag Tag) mapof.Any {
 		"href": tag.Href,
 	}
 }
+
+// NormalizeHashtag returns the canonical name of a hashtag, which is
+// lowercase and does not include the leading "#" or trailing punctuation.
+func NormalizeHashtag(name string) string {
+	name = strings.TrimSpace(name)
+	name = strings.TrimPrefix(name, "#")
+	name = strings.TrimRight(name, ".,;:!?")
+	return strings.ToLower(name)
+}
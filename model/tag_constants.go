@@ -0,0 +1,4 @@
+package model
+
+// TagTypeHashtag identifies a Tag that is a #hashtag
+const TagTypeHashtag = "Hashtag"
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTagSchema(t *testing.T) {
@@ -22,3 +23,10 @@ func TestTagSchema(t *testing.T) {
 	//TODO: Include DefaultAllow?
 
 }
+
+func TestNormalizeHashtag(t *testing.T) {
+	require.Equal(t, "emissary", NormalizeHashtag("#Emissary"))
+	require.Equal(t, "emissary", NormalizeHashtag(" emissary. "))
+	require.Equal(t, "fediverse", NormalizeHashtag("#FediVerse!"))
+	require.Equal(t, "", NormalizeHashtag("#"))
+}
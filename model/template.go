@@ -3,6 +3,7 @@ package model
 import (
 	"html/template"
 	"io/fs"
+	"sort"
 
 	"github.com/benpate/data/option"
 	"github.com/benpate/derp"
@@ -14,30 +15,31 @@ import (
 
 // Template represents an HTML template used for building Streams
 type Template struct {
-	TemplateID         string               `json:"templateId"         bson:"templateId"`         // Internal name/token other objects (like streams) will use to reference this Template.
-	URL                string               `json:"url"                bson:"url"`                // URL where this template is published
-	TemplateRole       string               `json:"templateRole"       bson:"templateRole"`       // Role that this Template performs in the system.  Used to match which streams can be contained by which other streams.
-	SocialRole         string               `json:"socialRole"         bson:"socialRole"`         // Role to use for this Template in social integrations (Article, Note, etc)
-	Model              string               `json:"model"              bson:"model"`              // Type of model object that this template works with. (Stream, User, Group, Domain, etc.)
-	Extends            sliceof.String       `json:"extends"            bson:"extends"`            // List of templates that this template extends.  The first template in the list is the most important, and the last template in the list is the least important.
-	ContainedBy        sliceof.String       `json:"containedBy"        bson:"containedBy"`        // Slice of Templates that can contain Streams that use this Template.
-	Label              string               `json:"label"              bson:"label"`              // Human-readable label used in management UI.
-	Description        string               `json:"description"        bson:"description"`        // Human-readable long-description text used in management UI.
-	Category           string               `json:"category"           bson:"category"`           // Human-readable category (grouping) used in management UI.
-	Icon               string               `json:"icon"               bson:"icon"`               // Icon image used in management UI.
-	Sort               int                  `json:"sort"               bson:"sort"`               // Sort order used in management UI.
-	ChildSortType      string               `json:"childSortType"      bson:"childSortType"`      // SortType used to display children
-	ChildSortDirection string               `json:"childSortDirection" bson:"childSortDirection"` // Sort direction "asc" or "desc" (Default is ascending)
-	WidgetLocations    sliceof.String       `json:"widget-locations"   bson:"widgetLocations"`    // List of locations where widgets can be placed.  Common values are: "TOP", "BOTTOM", "LEFT", "RIGHT"
-	Schema             schema.Schema        `json:"schema"             bson:"schema"`             // JSON Schema that describes the data required to populate this Template.
-	States             mapof.Object[State]  `json:"states"             bson:"states"`             // Map of States (by state.ID) that Streams of this Template can be in.
-	AccessRoles        mapof.Object[Role]   `json:"accessRoles"        bson:"accessRoles"`        // Map of custom roles defined by this Template.
-	Actions            mapof.Object[Action] `json:"actions"            bson:"actions"`            // Map of actions that can be performed on streams of this Template
-	HTMLTemplate       *template.Template   `json:"-"                  bson:"-"`                  // Compiled HTML template
-	Bundles            mapof.Object[Bundle] `json:"bundles"            bson:"bundles"`            // Additional resources (JS, HS, CSS) reqired tp remder this Template.
-	Resources          fs.FS                `json:"-"                  bson:"-"`                  // File system containing the template resources
-	DefaultAction      string               `json:"defaultAction"      bson:"defaultAction"`      // Name of the action to be used when none is provided.  Also serves as the permissions for viewing a Stream.  If this is empty, it is assumed to be "view"
-	Actor              StreamActor          `json:"actor"              bson:"actor"`              // ActivityPub Actor operated on behalf of this Template/Stream
+	TemplateID         string                   `json:"templateId"         bson:"templateId"`         // Internal name/token other objects (like streams) will use to reference this Template.
+	URL                string                   `json:"url"                bson:"url"`                // URL where this template is published
+	TemplateRole       string                   `json:"templateRole"       bson:"templateRole"`       // Role that this Template performs in the system.  Used to match which streams can be contained by which other streams.
+	SocialRole         string                   `json:"socialRole"         bson:"socialRole"`         // Role to use for this Template in social integrations (Article, Note, etc)
+	Model              string                   `json:"model"              bson:"model"`              // Type of model object that this template works with. (Stream, User, Group, Domain, etc.)
+	Extends            sliceof.String           `json:"extends"            bson:"extends"`            // List of templates that this template extends.  The first template in the list is the most important, and the last template in the list is the least important.
+	ContainedBy        sliceof.String           `json:"containedBy"        bson:"containedBy"`        // Slice of Templates that can contain Streams that use this Template.
+	Label              string                   `json:"label"              bson:"label"`              // Human-readable label used in management UI.
+	Description        string                   `json:"description"        bson:"description"`        // Human-readable long-description text used in management UI.
+	Category           string                   `json:"category"           bson:"category"`           // Human-readable category (grouping) used in management UI.
+	Icon               string                   `json:"icon"               bson:"icon"`               // Icon image used in management UI.
+	Sort               int                      `json:"sort"               bson:"sort"`               // Sort order used in management UI.
+	ChildSortType      string                   `json:"childSortType"      bson:"childSortType"`      // SortType used to display children
+	ChildSortDirection string                   `json:"childSortDirection" bson:"childSortDirection"` // Sort direction "asc" or "desc" (Default is ascending)
+	WidgetLocations    sliceof.String           `json:"widget-locations"   bson:"widgetLocations"`    // List of locations where widgets can be placed.  Common values are: "TOP", "BOTTOM", "LEFT", "RIGHT"
+	Schema             schema.Schema            `json:"schema"             bson:"schema"`             // JSON Schema that describes the data required to populate this Template.
+	States             mapof.Object[State]      `json:"states"             bson:"states"`             // Map of States (by state.ID) that Streams of this Template can be in.
+	AccessRoles        mapof.Object[Role]       `json:"accessRoles"        bson:"accessRoles"`        // Map of custom roles defined by this Template.
+	Actions            mapof.Object[Action]     `json:"actions"            bson:"actions"`            // Map of actions that can be performed on streams of this Template
+	Transitions        mapof.Object[Transition] `json:"transitions"    bson:"transitions"`            // Map of editorial workflow Transitions (by transition.ID) that move Streams between States
+	HTMLTemplate       *template.Template       `json:"-"                  bson:"-"`                  // Compiled HTML template
+	Bundles            mapof.Object[Bundle]     `json:"bundles"            bson:"bundles"`            // Additional resources (JS, HS, CSS) reqired tp remder this Template.
+	Resources          fs.FS                    `json:"-"                  bson:"-"`                  // File system containing the template resources
+	DefaultAction      string                   `json:"defaultAction"      bson:"defaultAction"`      // Name of the action to be used when none is provided.  Also serves as the permissions for viewing a Stream.  If this is empty, it is assumed to be "view"
+	Actor              StreamActor              `json:"actor"              bson:"actor"`              // ActivityPub Actor operated on behalf of this Template/Stream
 }
 
 // NewTemplate creates a new, fully initialized Template object
@@ -53,6 +55,7 @@ func NewTemplate(templateID string, funcMap template.FuncMap) Template {
 		States:             make(map[string]State),
 		AccessRoles:        make(map[string]Role),
 		Actions:            make(map[string]Action),
+		Transitions:        make(map[string]Transition),
 		DefaultAction:      "view",
 		HTMLTemplate:       template.New("").Funcs(funcMap),
 	}
@@ -106,6 +109,36 @@ func (template *Template) Action(actionID string) (Action, bool) {
 	return action, ok
 }
 
+// Transition returns the workflow Transition for a specified name
+func (template *Template) Transition(transitionID string) (Transition, bool) {
+	transition, ok := template.Transitions[transitionID]
+
+	if ok {
+		transition.TransitionID = transitionID
+	}
+
+	return transition, ok
+}
+
+// TransitionsFrom returns all workflow Transitions that can begin from the provided State
+func (template *Template) TransitionsFrom(stateID string) []Transition {
+
+	result := make([]Transition, 0)
+
+	for transitionID, transition := range template.Transitions {
+		if transition.CanStartFrom(stateID) {
+			transition.TransitionID = transitionID
+			result = append(result, transition)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].TransitionID < result[j].TransitionID
+	})
+
+	return result
+}
+
 // Default returns the default Action for this Template.
 func (template *Template) Default() Action {
 	return template.Actions[template.DefaultAction]
@@ -167,6 +200,13 @@ func (template *Template) Inherit(parent *Template) {
 		}
 	}
 
+	// Inherit Transitions from the parent (if not already defined)
+	for transitionID, transition := range parent.Transitions {
+		if _, ok := template.Transitions[transitionID]; !ok {
+			template.Transitions[transitionID] = transition
+		}
+	}
+
 	// Inherit HTMLTemplates from the parent (if not already defined)
 	for _, templateName := range parent.HTMLTemplate.Templates() {
 		if template.HTMLTemplate.Lookup(templateName.Name()) == nil {
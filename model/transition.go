@@ -0,0 +1,65 @@
+package model
+
+// Transition defines an editorial workflow step that moves a Stream from one State into
+// another.  Transitions are declared by Templates, and may require approval from one or
+// more reviewers before the Stream's state is actually changed.
+type Transition struct {
+	TransitionID  string   `json:"transitionId" bson:"transitionId"` // Unique ID for this Transition (within this Template)
+	Label         string   `json:"label"        bson:"label"`        // Human-friendly label for this Transition
+	Description   string   `json:"description"  bson:"description"`  // Description of this Transition
+	FromStates    []string `json:"from"         bson:"from"`         // List of States that this Transition can start from.  If empty, then any state is allowed.
+	ToState       string   `json:"to"           bson:"to"`           // State that the Stream is moved into once this Transition is approved
+	ApproverRoles []string `json:"approvers"    bson:"approvers"`    // List of roles that can approve this Transition.  If empty, then only owners can approve.
+	Approvals     int      `json:"approvals"    bson:"approvals"`    // Number of approvals required before the Transition is complete (Default: 1)
+	Publish       bool     `json:"publish"      bson:"publish"`      // If TRUE, then the Stream is published to the requester's outbox once the Transition is approved
+}
+
+// NewTransition returns a fully initialized Transition object
+func NewTransition() Transition {
+	return Transition{
+		FromStates:    make([]string, 0),
+		ApproverRoles: make([]string, 0),
+		Approvals:     1,
+	}
+}
+
+// IsZero returns TRUE if this Transition does not move into a new State
+func (transition Transition) IsZero() bool {
+	return transition.ToState == ""
+}
+
+// CanStartFrom returns TRUE if this Transition can begin from the provided State
+func (transition Transition) CanStartFrom(stateID string) bool {
+
+	if len(transition.FromStates) == 0 {
+		return true
+	}
+
+	return matchOne(transition.FromStates, stateID)
+}
+
+// RequiredApprovals returns the number of approvals required to complete this Transition.
+// This value is always at least 1.
+func (transition Transition) RequiredApprovals() int {
+	return max(transition.Approvals, 1)
+}
+
+// AllowedRoles returns all of the roles that are allowed to approve this Transition.
+// Owners can always approve Transitions, no matter what.
+func (transition Transition) AllowedRoles() []string {
+	result := make([]string, 0, len(transition.ApproverRoles)+1)
+	result = append(result, transition.ApproverRoles...)
+	result = append(result, MagicRoleOwner)
+	return result
+}
+
+// UserCanApprove returns TRUE if the provided authorization is allowed to approve this Transition
+func (transition Transition) UserCanApprove(enumerator RoleStateEnumerator, authorization *Authorization) bool {
+
+	// RULE: Anonymous users cannot approve anything
+	if !authorization.IsAuthenticated() {
+		return false
+	}
+
+	return matchAny(enumerator.Roles(authorization), transition.AllowedRoles())
+}
@@ -0,0 +1,177 @@
+package model
+
+import (
+	"strconv"
+
+	"github.com/benpate/data/journal"
+	"github.com/benpate/rosetta/sliceof"
+	"github.com/benpate/toot/object"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Trend is a hashtag, link, or status that is popular in recent activity on this server.
+// Trends are calculated in the background, and must be approved by a Domain Owner
+// before they are shown publicly.
+type Trend struct {
+	TrendID        primitive.ObjectID           `json:"trendId"        bson:"_id"`                    // Unique identifier for this Trend
+	Type           string                       `json:"type"           bson:"type"`                   // Type of Trend (TAG, LINK, STATUS)
+	Key            string                       `json:"key"            bson:"key"`                    // Hashtag name (without the #), link URL, or status URL
+	Label          string                       `json:"label"          bson:"label,omitempty"`        // Human-friendly label (hashtag, page title, or status author)
+	Description    string                       `json:"description"    bson:"description,omitempty"`  // Description of the linked page or status
+	ImageURL       string                       `json:"imageUrl"       bson:"imageUrl,omitempty"`     // Preview image for the linked page or status
+	AuthorName     string                       `json:"authorName"     bson:"authorName,omitempty"`   // Author of the linked page or status
+	ProviderName   string                       `json:"providerName"   bson:"providerName,omitempty"` // Website that published the linked page
+	StateID        string                       `json:"stateId"        bson:"stateId"`                // Review state (PENDING, APPROVED, REJECTED)
+	Score          float64                      `json:"score"          bson:"score"`                  // Time-weighted popularity score.  Zero if the Trend is no longer active
+	Uses           int64                        `json:"uses"           bson:"uses"`                   // Number of times this Trend was used during the trending period
+	Accounts       int64                        `json:"accounts"       bson:"accounts"`               // Number of distinct accounts that used this Trend during the trending period
+	History        sliceof.Object[TrendHistory] `json:"history"        bson:"history"`                // Daily usage statistics, newest first
+	CalculatedDate int64                        `json:"calculatedDate" bson:"calculatedDate"`         // Unix timestamp of the last time this Trend was calculated
+
+	journal.Journal `json:"-" bson:",inline"`
+}
+
+// NewTrend returns a fully initialized Trend object
+func NewTrend() Trend {
+	return Trend{
+		TrendID: primitive.NewObjectID(),
+		StateID: TrendStatePending,
+		History: sliceof.NewObject[TrendHistory](),
+	}
+}
+
+/******************************************
+ * data.Object Interface
+ ******************************************/
+
+// ID returns the primary key of this object
+func (trend Trend) ID() string {
+	return trend.TrendID.Hex()
+}
+
+/******************************************
+ * Other Data Accessors
+ ******************************************/
+
+// IsApproved returns TRUE if this Trend can be shown publicly
+func (trend Trend) IsApproved() bool {
+	return trend.StateID == TrendStateApproved
+}
+
+// IsRejected returns TRUE if a Domain Owner has hidden this Trend
+func (trend Trend) IsRejected() bool {
+	return trend.StateID == TrendStateRejected
+}
+
+// IsActive returns TRUE if this Trend was seen during the most recent trending period
+func (trend Trend) IsActive() bool {
+	return trend.Score > 0
+}
+
+// DisplayLabel returns the best available label for this Trend
+func (trend Trend) DisplayLabel() string {
+
+	switch trend.Type {
+
+	case TrendTypeTag:
+		return "#" + trend.Key
+
+	default:
+		if trend.Label != "" {
+			return trend.Label
+		}
+	}
+
+	return trend.Key
+}
+
+// StateLabel returns a human-friendly label for the review state
+func (trend Trend) StateLabel() string {
+
+	switch trend.StateID {
+
+	case TrendStateApproved:
+		return "Approved"
+
+	case TrendStateRejected:
+		return "Rejected"
+	}
+
+	return "Pending Review"
+}
+
+// TypeLabel returns a human-friendly label for the type of Trend
+func (trend Trend) TypeLabel() string {
+
+	switch trend.Type {
+
+	case TrendTypeLink:
+		return "Link"
+
+	case TrendTypeStatus:
+		return "Post"
+	}
+
+	return "Hashtag"
+}
+
+/******************************************
+ * Mastodon API
+ ******************************************/
+
+// Tag returns a Mastodon API representation of a hashtag Trend
+func (trend Trend) Tag(host string) object.Tag {
+
+	return object.Tag{
+		Name:    trend.Key,
+		URL:     host + "/tags/" + trend.Key,
+		History: trend.TagHistory(),
+	}
+}
+
+// TagHistory returns the daily usage statistics for this Trend in Mastodon API format
+func (trend Trend) TagHistory() []object.TagHistory {
+
+	result := make([]object.TagHistory, len(trend.History))
+
+	for index, history := range trend.History {
+		result[index] = history.Toot()
+	}
+
+	return result
+}
+
+// PreviewCard returns a Mastodon API representation of a link Trend
+func (trend Trend) PreviewCard() object.PreviewCard {
+
+	return object.PreviewCard{
+		URL:          trend.Key,
+		Title:        trend.Label,
+		Description:  trend.Description,
+		Type:         "link",
+		AuthorName:   trend.AuthorName,
+		ProviderName: trend.ProviderName,
+		Image:        trend.ImageURL,
+	}
+}
+
+/******************************************
+ * TrendHistory
+ ******************************************/
+
+// TrendHistory contains the usage statistics for a Trend on a single day
+type TrendHistory struct {
+	Day      int64 `json:"day"      bson:"day"`      // Unix timestamp of midnight (UTC) on the given day
+	Uses     int64 `json:"uses"     bson:"uses"`     // Number of times the Trend was used on this day
+	Accounts int64 `json:"accounts" bson:"accounts"` // Number of distinct accounts that used the Trend on this day
+}
+
+// Toot returns a Mastodon API representation of this TrendHistory
+func (history TrendHistory) Toot() object.TagHistory {
+
+	return object.TagHistory{
+		Day:      strconv.FormatInt(history.Day, 10),
+		Uses:     strconv.FormatInt(history.Uses, 10),
+		Accounts: strconv.FormatInt(history.Accounts, 10),
+	}
+}
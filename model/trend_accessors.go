@@ -0,0 +1,132 @@
+package model
+
+import (
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TrendSchema returns a Rosetta Schema for the Trend object
+func TrendSchema() schema.Element {
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"trendId":        schema.String{Format: "objectId"},
+			"type":           schema.String{Enum: []string{TrendTypeTag, TrendTypeLink, TrendTypeStatus}, Required: true},
+			"key":            schema.String{MaxLength: 2048, Required: true},
+			"label":          schema.String{MaxLength: 256},
+			"description":    schema.String{MaxLength: 1024},
+			"imageUrl":       schema.String{Format: "url"},
+			"authorName":     schema.String{MaxLength: 128},
+			"providerName":   schema.String{MaxLength: 128},
+			"stateId":        schema.String{Enum: []string{TrendStatePending, TrendStateApproved, TrendStateRejected}},
+			"score":          schema.Number{},
+			"uses":           schema.Integer{BitSize: 64},
+			"accounts":       schema.Integer{BitSize: 64},
+			"history":        schema.Array{Items: TrendHistorySchema()},
+			"calculatedDate": schema.Integer{BitSize: 64},
+		},
+	}
+}
+
+// TrendHistorySchema returns a Rosetta Schema for the TrendHistory object
+func TrendHistorySchema() schema.Element {
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"day":      schema.Integer{BitSize: 64},
+			"uses":     schema.Integer{BitSize: 64},
+			"accounts": schema.Integer{BitSize: 64},
+		},
+	}
+}
+
+/******************************************
+ * Getter/Setter Interfaces
+ ******************************************/
+
+func (trend *Trend) GetPointer(name string) (any, bool) {
+
+	switch name {
+
+	case "type":
+		return &trend.Type, true
+
+	case "key":
+		return &trend.Key, true
+
+	case "label":
+		return &trend.Label, true
+
+	case "description":
+		return &trend.Description, true
+
+	case "imageUrl":
+		return &trend.ImageURL, true
+
+	case "authorName":
+		return &trend.AuthorName, true
+
+	case "providerName":
+		return &trend.ProviderName, true
+
+	case "stateId":
+		return &trend.StateID, true
+
+	case "score":
+		return &trend.Score, true
+
+	case "uses":
+		return &trend.Uses, true
+
+	case "accounts":
+		return &trend.Accounts, true
+
+	case "history":
+		return &trend.History, true
+
+	case "calculatedDate":
+		return &trend.CalculatedDate, true
+	}
+
+	return nil, false
+}
+
+func (trend *Trend) GetStringOK(name string) (string, bool) {
+
+	switch name {
+
+	case "trendId":
+		return trend.TrendID.Hex(), true
+	}
+
+	return "", false
+}
+
+func (trend *Trend) SetString(name string, value string) bool {
+
+	switch name {
+
+	case "trendId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			trend.TrendID = objectID
+			return true
+		}
+	}
+
+	return false
+}
+
+func (history *TrendHistory) GetPointer(name string) (any, bool) {
+
+	switch name {
+
+	case "day":
+		return &history.Day, true
+
+	case "uses":
+		return &history.Uses, true
+
+	case "accounts":
+		return &history.Accounts, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,19 @@
+package model
+
+// TrendStatePending signifies a Trend that has not yet been reviewed by a Domain Owner
+const TrendStatePending = "PENDING"
+
+// TrendStateApproved signifies a Trend that a Domain Owner has approved for public display
+const TrendStateApproved = "APPROVED"
+
+// TrendStateRejected signifies a Trend that a Domain Owner has hidden from public display
+const TrendStateRejected = "REJECTED"
+
+// TrendTypeTag identifies a trending hashtag
+const TrendTypeTag = "TAG"
+
+// TrendTypeLink identifies a trending link to an external web page
+const TrendTypeLink = "LINK"
+
+// TrendTypeStatus identifies a trending status (post)
+const TrendTypeStatus = "STATUS"
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrendSchema(t *testing.T) {
+
+	trend := NewTrend()
+	s := schema.New(TrendSchema())
+
+	table := []tableTestItem{
+		{"trendId", "123456781234567812345678", nil},
+		{"type", "LINK", nil},
+		{"key", "https://example.com/article", nil},
+		{"label", "An Interesting Article", nil},
+		{"description", "Everything you need to know", nil},
+		{"imageUrl", "https://example.com/image.jpg", nil},
+		{"authorName", "Jane Doe", nil},
+		{"providerName", "Example News", nil},
+		{"stateId", "APPROVED", nil},
+		{"score", "2.5", 2.5},
+		{"uses", int64(42), nil},
+		{"accounts", int64(12), nil},
+		{"history.0.day", int64(1234567890), nil},
+		{"history.0.uses", int64(7), nil},
+		{"history.0.accounts", int64(3), nil},
+		{"calculatedDate", int64(1234567890), nil},
+	}
+
+	tableTest_Schema(t, &s, &trend, table)
+}
+
+func TestTrendTag(t *testing.T) {
+
+	trend := NewTrend()
+	trend.Type = TrendTypeTag
+	trend.Key = "caturday"
+	trend.History = append(trend.History, TrendHistory{Day: 1700000000, Uses: 12, Accounts: 5})
+
+	tag := trend.Tag("https://local.social")
+
+	require.Equal(t, "caturday", tag.Name)
+	require.Equal(t, "https://local.social/tags/caturday", tag.URL)
+	require.Equal(t, 1, len(tag.History))
+	require.Equal(t, "1700000000", tag.History[0].Day)
+	require.Equal(t, "12", tag.History[0].Uses)
+	require.Equal(t, "5", tag.History[0].Accounts)
+	require.Equal(t, "#caturday", trend.DisplayLabel())
+}
+
+func TestTrendPreviewCard(t *testing.T) {
+
+	trend := NewTrend()
+	trend.Type = TrendTypeLink
+	trend.Key = "https://example.com/article"
+	trend.Label = "An Interesting Article"
+	trend.ImageURL = "https://example.com/image.jpg"
+
+	card := trend.PreviewCard()
+
+	require.Equal(t, "https://example.com/article", card.URL)
+	require.Equal(t, "An Interesting Article", card.Title)
+	require.Equal(t, "link", card.Type)
+	require.Equal(t, "https://example.com/image.jpg", card.Image)
+	require.Equal(t, "An Interesting Article", trend.DisplayLabel())
+}
@@ -15,41 +15,46 @@ import (
 
 // User represents a person or machine account that can own pages and sections.
 type User struct {
-	UserID          primitive.ObjectID         `json:"userId"          bson:"_id"`                  // Unique identifier for this user.
-	MapIDs          mapof.String               `json:"mapIds"          bson:"mapIds"`               // Map of IDs for this user on other web services.
-	GroupIDs        id.Slice                   `json:"groupIds"        bson:"groupIds"`             // Slice of IDs for the groups that this user belongs to.
-	IconID          primitive.ObjectID         `json:"iconId"          bson:"iconId"`               // AttachmentID of this user's avatar/icon image.
-	ImageID         primitive.ObjectID         `json:"imageId"         bson:"imageId"`              // AttachmentID of this user's banner image.
-	DisplayName     string                     `json:"displayName"     bson:"displayName"`          // Name to be displayed for this user
-	StatusMessage   string                     `json:"statusMessage"   bson:"statusMessage"`        // Status summary for this user
-	Location        string                     `json:"location"        bson:"location"`             // Human-friendly description of this user's physical location.
-	ProfileURL      string                     `json:"profileUrl"      bson:"profileUrl"`           // Fully Qualified profile URL for this user (including domain name)
-	EmailAddress    string                     `json:"emailAddress"    bson:"emailAddress"`         // Email address for this user
-	Username        string                     `json:"username"        bson:"username"`             // This is the primary public identifier for the user.
-	Password        string                     `json:"-"               bson:"password"`             // This password should be encrypted with BCrypt.
-	Locale          string                     `json:"locale"          bson:"locale"`               // Language code for this user's preferred language.
-	SignupNote      string                     `json:"signupNote"      bson:"signupNote,omitempty"` // Note that was included when this user signed up.
-	InboxTemplate   string                     `json:"inboxTemplate"   bson:"inboxTemplate"`        // Template for the user's inbox
-	OutboxTemplate  string                     `json:"outboxTemplate"  bson:"outboxTemplate"`       // Template for the user's outbox
-	Links           sliceof.Object[PersonLink] `json:"links"           bson:"links"`                // Slice of links to profiles on other web services.
-	FollowerCount   int                        `json:"followerCount"   bson:"followerCount"`        // Number of followers for this user
-	FollowingCount  int                        `json:"followingCount"  bson:"followingCount"`       // Number of users that this user is following
-	RuleCount       int                        `json:"ruleCount"       bson:"ruleCount"`            // Number of users that this user is following
-	IsOwner         bool                       `json:"isOwner"         bson:"isOwner"`              // If TRUE, then this user is a website owner with FULL privileges.
-	IsPublic        bool                       `json:"isPublic"        bson:"isPublic"`             // If TRUE, then this user's profile is publicly available
-	PasswordReset   PasswordReset              `json:"-"               bson:"passwordReset"`        // Most recent password reset information.
-	Data            mapof.String               `json:"data"            bson:"data"`                 // Custom profile data that can be stored with this User.
+	UserID          primitive.ObjectID         `json:"userId"          bson:"_id"`                   // Unique identifier for this user.
+	MapIDs          mapof.String               `json:"mapIds"          bson:"mapIds"`                // Map of IDs for this user on other web services.
+	GroupIDs        id.Slice                   `json:"groupIds"        bson:"groupIds"`              // Slice of IDs for the groups that this user belongs to.
+	IconID          primitive.ObjectID         `json:"iconId"          bson:"iconId"`                // AttachmentID of this user's avatar/icon image.
+	ImageID         primitive.ObjectID         `json:"imageId"         bson:"imageId"`               // AttachmentID of this user's banner image.
+	DisplayName     string                     `json:"displayName"     bson:"displayName"`           // Name to be displayed for this user
+	StatusMessage   string                     `json:"statusMessage"   bson:"statusMessage"`         // Status summary for this user
+	Location        string                     `json:"location"        bson:"location"`              // Human-friendly description of this user's physical location.
+	ProfileURL      string                     `json:"profileUrl"      bson:"profileUrl"`            // Fully Qualified profile URL for this user (including domain name)
+	EmailAddress    string                     `json:"emailAddress"    bson:"emailAddress"`          // Email address for this user
+	Username        string                     `json:"username"        bson:"username"`              // This is the primary public identifier for the user.
+	Password        string                     `json:"-"               bson:"password"`              // This password should be encrypted with BCrypt.
+	Locale          string                     `json:"locale"          bson:"locale"`                // Language code for this user's preferred language.
+	SignupNote      string                     `json:"signupNote"      bson:"signupNote,omitempty"`  // Note that was included when this user signed up.
+	InboxTemplate   string                     `json:"inboxTemplate"   bson:"inboxTemplate"`         // Template for the user's inbox
+	OutboxTemplate  string                     `json:"outboxTemplate"  bson:"outboxTemplate"`        // Template for the user's outbox
+	Links           sliceof.Object[PersonLink] `json:"links"           bson:"links"`                 // Slice of links to profiles on other web services.
+	AlsoKnownAs     sliceof.String             `json:"alsoKnownAs"     bson:"alsoKnownAs,omitempty"` // Slice of other ActivityPub actor URLs that also belong to this user (used for account migration)
+	MovedTo         string                     `json:"movedTo"         bson:"movedTo,omitempty"`     // ActivityPub actor URL that this user has moved to (if any)
+	QuotePolicy     string                     `json:"quotePolicy"     bson:"quotePolicy,omitempty"` // Who is allowed to quote this user's posts (ANYONE, FOLLOWERS, NOBODY)
+	FollowerCount   int                        `json:"followerCount"   bson:"followerCount"`         // Number of followers for this user
+	FollowingCount  int                        `json:"followingCount"  bson:"followingCount"`        // Number of users that this user is following
+	RuleCount       int                        `json:"ruleCount"       bson:"ruleCount"`             // Number of users that this user is following
+	IsOwner         bool                       `json:"isOwner"         bson:"isOwner"`               // If TRUE, then this user is a website owner with FULL privileges.
+	IsPublic        bool                       `json:"isPublic"        bson:"isPublic"`              // If TRUE, then this user's profile is publicly available
+	PasswordReset   PasswordReset              `json:"-"               bson:"passwordReset"`         // Most recent password reset information.
+	Data            mapof.String               `json:"data"            bson:"data"`                  // Custom profile data that can be stored with this User.
 	journal.Journal `json:"-" bson:",inline"`
 }
 
 // NewUser returns a fully initialized User object.
 func NewUser() User {
 	return User{
-		UserID:   primitive.NewObjectID(),
-		MapIDs:   mapof.NewString(),
-		GroupIDs: make([]primitive.ObjectID, 0),
-		Links:    sliceof.NewObject[PersonLink](),
-		Data:     mapof.NewString(),
+		UserID:      primitive.NewObjectID(),
+		MapIDs:      mapof.NewString(),
+		GroupIDs:    make([]primitive.ObjectID, 0),
+		Links:       sliceof.NewObject[PersonLink](),
+		AlsoKnownAs: sliceof.NewString(),
+		QuotePolicy: UserQuotePolicyAnyone,
+		Data:        mapof.NewString(),
 	}
 }
 
@@ -215,6 +220,11 @@ func (user *User) Roles(authorization *Authorization) []string {
  * ActivityPub Interfaces
  ******************************************/
 
+// IsMoved returns TRUE if this User has moved to another ActivityPub account
+func (user User) IsMoved() bool {
+	return user.MovedTo != ""
+}
+
 func (user User) GetJSONLD() mapof.Any {
 
 	result := mapof.Any{
@@ -234,6 +244,18 @@ func (user User) GetJSONLD() mapof.Any {
 		vocab.PropertyLiked:             user.ActivityPubLikedURL(),
 		vocab.PropertyBlocked:           user.ActivityPubBlockedURL(),
 		vocab.PropertyPublicKey:         user.ActivityPubPublicKeyURL(),
+		vocab.PropertyEndpoints: mapof.Any{
+			"sharedInbox": user.ActivityPubSharedInboxURL(),
+		},
+	}
+
+	// Conditionally add account migration properties
+	if len(user.AlsoKnownAs) > 0 {
+		result["alsoKnownAs"] = user.AlsoKnownAs
+	}
+
+	if user.MovedTo != "" {
+		result["movedTo"] = user.MovedTo
 	}
 
 	// Conditionally add the Avatar URL
@@ -283,6 +305,10 @@ func (user *User) ActivityPubInboxURL() string {
 	return user.ProfileURL + "/pub/inbox"
 }
 
+func (user *User) ActivityPubSharedInboxURL() string {
+	return ActivityPubSharedInboxURL(user.ProfileURL)
+}
+
 func (user *User) ActivityPubFollowersURL() string {
 	if user.ProfileURL == "" {
 		return ""
@@ -352,3 +378,20 @@ func (user User) Toot() object.Account {
 func (user User) GetRank() int64 {
 	return user.CreateDate
 }
+
+// AllowsQuotes returns TRUE if this user allows their posts to be quoted
+// by the provided audience.  Users created before quote policies existed
+// allow quotes from anyone.
+func (user User) AllowsQuotes(isFollower bool) bool {
+
+	switch user.QuotePolicy {
+
+	case UserQuotePolicyNobody:
+		return false
+
+	case UserQuotePolicyFollowers:
+		return isFollower
+	}
+
+	return true
+}
@@ -21,6 +21,9 @@ func UserSchema() schema.Element {
 			"statusMessage":  schema.String{MaxLength: 128},
 			"location":       schema.String{MaxLength: 64},
 			"links":          schema.Array{Items: PersonLinkSchema(), MaxLength: 6},
+			"alsoKnownAs":    schema.Array{Items: schema.String{Format: "url"}, MaxLength: 8},
+			"movedTo":        schema.String{Format: "url"},
+			"quotePolicy":    schema.String{Enum: []string{UserQuotePolicyAnyone, UserQuotePolicyFollowers, UserQuotePolicyNobody}},
 			"profileUrl":     schema.String{Format: "url"},
 			"emailAddress":   schema.String{Format: "email", Required: true},
 			"username":       schema.String{MaxLength: 32, Required: true},
@@ -55,6 +58,15 @@ func (user *User) GetPointer(name string) (any, bool) {
 	case "links":
 		return &user.Links, true
 
+	case "alsoKnownAs":
+		return &user.AlsoKnownAs, true
+
+	case "movedTo":
+		return &user.MovedTo, true
+
+	case "quotePolicy":
+		return &user.QuotePolicy, true
+
 	case "isOwner":
 		return &user.IsOwner, true
 
@@ -3,3 +3,12 @@ package model
 // UserMapIDStripe identifies the Stripe CustomerID for a User
 // https://docs.stripe.com/api/customers/object#customer_object-id
 const UserMapIDStripe = "STRIPE"
+
+// UserQuotePolicyAnyone allows anyone to quote a User's posts
+const UserQuotePolicyAnyone = "ANYONE"
+
+// UserQuotePolicyFollowers allows only a User's followers to quote their posts
+const UserQuotePolicyFollowers = "FOLLOWERS"
+
+// UserQuotePolicyNobody does not allow anyone to quote a User's posts
+const UserQuotePolicyNobody = "NOBODY"
@@ -24,6 +24,9 @@ func TestUserSchema(t *testing.T) {
 		{"location", "LOCATION", nil},
 		{"links.0.name", "LINK 1", nil},
 		{"links.0.profileUrl", "https://profile.url", nil},
+		{"alsoKnownAs.0", "https://old.server/@user", nil},
+		{"movedTo", "https://new.server/@user", nil},
+		{"quotePolicy", "FOLLOWERS", nil},
 		{"profileUrl", "http://profile.url", nil},
 		{"emailAddress", "email@address.url", nil},
 		{"username", "USERNAME", nil},
@@ -49,3 +52,19 @@ func TestUserJSONLD(t *testing.T) {
 	getter := any(user).(JSONLDGetter)
 	require.NotNil(t, getter.GetJSONLD())
 }
+
+func TestUserAllowsQuotes(t *testing.T) {
+	user := NewUser()
+	require.True(t, user.AllowsQuotes(false))
+
+	user.QuotePolicy = UserQuotePolicyFollowers
+	require.True(t, user.AllowsQuotes(true))
+	require.False(t, user.AllowsQuotes(false))
+
+	user.QuotePolicy = UserQuotePolicyNobody
+	require.False(t, user.AllowsQuotes(true))
+
+	// Legacy users without a policy allow anyone to quote them
+	user.QuotePolicy = ""
+	require.True(t, user.AllowsQuotes(false))
+}
@@ -0,0 +1,40 @@
+package queries
+
+import (
+	"context"
+
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AttachmentUsageResult defines the results of the custom AttachmentUsage query
+type AttachmentUsageResult struct {
+	Files int   `bson:"files"`
+	Used  int64 `bson:"used"`
+}
+
+// AttachmentUsage returns the number of files and total number of bytes uploaded by the provided User
+func AttachmentUsage(ctx context.Context, collection data.Collection, userID primitive.ObjectID) (int, int64, error) {
+
+	// Set up the mongodb pipeline query and result
+	query := bson.A{
+		bson.M{"$match": bson.M{"userId": userID, "deleteDate": 0}},
+		bson.M{"$group": bson.M{"_id": nil, "files": bson.M{"$sum": 1}, "used": bson.M{"$sum": "$size"}}},
+	}
+
+	result := []AttachmentUsageResult{}
+
+	// Try to execute the query as a mongodb pipeline
+	if err := pipeline(ctx, collection, &result, query); err != nil {
+		return 0, 0, derp.Wrap(err, "queries.AttachmentUsage", "Error totaling attachments", userID)
+	}
+
+	// If there are no results, then the User has not uploaded anything.
+	if len(result) == 0 {
+		return 0, 0, nil
+	}
+
+	return result[0].Files, result[0].Used, nil
+}
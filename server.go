@@ -19,8 +19,10 @@ import (
 
 	"github.com/EmissarySocial/emissary/config"
 	"github.com/EmissarySocial/emissary/handler"
+	ap_instance "github.com/EmissarySocial/emissary/handler/activitypub_instance"
 	ap_stream "github.com/EmissarySocial/emissary/handler/activitypub_stream"
 	ap_user "github.com/EmissarySocial/emissary/handler/activitypub_user"
+	"github.com/EmissarySocial/emissary/handler/mastodon"
 	"github.com/EmissarySocial/emissary/handler/stripe"
 	"github.com/EmissarySocial/emissary/handler/unsplash"
 	mw "github.com/EmissarySocial/emissary/middleware"
@@ -230,6 +232,8 @@ func makeStandardRoutes(factory *server.Factory, e *echo.Echo) {
 	e.POST("/.webmention", handler.PostWebMention(factory))
 	e.GET("/.websub/:userId/:followingId", handler.GetWebSubClient(factory))
 	e.POST("/.websub/:userId/:followingId", handler.PostWebSubClient(factory))
+	e.GET("/.rsscloud/:userId/:followingId", handler.GetRSSCloudClient(factory))
+	e.POST("/.rsscloud/:userId/:followingId", handler.PostRSSCloudClient(factory))
 	e.GET("/.widgets/:widgetId/:bundleId", handler.GetWidgetBundle(factory))
 	e.GET("/.widgets/:widgetId//resources/:filename", handler.GetWidgetResource(factory))
 
@@ -257,6 +261,11 @@ func makeStandardRoutes(factory *server.Factory, e *echo.Echo) {
 	e.GET("/:stream/sse", handler.ServerSentEvent(factory))                   // TODO: LOW: Can SSE be moved into a custom build step?
 	e.GET("/:stream/qrcode", handler.GetQRCode(factory))                      // TODO: LOW: Can QR Codes be moved into a custom build step?
 
+	// Hashtag Pages
+	e.GET("/tags/:tag", handler.GetHashtag(factory))
+	e.GET("/tags/:tag/:action", handler.GetHashtag(factory))
+	e.POST("/tags/:tag/:action", handler.PostHashtag(factory))
+
 	// Profile Pages
 	// NOTE: these are rewritten from /@:userId by the rewrite middleware
 	e.GET("/@", handler.TBD)
@@ -266,36 +275,55 @@ func makeStandardRoutes(factory *server.Factory, e *echo.Echo) {
 	e.POST("/@:userId/:action", handler.PostOutbox(factory))
 	e.GET("/@:userId/icon", handler.GetProfileIcon(factory))
 	e.GET("/@:userId/image", handler.GetProfileImage(factory))
+	e.GET("/@:userId/media/:attachment", handler.GetProfileMedia(factory))
+	e.GET("/@:userId/blogroll.opml", handler.GetBlogrollOPML(factory))
 
 	// Profile Pages for "me" only routes
 	e.GET("/@me/inbox", handler.GetInbox(factory))
 	e.POST("/@me/inbox", handler.PostInbox(factory))
 	e.GET("/@me/inbox/:action", handler.GetInbox(factory))
 	e.POST("/@me/inbox/:action", handler.PostInbox(factory))
+	e.GET("/@me/following.csv", handler.GetFollowingCSV(factory))
+	e.POST("/@me/following.csv", handler.PostFollowingCSV(factory))
+	e.GET("/@me/followers.csv", handler.GetFollowersCSV(factory))
+	e.GET("/@me/following.opml", handler.GetFollowingOPML(factory))
+	e.POST("/@me/following.opml", handler.PostFollowingOPML(factory))
+	e.GET("/@me/retention-report", handler.GetRetentionReport(factory))
+
+	// ActivityPub Shared Inbox
+	e.POST("/pub/shared-inbox", handler.PostSharedInbox(factory))
+
+	// ActivityPub Routes for the Instance Actor
+	e.GET("/pub/actor", ap_instance.GetJSONLD(factory))
+	e.POST("/pub/actor/inbox", ap_instance.PostInbox(factory))
+	e.GET("/pub/actor/outbox", ap_instance.GetOutboxCollection(factory))
 
 	// ActivityPub Routes for Users
 	e.GET("/@:userId/pub", handler.GetOutbox(factory))
 	e.POST("/@:userId/pub/inbox", ap_user.PostInbox(factory))
-	e.GET("/@:userId/pub/outbox", ap_user.GetOutboxCollection(factory))
-	e.GET("/@:userId/pub/followers", ap_user.GetFollowersCollection(factory))
-	e.GET("/@:userId/pub/following", ap_user.GetFollowingCollection(factory))
-	e.GET("/@:userId/pub/following/:followingId", ap_user.GetFollowingRecord(factory))
-	e.GET("/@:userId/pub/shared", ap_user.GetResponseCollection(factory, vocab.ActivityTypeAnnounce))
-	e.GET("/@:userId/pub/shared/:response", ap_user.GetResponse(factory, vocab.ActivityTypeAnnounce))
-	e.GET("/@:userId/pub/liked", ap_user.GetResponseCollection(factory, vocab.ActivityTypeLike))
-	e.GET("/@:userId/pub/liked/:response", ap_user.GetResponse(factory, vocab.ActivityTypeLike))
-	e.GET("/@:userId/pub/disliked", ap_user.GetResponseCollection(factory, vocab.ActivityTypeDislike))
-	e.GET("/@:userId/pub/disliked/:response", ap_user.GetResponse(factory, vocab.ActivityTypeDislike))
-	e.GET("/@:userId/pub/blocked", ap_user.GetBlockedCollection(factory))
-	e.GET("/@:userId/pub/blocked/:ruleId", ap_user.GetBlock(factory))
+	e.GET("/@:userId/pub/outbox", ap_user.GetOutboxCollection(factory), mw.AuthorizedFetch(factory))
+	e.GET("/@:userId/pub/followers", ap_user.GetFollowersCollection(factory), mw.AuthorizedFetch(factory))
+	e.GET("/@:userId/pub/following", ap_user.GetFollowingCollection(factory), mw.AuthorizedFetch(factory))
+	e.GET("/@:userId/pub/following/:followingId", ap_user.GetFollowingRecord(factory), mw.AuthorizedFetch(factory))
+	e.GET("/@:userId/pub/shared", ap_user.GetResponseCollection(factory, vocab.ActivityTypeAnnounce), mw.AuthorizedFetch(factory))
+	e.GET("/@:userId/pub/shared/:response", ap_user.GetResponse(factory, vocab.ActivityTypeAnnounce), mw.AuthorizedFetch(factory))
+	e.GET("/@:userId/pub/liked", ap_user.GetResponseCollection(factory, vocab.ActivityTypeLike), mw.AuthorizedFetch(factory))
+	e.GET("/@:userId/pub/liked/:response", ap_user.GetResponse(factory, vocab.ActivityTypeLike), mw.AuthorizedFetch(factory))
+	e.GET("/@:userId/pub/disliked", ap_user.GetResponseCollection(factory, vocab.ActivityTypeDislike), mw.AuthorizedFetch(factory))
+	e.GET("/@:userId/pub/disliked/:response", ap_user.GetResponse(factory, vocab.ActivityTypeDislike), mw.AuthorizedFetch(factory))
+	e.GET("/@:userId/pub/blocked", ap_user.GetBlockedCollection(factory), mw.AuthorizedFetch(factory))
+	e.GET("/@:userId/pub/blocked/:ruleId", ap_user.GetBlock(factory), mw.AuthorizedFetch(factory))
 
 	// ActivityPub Routes for Streams
 	e.GET("/:stream/pub", ap_stream.GetJSONLD(factory))
 	e.POST("/:stream/pub/inbox", ap_stream.PostInbox(factory))
-	e.GET("/:stream/pub/outbox", ap_stream.GetOutboxCollection(factory))
-	e.GET("/:stream/pub/followers", ap_stream.GetFollowersCollection(factory))
+	e.GET("/:stream/pub/outbox", ap_stream.GetOutboxCollection(factory), mw.AuthorizedFetch(factory))
+	e.GET("/:stream/pub/followers", ap_stream.GetFollowersCollection(factory), mw.AuthorizedFetch(factory))
 
 	// Domain Admin Pages
+	e.GET("/admin/blocklist.csv", handler.GetBlocklistCSV(factory), mw.Owner)
+	e.GET("/admin/blocklist.json", handler.GetBlocklistJSON(factory), mw.Owner)
+	e.POST("/admin/blocklist", handler.PostBlocklist(factory), mw.Owner)
 	e.GET("/admin", handler.GetAdmin(factory), mw.Owner)
 	e.GET("/admin/:param1", handler.GetAdmin(factory), mw.Owner)
 	e.POST("/admin/:param1", handler.PostAdmin(factory), mw.Owner)
@@ -322,6 +350,12 @@ func makeStandardRoutes(factory *server.Factory, e *echo.Echo) {
 
 	// Mastodon API
 	toot.Register(e, handler.Mastodon(factory))
+
+	// Mastodon Media API (handled outside of toot-echo to support file uploads)
+	e.POST("/api/v1/media", mastodon.PostMedia_V1(factory))
+	e.POST("/api/v2/media", mastodon.PostMedia_V2(factory))
+	e.GET("/api/v1/media/:id", mastodon.GetMedia(factory))
+	e.PUT("/api/v1/media/:id", mastodon.PutMedia(factory))
 }
 
 /******************************************
@@ -56,6 +56,7 @@ type Factory struct {
 	emailService        service.ServerEmail
 	taskQueue           queue.Queue
 	activityService     service.ActivityStream
+	requestSigner       service.RequestSigner
 	embeddedFiles       embed.FS
 
 	activityStreamCache *mongo.Client
@@ -123,6 +124,7 @@ func NewFactory(storage config.Storage, embeddedFiles embed.FS) *Factory {
 	)
 
 	factory.activityService = service.NewActivityStream()
+	factory.requestSigner = service.NewRequestSigner()
 
 	go factory.start()
 
@@ -179,6 +181,7 @@ func (factory *Factory) start() {
 		for domainID := range factory.domains {
 			factory.mutex.Lock()
 			if factory.domains[domainID].MarkForDeletion {
+				factory.requestSigner.Remove(domainID)
 				delete(factory.domains, domainID)
 			}
 			factory.mutex.Unlock()
@@ -236,6 +239,7 @@ func (factory *Factory) refreshDomain(config config.Config, domainConfig config.
 		factory.port(domainConfig),
 		config.Providers,
 		&factory.activityService,
+		&factory.requestSigner,
 		&factory.registrationService,
 		&factory.emailService,
 		&factory.themeService,
@@ -606,9 +610,10 @@ func (factory *Factory) RefreshActivityService(connection mapof.String) {
 		sherlock.WithUserAgent("Emissary Social: https://emissary.social"),
 	)
 
-	normalizerClient := asnormalizer.New(sherlockClient)       // enforce opinionated data formats
-	contextMakerClient := ascontextmaker.New(normalizerClient) // compute document context (if missing)
-	cacheRulesClient := ascacherules.New(contextMakerClient)   // apply custom caching rules to documents
+	signerClient := factory.requestSigner.Client(sherlockClient) // sign outbound GETs for servers that require authorized fetch
+	normalizerClient := asnormalizer.New(signerClient)           // enforce opinionated data formats
+	contextMakerClient := ascontextmaker.New(normalizerClient)   // compute document context (if missing)
+	cacheRulesClient := ascacherules.New(contextMakerClient)     // apply custom caching rules to documents
 
 	cacheClient := ascache.New(cacheRulesClient, collection, ascache.WithIgnoreHeaders()) // cache data in MongoDB
 	hashClient := ashash.New(cacheClient)                                                 // Traverse hash values within documents
@@ -622,6 +627,11 @@ func (factory *Factory) RefreshActivityService(connection mapof.String) {
 	// factory.activityService.Refresh(readOnlyCache, mongodb.NewCollection(collection))
 }
 
+// RequestSigner returns the server-wide service that signs outbound ActivityPub GET requests
+func (factory *Factory) RequestSigner() *service.RequestSigner {
+	return &factory.requestSigner
+}
+
 func (factory *Factory) HTTPCache() *httpcache.HTTPCache {
 	return &factory.httpCache
 }
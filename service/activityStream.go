@@ -10,6 +10,7 @@ import (
 	"github.com/benpate/data"
 	"github.com/benpate/data/option"
 	"github.com/benpate/derp"
+	"github.com/benpate/domain"
 	"github.com/benpate/exp"
 	"github.com/benpate/hannibal/streams"
 	"github.com/benpate/hannibal/vocab"
@@ -21,6 +22,7 @@ type ActivityStream struct {
 	collection  data.Collection
 	innerClient streams.Client
 	cacheClient *ascache.Client
+	hostname    string // Domain whose instance actor signs outbound requests (empty for the server)
 }
 
 /******************************************
@@ -39,6 +41,14 @@ func (service *ActivityStream) Refresh(innerClient streams.Client, cacheClient *
 	service.collection = collection
 }
 
+// ForDomain returns a copy of this service that shares the same cache, but signs
+// outbound requests using the instance actor of the provided Domain.
+func (service *ActivityStream) ForDomain(hostname string) ActivityStream {
+	result := *service
+	result.hostname = hostname
+	return result
+}
+
 /******************************************
  * Hannibal HTTP Client Interface
  ******************************************/
@@ -57,6 +67,11 @@ func (service *ActivityStream) Load(url string, options ...any) (streams.Documen
 		return streams.Document{}, derp.NewInternalError(location, "Client not initialized")
 	}
 
+	// Sign outbound requests as this Domain
+	if service.hostname != "" {
+		options = append(options, SignAs(service.hostname))
+	}
+
 	// Forward request to inner client
 	result, err := service.innerClient.Load(url, options...)
 
@@ -106,6 +121,24 @@ func (service *ActivityStream) PurgeCache() error {
 	return nil
 }
 
+// AccountAddress returns the "username@hostname" address for an ActivityPub actor, which is
+// the format used by other fediverse servers to import and export accounts.  If the actor
+// cannot be loaded, then the original actorID is returned instead.
+func (service *ActivityStream) AccountAddress(actorID string) string {
+
+	actor, err := service.Load(actorID)
+
+	if err != nil {
+		return actorID
+	}
+
+	if username := actor.PreferredUsername(); username != "" {
+		return username + "@" + domain.NameOnly(actor.ID())
+	}
+
+	return actorID
+}
+
 /******************************************
  * Custom Query Methods
  ******************************************/
@@ -239,6 +272,49 @@ func (service *ActivityStream) QueryLikesBeforeDate(relationHref string, maxDate
 	return service.queryByRelation(vocab.ActivityTypeLike, relationHref, "before", maxDate, done)
 }
 
+// QueryByHashtags returns the public documents in the cache that include any of the provided (normalized) hashtag names
+func (service *ActivityStream) QueryByHashtags(names []string, criteria exp.Expression, options ...option.Option) ([]streams.Document, error) {
+
+	const location = "service.ActivityStream.QueryByHashtags"
+
+	result := make([]streams.Document, 0)
+
+	if len(names) == 0 {
+		return result, nil
+	}
+
+	// Only public documents appear in hashtag results
+	isPublic := exp.Or(
+		exp.Equal("object.to", vocab.NamespaceActivityStreamsPublic),
+		exp.Equal("object.cc", vocab.NamespaceActivityStreamsPublic),
+	)
+
+	criteria = exp.And(criteria, hashtagExpression("object.tag.name", names), isPublic)
+
+	documents, err := service.documentIterator(criteria, options...)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error querying database", names)
+	}
+
+	defer documents.Close()
+
+	value := ascache.NewValue()
+	for documents.Next(&value) {
+
+		result = append(result, streams.NewDocument(
+			value.Object,
+			streams.WithHTTPHeader(value.HTTPHeader),
+			streams.WithStats(value.Statistics),
+			streams.WithClient(service),
+		))
+
+		value = ascache.NewValue()
+	}
+
+	return result, nil
+}
+
 /******************************************
  * Internal Methods
  ******************************************/
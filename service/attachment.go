@@ -1,20 +1,37 @@
 package service
 
 import (
+	"context"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"slices"
+
 	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/queries"
+	"github.com/EmissarySocial/emissary/tools/blurhash"
 	"github.com/benpate/data"
 	"github.com/benpate/data/option"
 	"github.com/benpate/derp"
 	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/queue"
 	"github.com/benpate/mediaserver"
 	"github.com/benpate/rosetta/schema"
+	"github.com/spf13/afero"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	_ "golang.org/x/image/webp"
 )
 
 // Attachment manages all interactions with the Attachment collection
 type Attachment struct {
 	collection  data.Collection
 	mediaServer mediaserver.MediaServer
+	originals   afero.Fs
+	cache       afero.Fs
+	queue       queue.Queue
 	host        string
 }
 
@@ -28,9 +45,12 @@ func NewAttachment() Attachment {
  ******************************************/
 
 // Refresh updates any stateful data that is cached inside this service.
-func (service *Attachment) Refresh(collection data.Collection, mediaServer mediaserver.MediaServer, host string) {
+func (service *Attachment) Refresh(collection data.Collection, mediaServer mediaserver.MediaServer, originals afero.Fs, cache afero.Fs, queue queue.Queue, host string) {
 	service.collection = collection
 	service.mediaServer = mediaServer
+	service.originals = originals
+	service.cache = cache
+	service.queue = queue
 	service.host = host
 }
 
@@ -190,6 +210,166 @@ func (service *Attachment) DeleteByID(objectType string, objectID primitive.Obje
 	return nil
 }
 
+// QueryMedia returns the unattached media files (uploaded via the Mastodon API) that match the provided IDs.
+// Results are returned in the same order as the provided IDs.
+func (service *Attachment) QueryMedia(userID primitive.ObjectID, mediaIDs []string) ([]model.Attachment, error) {
+
+	const location = "service.Attachment.QueryMedia"
+
+	result := make([]model.Attachment, 0, len(mediaIDs))
+
+	for _, mediaID := range mediaIDs {
+
+		attachmentID, err := primitive.ObjectIDFromHex(mediaID)
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Invalid media ID", mediaID, derp.WithBadRequest())
+		}
+
+		attachment := model.NewAttachment(model.AttachmentObjectTypeUser, userID)
+
+		if err := service.LoadByID(model.AttachmentObjectTypeUser, userID, attachmentID, &attachment); err != nil {
+			return nil, derp.Wrap(err, location, "Error loading media", mediaID)
+		}
+
+		if attachment.Category != model.AttachmentCategoryMedia {
+			return nil, derp.NewBadRequestError(location, "Media has already been attached", mediaID)
+		}
+
+		// RULE: Media cannot be attached until it has finished processing
+		if !attachment.IsReady() {
+			return nil, derp.New(http.StatusUnprocessableEntity, location, "Media is still being processed", mediaID)
+		}
+
+		result = append(result, attachment)
+	}
+
+	return result, nil
+}
+
+// AttachMedia moves unattached media files (uploaded via the Mastodon API) onto a Stream
+func (service *Attachment) AttachMedia(userID primitive.ObjectID, mediaIDs []string, streamID primitive.ObjectID) ([]model.Attachment, error) {
+
+	const location = "service.Attachment.AttachMedia"
+
+	attachments, err := service.QueryMedia(userID, mediaIDs)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error loading media", mediaIDs)
+	}
+
+	for index := range attachments {
+		attachments[index].ObjectType = model.AttachmentObjectTypeStream
+		attachments[index].ObjectID = streamID
+		attachments[index].Category = ""
+		attachments[index].Rank = index
+
+		if err := service.Save(&attachments[index], "Attached to Stream"); err != nil {
+			return nil, derp.Wrap(err, location, "Error saving attachment", attachments[index])
+		}
+	}
+
+	return attachments, nil
+}
+
+// SetStreamMedia replaces the attachments on a Stream with the provided media IDs (as sent by the Mastodon API).
+// Existing attachments missing from the list are removed, new media is moved onto the Stream,
+// and every attachment is ranked in the order provided.
+func (service *Attachment) SetStreamMedia(userID primitive.ObjectID, mediaIDs []string, streamID primitive.ObjectID) ([]model.Attachment, error) {
+
+	const location = "service.Attachment.SetStreamMedia"
+
+	existing, err := service.QueryByObjectID(model.AttachmentObjectTypeStream, streamID)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error loading existing attachments", streamID)
+	}
+
+	kept, removed, addedIDs := diffStreamMedia(existing, mediaIDs)
+
+	// Validate new media before changing anything
+	added, err := service.QueryMedia(userID, addedIDs)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error loading media", addedIDs)
+	}
+
+	for _, attachment := range added {
+		kept[attachment.AttachmentID.Hex()] = attachment
+	}
+
+	// Remove attachments that the client left out
+	for index := range removed {
+		if err := service.Delete(&removed[index], "Removed via Mastodon API"); err != nil {
+			return nil, derp.Wrap(err, location, "Error removing attachment", removed[index])
+		}
+	}
+
+	// Save everything else in the client's order
+	result := make([]model.Attachment, 0, len(kept))
+
+	for _, mediaID := range mediaIDs {
+
+		attachment, ok := kept[mediaID]
+
+		if !ok {
+			continue
+		}
+
+		delete(kept, mediaID)
+
+		if attachment.ObjectType != model.AttachmentObjectTypeStream {
+			attachment.ObjectType = model.AttachmentObjectTypeStream
+			attachment.ObjectID = streamID
+			attachment.Category = ""
+		}
+
+		attachment.Rank = len(result)
+
+		if err := service.Save(&attachment, "Attached to Stream"); err != nil {
+			return nil, derp.Wrap(err, location, "Error saving attachment", attachment)
+		}
+
+		result = append(result, attachment)
+	}
+
+	return result, nil
+}
+
+// diffStreamMedia compares a Stream's existing attachments with the media IDs sent by a client.
+// It returns the attachments to keep (by ID), the attachments to remove, and the IDs of new media to attach.
+func diffStreamMedia(existing []model.Attachment, mediaIDs []string) (map[string]model.Attachment, []model.Attachment, []string) {
+
+	kept := make(map[string]model.Attachment, len(mediaIDs))
+	removed := make([]model.Attachment, 0)
+	addedIDs := make([]string, 0)
+
+	for _, attachment := range existing {
+		if slices.Contains(mediaIDs, attachment.AttachmentID.Hex()) {
+			kept[attachment.AttachmentID.Hex()] = attachment
+		} else {
+			removed = append(removed, attachment)
+		}
+	}
+
+	for _, mediaID := range mediaIDs {
+		if _, ok := kept[mediaID]; ok {
+			continue
+		}
+		if slices.Contains(addedIDs, mediaID) {
+			continue
+		}
+		addedIDs = append(addedIDs, mediaID)
+	}
+
+	return kept, removed, addedIDs
+}
+
+// Usage returns the number of files and total number of bytes uploaded by the provided User
+func (service *Attachment) Usage(userID primitive.ObjectID) (int, int64, error) {
+	return queries.AttachmentUsage(context.TODO(), service.collection, userID)
+}
+
 // DeleteByStream removes all attachments from the provided stream (virtual delete)
 func (service *Attachment) DeleteAll(objectType string, objectID primitive.ObjectID, note string) error {
 
@@ -267,3 +447,92 @@ func (service *Attachment) MakeRoom(objectType string, objectID primitive.Object
 
 	return nil
 }
+
+// Transcode queues a background task that probes video and audio Attachments for their
+// dimensions and duration, converts them into web-safe renditions, and extracts poster frames.
+// Other Attachments are left unchanged.
+func (service *Attachment) Transcode(attachment *model.Attachment) error {
+
+	const location = "service.Attachment.Transcode"
+
+	// Only video and audio files need to be transcoded
+	if !attachment.IsVideo() && !attachment.IsAudio() {
+		return nil
+	}
+
+	// Mark the Attachment as WORKING until the background task is complete
+	attachment.Status = model.AttachmentStatusWorking
+
+	if err := service.Save(attachment, "Transcoding"); err != nil {
+		return derp.Wrap(err, location, "Error saving Attachment", attachment)
+	}
+
+	service.queue.Push(NewTaskTranscodeAttachment(service, service.originals, service.cache, *attachment))
+	return nil
+}
+
+// TranscodeNow probes and transcodes video and audio Attachments immediately, instead of
+// in a background task, then reloads the finished Attachment.  Other Attachments are left unchanged.
+func (service *Attachment) TranscodeNow(attachment *model.Attachment) error {
+
+	const location = "service.Attachment.TranscodeNow"
+
+	// Only video and audio files need to be transcoded
+	if !attachment.IsVideo() && !attachment.IsAudio() {
+		return nil
+	}
+
+	// Transcoding errors are not fatal, because the original file can still be downloaded
+	task := NewTaskTranscodeAttachment(service, service.originals, service.cache, *attachment)
+
+	if err := task.Run(); err != nil {
+		derp.Report(derp.Wrap(err, location, "Error transcoding Attachment", attachment.AttachmentID))
+	}
+
+	// Reload the Attachment to get the values calculated by the transcoder
+	if err := service.Load(exp.Equal("_id", attachment.AttachmentID), attachment); err != nil {
+		return derp.Wrap(err, location, "Error reloading Attachment", attachment.AttachmentID)
+	}
+
+	return nil
+}
+
+// CalcBlurhash computes a BlurHash placeholder for image Attachments.
+// Other Attachments are left unchanged.
+func (service *Attachment) CalcBlurhash(attachment *model.Attachment) error {
+
+	const location = "service.Attachment.CalcBlurhash"
+
+	if attachment.MimeCategory() != model.AttachmentMediaTypeImage {
+		return nil
+	}
+
+	file, err := service.originals.Open(attachment.AttachmentID.Hex())
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error opening original file", attachment.AttachmentID)
+	}
+
+	defer file.Close()
+
+	hash, err := encodeBlurhash(file)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error encoding blurhash", attachment.AttachmentID)
+	}
+
+	attachment.Blurhash = hash
+	return nil
+}
+
+// encodeBlurhash decodes an image and returns its BlurHash
+func encodeBlurhash(reader io.Reader) (string, error) {
+
+	img, _, err := image.Decode(reader)
+
+	if err != nil {
+		return "", derp.Wrap(err, "service.encodeBlurhash", "Error decoding image")
+	}
+
+	return blurhash.Encode(img, 4, 3)
+}
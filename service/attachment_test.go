@@ -0,0 +1,40 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDiffStreamMedia(t *testing.T) {
+
+	first := model.NewAttachment(model.AttachmentObjectTypeStream, primitive.NewObjectID())
+	second := model.NewAttachment(model.AttachmentObjectTypeStream, first.ObjectID)
+	newID := primitive.NewObjectID().Hex()
+
+	existing := []model.Attachment{first, second}
+	mediaIDs := []string{newID, second.AttachmentID.Hex(), newID}
+
+	kept, removed, addedIDs := diffStreamMedia(existing, mediaIDs)
+
+	require.Equal(t, 1, len(kept))
+	require.Contains(t, kept, second.AttachmentID.Hex())
+
+	require.Equal(t, 1, len(removed))
+	require.Equal(t, first.AttachmentID, removed[0].AttachmentID)
+
+	require.Equal(t, []string{newID}, addedIDs)
+}
+
+func TestDiffStreamMedia_Empty(t *testing.T) {
+
+	first := model.NewAttachment(model.AttachmentObjectTypeStream, primitive.NewObjectID())
+
+	kept, removed, addedIDs := diffStreamMedia([]model.Attachment{first}, []string{})
+
+	require.Empty(t, kept)
+	require.Equal(t, 1, len(removed))
+	require.Empty(t, addedIDs)
+}
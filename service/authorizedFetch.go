@@ -0,0 +1,193 @@
+package service
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/sigs"
+)
+
+// AuthorizedFetch service verifies HTTP signatures on inbound ActivityPub GET requests.
+// When a Domain enables "authorized fetch" (also called "secure mode") then remote
+// servers must sign every request for our ActivityPub collections, which lets us
+// refuse content to actors and domains that have been blocked.
+type AuthorizedFetch struct {
+	domainService       *Domain
+	domainPolicyService *DomainPolicy
+	ruleService         *Rule
+	activityService     *ActivityStream
+}
+
+// NewAuthorizedFetch returns a fully initialized AuthorizedFetch service
+func NewAuthorizedFetch() AuthorizedFetch {
+	return AuthorizedFetch{}
+}
+
+// Refresh updates any stateful data that is cached inside this service.
+func (service *AuthorizedFetch) Refresh(domainService *Domain, domainPolicyService *DomainPolicy, ruleService *Rule, activityService *ActivityStream) {
+	service.domainService = domainService
+	service.domainPolicyService = domainPolicyService
+	service.ruleService = ruleService
+	service.activityService = activityService
+}
+
+// Close stops any background processes controlled by this service
+func (service *AuthorizedFetch) Close() {
+	// Nothing to do here.
+}
+
+/******************************************
+ * Custom Methods
+ ******************************************/
+
+// IsRequired returns TRUE if this Domain requires signatures on ActivityPub GET requests
+func (service *AuthorizedFetch) IsRequired() bool {
+	return service.domainService.Get().AuthorizedFetch
+}
+
+// Require returns an error if authorized fetch is enabled and the request
+// is not signed by an allowed actor.
+func (service *AuthorizedFetch) Require(request *http.Request) error {
+
+	if !service.IsRequired() {
+		return nil
+	}
+
+	if request.Header.Get("Signature") == "" {
+		return derp.NewUnauthorizedError("service.AuthorizedFetch.Require", "Request must include an HTTP signature")
+	}
+
+	_, err := service.Verify(request)
+	return err
+}
+
+// Allow returns an error if authorized fetch is enabled and the request is
+// signed by a blocked actor.  Unsigned requests are allowed, so that remote
+// servers can still retrieve actor profiles (and their public keys) in order
+// to verify our own signatures.
+func (service *AuthorizedFetch) Allow(request *http.Request) error {
+
+	if !service.IsRequired() {
+		return nil
+	}
+
+	if request.Header.Get("Signature") == "" {
+		return nil
+	}
+
+	_, err := service.Verify(request)
+	return err
+}
+
+// Verify validates the HTTP signature on an inbound GET request, and returns the
+// ID of the actor who signed it.  It returns an error if the signature is
+// invalid, or if the actor (or their domain) is blocked by this Domain.
+func (service *AuthorizedFetch) Verify(request *http.Request) (string, error) {
+	verifier := sigs.NewVerifier(sigs.VerifierFields(sigs.FieldRequestTarget, sigs.FieldHost, sigs.FieldDate))
+	return service.verify(request, verifier)
+}
+
+// VerifyPost validates the HTTP signature (and body digest) on an inbound POST request,
+// and returns the ID of the actor who signed it.  Unlike inbox.ReceiveRequest, the signer
+// does not need to be the actor of the posted activity, so this is used to identify
+// relays that forward activities from other actors.
+func (service *AuthorizedFetch) VerifyPost(request *http.Request) (string, error) {
+	return service.verify(request, sigs.NewVerifier())
+}
+
+/******************************************
+ * Helper Methods
+ ******************************************/
+
+// verify validates the HTTP signature on an inbound request using the provided Verifier,
+// and returns the ID of the actor who signed it.
+func (service *AuthorizedFetch) verify(request *http.Request, verifier sigs.Verifier) (string, error) {
+
+	const location = "service.AuthorizedFetch.verify"
+
+	keyID := domainPolicyKeyID(request.Header.Get("Signature"))
+
+	if keyID == "" {
+		return "", derp.NewUnauthorizedError(location, "Request must include an HTTP signature")
+	}
+
+	// RULE: Do not make network requests to suspended or blocked domains
+	if err := service.allowActor(keyID); err != nil {
+		return "", derp.Wrap(err, location, "Signing key is not allowed", keyID)
+	}
+
+	// Verify the signature using the public key published by the remote actor
+	actorID := ""
+
+	keyFinder := func(keyID string) (string, error) {
+		publicKeyPEM, owner, err := service.findKey(keyID)
+		actorID = owner
+		return publicKeyPEM, err
+	}
+
+	if err := verifier.Verify(request, keyFinder); err != nil {
+		return "", derp.Wrap(err, location, "Unable to verify HTTP signature", derp.WithCode(http.StatusUnauthorized))
+	}
+
+	// RULE: The verified actor must not be blocked
+	if err := service.allowActor(actorID); err != nil {
+		return "", derp.Wrap(err, location, "Actor is not allowed", actorID)
+	}
+
+	return actorID, nil
+}
+
+// allowActor returns an error if the provided actor (or their domain)
+// is suspended by a DomainPolicy or blocked by a Domain Rule
+func (service *AuthorizedFetch) allowActor(actorID string) error {
+
+	const location = "service.AuthorizedFetch.allowActor"
+
+	if service.domainPolicyService.IsSuspended(actorID) {
+		return derp.NewForbiddenError(location, "Domain is suspended", actorID)
+	}
+
+	rules, err := service.ruleService.QueryDomainBlocksByActor(actorID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading rules", actorID)
+	}
+
+	if len(rules) > 0 {
+		return derp.NewForbiddenError(location, "Actor is blocked", actorID)
+	}
+
+	return nil
+}
+
+// findKey retrieves a public key (and the ID of the actor that owns it) from the ActivityStream cache
+func (service *AuthorizedFetch) findKey(keyID string) (string, string, error) {
+
+	const location = "service.AuthorizedFetch.findKey"
+
+	// Most keys are fragments of the actor document, but some servers publish them separately
+	documentID, _, _ := strings.Cut(keyID, "#")
+	document, err := service.activityService.Load(documentID)
+
+	if err != nil {
+		return "", "", derp.Wrap(err, location, "Error loading public key", keyID)
+	}
+
+	// If the document is a standalone Key, then load the actor that owns it
+	if owner := document.Get("owner"); owner.NotNil() {
+
+		if document, err = owner.Load(); err != nil {
+			return "", "", derp.Wrap(err, location, "Error loading key owner", keyID)
+		}
+	}
+
+	// Search the actor's public keys for the one that matches the keyID
+	for key := document.PublicKey(); key.NotNil(); key = key.Tail() {
+		if key.ID() == keyID {
+			return key.PublicKeyPEM(), document.ID(), nil
+		}
+	}
+
+	return "", "", derp.NewUnauthorizedError(location, "Actor must publish the key used to sign this request", document.ID(), keyID)
+}
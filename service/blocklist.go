@@ -0,0 +1,428 @@
+package service
+
+import (
+	"io"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/remote"
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Blocklist manages subscriptions to shared domain blocklists, and imports/exports
+// server-wide domain Rules in the formats used by other fediverse servers.
+type Blocklist struct {
+	collection  data.Collection
+	ruleService *Rule
+	closed      chan bool
+}
+
+// NewBlocklist returns a fully initialized Blocklist service
+func NewBlocklist() Blocklist {
+	return Blocklist{
+		closed: make(chan bool),
+	}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+// Refresh updates any stateful data that is cached inside this service.
+func (service *Blocklist) Refresh(collection data.Collection, ruleService *Rule) {
+	service.collection = collection
+	service.ruleService = ruleService
+}
+
+// Close stops the background scheduler
+func (service *Blocklist) Close() {
+	close(service.closed)
+}
+
+// Start begins the background scheduler that re-fetches each Blocklist
+// according to its own polling frequency
+func (service *Blocklist) Start() {
+
+	const location = "service.Blocklist.Start"
+
+	// Wait until the service has booted up correctly.
+	for service.collection == nil {
+		time.Sleep(1 * time.Minute)
+	}
+
+	for {
+
+		// Check randomly between 30 and 60 minutes
+		select {
+
+		case <-service.closed:
+			return
+
+		case <-time.After(time.Duration(rand.Intn(30)+30) * time.Minute):
+
+			blocklists, err := service.QueryPollable()
+
+			if err != nil {
+				derp.Report(derp.Wrap(err, location, "Error listing pollable blocklists"))
+				continue
+			}
+
+			for index := range blocklists {
+				if err := service.Sync(&blocklists[index]); err != nil {
+					derp.Report(derp.Wrap(err, location, "Error synchronizing blocklist", blocklists[index].URL))
+				}
+			}
+		}
+	}
+}
+
+/******************************************
+ * Common Data Methods
+ ******************************************/
+
+// Query returns a slice of Blocklists that match the provided criteria
+func (service *Blocklist) Query(criteria exp.Expression, options ...option.Option) ([]model.Blocklist, error) {
+	result := make([]model.Blocklist, 0)
+	err := service.collection.Query(&result, notDeleted(criteria), options...)
+	return result, err
+}
+
+// List returns an iterator containing all of the Blocklists that match the provided criteria
+func (service *Blocklist) List(criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.collection.Iterator(notDeleted(criteria), options...)
+}
+
+// Load retrieves a Blocklist from the database
+func (service *Blocklist) Load(criteria exp.Expression, blocklist *model.Blocklist) error {
+
+	if err := service.collection.Load(notDeleted(criteria), blocklist); err != nil {
+		return derp.Wrap(err, "service.Blocklist.Load", "Error loading Blocklist", criteria)
+	}
+
+	return nil
+}
+
+// Save adds/updates a Blocklist in the database
+func (service *Blocklist) Save(blocklist *model.Blocklist, note string) error {
+
+	const location = "service.Blocklist.Save"
+
+	// Validate the value before saving
+	if err := service.Schema().Validate(blocklist); err != nil {
+		return derp.Wrap(err, location, "Error validating Blocklist", blocklist)
+	}
+
+	// Save the value to the database
+	if err := service.collection.Save(blocklist, note); err != nil {
+		return derp.Wrap(err, location, "Error saving Blocklist", blocklist, note)
+	}
+
+	return nil
+}
+
+// Delete removes a Blocklist from the database (virtual delete), along with all of the Rules that it created
+func (service *Blocklist) Delete(blocklist *model.Blocklist, note string) error {
+
+	const location = "service.Blocklist.Delete"
+
+	rules, err := service.ruleService.QueryByBlocklist(blocklist.BlocklistID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading Rules for Blocklist", blocklist.BlocklistID)
+	}
+
+	for index := range rules {
+		if err := service.ruleService.Delete(&rules[index], "Blocklist removed"); err != nil {
+			return derp.Wrap(err, location, "Error deleting Rule", rules[index].RuleID)
+		}
+	}
+
+	if err := service.collection.Delete(blocklist, note); err != nil {
+		return derp.Wrap(err, location, "Error deleting Blocklist", blocklist, note)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Model Service Methods
+ ******************************************/
+
+// ObjectType returns the type of object that this service manages
+func (service *Blocklist) ObjectType() string {
+	return "Blocklist"
+}
+
+// New returns a fully initialized model.Blocklist as a data.Object.
+func (service *Blocklist) ObjectNew() data.Object {
+	result := model.NewBlocklist()
+	return &result
+}
+
+func (service *Blocklist) ObjectID(object data.Object) primitive.ObjectID {
+
+	if blocklist, ok := object.(*model.Blocklist); ok {
+		return blocklist.BlocklistID
+	}
+
+	return primitive.NilObjectID
+}
+
+func (service *Blocklist) ObjectQuery(result any, criteria exp.Expression, options ...option.Option) error {
+	return service.collection.Query(result, notDeleted(criteria), options...)
+}
+
+func (service *Blocklist) ObjectList(criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.List(criteria, options...)
+}
+
+func (service *Blocklist) ObjectLoad(criteria exp.Expression) (data.Object, error) {
+	result := model.NewBlocklist()
+	err := service.Load(criteria, &result)
+	return &result, err
+}
+
+func (service *Blocklist) ObjectSave(object data.Object, comment string) error {
+	if blocklist, ok := object.(*model.Blocklist); ok {
+		return service.Save(blocklist, comment)
+	}
+	return derp.NewInternalError("service.Blocklist.ObjectSave", "Invalid Object Type", object)
+}
+
+func (service *Blocklist) ObjectDelete(object data.Object, comment string) error {
+	if blocklist, ok := object.(*model.Blocklist); ok {
+		return service.Delete(blocklist, comment)
+	}
+	return derp.NewInternalError("service.Blocklist.ObjectDelete", "Invalid Object Type", object)
+}
+
+func (service *Blocklist) ObjectUserCan(object data.Object, authorization model.Authorization, action string) error {
+	return derp.NewUnauthorizedError("service.Blocklist", "Not Authorized")
+}
+
+func (service *Blocklist) Schema() schema.Schema {
+	return schema.New(model.BlocklistSchema())
+}
+
+/******************************************
+ * Custom Queries
+ ******************************************/
+
+// LoadByID retrieves a single Blocklist by its ID
+func (service *Blocklist) LoadByID(blocklistID primitive.ObjectID, blocklist *model.Blocklist) error {
+	return service.Load(exp.Equal("_id", blocklistID), blocklist)
+}
+
+// QueryPollable returns all Blocklists that are ready to be fetched again
+func (service *Blocklist) QueryPollable() ([]model.Blocklist, error) {
+	return service.Query(exp.LessOrEqual("nextPoll", time.Now().Unix()))
+}
+
+/******************************************
+ * Subscriptions
+ ******************************************/
+
+// Sync fetches a Blocklist from its remote URL, and synchronizes its entries into
+// server-wide DOMAIN Rules.  Rules for new entries are created (unless the domain is
+// already blocked), rules for changed entries are updated, and rules for entries that
+// are no longer listed are removed.  Empty lists are treated as a failed fetch.
+func (service *Blocklist) Sync(blocklist *model.Blocklist) error {
+
+	const location = "service.Blocklist.Sync"
+
+	// Fetch the remote blocklist
+	var body string
+	if err := remote.Get(blocklist.URL).Accept("text/csv", "application/json", "text/plain").Result(&body).Send(); err != nil {
+		return service.syncFailure(blocklist, derp.Wrap(err, location, "Error fetching blocklist", blocklist.URL))
+	}
+
+	entries, err := ParseBlocklist(strings.NewReader(body))
+
+	if err != nil {
+		return service.syncFailure(blocklist, derp.Wrap(err, location, "Error parsing blocklist", blocklist.URL))
+	}
+
+	// RULE: Never prune existing Rules because of an empty (or unreadable) response
+	if len(entries) == 0 {
+		return service.syncFailure(blocklist, derp.NewInternalError(location, "Blocklist is empty", blocklist.URL))
+	}
+
+	// Index the Rules that were previously imported from this Blocklist
+	rules, err := service.ruleService.QueryByBlocklist(blocklist.BlocklistID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading Rules for Blocklist", blocklist.BlocklistID)
+	}
+
+	existing := make(map[string]model.Rule, len(rules))
+
+	for _, rule := range rules {
+		existing[rule.Trigger] = rule
+	}
+
+	// Create or update a Rule for each entry
+	ruleCount := 0
+
+	for _, entry := range entries {
+
+		action := entry.RuleAction()
+
+		if action == "" {
+			continue
+		}
+
+		rule, found := existing[entry.Domain]
+		delete(existing, entry.Domain)
+
+		if !found {
+
+			// Skip domains that already have a server-wide rule (added manually, or by another Blocklist)
+			rule = model.NewRule()
+			if err := service.ruleService.LoadByTrigger(primitive.NilObjectID, model.RuleTypeDomain, entry.Domain, &rule); err == nil {
+				continue
+			} else if !derp.NotFound(err) {
+				return derp.Wrap(err, location, "Error checking for existing Rule", entry.Domain)
+			}
+
+			rule.Type = model.RuleTypeDomain
+			rule.Trigger = entry.Domain
+		} else if (rule.Action == action) && (rule.Summary == entry.Comment) && (rule.BlocklistLabel == blocklist.Label) {
+			ruleCount++
+			continue
+		}
+
+		rule.UserID = primitive.NilObjectID
+		rule.BlocklistID = blocklist.BlocklistID
+		rule.BlocklistLabel = blocklist.Label
+		rule.Action = action
+		rule.Summary = entry.Comment
+
+		if err := service.ruleService.Save(&rule, "Imported from blocklist"); err != nil {
+			return derp.Wrap(err, location, "Error saving Rule", rule)
+		}
+
+		ruleCount++
+	}
+
+	// Remove Rules for entries that are no longer listed
+	for _, rule := range existing {
+		if err := service.ruleService.Delete(&rule, "Removed from blocklist"); err != nil {
+			return derp.Wrap(err, location, "Error deleting Rule", rule.RuleID)
+		}
+	}
+
+	// Update the Blocklist status
+	blocklist.RuleCount = ruleCount
+	blocklist.SetPolled(model.BlocklistStatusSuccess, "")
+
+	if err := service.Save(blocklist, "Synchronized"); err != nil {
+		return derp.Wrap(err, location, "Error saving Blocklist", blocklist)
+	}
+
+	return nil
+}
+
+// syncFailure records a failed fetch on the Blocklist, and returns the original error
+func (service *Blocklist) syncFailure(blocklist *model.Blocklist, err error) error {
+
+	blocklist.SetPolled(model.BlocklistStatusFailure, derp.Message(err))
+
+	if saveErr := service.Save(blocklist, "Synchronization failed"); saveErr != nil {
+		derp.Report(derp.Wrap(saveErr, "service.Blocklist.syncFailure", "Error saving Blocklist", blocklist))
+	}
+
+	return err
+}
+
+/******************************************
+ * Import / Export
+ ******************************************/
+
+// Import reads a blocklist file (CSV or JSON) and creates a server-wide DOMAIN Rule for
+// every entry that is not already blocked.  It returns the number of entries imported.
+func (service *Blocklist) Import(reader io.Reader) (int, error) {
+
+	const location = "service.Blocklist.Import"
+
+	entries, err := ParseBlocklist(reader)
+
+	if err != nil {
+		return 0, derp.Wrap(err, location, "Error parsing blocklist")
+	}
+
+	count := 0
+
+	for _, entry := range entries {
+
+		action := entry.RuleAction()
+
+		if action == "" {
+			continue
+		}
+
+		// Skip domains that already have a server-wide rule
+		rule := model.NewRule()
+		if err := service.ruleService.LoadByTrigger(primitive.NilObjectID, model.RuleTypeDomain, entry.Domain, &rule); err == nil {
+			continue
+		} else if !derp.NotFound(err) {
+			return count, derp.Wrap(err, location, "Error checking for existing Rule", entry.Domain)
+		}
+
+		rule.UserID = primitive.NilObjectID
+		rule.Type = model.RuleTypeDomain
+		rule.Trigger = entry.Domain
+		rule.Action = action
+		rule.Summary = entry.Comment
+
+		if err := service.ruleService.Save(&rule, "Imported from file"); err != nil {
+			return count, derp.Wrap(err, location, "Error saving Rule", rule)
+		}
+
+		count++
+	}
+
+	return count, nil
+}
+
+// ExportCSV writes all server-wide DOMAIN Rules as a Mastodon-compatible CSV file
+func (service *Blocklist) ExportCSV(writer io.Writer) error {
+
+	const location = "service.Blocklist.ExportCSV"
+
+	rules, err := service.ruleService.QueryDomainRules()
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading domain Rules")
+	}
+
+	if err := WriteBlocklistCSV(writer, rules); err != nil {
+		return derp.Wrap(err, location, "Error writing CSV file")
+	}
+
+	return nil
+}
+
+// ExportJSON writes all server-wide DOMAIN Rules as a JSON array of Mastodon DomainBlocks
+func (service *Blocklist) ExportJSON(writer io.Writer) error {
+
+	const location = "service.Blocklist.ExportJSON"
+
+	rules, err := service.ruleService.QueryDomainRules()
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading domain Rules")
+	}
+
+	if err := WriteBlocklistJSON(writer, rules); err != nil {
+		return derp.Wrap(err, location, "Error writing JSON file")
+	}
+
+	return nil
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/convert"
+	"github.com/benpate/rosetta/first"
+	"github.com/benpate/toot/object"
+)
+
+// csvHeaderBlocklist is the header row used by Mastodon-compatible domain blocklist exports
+var csvHeaderBlocklist = []string{"#domain", "#severity", "#reject_media", "#reject_reports", "#public_comment", "#obfuscate"}
+
+// ParseBlocklist reads a domain blocklist in any of the common formats:
+// Mastodon CSV exports (with or without a header row), plain text files with
+// one domain per line, JSON arrays of domain names, and JSON arrays of objects
+// like those returned by the Mastodon `/api/v1/instance/domain_blocks` endpoint.
+func ParseBlocklist(reader io.Reader) ([]model.BlocklistEntry, error) {
+
+	const location = "service.ParseBlocklist"
+
+	body, err := io.ReadAll(reader)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error reading blocklist")
+	}
+
+	body = bytes.TrimSpace(body)
+
+	var entries []model.BlocklistEntry
+
+	if bytes.HasPrefix(body, []byte("[")) || bytes.HasPrefix(body, []byte("{")) {
+		entries, err = parseBlocklistJSON(body)
+	} else {
+		entries, err = parseBlocklistCSV(body)
+	}
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error parsing blocklist")
+	}
+
+	// Remove invalid and duplicate entries
+	result := make([]model.BlocklistEntry, 0, len(entries))
+	seen := make(map[string]bool, len(entries))
+
+	for _, entry := range entries {
+
+		entry.Domain = normalizeBlocklistDomain(entry.Domain)
+		entry.Severity = normalizeBlocklistSeverity(entry.Severity)
+		entry.Comment = strings.TrimSpace(entry.Comment)
+
+		if (entry.Domain == "") || seen[entry.Domain] {
+			continue
+		}
+
+		seen[entry.Domain] = true
+		result = append(result, entry)
+	}
+
+	return result, nil
+}
+
+// parseBlocklistJSON reads a JSON array of domain names or domain block objects
+func parseBlocklistJSON(body []byte) ([]model.BlocklistEntry, error) {
+
+	var items []any
+
+	if err := json.Unmarshal(body, &items); err != nil {
+
+		// Some lists wrap the array in an object
+		var wrapper map[string]any
+		if err := json.Unmarshal(body, &wrapper); err != nil {
+			return nil, derp.Wrap(err, "service.parseBlocklistJSON", "Invalid JSON")
+		}
+
+		for _, key := range []string{"domain_blocks", "blocks", "domains"} {
+			if list, ok := wrapper[key].([]any); ok {
+				items = list
+				break
+			}
+		}
+	}
+
+	result := make([]model.BlocklistEntry, 0, len(items))
+
+	for _, item := range items {
+
+		switch item := item.(type) {
+
+		case string:
+			result = append(result, model.BlocklistEntry{Domain: item})
+
+		case map[string]any:
+			result = append(result, model.BlocklistEntry{
+				Domain:   firstBlocklistValue(item, "domain", "host", "name"),
+				Severity: firstBlocklistValue(item, "severity"),
+				Comment:  firstBlocklistValue(item, "comment", "public_comment", "reason"),
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// parseBlocklistCSV reads a Mastodon CSV export, or a plain text file with one domain per line
+func parseBlocklistCSV(body []byte) ([]model.BlocklistEntry, error) {
+
+	csvReader := csv.NewReader(bytes.NewReader(body))
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	records, err := csvReader.ReadAll()
+
+	if err != nil {
+		return nil, derp.Wrap(err, "service.parseBlocklistCSV", "Invalid CSV")
+	}
+
+	// Default column positions match the Mastodon export format
+	columns := map[string]int{"domain": 0, "severity": 1, "public_comment": 4}
+	result := make([]model.BlocklistEntry, 0, len(records))
+
+	for index, record := range records {
+
+		if len(record) == 0 {
+			continue
+		}
+
+		// Read column positions from the header row, if present
+		if index == 0 {
+			if header := strings.TrimPrefix(strings.ToLower(record[0]), "#"); header == "domain" {
+				columns = make(map[string]int, len(record))
+				for position, name := range record {
+					columns[strings.TrimPrefix(strings.ToLower(strings.TrimSpace(name)), "#")] = position
+				}
+				continue
+			}
+		}
+
+		// Skip comments in plain text lists
+		if strings.HasPrefix(record[0], "#") {
+			continue
+		}
+
+		result = append(result, model.BlocklistEntry{
+			Domain:   csvColumn(record, columns, "domain"),
+			Severity: csvColumn(record, columns, "severity"),
+			Comment:  first.String(csvColumn(record, columns, "public_comment"), csvColumn(record, columns, "comment")),
+		})
+	}
+
+	return result, nil
+}
+
+// WriteBlocklistCSV writes server-wide DOMAIN Rules as a Mastodon-compatible CSV file
+func WriteBlocklistCSV(writer io.Writer, rules []model.Rule) error {
+
+	const location = "service.WriteBlocklistCSV"
+
+	csvWriter := csv.NewWriter(writer)
+
+	if err := csvWriter.Write(csvHeaderBlocklist); err != nil {
+		return derp.Wrap(err, location, "Error writing CSV header")
+	}
+
+	for _, rule := range rules {
+
+		row := []string{rule.Trigger, blocklistSeverity(rule), "false", "false", rule.Summary, "false"}
+
+		if err := csvWriter.Write(row); err != nil {
+			return derp.Wrap(err, location, "Error writing CSV row", rule.RuleID)
+		}
+	}
+
+	csvWriter.Flush()
+
+	if err := csvWriter.Error(); err != nil {
+		return derp.Wrap(err, location, "Error writing CSV file")
+	}
+
+	return nil
+}
+
+// WriteBlocklistJSON writes server-wide DOMAIN Rules as a JSON array of Mastodon DomainBlocks
+func WriteBlocklistJSON(writer io.Writer, rules []model.Rule) error {
+
+	result := make([]object.DomainBlock, 0, len(rules))
+
+	for _, rule := range rules {
+
+		digest := sha256.Sum256([]byte(rule.Trigger))
+
+		result = append(result, object.DomainBlock{
+			Domain:   rule.Trigger,
+			Digest:   hex.EncodeToString(digest[:]),
+			Severity: blocklistSeverity(rule),
+			Comment:  rule.Summary,
+		})
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(result); err != nil {
+		return derp.Wrap(err, "service.WriteBlocklistJSON", "Error encoding JSON")
+	}
+
+	return nil
+}
+
+// blocklistSeverity returns the Mastodon severity that matches a Rule's action
+func blocklistSeverity(rule model.Rule) string {
+
+	if rule.Action == model.RuleActionMute {
+		return model.BlocklistSeveritySilence
+	}
+
+	return model.BlocklistSeveritySuspend
+}
+
+// normalizeBlocklistDomain returns a lowercase domain name, or an empty string
+// if the value is obfuscated or otherwise invalid.
+func normalizeBlocklistDomain(value string) string {
+
+	value = strings.ToLower(strings.TrimSpace(value))
+	value = strings.TrimPrefix(value, "*.")
+	value = strings.TrimSuffix(value, ".")
+
+	// Obfuscated domains (like "ex*mple.com") cannot be matched
+	if strings.ContainsAny(value, "*? /") {
+		return ""
+	}
+
+	if !strings.Contains(value, ".") {
+		return ""
+	}
+
+	return value
+}
+
+// normalizeBlocklistSeverity maps the many severity names used by other servers onto Mastodon severities
+func normalizeBlocklistSeverity(value string) string {
+
+	switch strings.ToLower(strings.TrimSpace(value)) {
+
+	case "silence", "limit", "mute":
+		return model.BlocklistSeveritySilence
+
+	case "noop", "none":
+		return model.BlocklistSeverityNoop
+	}
+
+	return model.BlocklistSeveritySuspend
+}
+
+// firstBlocklistValue returns the first non-empty string value from a JSON object
+func firstBlocklistValue(item map[string]any, names ...string) string {
+
+	for _, name := range names {
+		if value := convert.String(item[name]); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// csvColumn returns a named column from a CSV record, or an empty string if it does not exist
+func csvColumn(record []string, columns map[string]int, name string) string {
+
+	if position, ok := columns[name]; ok && (position < len(record)) {
+		return strings.TrimSpace(record[position])
+	}
+
+	return ""
+}
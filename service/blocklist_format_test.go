@@ -0,0 +1,83 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBlocklist_MastodonCSV(t *testing.T) {
+
+	body := "#domain,#severity,#reject_media,#reject_reports,#public_comment,#obfuscate\n" +
+		"spam.example,suspend,false,false,Spam,false\n" +
+		"loud.example,silence,true,false,,false\n" +
+		"ok.example,noop,true,false,,false\n" +
+		"ob*scured.example,suspend,false,false,,true\n"
+
+	entries, err := ParseBlocklist(strings.NewReader(body))
+	require.Nil(t, err)
+	require.Equal(t, []model.BlocklistEntry{
+		{Domain: "spam.example", Severity: "suspend", Comment: "Spam"},
+		{Domain: "loud.example", Severity: "silence"},
+		{Domain: "ok.example", Severity: "noop"},
+	}, entries)
+}
+
+func TestParseBlocklist_PlainText(t *testing.T) {
+
+	body := "# My blocklist\nspam.example\n*.Wildcard.example\nspam.example\n"
+
+	entries, err := ParseBlocklist(strings.NewReader(body))
+	require.Nil(t, err)
+	require.Equal(t, []model.BlocklistEntry{
+		{Domain: "spam.example", Severity: "suspend"},
+		{Domain: "wildcard.example", Severity: "suspend"},
+	}, entries)
+}
+
+func TestParseBlocklist_JSON(t *testing.T) {
+
+	{
+		body := `[{"domain":"spam.example","digest":"abc","severity":"suspend","comment":"Spam"},{"domain":"loud.example","severity":"silence"}]`
+
+		entries, err := ParseBlocklist(strings.NewReader(body))
+		require.Nil(t, err)
+		require.Equal(t, []model.BlocklistEntry{
+			{Domain: "spam.example", Severity: "suspend", Comment: "Spam"},
+			{Domain: "loud.example", Severity: "silence"},
+		}, entries)
+	}
+
+	{
+		entries, err := ParseBlocklist(strings.NewReader(`["spam.example", "loud.example"]`))
+		require.Nil(t, err)
+		require.Equal(t, 2, len(entries))
+	}
+
+	{
+		_, err := ParseBlocklist(strings.NewReader(`[not json`))
+		require.NotNil(t, err)
+	}
+}
+
+func TestWriteBlocklistCSV(t *testing.T) {
+
+	rules := []model.Rule{
+		{Trigger: "spam.example", Action: model.RuleActionBlock, Summary: "Spam"},
+		{Trigger: "loud.example", Action: model.RuleActionMute},
+	}
+
+	var buffer bytes.Buffer
+	require.Nil(t, WriteBlocklistCSV(&buffer, rules))
+
+	// Exported files can be re-imported
+	entries, err := ParseBlocklist(&buffer)
+	require.Nil(t, err)
+	require.Equal(t, []model.BlocklistEntry{
+		{Domain: "spam.example", Severity: "suspend", Comment: "Spam"},
+		{Domain: "loud.example", Severity: "silence"},
+	}, entries)
+}
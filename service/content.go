@@ -85,6 +85,12 @@ func (service *Content) Format(content *model.Content) {
 	}
 
 	// Sanitize all HTML, no matter what source format
+	content.HTML = contentPolicy().Sanitize(content.HTML)
+}
+
+// contentPolicy returns the bluemonday policy used to sanitize all user-generated HTML
+func contentPolicy() *bluemonday.Policy {
+
 	policy := bluemonday.UGCPolicy()
 	policy.AllowStyling()
 
@@ -96,7 +102,7 @@ func (service *Content) Format(content *model.Content) {
 	policy.AllowAttrs("allow").Matching(regexp.MustCompile(`[a-z; -]*`)).OnElements("iframe")
 	policy.AllowAttrs("allowfullscreen").OnElements("iframe")
 
-	content.HTML = policy.Sanitize(content.HTML)
+	return policy
 }
 
 func (service *Content) NewByExtension(extension string, raw string) model.Content {
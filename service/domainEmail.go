@@ -193,6 +193,122 @@ func (service *DomainEmail) SendFollowerActivity(follower model.Follower, activi
 	return nil
 }
 
+// SendReviewRequest sends an email to a User who has been asked to review a Stream.
+func (service *DomainEmail) SendReviewRequest(assignee *model.User, stream *model.Stream, review *model.Review) error {
+
+	// Build the email message
+	message := mail.NewMSG().
+		SetSubject("Review Requested: " + stream.Label).
+		SetSender(service.owner.EmailAddress).
+		AddTo(assignee.EmailAddress)
+
+	// Send the review request email
+	err := service.serverEmail.Send(
+		service.smtp,
+		message,
+		"review-request",
+		mapof.Any{
+			// Assignee info available to the template
+			"DisplayName": assignee.DisplayName,
+
+			// Review info available to the template
+			"StreamURL":   stream.URL,
+			"StreamLabel": stream.Label,
+			"RequestedBy": review.RequestedBy,
+			"Comment":     review.Comment,
+			"ToStateID":   review.ToStateID,
+
+			// Domain info available to the template
+			"Owner": service.owner,
+			"Host":  service.host(),
+			"Label": service.label,
+		},
+	)
+
+	if err != nil {
+		return derp.Wrap(err, "service.DomainEmail.SendReviewRequest", "Error sending review request email to user", assignee.EmailAddress)
+	}
+
+	return nil
+}
+
+// SendReviewDecision sends an email to the User who requested a Review, once that Review is complete.
+func (service *DomainEmail) SendReviewDecision(requester *model.User, stream *model.Stream, review *model.Review) error {
+
+	// Build the email message
+	message := mail.NewMSG().
+		SetSubject("Review " + review.StatusID + ": " + stream.Label).
+		SetSender(service.owner.EmailAddress).
+		AddTo(requester.EmailAddress)
+
+	// Send the review decision email
+	err := service.serverEmail.Send(
+		service.smtp,
+		message,
+		"review-decision",
+		mapof.Any{
+			// Requester info available to the template
+			"DisplayName": requester.DisplayName,
+
+			// Review info available to the template
+			"StreamURL":   stream.URL,
+			"StreamLabel": stream.Label,
+			"StatusID":    review.StatusID,
+			"Approvals":   review.Approvals,
+			"ToStateID":   review.ToStateID,
+
+			// Domain info available to the template
+			"Owner": service.owner,
+			"Host":  service.host(),
+			"Label": service.label,
+		},
+	)
+
+	if err != nil {
+		return derp.Wrap(err, "service.DomainEmail.SendReviewDecision", "Error sending review decision email to user", requester.EmailAddress)
+	}
+
+	return nil
+}
+
+// SendSavedSearchAlert sends an email to a User when new messages match one of their saved searches.
+func (service *DomainEmail) SendSavedSearchAlert(user *model.User, folder *model.Folder, newCount int) error {
+
+	// Build the email message
+	message := mail.NewMSG().
+		SetSubject("New Results: " + folder.Label).
+		SetSender(service.owner.EmailAddress).
+		AddTo(user.EmailAddress)
+
+	// Send the saved search alert email
+	err := service.serverEmail.Send(
+		service.smtp,
+		message,
+		"saved-search-alert",
+		mapof.Any{
+			// User info available to the template
+			"DisplayName": user.DisplayName,
+
+			// Saved search info available to the template
+			"FolderURL":   service.host() + "/@me/inbox/inbox?folderId=" + folder.FolderID.Hex(),
+			"FolderLabel": folder.Label,
+			"Keyword":     folder.Query.Keyword,
+			"NewCount":    newCount,
+
+			// Domain info available to the template
+			"Owner": service.owner,
+			"Host":  service.host(),
+			"Label": service.label,
+		},
+	)
+
+	if err != nil {
+		return derp.Wrap(err, "service.DomainEmail.SendSavedSearchAlert", "Error sending saved search alert to user", user.EmailAddress)
+	}
+
+	return nil
+}
+
 /******************************************
  * Helper Methods
  ******************************************/
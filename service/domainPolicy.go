@@ -0,0 +1,482 @@
+package service
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DomainPolicy manages the server-wide federation policies for remote domains.
+// Policies are cached in memory because they are checked on every inbound
+// request, outbound delivery, and remote document that is displayed.
+type DomainPolicy struct {
+	collection data.Collection
+	cache      []model.DomainPolicy
+	loaded     bool // TRUE if the cache is up to date with the database
+	ready      bool // TRUE if the cache has EVER been loaded successfully
+	mutex      sync.RWMutex
+}
+
+// NewDomainPolicy returns a fully initialized DomainPolicy service
+func NewDomainPolicy() DomainPolicy {
+	return DomainPolicy{}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+// Refresh updates any stateful data that is cached inside this service.
+func (service *DomainPolicy) Refresh(collection data.Collection) {
+	service.collection = collection
+	service.reset()
+}
+
+// Close stops any background processes controlled by this service
+func (service *DomainPolicy) Close() {
+	// Nothin to do here.
+}
+
+/******************************************
+ * Common Data Methods
+ ******************************************/
+
+// Query returns a slice of DomainPolicies that match the provided criteria
+func (service *DomainPolicy) Query(criteria exp.Expression, options ...option.Option) ([]model.DomainPolicy, error) {
+	result := make([]model.DomainPolicy, 0)
+	err := service.collection.Query(&result, notDeleted(criteria), options...)
+	return result, err
+}
+
+// List returns an iterator containing all of the DomainPolicies that match the provided criteria
+func (service *DomainPolicy) List(criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.collection.Iterator(notDeleted(criteria), options...)
+}
+
+// Load retrieves a DomainPolicy from the database
+func (service *DomainPolicy) Load(criteria exp.Expression, policy *model.DomainPolicy) error {
+
+	if err := service.collection.Load(notDeleted(criteria), policy); err != nil {
+		return derp.Wrap(err, "service.DomainPolicy.Load", "Error loading DomainPolicy", criteria)
+	}
+
+	return nil
+}
+
+// Save adds/updates a DomainPolicy in the database
+func (service *DomainPolicy) Save(policy *model.DomainPolicy, note string) error {
+
+	const location = "service.DomainPolicy.Save"
+
+	// Normalize the hostname so that it can be matched against remote URLs
+	policy.Hostname = domainPolicyHostname(policy.Hostname)
+
+	// Validate the value before saving
+	if err := service.Schema().Validate(policy); err != nil {
+		return derp.Wrap(err, location, "Error validating DomainPolicy", policy)
+	}
+
+	// RULE: Only one policy per hostname
+	existing := model.NewDomainPolicy()
+	if err := service.LoadByHostname(policy.Hostname, &existing); err == nil {
+		if existing.DomainPolicyID != policy.DomainPolicyID {
+			return derp.NewBadRequestError(location, "A policy already exists for this domain", policy.Hostname)
+		}
+	} else if !derp.NotFound(err) {
+		return derp.Wrap(err, location, "Error checking for existing DomainPolicy", policy.Hostname)
+	}
+
+	// Save the value to the database
+	if err := service.collection.Save(policy, note); err != nil {
+		return derp.Wrap(err, location, "Error saving DomainPolicy", policy, note)
+	}
+
+	service.reset()
+	return nil
+}
+
+// Delete removes a DomainPolicy from the database (virtual delete)
+func (service *DomainPolicy) Delete(policy *model.DomainPolicy, note string) error {
+
+	if err := service.collection.Delete(policy, note); err != nil {
+		return derp.Wrap(err, "service.DomainPolicy.Delete", "Error deleting DomainPolicy", policy, note)
+	}
+
+	service.reset()
+	return nil
+}
+
+/******************************************
+ * Model Service Methods
+ ******************************************/
+
+// ObjectType returns the type of object that this service manages
+func (service *DomainPolicy) ObjectType() string {
+	return "DomainPolicy"
+}
+
+// New returns a fully initialized model.DomainPolicy as a data.Object.
+func (service *DomainPolicy) ObjectNew() data.Object {
+	result := model.NewDomainPolicy()
+	return &result
+}
+
+func (service *DomainPolicy) ObjectID(object data.Object) primitive.ObjectID {
+
+	if policy, ok := object.(*model.DomainPolicy); ok {
+		return policy.DomainPolicyID
+	}
+
+	return primitive.NilObjectID
+}
+
+func (service *DomainPolicy) ObjectQuery(result any, criteria exp.Expression, options ...option.Option) error {
+	return service.collection.Query(result, notDeleted(criteria), options...)
+}
+
+func (service *DomainPolicy) ObjectList(criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.List(criteria, options...)
+}
+
+func (service *DomainPolicy) ObjectLoad(criteria exp.Expression) (data.Object, error) {
+	result := model.NewDomainPolicy()
+	err := service.Load(criteria, &result)
+	return &result, err
+}
+
+func (service *DomainPolicy) ObjectSave(object data.Object, comment string) error {
+	if policy, ok := object.(*model.DomainPolicy); ok {
+		return service.Save(policy, comment)
+	}
+	return derp.NewInternalError("service.DomainPolicy.ObjectSave", "Invalid Object Type", object)
+}
+
+func (service *DomainPolicy) ObjectDelete(object data.Object, comment string) error {
+	if policy, ok := object.(*model.DomainPolicy); ok {
+		return service.Delete(policy, comment)
+	}
+	return derp.NewInternalError("service.DomainPolicy.ObjectDelete", "Invalid Object Type", object)
+}
+
+func (service *DomainPolicy) ObjectUserCan(object data.Object, authorization model.Authorization, action string) error {
+	return derp.NewUnauthorizedError("service.DomainPolicy", "Not Authorized")
+}
+
+func (service *DomainPolicy) Schema() schema.Schema {
+	return schema.New(model.DomainPolicySchema())
+}
+
+/******************************************
+ * Custom Queries
+ ******************************************/
+
+// LoadByID retrieves a single DomainPolicy by its ID
+func (service *DomainPolicy) LoadByID(policyID primitive.ObjectID, policy *model.DomainPolicy) error {
+	return service.Load(exp.Equal("_id", policyID), policy)
+}
+
+// LoadByHostname retrieves a single DomainPolicy by its hostname
+func (service *DomainPolicy) LoadByHostname(hostname string, policy *model.DomainPolicy) error {
+	return service.Load(exp.Equal("hostname", domainPolicyHostname(hostname)), policy)
+}
+
+// QueryAll returns all DomainPolicies, sorted by hostname
+func (service *DomainPolicy) QueryAll() ([]model.DomainPolicy, error) {
+	return service.Query(exp.All(), option.SortAsc("hostname"))
+}
+
+// QueryPublic returns all DomainPolicies that are published in the Mastodon API
+func (service *DomainPolicy) QueryPublic() ([]model.DomainPolicy, error) {
+	criteria := exp.Equal("isPublic", true).AndNotEqual("severity", model.DomainPolicySeverityNone)
+	return service.Query(criteria, option.SortAsc("hostname"))
+}
+
+/******************************************
+ * Policy Enforcement
+ ******************************************/
+
+// Match returns the DomainPolicy that applies to the provided URL or hostname.  The
+// most specific policy wins, so "media.remote.social" is matched before "remote.social".
+func (service *DomainPolicy) Match(uri string) (model.DomainPolicy, bool) {
+
+	hostname := domainPolicyHostname(uri)
+
+	if hostname == "" {
+		return model.DomainPolicy{}, false
+	}
+
+	policies, ok := service.cached()
+
+	// RULE: Fail closed.  If policies have never been loaded, then treat every domain as suspended
+	if !ok {
+		return model.DomainPolicy{Hostname: hostname, Severity: model.DomainPolicySeveritySuspend}, true
+	}
+
+	var result model.DomainPolicy
+	var found bool
+
+	for _, policy := range policies {
+		if policy.MatchHostname(hostname) {
+			if !found || (len(policy.Hostname) > len(result.Hostname)) {
+				result = policy
+				found = true
+			}
+		}
+	}
+
+	return result, found
+}
+
+// IsSuspended returns TRUE if all federation with the provided URL is blocked
+func (service *DomainPolicy) IsSuspended(uri string) bool {
+	policy, found := service.Match(uri)
+	return found && policy.IsSuspended()
+}
+
+// IsSilenced returns TRUE if the provided URL is hidden from public views
+func (service *DomainPolicy) IsSilenced(uri string) bool {
+	policy, found := service.Match(uri)
+	return found && policy.IsSilenced()
+}
+
+// IsMediaRejected returns TRUE if media attachments from the provided URL should not be stored or displayed
+func (service *DomainPolicy) IsMediaRejected(uri string) bool {
+	policy, found := service.Match(uri)
+	return found && (policy.IsSuspended() || policy.RejectMedia)
+}
+
+// AllowRequest returns an error if an inbound HTTP request was signed by an actor on
+// a suspended domain.  This is checked BEFORE the request signature is verified, so
+// that we never make network requests to suspended domains.
+func (service *DomainPolicy) AllowRequest(request *http.Request) error {
+
+	keyID := domainPolicyKeyID(request.Header.Get("Signature"))
+
+	if keyID == "" {
+		keyID = domainPolicyKeyID(request.Header.Get("Authorization"))
+	}
+
+	if service.IsSuspended(keyID) {
+		return derp.NewForbiddenError("service.DomainPolicy.AllowRequest", "Domain is suspended", keyID)
+	}
+
+	return nil
+}
+
+// AllowActivity returns an error if an inbound activity was sent by an actor
+// (or contains an object) from a suspended domain
+func (service *DomainPolicy) AllowActivity(activity streams.Document) error {
+
+	const location = "service.DomainPolicy.AllowActivity"
+
+	if actorID := activity.Actor().ID(); service.IsSuspended(actorID) {
+		return derp.NewForbiddenError(location, "Domain is suspended", actorID)
+	}
+
+	if objectID := activity.Object().ID(); service.IsSuspended(objectID) {
+		return derp.NewForbiddenError(location, "Domain is suspended", objectID)
+	}
+
+	return nil
+}
+
+// Allow returns TRUE if the provided document can be displayed.  If publicOnly is TRUE, then
+// documents from silenced domains are also rejected.  The document is passed as a pointer
+// because it MAY BE MODIFIED to remove media or add a content warning.
+func (service *DomainPolicy) Allow(document *streams.Document, publicOnly bool) bool {
+
+	policy, found := service.Match(domainPolicyDocumentURL(*document))
+
+	if !found {
+		return true
+	}
+
+	if policy.IsSuspended() {
+		return false
+	}
+
+	if publicOnly && policy.IsSilenced() {
+		return false
+	}
+
+	domainPolicyApply(document, policy)
+	return true
+}
+
+// Apply removes media and adds content warnings to a document, based on the policy of
+// its originating domain.  It does not remove documents from suspended domains.
+func (service *DomainPolicy) Apply(document *streams.Document) {
+
+	if policy, found := service.Match(domainPolicyDocumentURL(*document)); found {
+		domainPolicyApply(document, policy)
+	}
+}
+
+// Channel filters a channel of documents, removing those from suspended domains
+// (and silenced domains, if publicOnly is TRUE), and applying media and content
+// warning policies to the rest.
+func (service *DomainPolicy) Channel(ch <-chan streams.Document, publicOnly bool) <-chan streams.Document {
+
+	result := make(chan streams.Document)
+
+	go func() {
+		defer close(result)
+
+		for document := range ch {
+			if service.Allow(&document, publicOnly) {
+				result <- document
+			}
+		}
+	}()
+
+	return result
+}
+
+// ChannelAllow filters a channel of Followers, removing those on suspended domains
+func (service *DomainPolicy) ChannelAllow(ch <-chan model.Follower) <-chan model.Follower {
+
+	result := make(chan model.Follower)
+
+	go func() {
+		defer close(result)
+
+		for follower := range ch {
+			if !service.IsSuspended(follower.Actor.ProfileURL) {
+				result <- follower
+			}
+		}
+	}()
+
+	return result
+}
+
+// Client wraps a streams.Client so that it never loads documents from suspended domains
+func (service *DomainPolicy) Client(innerClient streams.Client) streams.Client {
+	return NewDomainPolicyClient(service, innerClient)
+}
+
+/******************************************
+ * Helper Methods
+ ******************************************/
+
+// cached returns all DomainPolicies, loading them from the database if necessary.
+// If the database cannot be read, then the previous cache is used instead.  The
+// second return value is FALSE if no policies have ever been loaded successfully.
+func (service *DomainPolicy) cached() ([]model.DomainPolicy, bool) {
+
+	service.mutex.RLock()
+
+	if service.loaded {
+		defer service.mutex.RUnlock()
+		return service.cache, true
+	}
+
+	service.mutex.RUnlock()
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	if service.collection == nil {
+		return nil, true
+	}
+
+	policies, err := service.Query(exp.All())
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, "service.DomainPolicy.cached", "Error loading DomainPolicies"))
+		return service.cache, service.ready
+	}
+
+	service.cache = policies
+	service.loaded = true
+	service.ready = true
+	return service.cache, true
+}
+
+// reset marks the in-memory cache as stale so that policies are reloaded on next use.
+// The stale cache is kept as a fallback in case the database cannot be read.
+func (service *DomainPolicy) reset() {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	service.loaded = false
+}
+
+// domainPolicyApply removes media and adds content warnings to a document
+func domainPolicyApply(document *streams.Document, policy model.DomainPolicy) {
+
+	if policy.RejectMedia {
+		document.SetProperty(vocab.PropertyAttachment, nil)
+		document.SetProperty(vocab.PropertyImage, nil)
+		document.SetProperty(vocab.PropertyIcon, nil)
+	}
+
+	if policy.IsContentWarning() {
+		document.SetProperty("sensitive", true)
+
+		if document.Summary() == "" {
+			document.SetProperty(vocab.PropertySummary, policy.ContentWarning)
+		}
+	}
+}
+
+// domainPolicyDocumentURL returns the URL used to match a document against DomainPolicies.
+// This is the document's author, if known, or the document's own ID.
+func domainPolicyDocumentURL(document streams.Document) string {
+
+	if actorID := document.Actor().ID(); actorID != "" {
+		return actorID
+	}
+
+	if attributedTo := document.AttributedTo().ID(); attributedTo != "" {
+		return attributedTo
+	}
+
+	return document.ID()
+}
+
+// domainPolicyHostname returns the lowercase hostname for a URL or bare hostname
+func domainPolicyHostname(value string) string {
+
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	if value == "" {
+		return ""
+	}
+
+	if !strings.Contains(value, "://") {
+		value = "https://" + value
+	}
+
+	parsed, err := url.Parse(value)
+
+	if err != nil {
+		return ""
+	}
+
+	return parsed.Hostname()
+}
+
+// domainPolicyKeyID extracts the keyId parameter from an HTTP Signature header
+func domainPolicyKeyID(header string) string {
+
+	_, keyID, found := strings.Cut(header, `keyId="`)
+
+	if !found {
+		return ""
+	}
+
+	keyID, _, _ = strings.Cut(keyID, `"`)
+	return keyID
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+)
+
+// DomainPolicyClient is a streams.Client that refuses to load documents from
+// suspended domains.  It is used for outbound deliveries, so that activities
+// are never sent to a suspended server.
+type DomainPolicyClient struct {
+	domainPolicyService *DomainPolicy
+	innerClient         streams.Client
+}
+
+// NewDomainPolicyClient returns a fully initialized DomainPolicyClient that wraps the provided client
+func NewDomainPolicyClient(domainPolicyService *DomainPolicy, innerClient streams.Client) *DomainPolicyClient {
+	return &DomainPolicyClient{
+		domainPolicyService: domainPolicyService,
+		innerClient:         innerClient,
+	}
+}
+
+// Load implements the streams.Client interface
+func (client *DomainPolicyClient) Load(uri string, options ...any) (streams.Document, error) {
+
+	if client.domainPolicyService.IsSuspended(uri) {
+		return streams.NilDocument(), derp.NewForbiddenError("service.DomainPolicyClient.Load", "Domain is suspended", uri)
+	}
+
+	return client.innerClient.Load(uri, options...)
+}
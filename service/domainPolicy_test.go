@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	mockdb "github.com/benpate/data-mock"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDomainPolicyMatch(t *testing.T) {
+
+	service := testDomainPolicyService(
+		model.DomainPolicy{Hostname: "remote.social", Severity: model.DomainPolicySeveritySuspend},
+		model.DomainPolicy{Hostname: "media.remote.social", Severity: model.DomainPolicySeveritySilence},
+	)
+
+	// The most specific policy wins
+	policy, found := service.Match("https://media.remote.social/users/bob")
+	require.True(t, found)
+	require.Equal(t, "media.remote.social", policy.Hostname)
+
+	// Subdomains inherit their parent's policy
+	policy, found = service.Match("https://www.remote.social/users/bob")
+	require.True(t, found)
+	require.Equal(t, "remote.social", policy.Hostname)
+
+	// Other domains are not matched, even if they share a suffix
+	_, found = service.Match("https://notremote.social/users/bob")
+	require.False(t, found)
+
+	_, found = service.Match("")
+	require.False(t, found)
+
+	require.True(t, service.IsSuspended("https://remote.social/users/bob"))
+	require.True(t, service.IsSilenced("https://media.remote.social/users/bob"))
+	require.False(t, service.IsSuspended("https://media.remote.social/users/bob"))
+}
+
+func TestDomainPolicyAllow(t *testing.T) {
+
+	service := testDomainPolicyService(
+		model.DomainPolicy{Hostname: "suspended.social", Severity: model.DomainPolicySeveritySuspend},
+		model.DomainPolicy{Hostname: "silenced.social", Severity: model.DomainPolicySeveritySilence},
+		model.DomainPolicy{Hostname: "nomedia.social", Severity: model.DomainPolicySeverityNone, RejectMedia: true, ContentWarning: "Spicy"},
+	)
+
+	// Suspended domains are never allowed
+	document := testDomainPolicyDocument("https://suspended.social/users/bob")
+	require.False(t, service.Allow(&document, false))
+
+	// Silenced domains are only allowed in private views
+	document = testDomainPolicyDocument("https://silenced.social/users/bob")
+	require.False(t, service.Allow(&document, true))
+	require.True(t, service.Allow(&document, false))
+
+	// Media is removed, and content warnings are added
+	document = testDomainPolicyDocument("https://nomedia.social/users/bob")
+	require.True(t, service.Allow(&document, true))
+	require.True(t, document.Attachment().IsNil())
+	require.True(t, document.Image().IsNil())
+	require.Equal(t, "Spicy", document.Summary())
+	require.True(t, service.IsMediaRejected("https://nomedia.social/users/bob"))
+
+	// Unknown domains are left unchanged
+	document = testDomainPolicyDocument("https://remote.social/users/bob")
+	require.True(t, service.Allow(&document, true))
+	require.True(t, document.Attachment().NotNil())
+	require.Equal(t, "", document.Summary())
+	require.False(t, service.IsMediaRejected("https://remote.social/users/bob"))
+}
+
+func TestDomainPolicyCache_Stale(t *testing.T) {
+
+	service := testDomainPolicyService(
+		model.DomainPolicy{Hostname: "remote.social", Severity: model.DomainPolicySeveritySuspend},
+	)
+
+	// If policies cannot be reloaded, then the previous policies are still enforced
+	service.collection = testDomainPolicyCollection(t)
+	service.reset()
+
+	require.True(t, service.IsSuspended("https://remote.social/users/bob"))
+	require.False(t, service.IsSuspended("https://other.social/users/bob"))
+}
+
+func TestDomainPolicyCache_FailClosed(t *testing.T) {
+
+	// If policies have never been loaded, then every domain is suspended
+	service := NewDomainPolicy()
+	service.Refresh(testDomainPolicyCollection(t))
+
+	require.True(t, service.IsSuspended("https://other.social/users/bob"))
+
+	document := testDomainPolicyDocument("https://other.social/users/bob")
+	require.False(t, service.Allow(&document, false))
+}
+
+func TestDomainPolicyHostname(t *testing.T) {
+	require.Equal(t, "remote.social", domainPolicyHostname("remote.social"))
+	require.Equal(t, "remote.social", domainPolicyHostname(" REMOTE.social "))
+	require.Equal(t, "remote.social", domainPolicyHostname("https://remote.social/users/bob#main-key"))
+	require.Equal(t, "remote.social", domainPolicyHostname("http://remote.social:8080"))
+	require.Equal(t, "", domainPolicyHostname(""))
+}
+
+func TestDomainPolicyKeyID(t *testing.T) {
+	header := `keyId="https://remote.social/users/bob#main-key",algorithm="rsa-sha256",headers="(request-target) host date",signature="abc"`
+	require.Equal(t, "https://remote.social/users/bob#main-key", domainPolicyKeyID(header))
+	require.Equal(t, "https://remote.social/users/bob#main-key", domainPolicyKeyID("Signature "+header))
+	require.Equal(t, "", domainPolicyKeyID(""))
+	require.Equal(t, "", domainPolicyKeyID(`algorithm="rsa-sha256"`))
+}
+
+// testDomainPolicyService returns a DomainPolicy service whose cache is pre-loaded with the provided policies
+func testDomainPolicyService(policies ...model.DomainPolicy) *DomainPolicy {
+	service := NewDomainPolicy()
+	service.cache = policies
+	service.loaded = true
+	service.ready = true
+	return &service
+}
+
+// testDomainPolicyCollection returns a mock collection that cannot be queried
+func testDomainPolicyCollection(t *testing.T) data.Collection {
+	session, err := mockdb.New().Session(context.TODO())
+	require.Nil(t, err)
+	return session.Collection("DomainPolicy")
+}
+
+// testDomainPolicyDocument returns a Note (with media) that is attributed to the provided actor
+func testDomainPolicyDocument(actorID string) streams.Document {
+	return streams.NewDocument(mapof.Any{
+		vocab.PropertyID:           actorID + "/notes/1",
+		vocab.PropertyType:         vocab.ObjectTypeNote,
+		vocab.PropertyAttributedTo: actorID,
+		vocab.PropertyContent:      "Hello World",
+		vocab.PropertyImage:        "https://cdn.social/image.jpg",
+		vocab.PropertyAttachment: []any{mapof.Any{
+			vocab.PropertyType: vocab.ObjectTypeImage,
+			vocab.PropertyURL:  "https://cdn.social/image.jpg",
+		}},
+	})
+}
@@ -203,8 +203,13 @@ func (service *EncryptionKey) Verify(message []byte, signature []byte, encryptio
 // OwnerID returns the publicly accessible URL of the Actor who owns this EncryptionKey
 func (service *EncryptionKey) OwnerID(encryptionKey *model.EncryptionKey) string {
 
-	if encryptionKey.ParentType == model.EncryptionKeyTypeUser {
+	switch encryptionKey.ParentType {
+
+	case model.EncryptionKeyTypeUser:
 		return service.host + "/@" + encryptionKey.ParentID.Hex()
+
+	case model.EncryptionKeyTypeInstance:
+		return service.host + "/pub/actor"
 	}
 
 	return service.host + "/" + encryptionKey.ParentID.Hex()
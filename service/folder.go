@@ -1,12 +1,16 @@
 package service
 
 import (
+	"sync"
+	"time"
+
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/queries"
 	"github.com/benpate/data"
 	"github.com/benpate/data/option"
 	"github.com/benpate/derp"
 	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/queue"
 	"github.com/benpate/rosetta/first"
 	"github.com/benpate/rosetta/schema"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -18,11 +22,19 @@ type Folder struct {
 	themeService  *Theme
 	domainService *Domain
 	inboxService  *Inbox
+	userService   *User
+	emailService  *DomainEmail
+	queue         queue.Queue
+	mutex         *sync.Mutex
+	pending       map[primitive.ObjectID]bool // Users whose smart folders are waiting to be recalculated
 }
 
 // NewFolder returns a fully populated Folder service
 func NewFolder() Folder {
-	service := Folder{}
+	service := Folder{
+		mutex:   &sync.Mutex{},
+		pending: make(map[primitive.ObjectID]bool),
+	}
 	return service
 }
 
@@ -31,11 +43,14 @@ func NewFolder() Folder {
  ******************************************/
 
 // Refresh updates any stateful data that is cached inside this service.
-func (service *Folder) Refresh(collection data.Collection, themeService *Theme, domainService *Domain, inboxService *Inbox) {
+func (service *Folder) Refresh(collection data.Collection, themeService *Theme, domainService *Domain, inboxService *Inbox, userService *User, emailService *DomainEmail, queue queue.Queue) {
 	service.collection = collection
 	service.themeService = themeService
 	service.domainService = domainService
 	service.inboxService = inboxService
+	service.userService = userService
+	service.emailService = emailService
+	service.queue = queue
 }
 
 // Close stops any background processes controlled by this service
@@ -82,6 +97,11 @@ func (service *Folder) Save(folder *model.Folder, note string) error {
 		return derp.Wrap(err, "service.Folder.Save", "Error validating Folder", folder)
 	}
 
+	// Saved searches only alert on messages that arrive after they are created
+	if folder.IsSearch() && (folder.AlertDate == 0) {
+		folder.AlertDate = time.Now().Unix()
+	}
+
 	// Save the value to the database
 	if err := service.collection.Save(folder, note); err != nil {
 		return derp.Wrap(err, "service.Folder", "Error saving Folder", folder, note)
@@ -93,8 +113,11 @@ func (service *Folder) Save(folder *model.Folder, note string) error {
 // Delete removes an Folder from the database (virtual delete)
 func (service *Folder) Delete(folder *model.Folder, note string) error {
 
-	if err := service.inboxService.DeleteByFolder(folder.UserID, folder.FolderID); err != nil {
-		return derp.Wrap(err, "service.Folder", "Error deleting Folder activities", folder, note)
+	// Smart folders do not own any messages, so only standard folders remove their contents
+	if !folder.IsSmart() {
+		if err := service.inboxService.DeleteByFolder(folder.UserID, folder.FolderID); err != nil {
+			return derp.Wrap(err, "service.Folder", "Error deleting Folder activities", folder, note)
+		}
 	}
 
 	// Delete Folder record last.
@@ -179,6 +202,15 @@ func (service *Folder) QueryByUserID(userID primitive.ObjectID) ([]model.Folder,
 	return service.Query(exp.Equal("userId", userID), option.SortAsc("rank"))
 }
 
+// QuerySmartFolders returns all smart folders and saved searches for a given user
+func (service *Folder) QuerySmartFolders(userID primitive.ObjectID) ([]model.Folder, error) {
+
+	criteria := exp.Equal("userId", userID).
+		AndIn("type", []string{model.FolderTypeSmart, model.FolderTypeSearch})
+
+	return service.Query(criteria, option.SortAsc("rank"))
+}
+
 // LoadByID loads a single stream that matches the provided ID
 func (service *Folder) LoadByID(userID primitive.ObjectID, folderID primitive.ObjectID, result *model.Folder) error {
 
@@ -255,7 +287,127 @@ func (service *Folder) CalculateUnreadCount(userID primitive.ObjectID, folderID
 		return derp.Wrap(err, "service.Folder.CalculateUnreadCount", "Error counting unread messages", userID, folderID)
 	}
 
-	return service.SetUnreadCount(userID, folderID, unreadCount)
+	if err := service.SetUnreadCount(userID, folderID, unreadCount); err != nil {
+		return derp.Wrap(err, "service.Folder.CalculateUnreadCount", "Error setting unread count", userID, folderID)
+	}
+
+	// Smart folders may also include this message, so recalculate them too.
+	service.QueueSmartFolders(userID)
+
+	return nil
+}
+
+// QueueSmartFolders schedules a background recalculation of a User's smart folders.
+// Requests are debounced, so that a burst of new messages triggers only one recalculation
+// per smartFolderDelay.
+func (service *Folder) QueueSmartFolders(userID primitive.ObjectID) {
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	// RULE: Do not schedule a second recalculation if one is already waiting
+	if service.pending[userID] {
+		return
+	}
+
+	service.pending[userID] = true
+
+	time.AfterFunc(smartFolderDelay, func() {
+		service.queue.Push(NewTaskCalculateSmartFolders(service, userID))
+	})
+}
+
+// clearPendingSmartFolders allows new recalculations to be scheduled for a User
+func (service *Folder) clearPendingSmartFolders(userID primitive.ObjectID) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+	delete(service.pending, userID)
+}
+
+// CalculateSmartFolders updates the "unreadCount" of every smart folder owned by a User,
+// and sends alerts for saved searches that have new matching messages.
+func (service *Folder) CalculateSmartFolders(userID primitive.ObjectID) error {
+
+	const location = "service.Folder.CalculateSmartFolders"
+
+	folders, err := service.QuerySmartFolders(userID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading smart folders", userID)
+	}
+
+	for index := range folders {
+
+		folder := &folders[index]
+
+		// Count the unread messages that match this folder's query
+		unreadCount, err := service.inboxService.CountUnreadByFolderQuery(userID, folder.Query)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Error counting unread messages", folder.FolderID)
+		}
+
+		if err := service.SetUnreadCount(userID, folder.FolderID, unreadCount); err != nil {
+			return derp.Wrap(err, location, "Error setting unread count", folder.FolderID)
+		}
+
+		// Saved searches also alert the User about new messages
+		if folder.IsSearch() {
+			if err := service.sendSearchAlert(folder); err != nil {
+				return derp.Wrap(err, location, "Error sending saved search alert", folder.FolderID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// sendSearchAlert emails the owner of a saved search when new messages match its query.
+// Alerts are throttled so that a User receives at most one alert per FolderSearchAlertInterval.
+func (service *Folder) sendSearchAlert(folder *model.Folder) error {
+
+	const location = "service.Folder.sendSearchAlert"
+
+	now := time.Now().Unix()
+
+	// RULE: Do not send alerts more often than the alert interval
+	if now-folder.AlertDate < model.FolderSearchAlertInterval {
+		return nil
+	}
+
+	// Count messages that have arrived since the last alert
+	newCount, err := service.inboxService.CountNewByFolderQuery(folder.UserID, folder.Query, folder.AlertDate)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error counting new messages", folder.FolderID)
+	}
+
+	if newCount == 0 {
+		return nil
+	}
+
+	// Load the User who owns the saved search
+	user := model.NewUser()
+
+	if err := service.userService.LoadByID(folder.UserID, &user); err != nil {
+		return derp.Wrap(err, location, "Error loading User", folder.UserID)
+	}
+
+	// Send the alert (if the User has an email address)
+	if user.EmailAddress != "" {
+		if err := service.emailService.SendSavedSearchAlert(&user, folder, newCount); err != nil {
+			return derp.Wrap(err, location, "Error sending alert", folder.FolderID)
+		}
+	}
+
+	// Mark the alert as sent
+	folder.AlertDate = now
+
+	if err := service.Save(folder, "Saved search alert sent"); err != nil {
+		return derp.Wrap(err, location, "Error saving Folder", folder.FolderID)
+	}
+
+	return nil
 }
 
 // SetUnreadCount uses an optimized query to update the the "readDate" and "unreadCount" of a particular folder
@@ -33,6 +33,12 @@ func (service FolderLookupProvider) Get() []form.LookupCode {
 	result := make([]form.LookupCode, 0, len(folders))
 
 	for _, folder := range folders {
+
+		// Smart folders are calculated from queries, so they cannot receive messages directly
+		if folder.IsSmart() {
+			continue
+		}
+
 		result = append(result, folder.LookupCode())
 	}
 
@@ -0,0 +1,572 @@
+package service
+
+import (
+	"regexp"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/queue"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// followedTagBackfill is the number of cached posts delivered when a User first follows a hashtag
+const followedTagBackfill = 20
+
+// followedTagReceivedLimit is the number of recently received messages that are searched for hashtag timelines
+const followedTagReceivedLimit = 500
+
+// FollowedTag manages the hashtags that Users follow.  Public posts that include
+// a followed hashtag are delivered into a dedicated "Hashtags" Folder in each
+// follower's inbox.
+type FollowedTag struct {
+	collection          data.Collection
+	folderService       *Folder
+	inboxService        *Inbox
+	activityService     *ActivityStream
+	domainPolicyService *DomainPolicy
+	relayMessageService *RelayMessage
+	ruleService         *Rule
+	queue               queue.Queue
+	host                string
+}
+
+// NewFollowedTag returns a fully initialized FollowedTag service
+func NewFollowedTag() FollowedTag {
+	return FollowedTag{}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+// Refresh updates any stateful data that is cached inside this service.
+func (service *FollowedTag) Refresh(collection data.Collection, folderService *Folder, inboxService *Inbox, activityService *ActivityStream, domainPolicyService *DomainPolicy, relayMessageService *RelayMessage, ruleService *Rule, queue queue.Queue, host string) {
+	service.collection = collection
+	service.folderService = folderService
+	service.inboxService = inboxService
+	service.activityService = activityService
+	service.domainPolicyService = domainPolicyService
+	service.relayMessageService = relayMessageService
+	service.ruleService = ruleService
+	service.queue = queue
+	service.host = host
+}
+
+// Close stops any background processes controlled by this service
+func (service *FollowedTag) Close() {
+	// Nothin to do here.
+}
+
+/******************************************
+ * Common Data Methods
+ ******************************************/
+
+// Query returns a slice of FollowedTags that match the provided criteria
+func (service *FollowedTag) Query(criteria exp.Expression, options ...option.Option) ([]model.FollowedTag, error) {
+	result := make([]model.FollowedTag, 0)
+	err := service.collection.Query(&result, notDeleted(criteria), options...)
+	return result, err
+}
+
+// List returns an iterator containing all of the FollowedTags that match the provided criteria
+func (service *FollowedTag) List(criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.collection.Iterator(notDeleted(criteria), options...)
+}
+
+// Load retrieves a FollowedTag from the database
+func (service *FollowedTag) Load(criteria exp.Expression, followedTag *model.FollowedTag) error {
+
+	if err := service.collection.Load(notDeleted(criteria), followedTag); err != nil {
+		return derp.Wrap(err, "service.FollowedTag.Load", "Error loading FollowedTag", criteria)
+	}
+
+	return nil
+}
+
+// Save adds/updates a FollowedTag in the database
+func (service *FollowedTag) Save(followedTag *model.FollowedTag, note string) error {
+
+	const location = "service.FollowedTag.Save"
+
+	// Normalize the name so that it can be matched against incoming hashtags
+	followedTag.Name = model.NormalizeHashtag(followedTag.Name)
+
+	// Validate the value before saving
+	if err := service.Schema().Validate(followedTag); err != nil {
+		return derp.Wrap(err, location, "Error validating FollowedTag", followedTag)
+	}
+
+	// Save the value to the database
+	if err := service.collection.Save(followedTag, note); err != nil {
+		return derp.Wrap(err, location, "Error saving FollowedTag", followedTag, note)
+	}
+
+	return nil
+}
+
+// Delete removes a FollowedTag from the database (virtual delete)
+func (service *FollowedTag) Delete(followedTag *model.FollowedTag, note string) error {
+
+	if err := service.collection.Delete(followedTag, note); err != nil {
+		return derp.Wrap(err, "service.FollowedTag.Delete", "Error deleting FollowedTag", followedTag, note)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Model Service Methods
+ ******************************************/
+
+// ObjectType returns the type of object that this service manages
+func (service *FollowedTag) ObjectType() string {
+	return "FollowedTag"
+}
+
+// New returns a fully initialized model.FollowedTag as a data.Object.
+func (service *FollowedTag) ObjectNew() data.Object {
+	result := model.NewFollowedTag()
+	return &result
+}
+
+func (service *FollowedTag) ObjectID(object data.Object) primitive.ObjectID {
+
+	if followedTag, ok := object.(*model.FollowedTag); ok {
+		return followedTag.FollowedTagID
+	}
+
+	return primitive.NilObjectID
+}
+
+func (service *FollowedTag) ObjectQuery(result any, criteria exp.Expression, options ...option.Option) error {
+	return service.collection.Query(result, notDeleted(criteria), options...)
+}
+
+func (service *FollowedTag) ObjectList(criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.List(criteria, options...)
+}
+
+func (service *FollowedTag) ObjectLoad(criteria exp.Expression) (data.Object, error) {
+	result := model.NewFollowedTag()
+	err := service.Load(criteria, &result)
+	return &result, err
+}
+
+func (service *FollowedTag) ObjectSave(object data.Object, comment string) error {
+	if followedTag, ok := object.(*model.FollowedTag); ok {
+		return service.Save(followedTag, comment)
+	}
+	return derp.NewInternalError("service.FollowedTag.ObjectSave", "Invalid Object Type", object)
+}
+
+func (service *FollowedTag) ObjectDelete(object data.Object, comment string) error {
+	if followedTag, ok := object.(*model.FollowedTag); ok {
+		return service.Delete(followedTag, comment)
+	}
+	return derp.NewInternalError("service.FollowedTag.ObjectDelete", "Invalid Object Type", object)
+}
+
+func (service *FollowedTag) ObjectUserCan(object data.Object, authorization model.Authorization, action string) error {
+	return derp.NewUnauthorizedError("service.FollowedTag", "Not Authorized")
+}
+
+func (service *FollowedTag) Schema() schema.Schema {
+	return schema.New(model.FollowedTagSchema())
+}
+
+/******************************************
+ * Custom Queries
+ ******************************************/
+
+// QueryByUser returns the hashtags followed by a User
+func (service *FollowedTag) QueryByUser(userID primitive.ObjectID, criteria exp.Expression, options ...option.Option) ([]model.FollowedTag, error) {
+	return service.Query(criteria.AndEqual("userId", userID), options...)
+}
+
+// QueryByNames returns the FollowedTags (for all Users) that match any of the provided hashtag names
+func (service *FollowedTag) QueryByNames(names []string) ([]model.FollowedTag, error) {
+	return service.Query(exp.In("name", names))
+}
+
+// LoadByName retrieves a single hashtag followed by a User
+func (service *FollowedTag) LoadByName(userID primitive.ObjectID, name string, followedTag *model.FollowedTag) error {
+
+	criteria := exp.
+		Equal("userId", userID).
+		AndEqual("name", model.NormalizeHashtag(name))
+
+	return service.Load(criteria, followedTag)
+}
+
+/******************************************
+ * Hashtag Timelines
+ ******************************************/
+
+// QueryDocuments returns the public documents that include any of the provided (normalized) hashtag
+// names.  Results are limited to documents that this Domain has actually received (in a User's inbox
+// or from a Relay), and are filtered by the Domain's policies and the administrator's block rules.
+func (service *FollowedTag) QueryDocuments(names []string, criteria exp.Expression, options ...option.Option) ([]streams.Document, error) {
+
+	const location = "service.FollowedTag.QueryDocuments"
+
+	result := make([]streams.Document, 0)
+
+	urls, err := service.queryReceivedURLs(names)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error loading received messages", names)
+	}
+
+	if len(urls) == 0 {
+		return result, nil
+	}
+
+	documents, err := service.activityService.QueryByHashtags(names, criteria.AndIn("object.id", urls), options...)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error loading documents", names)
+	}
+
+	for _, document := range documents {
+
+		// RULE: Suspended and silenced domains are not shown in hashtag timelines
+		if !service.domainPolicyService.Allow(&document, true) {
+			continue
+		}
+
+		// RULE: Authors blocked by the administrator are not shown in hashtag timelines
+		authorID := document.AttributedTo().ID()
+
+		if rules, err := service.ruleService.QueryDomainBlocksByActor(authorID); err != nil {
+			return nil, derp.Wrap(err, location, "Error loading domain rules", authorID)
+		} else if len(rules) > 0 {
+			continue
+		}
+
+		result = append(result, document)
+	}
+
+	return result, nil
+}
+
+// queryReceivedURLs returns the URLs of the most recent Messages and RelayMessages
+// received by this Domain that include any of the provided hashtag names.
+func (service *FollowedTag) queryReceivedURLs(names []string) ([]string, error) {
+
+	const location = "service.FollowedTag.queryReceivedURLs"
+
+	result := make([]string, 0)
+
+	if len(names) == 0 {
+		return result, nil
+	}
+
+	criteria := exp.In("hashtags", names)
+	options := []option.Option{option.Fields("url"), option.SortDesc("publishDate"), option.MaxRows(followedTagReceivedLimit)}
+
+	messages, err := service.inboxService.Query(criteria, options...)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error loading messages", names)
+	}
+
+	relayMessages, err := service.relayMessageService.Query(criteria, options...)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error loading relay messages", names)
+	}
+
+	found := make(map[string]bool)
+
+	for _, message := range messages {
+		if !found[message.URL] {
+			found[message.URL] = true
+			result = append(result, message.URL)
+		}
+	}
+
+	for _, message := range relayMessages {
+		if !found[message.URL] {
+			found[message.URL] = true
+			result = append(result, message.URL)
+		}
+	}
+
+	return result, nil
+}
+
+/******************************************
+ * Following Hashtags
+ ******************************************/
+
+// Follow adds a hashtag to the list that a User follows.  Matching public posts that this
+// Domain has already received are delivered to the User's hashtag Folder immediately.
+func (service *FollowedTag) Follow(userID primitive.ObjectID, name string) (model.FollowedTag, error) {
+
+	const location = "service.FollowedTag.Follow"
+
+	name = model.NormalizeHashtag(name)
+
+	if name == "" {
+		return model.FollowedTag{}, derp.NewBadRequestError(location, "Hashtag is required")
+	}
+
+	// If the User already follows this hashtag, then there's nothing more to do
+	followedTag := model.NewFollowedTag()
+
+	if err := service.LoadByName(userID, name, &followedTag); err == nil {
+		return followedTag, nil
+	} else if !derp.NotFound(err) {
+		return model.FollowedTag{}, derp.Wrap(err, location, "Error loading FollowedTag", name)
+	}
+
+	// Find (or create) the Folder that receives hashtag posts
+	folderID, err := service.hashtagFolderID(userID)
+
+	if err != nil {
+		return model.FollowedTag{}, derp.Wrap(err, location, "Error loading hashtag folder", userID)
+	}
+
+	followedTag.UserID = userID
+	followedTag.Name = name
+	followedTag.FolderID = folderID
+
+	if err := service.Save(&followedTag, "Followed"); err != nil {
+		return model.FollowedTag{}, derp.Wrap(err, location, "Error saving FollowedTag", name)
+	}
+
+	// Backfill the Folder with recent posts that this Domain has received
+	documents, err := service.QueryDocuments([]string{name}, exp.All(), option.SortDesc("published"), option.MaxRows(followedTagBackfill))
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, location, "Error loading cached documents", name))
+		return followedTag, nil
+	}
+
+	for _, document := range documents {
+		if err := service.deliver(&followedTag, document); err != nil {
+			derp.Report(derp.Wrap(err, location, "Error delivering cached document", document.ID()))
+		}
+	}
+
+	return followedTag, nil
+}
+
+// Unfollow removes a hashtag from the list that a User follows.  Posts that
+// were already delivered remain in the User's hashtag Folder.
+func (service *FollowedTag) Unfollow(userID primitive.ObjectID, name string) error {
+
+	const location = "service.FollowedTag.Unfollow"
+
+	followedTag := model.NewFollowedTag()
+
+	if err := service.LoadByName(userID, name, &followedTag); err != nil {
+
+		if derp.NotFound(err) {
+			return nil
+		}
+
+		return derp.Wrap(err, location, "Error loading FollowedTag", name)
+	}
+
+	if err := service.Delete(&followedTag, "Unfollowed"); err != nil {
+		return derp.Wrap(err, location, "Error deleting FollowedTag", name)
+	}
+
+	return nil
+}
+
+// hashtagFolderID returns the ID of the Folder that receives a User's hashtag posts,
+// creating the Folder if it does not already exist.
+func (service *FollowedTag) hashtagFolderID(userID primitive.ObjectID) (primitive.ObjectID, error) {
+
+	const location = "service.FollowedTag.hashtagFolderID"
+
+	folder := model.NewFolder()
+
+	if err := service.folderService.LoadByLabel(userID, model.FollowedTagFolderLabel, &folder); err == nil {
+		return folder.FolderID, nil
+	} else if !derp.NotFound(err) {
+		return primitive.NilObjectID, derp.Wrap(err, location, "Error loading Folder", userID)
+	}
+
+	// Add the new Folder after all of the User's existing Folders
+	folders, err := service.folderService.QueryByUserID(userID)
+
+	if err != nil {
+		return primitive.NilObjectID, derp.Wrap(err, location, "Error loading Folders", userID)
+	}
+
+	folder.UserID = userID
+	folder.Label = model.FollowedTagFolderLabel
+	folder.Icon = model.FollowedTagFolderIcon
+	folder.Layout = model.FolderLayoutSocial
+	folder.Rank = len(folders)
+
+	if err := service.folderService.Save(&folder, "Created for followed hashtags"); err != nil {
+		return primitive.NilObjectID, derp.Wrap(err, location, "Error saving Folder", userID)
+	}
+
+	return folder.FolderID, nil
+}
+
+/******************************************
+ * Delivering Posts
+ ******************************************/
+
+// Deliver queues a public document to be added to the hashtag Folder of
+// every User who follows one of the hashtags in the document.
+func (service *FollowedTag) Deliver(document streams.Document) {
+
+	document = document.UnwrapActivity()
+
+	// RULE: Only public documents from allowed domains are delivered to hashtag followers
+	if !service.allow(&document) {
+		return
+	}
+
+	names := DocumentHashtags(document)
+
+	if len(names) == 0 {
+		return
+	}
+
+	service.queue.Push(NewTaskDeliverFollowedTags(service, document, names))
+}
+
+// deliverAll adds a document to the hashtag Folder of every User who follows one of the
+// provided hashtags.  Errors for individual Users are reported, and do not prevent
+// delivery to the remaining Users.
+func (service *FollowedTag) deliverAll(document streams.Document, names []string) error {
+
+	const location = "service.FollowedTag.deliverAll"
+
+	followedTags, err := service.QueryByNames(names)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading FollowedTags", names)
+	}
+
+	for index := range followedTags {
+		if err := service.deliver(&followedTags[index], document); err != nil {
+			derp.Report(derp.Wrap(err, location, "Error delivering document", document.ID(), followedTags[index].UserID))
+		}
+	}
+
+	return nil
+}
+
+// allow returns TRUE if a document can be delivered to hashtag followers.  Documents must be
+// public, and cannot come from suspended or silenced domains.  The document MAY BE MODIFIED
+// to remove media or add a content warning.
+func (service *FollowedTag) allow(document *streams.Document) bool {
+
+	if !relayIsPublic(*document) {
+		return false
+	}
+
+	return service.domainPolicyService.Allow(document, true)
+}
+
+// deliver adds a document to the hashtag Folder of a single User.  Documents that
+// are already in the User's inbox only receive an additional reference.
+func (service *FollowedTag) deliver(followedTag *model.FollowedTag, document streams.Document) error {
+
+	const location = "service.FollowedTag.deliver"
+
+	if notAdequate(document) {
+		return nil
+	}
+
+	origin := followedTag.Origin(service.host + "/tags/" + followedTag.Name)
+
+	// If the document is already in the User's inbox, then just add a reference to it
+	message := model.NewMessage()
+
+	if err := service.inboxService.LoadByURL(followedTag.UserID, document.ID(), &message); err == nil {
+
+		if !message.AddReference(origin) {
+			return nil
+		}
+
+		if err := service.inboxService.Save(&message, "Hashtag reference added"); err != nil {
+			return derp.Wrap(err, location, "Error updating message", message.MessageID)
+		}
+
+		return nil
+
+	} else if !derp.NotFound(err) {
+		return derp.Wrap(err, location, "Error searching for existing message", document.ID())
+	}
+
+	// Otherwise, add a new Message to the hashtag Folder
+	message.UserID = followedTag.UserID
+	message.FolderID = followedTag.FolderID
+	message.SocialRole = document.Type()
+	message.URL = document.ID()
+	message.Domain = domainPolicyHostname(document.ID())
+	message.AttributedTo = getMessageAuthor(document)
+	message.SearchText = getMessageSearchText(document)
+	message.Hashtags = DocumentHashtags(document)
+	message.Links = trendLinks(document.Content())
+	message.HasAttachment = document.Attachment().NotNil()
+	message.InReplyTo = document.InReplyTo().ID()
+	message.PublishDate = document.Published().Unix()
+	message.AddReference(origin)
+
+	if err := service.inboxService.Save(&message, "Delivered by hashtag"); err != nil {
+		return derp.Wrap(err, location, "Error saving message", document.ID())
+	}
+
+	return nil
+}
+
+/******************************************
+ * Helper Functions
+ ******************************************/
+
+// DocumentHashtags returns the normalized names of all hashtags in a document
+func DocumentHashtags(document streams.Document) []string {
+
+	result := make([]string, 0)
+	found := make(map[string]bool)
+
+	for tag := document.Tag(); tag.NotNil(); tag = tag.Tail() {
+
+		head := tag.Head()
+
+		if head.Type() != model.TagTypeHashtag {
+			continue
+		}
+
+		name := model.NormalizeHashtag(head.Name())
+
+		if name == "" || found[name] {
+			continue
+		}
+
+		found[name] = true
+		result = append(result, name)
+	}
+
+	return result
+}
+
+// hashtagExpression returns criteria that match any of the provided (normalized) hashtag names
+// in a list of tags.  Hashtags are case-insensitive, so this uses an anchored regular expression
+// instead of a simple equality check.
+func hashtagExpression(field string, names []string) exp.Expression {
+
+	result := exp.OrExpression{}
+
+	for _, name := range names {
+		result = append(result, exp.Contains(field, "^#"+regexp.QuoteMeta(name)+"$"))
+	}
+
+	return result
+}
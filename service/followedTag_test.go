@@ -0,0 +1,69 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentHashtags(t *testing.T) {
+
+	document := streams.NewDocument(mapof.Any{
+		vocab.PropertyTag: []any{
+			mapof.Any{vocab.PropertyType: "Hashtag", vocab.PropertyName: "#Emissary"},
+			mapof.Any{vocab.PropertyType: vocab.LinkTypeMention, vocab.PropertyName: "@bob@remote.social"},
+			mapof.Any{vocab.PropertyType: "Hashtag", vocab.PropertyName: "#emissary"},
+			mapof.Any{vocab.PropertyType: "Hashtag", vocab.PropertyName: "#Fediverse"},
+		},
+	})
+
+	require.Equal(t, []string{"emissary", "fediverse"}, DocumentHashtags(document))
+}
+
+func TestDocumentHashtags_Empty(t *testing.T) {
+	document := streams.NewDocument(mapof.Any{})
+	require.Empty(t, DocumentHashtags(document))
+}
+
+func TestHashtagExpression(t *testing.T) {
+
+	criteria := hashtagExpression("tags.name", []string{"emissary", "c++"})
+
+	result, ok := criteria.(exp.OrExpression)
+	require.True(t, ok)
+	require.Equal(t, 2, len(result))
+	require.Equal(t, exp.Contains("tags.name", "^#emissary$"), result[0])
+	require.Equal(t, exp.Contains("tags.name", `^#c\+\+$`), result[1])
+}
+
+func TestGetMessage_Hashtags(t *testing.T) {
+
+	following := model.NewFollowing()
+
+	document := func(to string) streams.Document {
+		return streams.NewDocument(mapof.Any{
+			vocab.PropertyID:      "https://remote.social/notes/1",
+			vocab.PropertyType:    vocab.ObjectTypeNote,
+			vocab.PropertyTo:      to,
+			vocab.PropertyContent: `<p>Read <a href="https://example.com/article?utm_source=feed">this</a></p>`,
+			vocab.PropertyTag: []any{
+				mapof.Any{vocab.PropertyType: "Hashtag", vocab.PropertyName: "#Emissary"},
+			},
+		})
+	}
+
+	// Public hashtags and links are recorded for hashtag timelines and trends
+	message := getMessage(&following, document(vocab.NamespaceActivityStreamsPublic), model.OriginTypePrimary)
+	require.Equal(t, []string{"emissary"}, []string(message.Hashtags))
+	require.Equal(t, []string{"https://example.com/article"}, []string(message.Links))
+
+	// Private messages are never shown in hashtag timelines
+	message = getMessage(&following, document("https://remote.social/users/alice/followers"), model.OriginTypePrimary)
+	require.Empty(t, message.Hashtags)
+	require.Empty(t, message.Links)
+}
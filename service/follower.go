@@ -1,6 +1,10 @@
 package service
 
 import (
+	"encoding/csv"
+	"io"
+	"time"
+
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/benpate/data"
 	"github.com/benpate/data/option"
@@ -346,6 +350,59 @@ func (service *Follower) LoadOrCreateByWebSub(objectType string, parentID primit
 	return result, derp.Wrap(err, "service.Follower.LoadByWebSub", "Error loading follower", parentID, callback)
 }
 
+/******************************************
+ * RSSCloud Queries
+ ******************************************/
+
+// RSSCloudFollowersChannel returns a channel containing all of the unexpired Followers
+// of specific parentID who use RSSCloud for updates
+func (service *Follower) RSSCloudFollowersChannel(parentType string, parentID primitive.ObjectID) (<-chan model.Follower, error) {
+
+	return service.Channel(
+		exp.Equal("parentId", parentID).
+			AndEqual("type", parentType).
+			AndEqual("method", model.FollowerMethodRSSCloud).
+			AndGreaterThan("expireDate", time.Now().Unix()),
+	)
+}
+
+// LoadByRSSCloud retrieves a follower based on the parentID and notification callback
+func (service *Follower) LoadByRSSCloud(objectType string, parentID primitive.ObjectID, callback string, result *model.Follower) error {
+
+	criteria := exp.
+		Equal("type", objectType).
+		AndEqual("parentId", parentID).
+		AndEqual("method", model.FollowerMethodRSSCloud).
+		AndEqual("actor.inboxUrl", callback)
+
+	return service.Load(criteria, result)
+}
+
+// LoadOrCreateByRSSCloud finds a follower based on the parentID and notification callback.  If no follower is found, a new record is created.
+func (service *Follower) LoadOrCreateByRSSCloud(objectType string, parentID primitive.ObjectID, callback string) (model.Follower, error) {
+
+	// Try to load the Follower from the database
+	result := model.NewFollower()
+	err := service.LoadByRSSCloud(objectType, parentID, callback, &result)
+
+	// If EXISTS, then we've found it.
+	if err == nil {
+		return result, nil
+	}
+
+	// If NOT EXISTS, then create a new one
+	if derp.NotFound(err) {
+		result.ParentID = parentID
+		result.ParentType = objectType
+		result.Method = model.FollowerMethodRSSCloud
+		result.Actor.InboxURL = callback
+		return result, nil
+	}
+
+	// If REAL ERROR, then derp
+	return result, derp.Wrap(err, "service.Follower.LoadOrCreateByRSSCloud", "Error loading follower", parentID, callback)
+}
+
 func (service *Follower) LoadParentActor(follower *model.Follower) (model.PersonLink, error) {
 
 	switch follower.ParentType {
@@ -410,11 +467,12 @@ func (service *Follower) NewActivityPubFollower(parentType string, parentID prim
 	follower.StateID = model.FollowerStateActive
 
 	follower.Actor = model.PersonLink{
-		ProfileURL:   actor.ID(),
-		Name:         actor.Name(),
-		IconURL:      actor.IconOrImage().URL(),
-		InboxURL:     actor.Get("inbox").String(),
-		EmailAddress: actor.Get("email").String(),
+		ProfileURL:     actor.ID(),
+		Name:           actor.Name(),
+		IconURL:        actor.IconOrImage().URL(),
+		InboxURL:       actor.Get("inbox").String(),
+		SharedInboxURL: actor.Endpoints().Get("sharedInbox").String(),
+		EmailAddress:   actor.Get("email").String(),
 	}
 
 	// Try to save the new follower to the database
@@ -522,3 +580,38 @@ func (service *Follower) SendFollowConfirmation(follower *model.Follower) error
 
 	return nil
 }
+
+// ExportCSV writes all of a User's ActivityPub Followers into a CSV file
+// that lists the "username@hostname" address of each Follower.
+func (service *Follower) ExportCSV(userID primitive.ObjectID, writer io.Writer) error {
+
+	const location = "service.Follower.ExportCSV"
+
+	followers, err := service.ActivityPubFollowersChannel(model.FollowerTypeUser, userID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading Followers", userID)
+	}
+
+	csvWriter := csv.NewWriter(writer)
+
+	if err := csvWriter.Write([]string{"Account address"}); err != nil {
+		return derp.Wrap(err, location, "Error writing CSV header")
+	}
+
+	for follower := range followers {
+		address := service.activityService.AccountAddress(follower.Actor.ProfileURL)
+
+		if err := csvWriter.Write([]string{address}); err != nil {
+			return derp.Wrap(err, location, "Error writing CSV row", follower.FollowerID)
+		}
+	}
+
+	csvWriter.Flush()
+
+	if err := csvWriter.Error(); err != nil {
+		return derp.Wrap(err, location, "Error writing CSV file")
+	}
+
+	return nil
+}
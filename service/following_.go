@@ -9,6 +9,7 @@ import (
 	"github.com/benpate/data/option"
 	"github.com/benpate/derp"
 	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/queue"
 	"github.com/benpate/hannibal/vocab"
 
 	"github.com/benpate/rosetta/mapof"
@@ -21,15 +22,18 @@ const followingMimeStack = "application/activity+json; q=1.0, text/html; q=0.9,
 
 // Following manages all interactions with the Following collection
 type Following struct {
-	collection      data.Collection
-	streamService   *Stream
-	userService     *User
-	inboxService    *Inbox
-	folderService   *Folder
-	keyService      *EncryptionKey
-	activityService *ActivityStream
-	host            string
-	closed          chan bool
+	collection          data.Collection
+	streamService       *Stream
+	userService         *User
+	inboxService        *Inbox
+	folderService       *Folder
+	followedTagService  *FollowedTag
+	domainPolicyService *DomainPolicy
+	keyService          *EncryptionKey
+	activityService     *ActivityStream
+	queue               queue.Queue
+	host                string
+	closed              chan bool
 }
 
 // NewFollowing returns a fully populated Following service.
@@ -42,14 +46,17 @@ func NewFollowing() Following {
  ******************************************/
 
 // Refresh updates any stateful data that is cached inside this service.
-func (service *Following) Refresh(collection data.Collection, streamService *Stream, userService *User, inboxService *Inbox, folderService *Folder, keyService *EncryptionKey, activityService *ActivityStream, host string) {
+func (service *Following) Refresh(collection data.Collection, streamService *Stream, userService *User, inboxService *Inbox, folderService *Folder, followedTagService *FollowedTag, domainPolicyService *DomainPolicy, keyService *EncryptionKey, activityService *ActivityStream, queue queue.Queue, host string) {
 	service.collection = collection
 	service.streamService = streamService
 	service.userService = userService
 	service.inboxService = inboxService
 	service.folderService = folderService
+	service.followedTagService = followedTagService
+	service.domainPolicyService = domainPolicyService
 	service.keyService = keyService
 	service.activityService = activityService
+	service.queue = queue
 	service.host = host
 }
 
@@ -103,13 +110,9 @@ func (service *Following) Start() {
 			default:
 
 				// Poll each following for new items.
-				if err := service.Connect(following); err != nil {
+				if err := service.Poll(following); err != nil {
 					derp.Report(derp.Wrap(err, location, "Error connecting to remote server"))
 				}
-
-				if err := service.PurgeInbox(following); err != nil {
-					derp.Report(derp.Wrap(err, location, "Error purghing inbox"))
-				}
 			}
 
 			following = model.NewFollowing()
@@ -164,6 +167,11 @@ func (service *Following) Save(following *model.Following, note string) error {
 	following.StatusMessage = ""
 	following.ErrorCount = 0
 
+	// RULE: Reset polling validators so that the next poll is a full reload
+	following.FeedETag = ""
+	following.FeedLastModified = ""
+	following.NextPoll = 0
+
 	if following.Behavior == "" {
 		following.Behavior = model.FollowingBehaviorPostsAndReplies
 	}
@@ -319,6 +327,14 @@ func (service *Following) ListActivityPub(userID primitive.ObjectID, options ...
 	return service.List(criteria, options...)
 }
 
+// QueryActivityPubByProfileURL returns all ActivityPub Following records (for any User) that follow the provided profile URL
+func (service *Following) QueryActivityPubByProfileURL(profileURL string) ([]model.Following, error) {
+	criteria := exp.Equal("profileUrl", profileURL).
+		AndEqual("method", model.FollowingMethodActivityPub)
+
+	return service.Query(criteria)
+}
+
 /******************************************
  * Custom Queries
  ******************************************/
@@ -354,7 +370,8 @@ func (service *Following) QueryByFolderAndExp(userID primitive.ObjectID, folderI
 // ListPollable returns an iterator of all following that are ready to be polled
 func (service *Following) ListPollable() (data.Iterator, error) {
 	criteria := exp.LessThan("nextPoll", time.Now().Unix()).
-		AndNotEqual("method", model.FollowingMethodActivityPub) // Don't poll ActivityPub
+		AndNotEqual("method", model.FollowingMethodActivityPub). // Don't poll ActivityPub
+		AndNotEqual("status", model.FollowingStatusRejected)     // Don't retry rejected follows
 
 	return service.List(criteria, option.SortAsc("lastPolled"))
 }
@@ -442,30 +459,6 @@ func (service *Following) GetFollowingID(userID primitive.ObjectID, uri string)
 	}
 }
 
-// PurgeInbox removes all inbox items that are past their expiration date.
-// TODO: LOW: Should this be in the Inbox service?
-func (service *Following) PurgeInbox(following model.Following) error {
-
-	const location = "service.Following.PurgeFollowing"
-
-	// Check each following for expired items.
-	items, err := service.inboxService.QueryPurgeable(&following)
-
-	// If there was an error querying for purgeable items, log it and exit.
-	if err != nil {
-		return derp.Wrap(err, location, "Error querying purgeable items", following)
-	}
-
-	// Purge each item that has expired
-	for _, item := range items {
-		if err := service.inboxService.Delete(&item, "Purged"); err != nil {
-			return derp.Wrap(err, location, "Error purging item", item)
-		}
-	}
-
-	return nil
-}
-
 /******************************************
  * Other Updates Methods
  ******************************************/
@@ -490,7 +483,8 @@ func (service *Following) SetStatusSuccess(following *model.Following) error {
 	following.Status = model.FollowingStatusSuccess
 	following.StatusMessage = ""
 
-	following.NextPoll = following.LastPolled + int64(following.PollDuration*60*60)
+	// Poll adaptively schedules feeds, so never move an existing NextPoll earlier
+	following.NextPoll = max(following.NextPoll, following.LastPolled+int64(following.PollDuration*60*60))
 	following.ErrorCount = 0
 
 	// Save the Following to the database
@@ -515,8 +509,21 @@ func (service *Following) SetStatusFailure(following *model.Following, statusMes
 		errorBackoff = 8
 	}
 
-	errorBackoff = 2 ^ errorBackoff
-	following.NextPoll = time.Now().Add(time.Duration(errorBackoff) * time.Minute).Unix()
+	// Remote servers may also ask us to wait longer (via Retry-After) so never move an existing NextPoll earlier
+	errorBackoff = 1 << errorBackoff
+	following.NextPoll = max(following.NextPoll, time.Now().Add(time.Duration(errorBackoff)*time.Minute).Unix())
+
+	// Save the Following to the database
+	return service.collection.Save(following, "Updating status")
+}
+
+// SetStatusRejected updates a Following record to the "Rejected" status.
+// Rejected Following records are not polled again until the User reconnects them.
+func (service *Following) SetStatusRejected(following *model.Following, statusMessage string) error {
+
+	// Update Following state
+	following.Status = model.FollowingStatusRejected
+	following.StatusMessage = statusMessage
 
 	// Save the Following to the database
 	return service.collection.Save(following, "Updating status")
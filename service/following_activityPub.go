@@ -2,9 +2,12 @@ package service
 
 import (
 	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/ascache"
 	"github.com/benpate/derp"
 	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/sherlock"
 	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // connect_ActivityPub attempts to connect to a remote user using ActivityPub.
@@ -56,3 +59,99 @@ func (service *Following) disconnect_ActivityPub(following *model.Following) err
 
 	return nil
 }
+
+// Move re-points a User's Following record from an ActivityPub actor that has moved
+// to the actor's new account.  Per convention, the new account must list the old
+// account in its "alsoKnownAs" property.
+func (service *Following) Move(userID primitive.ObjectID, oldActorID string, newActorID string) error {
+
+	const location = "service.Following.Move"
+
+	// Find the existing Following record.  If we're not following this actor, then there's nothing to do.
+	following := model.NewFollowing()
+	if err := service.LoadByURL(userID, oldActorID, &following); err != nil {
+		if derp.NotFound(err) {
+			return nil
+		}
+		return derp.Wrap(err, location, "Error loading Following", userID, oldActorID)
+	}
+
+	// Load the new actor from the remote server
+	target, err := service.activityService.Load(newActorID, sherlock.AsActor())
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading new actor", newActorID)
+	}
+
+	// RULE: The new account must list the old account as an alias
+	if !isAlsoKnownAs(target, oldActorID) {
+		return derp.NewForbiddenError(location, "New actor does not list the old actor as an alias", oldActorID, target.ID())
+	}
+
+	// If the User already follows the new account, then keep the old messages
+	// under the existing record and remove the old record without cascading.
+	existing := model.NewFollowing()
+	if err := service.LoadByURL(userID, target.ID(), &existing); err == nil {
+
+		if err := service.inboxService.MoveOrigin(userID, following.FollowingID, &existing); err != nil {
+			return derp.Wrap(err, location, "Error moving messages to existing Following", following, existing)
+		}
+
+		if err := service.collection.Delete(&following, "Moved to "+target.ID()); err != nil {
+			return derp.Wrap(err, location, "Error removing Following", following)
+		}
+
+		go service.userService.CalcFollowingCount(following.UserID)
+		go derp.Report(service.folderService.ReCalculateUnreadCountFromFolder(following.UserID, following.FolderID))
+
+		service.Disconnect(&following)
+		return nil
+	}
+
+	// Otherwise, point the Following record at the new account and reconnect
+	following.URL = target.ID()
+	following.ProfileURL = target.ID()
+
+	if err := service.Save(&following, "Moved to "+target.ID()); err != nil {
+		return derp.Wrap(err, location, "Error saving Following", following)
+	}
+
+	return nil
+}
+
+// RefreshActor reloads a remote actor into the ActivityStream cache, then updates the
+// name and avatar of the User's Following record for that actor.  This is called when
+// the remote server sends an "Update" activity for its actor.
+func (service *Following) RefreshActor(userID primitive.ObjectID, actorID string) error {
+
+	const location = "service.Following.RefreshActor"
+
+	// Reload the actor into the cache
+	actor, err := service.activityService.Load(actorID, sherlock.AsActor(), ascache.WithForceReload())
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading actor", actorID)
+	}
+
+	// Find the Following record for this actor.  If there is none, then there's nothing more to do.
+	following := model.NewFollowing()
+
+	if err := service.LoadByURL(userID, actorID, &following); err != nil {
+
+		if derp.NotFound(err) {
+			return nil
+		}
+
+		return derp.Wrap(err, location, "Error loading Following", userID, actorID)
+	}
+
+	// Update the Following record (without reconnecting)
+	following.Label = actor.Name()
+	following.IconURL = actor.IconOrImage().URL()
+
+	if err := service.collection.Save(&following, "Actor updated"); err != nil {
+		return derp.Wrap(err, location, "Error saving Following", following)
+	}
+
+	return nil
+}
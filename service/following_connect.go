@@ -10,6 +10,7 @@ import (
 	"github.com/benpate/hannibal/collections"
 	"github.com/benpate/hannibal/streams"
 	"github.com/benpate/rosetta/channel"
+	"github.com/benpate/rosetta/mapof"
 	"github.com/benpate/sherlock"
 	"github.com/rs/zerolog/log"
 )
@@ -29,6 +30,13 @@ func (service *Following) RefreshAndConnect(following model.Following) {
 
 // Connect attempts to connect to a new URL and determines how to follow it.
 func (service *Following) Connect(following model.Following) error {
+	return service.connect(following, nil)
+}
+
+// connect connects to a Following's URL.  If the feed document has already been
+// downloaded (by Poll), then it is passed in feedBody so that it can be searched
+// for enclosures and RSSCloud endpoints without downloading it again.
+func (service *Following) connect(following model.Following, feedBody []byte) error {
 
 	const location = "service.Following.Connect"
 
@@ -52,26 +60,39 @@ func (service *Following) Connect(following model.Following) error {
 	following.Label = actor.Name()
 	following.ProfileURL = actor.ID()
 	following.IconURL = actor.IconOrImage().URL()
+	following.FeedURL = actor.ID() // The URL that the feed was loaded from (JSON Feed actors use their home page as their URL)
 
 	// ...and mark the status as "Success"
 	if err := service.SetStatusSuccess(&following); err != nil {
 		return derp.Wrap(err, location, "Error setting status", following)
 	}
 
+	// Feeds may include data that is not included in the actor's outbox
+	if (feedBody == nil) && following.IsFeed() && (following.FeedURL != "") {
+		feedBody = service.loadFeed(following.FeedURL)
+	}
+
 	// Try to load an initial list of messages from the actor's outbox
-	service.connect_LoadMessages(&following, &actor)
+	service.connect_LoadMessages(&following, &actor, feedBody)
 
 	// Try to connect to push services (WebSub, ActivityPub, etc)
-	service.connect_PushServices(&following, &actor)
+	service.connect_PushServices(&following, &actor, feedBody)
 
 	// Kool-Aid man says "ooooohhh yeah!"
 	return nil
 }
 
-func (service *Following) connect_LoadMessages(following *model.Following, actor *streams.Document) {
+func (service *Following) connect_LoadMessages(following *model.Following, actor *streams.Document, feedBody []byte) {
 
 	const location = "service.Following.connect_LoadMessages"
 
+	// Feeds may include podcast enclosures, which are not included in the actor's outbox
+	var enclosures map[string][]mapof.Any
+
+	if feedBody != nil {
+		enclosures = feedEnclosures(feedBody, following.FeedURL)
+	}
+
 	// Create a channel from this outbox...
 	done := make(chan struct{})
 	outbox := actor.Outbox()
@@ -95,6 +116,12 @@ func (service *Following) connect_LoadMessages(following *model.Following, actor
 		// nolint:errcheck
 		result, _ := document.Load(sherlock.WithDefaultValue(document.Map()))
 
+		// Preserve podcast enclosures as attachments
+		result = service.withEnclosures(result, enclosures[document.ID()])
+
+		// If requested, replace truncated feed items with the full article
+		result = service.fetchFullContent(following, result)
+
 		// Try to save the document to the database.
 		if err := service.SaveMessage(following, result, model.OriginTypePrimary); err != nil {
 			derp.Report(derp.Wrap(err, location, "Error saving document to Inbox", result.Value()))
@@ -108,7 +135,7 @@ func (service *Following) connect_LoadMessages(following *model.Following, actor
 }
 
 // connect_PushServices tries to connect to the best available push service
-func (service *Following) connect_PushServices(following *model.Following, actor *streams.Document) {
+func (service *Following) connect_PushServices(following *model.Following, actor *streams.Document, feedBody []byte) {
 
 	const location = "service.Following.connect_PushServices"
 
@@ -131,7 +158,6 @@ func (service *Following) connect_PushServices(following *model.Following, actor
 
 	// If a WebSub hub is defined, then use that.
 	if hub := actor.Endpoints().Get("websub").String(); hub != "" {
-		// TODO: LOW: Implement Fat Pings
 		if ok, err := service.connect_WebSub(following, hub); ok {
 			return
 		} else {
@@ -139,6 +165,8 @@ func (service *Following) connect_PushServices(following *model.Following, actor
 		}
 	}
 
-	// RSSCloud is TBD because WebSub seems to have won the war.
-	// TODO: LOW: RSSCloud
+	// Otherwise, try the RSSCloud server listed in the feed (if any)
+	if ok, err := service.connect_RSSCloud(following, feedBody); !ok && (err != nil) {
+		derp.Report(derp.Wrap(err, location, "Error connecting to RSSCloud"))
+	}
 }
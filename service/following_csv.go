@@ -0,0 +1,166 @@
+package service
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// csvHeaderFollowing is the header row used by Mastodon-compatible "following" exports
+var csvHeaderFollowing = []string{"Account address", "Show boosts", "Notify on new posts", "Languages"}
+
+// ExportCSV writes all of a User's Following records into a CSV file that uses the
+// same format as other fediverse servers.
+func (service *Following) ExportCSV(userID primitive.ObjectID, writer io.Writer) error {
+
+	const location = "service.Following.ExportCSV"
+
+	followings, err := service.Query(exp.Equal("userId", userID), option.SortAsc("label"))
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading Following records", userID)
+	}
+
+	addresses := make([]string, 0, len(followings))
+
+	for _, following := range followings {
+
+		// ActivityPub accounts are exported as "username@hostname". Everything else uses its URL.
+		if following.Method == model.FollowingMethodActivityPub {
+			addresses = append(addresses, service.activityService.AccountAddress(following.ProfileURL))
+		} else {
+			addresses = append(addresses, following.URL)
+		}
+	}
+
+	if err := writeFollowingCSV(writer, addresses); err != nil {
+		return derp.Wrap(err, location, "Error writing CSV file", userID)
+	}
+
+	return nil
+}
+
+// ImportCSV reads a CSV file of accounts (the first column of each row) and follows every
+// account that the User does not already follow.  New Following records are placed into
+// the User's first Folder, and are saved (and connected) in the background.  It returns
+// the number of new Following records that were queued.
+func (service *Following) ImportCSV(userID primitive.ObjectID, reader io.Reader) (int, error) {
+
+	const location = "service.Following.ImportCSV"
+
+	// Find the default folder for new Following records
+	folders, err := service.folderService.QueryByUserID(userID)
+
+	if err != nil {
+		return 0, derp.Wrap(err, location, "Error loading folders", userID)
+	}
+
+	if len(folders) == 0 {
+		return 0, derp.NewBadRequestError(location, "User must have at least one folder", userID)
+	}
+
+	addresses, err := readFollowingCSV(reader)
+
+	if err != nil {
+		return 0, derp.Wrap(err, location, "Error reading CSV file", derp.WithBadRequest())
+	}
+
+	count := 0
+
+	for _, address := range addresses {
+
+		// Skip accounts that are already being followed
+		existing := model.NewFollowing()
+		if err := service.LoadByURL(userID, address, &existing); err == nil {
+			continue
+		}
+
+		if err := service.Load(exp.Equal("userId", userID).AndEqual("url", address), &existing); err == nil {
+			continue
+		}
+
+		// Queue the new Following record.  Saving it will connect to the remote account.
+		following := model.NewFollowing()
+		following.UserID = userID
+		following.FolderID = folders[0].FolderID
+		following.URL = address
+
+		service.queue.Push(NewTaskImportFollowing(service, following, "Imported from CSV"))
+		count++
+	}
+
+	return count, nil
+}
+
+// writeFollowingCSV writes a list of account addresses in the same
+// "following" format used by other fediverse servers.
+func writeFollowingCSV(writer io.Writer, addresses []string) error {
+
+	const location = "service.writeFollowingCSV"
+
+	csvWriter := csv.NewWriter(writer)
+
+	if err := csvWriter.Write(csvHeaderFollowing); err != nil {
+		return derp.Wrap(err, location, "Error writing CSV header")
+	}
+
+	for _, address := range addresses {
+		if err := csvWriter.Write([]string{address, "true", "false", ""}); err != nil {
+			return derp.Wrap(err, location, "Error writing CSV row", address)
+		}
+	}
+
+	csvWriter.Flush()
+
+	if err := csvWriter.Error(); err != nil {
+		return derp.Wrap(err, location, "Error writing CSV file")
+	}
+
+	return nil
+}
+
+// readFollowingCSV returns the unique account addresses in the first column of a CSV file.
+// Empty rows and header rows are skipped.
+func readFollowingCSV(reader io.Reader) ([]string, error) {
+
+	const location = "service.readFollowingCSV"
+
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	result := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for {
+		record, err := csvReader.Read()
+
+		if err == io.EOF {
+			return result, nil
+		}
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Error reading CSV row")
+		}
+
+		address := strings.TrimSpace(record[0])
+
+		// Skip empty rows and header rows
+		if (address == "") || strings.EqualFold(address, csvHeaderFollowing[0]) {
+			continue
+		}
+
+		if seen[address] {
+			continue
+		}
+
+		seen[address] = true
+		result = append(result, address)
+	}
+}
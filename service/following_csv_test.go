@@ -0,0 +1,51 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollowingCSV_RoundTrip(t *testing.T) {
+
+	addresses := []string{
+		"alice@a.social",
+		"bob@b.social",
+		"https://example.com/feed.xml",
+		"https://example.com/posts?tag=one,two",
+	}
+
+	var buffer bytes.Buffer
+	require.Nil(t, writeFollowingCSV(&buffer, addresses))
+
+	// Exports use the same columns as other fediverse servers
+	require.True(t, strings.HasPrefix(buffer.String(), "Account address,Show boosts,Notify on new posts,Languages\n"))
+
+	result, err := readFollowingCSV(&buffer)
+	require.Nil(t, err)
+	require.Equal(t, addresses, result)
+}
+
+func TestFollowingCSV_Read(t *testing.T) {
+
+	// Mastodon exports include a header row and extra columns.  Other tools export a single
+	// column with no header at all.  Empty rows and duplicate addresses are skipped.
+	input := "Account address,Show boosts,Notify on new posts,Languages\n" +
+		"alice@a.social,true,false,\n" +
+		"\n" +
+		"  bob@b.social\n" +
+		"alice@a.social,false,true,en\n" +
+		"https://example.com/feed.xml,true\n"
+
+	result, err := readFollowingCSV(strings.NewReader(input))
+	require.Nil(t, err)
+	require.Equal(t, []string{"alice@a.social", "bob@b.social", "https://example.com/feed.xml"}, result)
+}
+
+func TestFollowingCSV_ReadInvalid(t *testing.T) {
+
+	_, err := readFollowingCSV(strings.NewReader("alice@a.social\n\"unterminated,quote\n"))
+	require.NotNil(t, err)
+}
@@ -17,5 +17,8 @@ func (service *Following) Disconnect(following *model.Following) {
 
 	case model.FollowingMethodWebSub:
 		service.disconnect_WebSub(following)
+
+	case model.FollowingMethodRSSCloud:
+		// RSSCloud does not have an "unsubscribe" request.  Registrations expire on their own after 25 hours.
 	}
 }
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"bytes"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/remote"
+	"github.com/benpate/rosetta/first"
+	"github.com/benpate/rosetta/list"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/mmcdole/gofeed"
+)
+
+// loadFeed downloads a feed document so that it can be searched for data (like
+// podcast enclosures and RSSCloud endpoints) that sherlock does not provide.
+func (service *Following) loadFeed(feedURL string) []byte {
+
+	const location = "service.Following.loadFeed"
+
+	txn := remote.Get(feedURL).
+		UserAgent("Emissary Social: https://emissary.social").
+		Header("Accept", followingMimeStack)
+
+	if err := txn.Send(); err != nil {
+		derp.Report(derp.Wrap(err, location, "Error loading feed", feedURL))
+		return nil
+	}
+
+	body, err := txn.ResponseBody()
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, location, "Error reading feed", feedURL))
+		return nil
+	}
+
+	return body
+}
+
+// feedEnclosures parses a feed document and returns the audio and video enclosures
+// of each item, as ActivityStreams attachments indexed by the item's URL.  Item URLs
+// are resolved the same way that sherlock resolves them, so that they match the IDs
+// of the documents in the feed's outbox.
+func feedEnclosures(body []byte, feedURL string) map[string][]mapof.Any {
+
+	result := make(map[string][]mapof.Any)
+	feed, err := gofeed.NewParser().Parse(bytes.NewReader(body))
+
+	if err != nil {
+		return result
+	}
+
+	baseURL, err := url.Parse(first.String(feed.FeedLink, feed.Link, feedURL))
+
+	if err != nil {
+		return result
+	}
+
+	for _, item := range feed.Items {
+
+		linkURL, err := baseURL.Parse(item.Link)
+
+		if err != nil {
+			continue
+		}
+
+		duration := 0
+		if item.ITunesExt != nil {
+			duration = parseITunesDuration(item.ITunesExt.Duration)
+		}
+
+		for _, enclosure := range item.Enclosures {
+
+			attachment := mapof.Any{
+				vocab.PropertyURL:       enclosure.URL,
+				vocab.PropertyMediaType: enclosure.Type,
+				vocab.PropertyName:      item.Title,
+			}
+
+			switch list.Slash(enclosure.Type).First() {
+
+			case "audio":
+				attachment[vocab.PropertyType] = vocab.ObjectTypeAudio
+
+			case "video":
+				attachment[vocab.PropertyType] = vocab.ObjectTypeVideo
+
+			default:
+				continue
+			}
+
+			if duration > 0 {
+				attachment[vocab.PropertyDuration] = "PT" + strconv.Itoa(duration) + "S"
+			}
+
+			result[linkURL.String()] = append(result[linkURL.String()], attachment)
+		}
+	}
+
+	return result
+}
+
+// withEnclosures adds feed enclosures to a document as attachments, and saves the
+// updated document into the ActivityStream cache so that it is used by the inbox.
+func (service *Following) withEnclosures(document streams.Document, enclosures []mapof.Any) streams.Document {
+
+	if len(enclosures) == 0 {
+		return document
+	}
+
+	// RULE: Do not store media from domains whose media is rejected
+	if service.domainPolicyService.IsMediaRejected(document.ID()) {
+		return document
+	}
+
+	attachments, changed := mergeEnclosures(document, enclosures)
+
+	if !changed {
+		return document
+	}
+
+	result := document.Clone()
+	result.SetProperty(vocab.PropertyAttachment, attachments)
+	service.activityService.Put(result)
+
+	return result
+}
+
+// mergeEnclosures appends feed enclosures to a document's existing attachments, skipping
+// any enclosure whose URL is already attached.  It returns TRUE if any enclosures were added.
+func mergeEnclosures(document streams.Document, enclosures []mapof.Any) ([]any, bool) {
+
+	result := make([]any, 0, len(enclosures))
+	urls := make(map[string]bool, len(enclosures))
+
+	for attachment := document.Attachment(); attachment.NotNil(); attachment = attachment.Tail() {
+		head := attachment.Head()
+		result = append(result, head.Value())
+		urls[head.URL()] = true
+		urls[head.Href()] = true
+	}
+
+	changed := false
+
+	for _, enclosure := range enclosures {
+
+		url := enclosure.GetString(vocab.PropertyURL)
+
+		if urls[url] {
+			continue
+		}
+
+		urls[url] = true
+		result = append(result, enclosure)
+		changed = true
+	}
+
+	return result, changed
+}
+
+// parseITunesDuration converts an <itunes:duration> value (seconds, MM:SS, or HH:MM:SS)
+// into a number of seconds.  It returns 0 if the value cannot be parsed.
+func parseITunesDuration(value string) int {
+
+	result := 0
+
+	// Ignore fractional seconds
+	value, _, _ = strings.Cut(strings.TrimSpace(value), ".")
+
+	for _, part := range strings.Split(value, ":") {
+
+		number, err := strconv.Atoi(part)
+
+		if err != nil {
+			return 0
+		}
+
+		result = (result * 60) + number
+	}
+
+	return result
+}
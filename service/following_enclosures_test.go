@@ -0,0 +1,89 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseITunesDuration(t *testing.T) {
+	require.Equal(t, 0, parseITunesDuration(""))
+	require.Equal(t, 0, parseITunesDuration("soon"))
+	require.Equal(t, 90, parseITunesDuration("90"))
+	require.Equal(t, 90, parseITunesDuration("90.5"))
+	require.Equal(t, 754, parseITunesDuration("12:34"))
+	require.Equal(t, 3723, parseITunesDuration("01:02:03"))
+}
+
+func TestFeedEnclosures(t *testing.T) {
+
+	body := `<?xml version="1.0"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+	<channel>
+		<title>Example Podcast</title>
+		<link>https://example.com/</link>
+		<item>
+			<title>Episode 1</title>
+			<link>/episodes/1</link>
+			<itunes:duration>01:00:00</itunes:duration>
+			<enclosure url="https://cdn.example.com/1.mp3" length="1234" type="audio/mpeg"/>
+		</item>
+		<item>
+			<title>Blog Post</title>
+			<link>https://example.com/post</link>
+			<enclosure url="https://cdn.example.com/photo.jpg" length="1234" type="image/jpeg"/>
+		</item>
+	</channel>
+</rss>`
+
+	result := feedEnclosures([]byte(body), "https://example.com/feed.xml")
+
+	require.Equal(t, map[string][]mapof.Any{
+		"https://example.com/episodes/1": {{
+			"type":      "Audio",
+			"url":       "https://cdn.example.com/1.mp3",
+			"mediaType": "audio/mpeg",
+			"name":      "Episode 1",
+			"duration":  "PT3600S",
+		}},
+	}, result)
+}
+
+func TestMergeEnclosures(t *testing.T) {
+
+	image := mapof.Any{"type": "Image", "url": "https://example.com/cover.jpg"}
+	audio := mapof.Any{"type": "Audio", "url": "https://cdn.example.com/1.mp3"}
+
+	document := streams.NewDocument(mapof.Any{
+		"id":         "https://example.com/episodes/1",
+		"attachment": []any{image, audio},
+	})
+
+	// Existing attachments are kept, and duplicate enclosures are skipped
+	video := mapof.Any{"type": "Video", "url": "https://cdn.example.com/1.mp4"}
+	result, changed := mergeEnclosures(document, []mapof.Any{audio, video})
+
+	require.True(t, changed)
+	require.Equal(t, 3, len(result))
+	require.Equal(t, "https://example.com/cover.jpg", streams.NewDocument(result[0]).URL())
+	require.Equal(t, "https://cdn.example.com/1.mp4", streams.NewDocument(result[2]).URL())
+
+	// Nothing changes when every enclosure is already attached
+	_, changed = mergeEnclosures(document, []mapof.Any{audio})
+	require.False(t, changed)
+}
+
+func TestMergeEnclosures_SingleAttachment(t *testing.T) {
+
+	document := streams.NewDocument(mapof.Any{
+		"id":         "https://example.com/episodes/1",
+		"attachment": mapof.Any{"type": "Image", "url": "https://example.com/cover.jpg"},
+	})
+
+	result, changed := mergeEnclosures(document, []mapof.Any{{"type": "Audio", "url": "https://cdn.example.com/1.mp3"}})
+
+	require.True(t, changed)
+	require.Equal(t, 2, len(result))
+}
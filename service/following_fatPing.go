@@ -0,0 +1,132 @@
+package service
+
+import (
+	"bytes"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/convert"
+	"github.com/benpate/derp"
+	"github.com/benpate/domain"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/first"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/mmcdole/gofeed"
+)
+
+// ReceiveFatPing imports the feed entries included in the body of a push notification
+// (a WebSub "fat ping") directly into the User's inbox, without re-fetching the feed.
+// It returns FALSE if the body does not contain any feed entries (a "thin ping"), in
+// which case the caller should reload the feed instead.  The body is only trusted for
+// this User's inbox, so it is never written into the shared ActivityStream cache.
+func (service *Following) ReceiveFatPing(following model.Following, body []byte) (bool, error) {
+
+	const location = "service.Following.ReceiveFatPing"
+
+	activities := fatPingActivities(body, following.URL)
+
+	if len(activities) == 0 {
+		return false, nil
+	}
+
+	// Enclosures and full-content extraction update the shared cache, so
+	// feeds that use them are reloaded from their origin instead
+	if following.FetchFullContent || len(feedEnclosures(body, following.URL)) > 0 {
+		return false, nil
+	}
+
+	for _, activity := range activities {
+
+		document := service.activityService.NewDocument(activity)
+
+		// RULE: Feeds can only push entries that they host themselves
+		if !isFatPingHost(following, document.ID()) {
+			continue
+		}
+
+		if err := service.SaveMessage(&following, document, model.OriginTypePrimary); err != nil {
+			derp.Report(derp.Wrap(err, location, "Error saving document to Inbox", document.ID()))
+		}
+	}
+
+	// Recalculate Folder unread counts
+	if err := service.folderService.ReCalculateUnreadCountFromFolder(following.UserID, following.FolderID); err != nil {
+		derp.Report(derp.Wrap(err, location, "Error recalculating unread count"))
+	}
+
+	// Mark the Following as up-to-date
+	following.LastPolled = time.Now().Unix()
+
+	if err := service.SetStatusSuccess(&following); err != nil {
+		return true, derp.Wrap(err, location, "Error updating following status", following.FollowingID)
+	}
+
+	return true, nil
+}
+
+// fatPingActivities parses a feed document (RSS, Atom, or JSONFeed) and returns its entries
+// as ActivityStreams objects, oldest first.  Entry IDs are resolved the same way that
+// sherlock resolves them, so that they match documents loaded from the feed's outbox.
+func fatPingActivities(body []byte, topicURL string) []mapof.Any {
+
+	feed, err := gofeed.NewParser().Parse(bytes.NewReader(body))
+
+	if err != nil {
+		return nil
+	}
+
+	baseURL, err := url.Parse(first.String(feed.FeedLink, feed.Link, topicURL))
+
+	if err != nil {
+		return nil
+	}
+
+	result := make([]mapof.Any, 0, len(feed.Items))
+
+	for _, item := range feed.Items {
+
+		linkURL, err := baseURL.Parse(item.Link)
+
+		if (err != nil) || (item.Link == "") {
+			continue
+		}
+
+		activity := convert.RSSToActivity(feed, item)
+		activity[vocab.PropertyType] = vocab.ObjectTypePage
+		activity[vocab.PropertyID] = linkURL.String()
+		activity[vocab.PropertyActor] = feed.FeedLink
+
+		if activity.GetInt64(vocab.PropertyPublished) == 0 {
+			activity[vocab.PropertyPublished] = time.Now().Unix()
+		}
+
+		result = append(result, activity)
+	}
+
+	sort.SliceStable(result, func(a int, b int) bool {
+		return result[a].GetInt64(vocab.PropertyPublished) < result[b].GetInt64(vocab.PropertyPublished)
+	})
+
+	return result
+}
+
+// isFatPingHost returns TRUE if the provided entry ID is hosted on the
+// same domain as the feed that the Following subscribes to.
+func isFatPingHost(following model.Following, entryID string) bool {
+
+	entryHost := domain.NameOnly(entryID)
+
+	if entryHost == "" {
+		return false
+	}
+
+	for _, feedURL := range []string{following.URL, following.FeedURL} {
+		if (feedURL != "") && (domain.NameOnly(feedURL) == entryHost) {
+			return true
+		}
+	}
+
+	return false
+}
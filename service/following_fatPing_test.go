@@ -0,0 +1,76 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFatPingActivities_RSS(t *testing.T) {
+
+	body := `<?xml version="1.0"?>
+<rss version="2.0">
+	<channel>
+		<title>Example Blog</title>
+		<link>https://example.com/</link>
+		<item>
+			<title>Second Post</title>
+			<link>/posts/2</link>
+			<description>The second post</description>
+			<pubDate>Tue, 02 Jan 2024 00:00:00 GMT</pubDate>
+		</item>
+		<item>
+			<title>First Post</title>
+			<link>https://example.com/posts/1</link>
+			<pubDate>Mon, 01 Jan 2024 00:00:00 GMT</pubDate>
+		</item>
+		<item>
+			<title>No Link</title>
+		</item>
+	</channel>
+</rss>`
+
+	result := fatPingActivities([]byte(body), "https://example.com/feed.xml")
+
+	require.Equal(t, 2, len(result))
+	require.Equal(t, "https://example.com/posts/1", result[0].GetString(vocab.PropertyID))
+	require.Equal(t, "https://example.com/posts/2", result[1].GetString(vocab.PropertyID))
+	require.Equal(t, "Second Post", result[1].GetString(vocab.PropertyName))
+	require.Equal(t, "The second post", result[1].GetString(vocab.PropertySummary))
+	require.Equal(t, vocab.ObjectTypePage, result[1].GetString(vocab.PropertyType))
+}
+
+func TestFatPingActivities_JSONFeed(t *testing.T) {
+
+	body := `{
+		"version": "https://jsonfeed.org/version/1.1",
+		"title": "Example Blog",
+		"home_page_url": "https://example.com/",
+		"items": [{"id": "1", "url": "https://example.com/posts/1", "title": "First Post", "content_html": "<p>Hello</p>"}]
+	}`
+
+	result := fatPingActivities([]byte(body), "https://example.com/feed.json")
+
+	require.Equal(t, 1, len(result))
+	require.Equal(t, "https://example.com/posts/1", result[0].GetString(vocab.PropertyID))
+	require.NotZero(t, result[0].GetInt64(vocab.PropertyPublished))
+}
+
+func TestFatPingActivities_ThinPing(t *testing.T) {
+	require.Empty(t, fatPingActivities(nil, "https://example.com/feed.xml"))
+	require.Empty(t, fatPingActivities([]byte("changed"), "https://example.com/feed.xml"))
+}
+
+func TestIsFatPingHost(t *testing.T) {
+
+	following := model.NewFollowing()
+	following.URL = "https://example.com/"
+	following.FeedURL = "https://feeds.example.com/feed.xml"
+
+	require.True(t, isFatPingHost(following, "https://example.com/posts/1"))
+	require.True(t, isFatPingHost(following, "https://feeds.example.com/posts/1"))
+	require.False(t, isFatPingHost(following, "https://other.com/posts/1"))
+	require.False(t, isFatPingHost(following, ""))
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/readability"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/remote"
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// fetchFullContent downloads the web page linked by a feed item and replaces the item's
+// (often truncated) content with the full article.  The updated document is saved into
+// the ActivityStream cache so that it is used by the inbox reader view.  If the article
+// cannot be extracted, then the original document is returned unchanged.
+func (service *Following) fetchFullContent(following *model.Following, document streams.Document) streams.Document {
+
+	const location = "service.Following.fetchFullContent"
+
+	// RULE: Only fetch full content when the User has asked for it
+	if !following.FetchFullContent {
+		return document
+	}
+
+	// RULE: ActivityPub documents are already complete
+	if following.Method == model.FollowingMethodActivityPub {
+		return document
+	}
+
+	// RULE: Documents that are already in the inbox have already been extracted
+	message := model.NewMessage()
+	if err := service.inboxService.LoadByURL(following.UserID, document.ID(), &message); err == nil {
+		return document
+	} else if !derp.NotFound(err) {
+		derp.Report(derp.Wrap(err, location, "Error searching for existing message", document.ID()))
+		return document
+	}
+
+	pageURL := document.URL()
+
+	if pageURL == "" {
+		return document
+	}
+
+	// RULE: Never load web pages from suspended domains
+	if service.domainPolicyService.IsSuspended(pageURL) {
+		return document
+	}
+
+	// Download the linked web page
+	txn := remote.Get(pageURL).
+		UserAgent("Emissary Social: https://emissary.social").
+		Header("Accept", "text/html")
+
+	if err := txn.Send(); err != nil {
+		derp.Report(derp.Wrap(err, location, "Error loading web page", pageURL))
+		return document
+	}
+
+	body, err := txn.ResponseBody()
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, location, "Error reading web page", pageURL))
+		return document
+	}
+
+	// Extract the article, and sanitize it using the same rules as local content
+	article, err := readability.Extract(bytes.NewReader(body), pageURL)
+
+	if err != nil {
+		return document
+	}
+
+	article = contentPolicy().Sanitize(article)
+
+	// RULE: Only replace content if the article is longer than what we already have
+	if plainTextLength(article) <= plainTextLength(document.Content()) {
+		return document
+	}
+
+	// Update the cached document with the full article
+	result := document.Clone()
+
+	if result.Summary() == "" {
+		result.SetProperty(vocab.PropertySummary, document.Content())
+	}
+
+	result.SetProperty(vocab.PropertyContent, article)
+	service.activityService.Put(result)
+
+	return result
+}
+
+// plainTextLength returns the length of the visible text in an HTML string
+func plainTextLength(value string) int {
+	text := bluemonday.StrictPolicy().Sanitize(value)
+	return len(strings.Join(strings.Fields(text), " "))
+}
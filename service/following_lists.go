@@ -0,0 +1,219 @@
+package service
+
+import (
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/slice"
+	"github.com/benpate/toot/object"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/******************************************
+ * List Membership
+ *
+ * Mastodon lists are stored as Folders.  List membership is a many-to-many
+ * relationship that is stored in each Following's ListIDs, and is independent
+ * of the single FolderID that the Following delivers messages into.
+ ******************************************/
+
+// QueryByList returns all Following records that are members of the provided list
+func (service *Following) QueryByList(userID primitive.ObjectID, listID primitive.ObjectID, criteria exp.Expression) ([]model.FollowingSummary, error) {
+
+	result := make([]model.FollowingSummary, 0)
+	criteria = criteria.
+		AndEqual("userId", userID).
+		AndEqual("listIds", listID)
+
+	err := service.collection.Query(&result, notDeleted(criteria), option.Fields(model.FollowingSummaryFields()...), option.SortAsc("label"))
+	return result, err
+}
+
+// AddToList adds the Following records for each of the provided profile URLs to a list.
+// Accounts must already be followed before they can be added to a list.
+func (service *Following) AddToList(userID primitive.ObjectID, listID primitive.ObjectID, profileURLs []string) error {
+
+	const location = "service.Following.AddToList"
+
+	for _, profileURL := range profileURLs {
+
+		following := model.NewFollowing()
+
+		if err := service.LoadByURL(userID, profileURL, &following); err != nil {
+			return derp.Wrap(err, location, "Account must be followed before adding it to a list", profileURL)
+		}
+
+		if !following.AddList(listID) {
+			continue
+		}
+
+		// Save directly to the collection so that we do not reconnect to the remote server
+		if err := service.collection.Save(&following, "Added to list"); err != nil {
+			return derp.Wrap(err, location, "Error saving Following", following.FollowingID)
+		}
+	}
+
+	return nil
+}
+
+// RemoveFromList removes the Following records for each of the provided profile URLs from a list.
+func (service *Following) RemoveFromList(userID primitive.ObjectID, listID primitive.ObjectID, profileURLs []string) error {
+
+	const location = "service.Following.RemoveFromList"
+
+	for _, profileURL := range profileURLs {
+
+		following := model.NewFollowing()
+
+		if err := service.LoadByURL(userID, profileURL, &following); err != nil {
+
+			// Accounts that are not followed are not in the list, either
+			if derp.NotFound(err) {
+				continue
+			}
+
+			return derp.Wrap(err, location, "Error loading Following", profileURL)
+		}
+
+		if !following.RemoveList(listID) {
+			continue
+		}
+
+		if err := service.collection.Save(&following, "Removed from list"); err != nil {
+			return derp.Wrap(err, location, "Error saving Following", following.FollowingID)
+		}
+	}
+
+	return nil
+}
+
+// RemoveAllFromList removes every member from a list.  This is called when the list is deleted.
+func (service *Following) RemoveAllFromList(userID primitive.ObjectID, listID primitive.ObjectID) error {
+
+	const location = "service.Following.RemoveAllFromList"
+
+	members, err := service.QueryByList(userID, listID, exp.All())
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading list members", listID)
+	}
+
+	profileURLs := slice.Map(members, func(member model.FollowingSummary) string {
+		return member.ProfileURL
+	})
+
+	return service.RemoveFromList(userID, listID, profileURLs)
+}
+
+/******************************************
+ * List Timelines
+ ******************************************/
+
+// QueryListTimeline returns the Messages posted by the members of a list, honoring the list's replies policy.
+func (service *Following) QueryListTimeline(userID primitive.ObjectID, list *model.Folder, criteria exp.Expression, options ...option.Option) ([]model.Message, error) {
+
+	const location = "service.Following.QueryListTimeline"
+
+	// Find all members of the list
+	members, err := service.QueryByList(userID, list.FolderID, exp.All())
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error loading list members", list.FolderID)
+	}
+
+	if len(members) == 0 {
+		return make([]model.Message, 0), nil
+	}
+
+	memberIDs := slice.Map(members, func(member model.FollowingSummary) primitive.ObjectID {
+		return member.FollowingID
+	})
+
+	memberURLs := slice.Map(members, func(member model.FollowingSummary) string {
+		return member.ProfileURL
+	})
+
+	// Limit results to Messages from list members
+	criteria = criteria.AndIn("origin.followingId", memberIDs)
+
+	// Apply the list's replies policy
+	repliesCriteria, err := service.listRepliesCriteria(userID, list.RepliesPolicy, memberURLs)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error loading replies policy", list.FolderID)
+	}
+
+	messages, err := service.inboxService.QueryByUserID(userID, criteria.And(repliesCriteria), options...)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error loading messages", list.FolderID)
+	}
+
+	return messages, nil
+}
+
+// QueryExclusiveListMemberIDs returns the IDs of all Following records that are members of
+// an "exclusive" list.  Messages from these Following records are hidden from the home timeline.
+func (service *Following) QueryExclusiveListMemberIDs(userID primitive.ObjectID) ([]primitive.ObjectID, error) {
+
+	const location = "service.Following.QueryExclusiveListMemberIDs"
+
+	lists, err := service.folderService.Query(exp.Equal("userId", userID).AndEqual("exclusive", true))
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error loading exclusive lists", userID)
+	}
+
+	if len(lists) == 0 {
+		return make([]primitive.ObjectID, 0), nil
+	}
+
+	listIDs := slice.Map(lists, func(list model.Folder) primitive.ObjectID {
+		return list.FolderID
+	})
+
+	members := make([]model.FollowingSummary, 0)
+	criteria := exp.Equal("userId", userID).AndIn("listIds", listIDs)
+
+	if err := service.collection.Query(&members, notDeleted(criteria), option.Fields("_id")); err != nil {
+		return nil, derp.Wrap(err, location, "Error loading exclusive list members", userID)
+	}
+
+	return slice.Map(members, func(member model.FollowingSummary) primitive.ObjectID {
+		return member.FollowingID
+	}), nil
+}
+
+// listRepliesCriteria returns the database criteria for the replies shown in a list timeline
+// with the provided replies policy.  Replies are matched using the author of the message
+// being replied to, which is recorded when each Message is saved.
+func (service *Following) listRepliesCriteria(userID primitive.ObjectID, repliesPolicy string, memberURLs []string) (exp.Expression, error) {
+
+	const location = "service.Following.listRepliesCriteria"
+
+	// Messages that are not replies are always allowed
+	notReply := exp.Equal("inReplyTo", nil)
+
+	switch repliesPolicy {
+
+	case object.ListRepliesPolicyNone:
+		return notReply, nil
+
+	case object.ListRepliesPolicyList:
+		return exp.Or(notReply, exp.In("inReplyToAuthor", memberURLs)), nil
+	}
+
+	// Default policy ("followed") allows replies to anyone the User follows
+	followings, err := service.QueryByUser(userID)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error loading followed accounts", userID)
+	}
+
+	followedURLs := slice.Map(followings, func(following model.FollowingSummary) string {
+		return following.ProfileURL
+	})
+
+	return exp.Or(notReply, exp.In("inReplyToAuthor", followedURLs)), nil
+}
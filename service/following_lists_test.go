@@ -0,0 +1,27 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/benpate/exp"
+	"github.com/benpate/toot/object"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestListRepliesCriteria(t *testing.T) {
+
+	service := Following{}
+	userID := primitive.NewObjectID()
+	members := []string{"https://remote.social/users/alice", "https://other.social/@bob"}
+
+	// Lists without replies only show messages that are not replies
+	criteria, err := service.listRepliesCriteria(userID, object.ListRepliesPolicyNone, members)
+	require.Nil(t, err)
+	require.Equal(t, exp.Equal("inReplyTo", nil), criteria)
+
+	// List replies are matched against the recorded author of the message being replied to
+	criteria, err = service.listRepliesCriteria(userID, object.ListRepliesPolicyList, members)
+	require.Nil(t, err)
+	require.Equal(t, exp.Or(exp.Equal("inReplyTo", nil), exp.In("inReplyToAuthor", members)), criteria)
+}
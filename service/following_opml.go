@@ -0,0 +1,297 @@
+package service
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/first"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// opmlDocument is the root element of an OPML 2.0 document (http://opml.org/spec2.opml)
+type opmlDocument struct {
+	XMLName xml.Name      `xml:"opml"`
+	Version string        `xml:"version,attr"`
+	Head    opmlHead      `xml:"head"`
+	Body    []opmlOutline `xml:"body>outline"`
+}
+
+// opmlHead contains the metadata for an OPML document
+type opmlHead struct {
+	Title       string `xml:"title,omitempty"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+	OwnerName   string `xml:"ownerName,omitempty"`
+	OwnerID     string `xml:"ownerId,omitempty"`
+	Docs        string `xml:"docs,omitempty"`
+}
+
+// opmlOutline is a single (possibly nested) outline element.  Outlines with an xmlUrl
+// are feed subscriptions.  Outlines without one are treated as categories.
+type opmlOutline struct {
+	Type        string        `xml:"type,attr,omitempty"`
+	Text        string        `xml:"text,attr"`
+	Title       string        `xml:"title,attr,omitempty"`
+	XMLURL      string        `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL     string        `xml:"htmlUrl,attr,omitempty"`
+	URL         string        `xml:"url,attr,omitempty"`
+	Description string        `xml:"description,attr,omitempty"`
+	Category    string        `xml:"category,attr,omitempty"`
+	Outlines    []opmlOutline `xml:"outline"`
+}
+
+// opmlFeed is a single subscription read from an OPML file
+type opmlFeed struct {
+	Folder string
+	Label  string
+	URL    string
+	Notes  string
+}
+
+// ExportOPML writes a User's Following records into an OPML 2.0 file, with one category per Folder.
+// If publicOnly is TRUE, then only Following records marked IsPublic are included (i.e. a public blogroll)
+func (service *Following) ExportOPML(user *model.User, publicOnly bool, writer io.Writer) error {
+
+	const location = "service.Following.ExportOPML"
+
+	var criteria exp.Expression = exp.Equal("userId", user.UserID)
+
+	if publicOnly {
+		criteria = criteria.AndEqual("isPublic", true)
+	}
+
+	followings, err := service.Query(criteria, option.SortAsc("folder"), option.SortAsc("label"))
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading Following records", user.UserID)
+	}
+
+	document := opmlDocument{
+		Version: "2.0",
+		Head: opmlHead{
+			Title:       user.DisplayName,
+			DateCreated: time.Now().UTC().Format(time.RFC1123Z),
+			OwnerName:   user.DisplayName,
+			OwnerID:     user.ActivityPubURL(),
+			Docs:        "http://opml.org/spec2.opml",
+		},
+		Body: opmlOutlines(followings, publicOnly),
+	}
+
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return derp.Wrap(err, location, "Error writing OPML header")
+	}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "\t")
+
+	if err := encoder.Encode(document); err != nil {
+		return derp.Wrap(err, location, "Error writing OPML file")
+	}
+
+	return nil
+}
+
+// opmlOutlines groups Following records into one outline per Folder.  Public blogrolls
+// do not reveal the User's folder names or private notes, so they are returned as a flat list.
+func opmlOutlines(followings []model.Following, publicOnly bool) []opmlOutline {
+
+	result := make([]opmlOutline, 0, len(followings))
+	folderIndex := make(map[string]int)
+
+	for _, following := range followings {
+
+		outline := opmlOutline{
+			Type:    "rss",
+			Text:    following.Label,
+			Title:   following.Label,
+			XMLURL:  following.ProfileURL,
+			HTMLURL: following.URL,
+		}
+
+		// Notes are private, so they are not included in public blogrolls
+		if !publicOnly {
+			outline.Description = following.Notes
+		}
+
+		// ActivityPub actors are not feeds, so export them as links
+		if following.Method == model.FollowingMethodActivityPub {
+			outline.Type = "link"
+			outline.XMLURL = ""
+			outline.URL = following.ProfileURL
+		}
+
+		if publicOnly || (following.Folder == "") {
+			result = append(result, outline)
+			continue
+		}
+
+		if index, ok := folderIndex[following.Folder]; ok {
+			result[index].Outlines = append(result[index].Outlines, outline)
+			continue
+		}
+
+		folderIndex[following.Folder] = len(result)
+		result = append(result, opmlOutline{
+			Text:     following.Folder,
+			Title:    following.Folder,
+			Outlines: []opmlOutline{outline},
+		})
+	}
+
+	return result
+}
+
+// ImportOPML reads an OPML file and follows every feed that the User does not already follow.
+// Outline categories are mapped onto Folders (by label), creating new Folders when needed.
+// Feeds without a category are placed into the User's first Folder.  New Following records
+// are saved in the background queue.  It returns the number of Following records that were queued.
+func (service *Following) ImportOPML(userID primitive.ObjectID, reader io.Reader) (int, error) {
+
+	const location = "service.Following.ImportOPML"
+
+	feeds, err := parseOPML(reader)
+
+	if err != nil {
+		return 0, derp.Wrap(err, location, "Error reading OPML file", derp.WithBadRequest())
+	}
+
+	// Load the User's existing folders
+	folders, err := service.folderService.QueryByUserID(userID)
+
+	if err != nil {
+		return 0, derp.Wrap(err, location, "Error loading folders", userID)
+	}
+
+	if len(folders) == 0 {
+		return 0, derp.NewBadRequestError(location, "User must have at least one folder", userID)
+	}
+
+	folderIDs := make(map[string]primitive.ObjectID, len(folders))
+	for _, folder := range folders {
+		folderIDs[strings.ToLower(folder.Label)] = folder.FolderID
+	}
+
+	count := 0
+
+	for _, feed := range feeds {
+
+		// Skip feeds that are already being followed
+		existing := model.NewFollowing()
+		if err := service.LoadByURL(userID, feed.URL, &existing); err == nil {
+			continue
+		}
+
+		if err := service.Load(exp.Equal("userId", userID).AndEqual("url", feed.URL), &existing); err == nil {
+			continue
+		}
+
+		// Find (or create) the Folder for this feed
+		folderID := folders[0].FolderID
+
+		if feed.Folder != "" {
+
+			if existingID, ok := folderIDs[strings.ToLower(feed.Folder)]; ok {
+				folderID = existingID
+
+			} else {
+
+				folder := model.NewFolder()
+				folder.UserID = userID
+				folder.Label = feed.Folder
+				folder.Layout = model.FolderLayoutNewspaper
+				folder.Rank = len(folderIDs)
+
+				if err := service.folderService.Save(&folder, "Imported from OPML"); err != nil {
+					return count, derp.Wrap(err, location, "Error creating folder", feed.Folder)
+				}
+
+				folderIDs[strings.ToLower(folder.Label)] = folder.FolderID
+				folderID = folder.FolderID
+			}
+		}
+
+		// Queue the new Following record.  Saving it will connect to the remote feed.
+		following := model.NewFollowing()
+		following.UserID = userID
+		following.FolderID = folderID
+		following.Label = feed.Label
+		following.Notes = feed.Notes
+		following.URL = feed.URL
+
+		service.queue.Push(NewTaskImportFollowing(service, following, "Imported from OPML"))
+		count++
+	}
+
+	return count, nil
+}
+
+// parseOPML reads all feed subscriptions from an OPML document.  Each feed is
+// assigned the label of the top-level category that contains it (if any).
+func parseOPML(reader io.Reader) ([]opmlFeed, error) {
+
+	const location = "service.parseOPML"
+
+	document := opmlDocument{}
+
+	decoder := xml.NewDecoder(reader)
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+
+	if err := decoder.Decode(&document); err != nil {
+		return nil, derp.Wrap(err, location, "Invalid OPML document")
+	}
+
+	result := make([]opmlFeed, 0)
+	seen := make(map[string]bool)
+
+	var walk func(outlines []opmlOutline, folder string)
+
+	walk = func(outlines []opmlOutline, folder string) {
+
+		for _, outline := range outlines {
+
+			feedURL := strings.TrimSpace(outline.XMLURL)
+
+			if (feedURL == "") && strings.EqualFold(outline.Type, "link") {
+				feedURL = strings.TrimSpace(outline.URL)
+			}
+
+			// Outlines without a URL are categories
+			if feedURL == "" {
+				walk(outline.Outlines, first.String(folder, strings.TrimSpace(outline.Text), strings.TrimSpace(outline.Title)))
+				continue
+			}
+
+			if seen[feedURL] {
+				continue
+			}
+
+			seen[feedURL] = true
+
+			// The "category" attribute is a comma-separated list of slash-delimited paths
+			category := folder
+			if category == "" {
+				category, _, _ = strings.Cut(outline.Category, ",")
+				category = strings.Trim(strings.TrimSpace(category), "/")
+				category, _, _ = strings.Cut(category, "/")
+			}
+
+			result = append(result, opmlFeed{
+				Folder: category,
+				Label:  first.String(strings.TrimSpace(outline.Title), strings.TrimSpace(outline.Text), feedURL),
+				URL:    feedURL,
+				Notes:  strings.TrimSpace(outline.Description),
+			})
+		}
+	}
+
+	walk(document.Body, "")
+	return result, nil
+}
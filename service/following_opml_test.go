@@ -0,0 +1,67 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOPML(t *testing.T) {
+
+	body := `<?xml version="1.0" encoding="ISO-8859-1"?>
+<opml version="2.0">
+	<head><title>Subscriptions</title></head>
+	<body>
+		<outline text="News">
+			<outline type="rss" text="First" title="First Feed" xmlUrl="https://first.example/feed.xml" htmlUrl="https://first.example" description="Daily"/>
+			<outline text="Nested">
+				<outline type="rss" text="Second" xmlUrl="https://second.example/rss"/>
+			</outline>
+		</outline>
+		<outline type="rss" text="Third" xmlUrl="https://third.example/atom" category="/Tech/Go,/Other"/>
+		<outline type="link" text="Actor" url="https://social.example/@actor"/>
+		<outline type="rss" text="Duplicate" xmlUrl="https://first.example/feed.xml"/>
+	</body>
+</opml>`
+
+	feeds, err := parseOPML(strings.NewReader(body))
+	require.Nil(t, err)
+	require.Equal(t, []opmlFeed{
+		{Folder: "News", Label: "First Feed", URL: "https://first.example/feed.xml", Notes: "Daily"},
+		{Folder: "News", Label: "Second", URL: "https://second.example/rss"},
+		{Folder: "Tech", Label: "Third", URL: "https://third.example/atom"},
+		{Folder: "", Label: "Actor", URL: "https://social.example/@actor"},
+	}, feeds)
+}
+
+func TestParseOPML_Invalid(t *testing.T) {
+	_, err := parseOPML(strings.NewReader("this is not xml"))
+	require.NotNil(t, err)
+}
+
+func TestOPMLOutlines(t *testing.T) {
+
+	followings := []model.Following{
+		{Folder: "News", Label: "First", ProfileURL: "https://first.example/feed.xml", URL: "https://first.example", Notes: "Private note"},
+		{Folder: "News", Label: "Second", ProfileURL: "https://second.example/rss"},
+		{Folder: "", Label: "Actor", ProfileURL: "https://social.example/@actor", Method: model.FollowingMethodActivityPub},
+	}
+
+	// Private exports include folders and notes
+	outlines := opmlOutlines(followings, false)
+	require.Equal(t, 2, len(outlines))
+	require.Equal(t, "News", outlines[0].Text)
+	require.Equal(t, "Private note", outlines[0].Outlines[0].Description)
+	require.Equal(t, "https://second.example/rss", outlines[0].Outlines[1].XMLURL)
+	require.Equal(t, "link", outlines[1].Type)
+	require.Equal(t, "https://social.example/@actor", outlines[1].URL)
+
+	// Public blogrolls do not reveal folders or notes
+	outlines = opmlOutlines(followings, true)
+	require.Equal(t, 3, len(outlines))
+	require.Equal(t, "First", outlines[0].Text)
+	require.Equal(t, "", outlines[0].Description)
+	require.Empty(t, outlines[0].Outlines)
+}
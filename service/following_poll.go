@@ -0,0 +1,248 @@
+package service
+
+import (
+	"bytes"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/ascache"
+	"github.com/benpate/derp"
+	"github.com/benpate/remote"
+	"github.com/benpate/rosetta/sliceof"
+	"github.com/benpate/sherlock"
+	"github.com/mmcdole/gofeed"
+	"github.com/mmcdole/gofeed/rss"
+)
+
+// followingPollMinimum is the shortest time between polls of a single feed
+const followingPollMinimum = time.Hour
+
+// followingPollMaximum is the longest time between polls of a single feed
+const followingPollMaximum = 7 * 24 * time.Hour
+
+// followingPollDefault is used when a feed's publishing rate cannot be determined
+const followingPollDefault = 24 * time.Hour
+
+// Poll checks a Following record for updates.  Feeds that are polled use a conditional
+// GET request (If-None-Match / If-Modified-Since) so that unchanged feeds are not
+// downloaded and re-processed.  Changed feeds are reloaded and imported via Connect,
+// and the next poll is scheduled based on the feed's observed publishing rate.
+func (service *Following) Poll(following model.Following) error {
+
+	const location = "service.Following.Poll"
+
+	// Push services (and records that have not yet found their feed) connect normally
+	if (following.Method != model.FollowingMethodPoll) || (following.FeedURL == "") {
+		return service.Connect(following)
+	}
+
+	now := time.Now()
+
+	txn := remote.Get(following.FeedURL).
+		UserAgent("Emissary Social: https://emissary.social").
+		Header("Accept", followingMimeStack)
+
+	if following.FeedETag != "" {
+		txn.Header("If-None-Match", following.FeedETag)
+	}
+
+	if following.FeedLastModified != "" {
+		txn.Header("If-Modified-Since", following.FeedLastModified)
+	}
+
+	err := txn.Send()
+	statusCode := txn.ResponseStatusCode()
+	header := txn.ResponseHeader()
+
+	following.LastStatusCode = statusCode
+
+	switch {
+
+	// The feed has not changed since the last poll.
+	case statusCode == http.StatusNotModified:
+
+		following.LastPolled = now.Unix()
+		following.BytesSaved += following.ContentLength
+		following.Status = model.FollowingStatusSuccess
+		following.StatusMessage = ""
+		following.ErrorCount = 0
+
+		interval := max(time.Duration(following.PollDuration)*time.Hour, cacheMaxAge(header))
+		following.NextPoll = nextPollTime(now, interval, following.SkipHours).Unix()
+
+		if err := service.collection.Save(&following, "Poll: Not Modified"); err != nil {
+			return derp.Wrap(err, location, "Error saving Following", following.FollowingID)
+		}
+
+		return nil
+
+	// The remote server asked us to slow down
+	case (statusCode == http.StatusTooManyRequests) || (statusCode == http.StatusServiceUnavailable):
+
+		if retryAfter := parseRetryAfter(header.Get("Retry-After"), now); retryAfter > 0 {
+			following.NextPoll = now.Add(min(retryAfter, followingPollMaximum)).Unix()
+		}
+
+		if err := service.SetStatusFailure(&following, "Remote server asked to retry later ("+strconv.Itoa(statusCode)+")"); err != nil {
+			return derp.Wrap(err, location, "Error saving Following", following.FollowingID)
+		}
+
+		return nil
+
+	// Other errors are handled by the regular connection process
+	case err != nil:
+		following.FeedETag = ""
+		following.FeedLastModified = ""
+		return service.Connect(following)
+	}
+
+	// Fall through means that the feed has changed.  Save the validators
+	// for next time, and calculate the next poll from the feed contents.
+	body, _ := txn.ResponseBody()
+
+	following.FeedETag = header.Get("ETag")
+	following.FeedLastModified = header.Get("Last-Modified")
+	following.ContentLength = int64(len(body))
+
+	interval, skipHours := feedPollInterval(body)
+	interval = max(interval, cacheMaxAge(header))
+
+	following.SkipHours = skipHours
+	following.PollDuration = max(int(interval/time.Hour), 1)
+	following.NextPoll = nextPollTime(now, interval, skipHours).Unix()
+
+	// Reload the feed into the cache (reusing the body that was just downloaded), then import new messages
+	preloaded := PreloadedResponse{URL: following.FeedURL, Header: header, Body: body}
+
+	// nolint:errcheck
+	service.activityService.Load(following.URL, sherlock.AsActor(), ascache.WithForceReload(), preloaded)
+
+	return service.connect(following, body)
+}
+
+// feedPollInterval calculates how often a feed should be polled, based on the
+// publish dates of its items and its RSS <ttl>.  It also returns the
+// RSS <skipHours> values (if any).
+func feedPollInterval(body []byte) (time.Duration, sliceof.Int) {
+
+	skipHours := sliceof.NewInt()
+
+	feed, err := gofeed.NewParser().Parse(bytes.NewReader(body))
+
+	if err != nil {
+		return followingPollDefault, skipHours
+	}
+
+	// Collect publish dates from the feed
+	dates := make([]time.Time, 0, len(feed.Items))
+	for _, item := range feed.Items {
+		if item.PublishedParsed != nil {
+			dates = append(dates, *item.PublishedParsed)
+		} else if item.UpdatedParsed != nil {
+			dates = append(dates, *item.UpdatedParsed)
+		}
+	}
+
+	interval := publishingInterval(dates)
+
+	// RSS feeds may also include <ttl> and <skipHours>
+	if feed.FeedType == "rss" {
+		if rssFeed, err := (&rss.Parser{}).Parse(bytes.NewReader(body)); err == nil {
+
+			if ttl, err := strconv.Atoi(strings.TrimSpace(rssFeed.TTL)); err == nil {
+				interval = max(interval, time.Duration(ttl)*time.Minute)
+			}
+
+			for _, value := range rssFeed.SkipHours {
+				if hour, err := strconv.Atoi(strings.TrimSpace(value)); (err == nil) && (hour >= 0) && (hour < 24) {
+					skipHours = append(skipHours, hour)
+				}
+			}
+		}
+	}
+
+	return min(interval, followingPollMaximum), skipHours
+}
+
+// publishingInterval returns a polling interval that is half of the average time
+// between the ten most recent publish dates.  This means that frequently updated feeds
+// are polled often, and dormant feeds are polled rarely.
+func publishingInterval(dates []time.Time) time.Duration {
+
+	if len(dates) < 2 {
+		return followingPollDefault
+	}
+
+	// Sort newest first, and only use the most recent items
+	sort.Slice(dates, func(a int, b int) bool {
+		return dates[a].After(dates[b])
+	})
+
+	if len(dates) > 10 {
+		dates = dates[:10]
+	}
+
+	average := dates[0].Sub(dates[len(dates)-1]) / time.Duration(len(dates)-1)
+
+	return min(max(average/2, followingPollMinimum), followingPollMaximum)
+}
+
+// nextPollTime returns the time of the next poll, skipping any hours that the feed has
+// asked us to avoid.
+func nextPollTime(now time.Time, interval time.Duration, skipHours []int) time.Time {
+
+	result := now.Add(min(max(interval, followingPollMinimum), followingPollMaximum))
+
+	// If every hour is skipped, then the feed is asking for the impossible.
+	if len(skipHours) >= 24 {
+		return result
+	}
+
+	for slices.Contains(skipHours, result.UTC().Hour()) {
+		result = result.Add(time.Hour).Truncate(time.Hour)
+	}
+
+	return result
+}
+
+// cacheMaxAge returns the max-age (or s-maxage) value from a Cache-Control header
+func cacheMaxAge(header http.Header) time.Duration {
+
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+
+		if (name == "max-age") || (name == "s-maxage") {
+			if seconds, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil {
+				return time.Duration(seconds) * time.Second
+			}
+		}
+	}
+
+	return 0
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of seconds or an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+
+	value = strings.TrimSpace(value)
+
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return date.Sub(now)
+	}
+
+	return 0
+}
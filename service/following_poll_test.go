@@ -0,0 +1,94 @@
+package service
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishingInterval(t *testing.T) {
+
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	// Not enough items uses the default
+	require.Equal(t, followingPollDefault, publishingInterval(nil))
+	require.Equal(t, followingPollDefault, publishingInterval([]time.Time{now}))
+
+	// One post per day => poll every 12 hours
+	daily := []time.Time{now.Add(-48 * time.Hour), now, now.Add(-24 * time.Hour)}
+	require.Equal(t, 12*time.Hour, publishingInterval(daily))
+
+	// Frequent posts are clamped to the minimum
+	frequent := []time.Time{now, now.Add(-time.Minute), now.Add(-2 * time.Minute)}
+	require.Equal(t, followingPollMinimum, publishingInterval(frequent))
+
+	// Dormant feeds are clamped to the maximum
+	dormant := []time.Time{now, now.AddDate(-1, 0, 0)}
+	require.Equal(t, followingPollMaximum, publishingInterval(dormant))
+}
+
+func TestNextPollTime(t *testing.T) {
+
+	now := time.Date(2026, 1, 10, 12, 30, 0, 0, time.UTC)
+
+	require.Equal(t, now.Add(2*time.Hour), nextPollTime(now, 2*time.Hour, nil))
+	require.Equal(t, now.Add(followingPollMinimum), nextPollTime(now, time.Minute, nil))
+
+	// Skipped hours move the poll to the top of the next allowed hour
+	require.Equal(t, time.Date(2026, 1, 10, 16, 0, 0, 0, time.UTC), nextPollTime(now, 2*time.Hour, []int{14, 15}))
+
+	// Skipping every hour is ignored
+	all := make([]int, 24)
+	for index := range all {
+		all[index] = index
+	}
+	require.Equal(t, now.Add(2*time.Hour), nextPollTime(now, 2*time.Hour, all))
+}
+
+func TestCacheMaxAge(t *testing.T) {
+
+	header := http.Header{}
+	require.Zero(t, cacheMaxAge(header))
+
+	header.Set("Cache-Control", "public, max-age=3600")
+	require.Equal(t, time.Hour, cacheMaxAge(header))
+
+	header.Set("Cache-Control", `s-maxage="120", no-transform`)
+	require.Equal(t, 2*time.Minute, cacheMaxAge(header))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	require.Zero(t, parseRetryAfter("", now))
+	require.Zero(t, parseRetryAfter("soon", now))
+	require.Equal(t, 90*time.Second, parseRetryAfter("90", now))
+	require.Equal(t, 30*time.Minute, parseRetryAfter("Sat, 10 Jan 2026 12:30:00 GMT", now))
+}
+
+func TestFeedPollInterval(t *testing.T) {
+
+	body := `<?xml version="1.0"?>
+<rss version="2.0">
+	<channel>
+		<title>Example</title>
+		<link>https://example.com</link>
+		<ttl>1440</ttl>
+		<skipHours><hour>1</hour><hour>2</hour><hour>99</hour></skipHours>
+		<item><title>One</title><pubDate>Sat, 10 Jan 2026 12:00:00 GMT</pubDate></item>
+		<item><title>Two</title><pubDate>Sat, 10 Jan 2026 10:00:00 GMT</pubDate></item>
+	</channel>
+</rss>`
+
+	interval, skipHours := feedPollInterval([]byte(body))
+	require.Equal(t, 24*time.Hour, interval)
+	require.Equal(t, []int{1, 2}, []int(skipHours))
+
+	// Unreadable feeds use the default
+	interval, skipHours = feedPollInterval([]byte("not a feed"))
+	require.Equal(t, followingPollDefault, interval)
+	require.Empty(t, skipHours)
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/sliceof"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StartPurge begins the background sweeper that removes expired inbox Messages
+// from every Following, according to each Following's RetentionPolicy.
+func (service *Following) StartPurge() {
+
+	const location = "service.Following.StartPurge"
+
+	// Wait until the service has booted up correctly.
+	for service.collection == nil {
+		time.Sleep(1 * time.Minute)
+	}
+
+	for {
+
+		// Sweep randomly between 1 and 2 hours
+		time.Sleep(time.Duration(rand.Intn(60)+60) * time.Minute)
+
+		// If (for some reason) the service collection is still nil, then
+		// wait this one out.
+		if service.collection == nil {
+			continue
+		}
+
+		it, err := service.List(exp.All())
+
+		if err != nil {
+			derp.Report(derp.Wrap(err, location, "Error listing following"))
+			continue
+		}
+
+		report := model.RetentionReport{}
+		following := model.NewFollowing()
+
+		for it.Next(&following) {
+			select {
+
+			// If we're done, we're done.
+			case <-service.closed:
+				return
+
+			default:
+
+				result, err := service.PurgeInbox(following, false)
+
+				if err != nil {
+					derp.Report(derp.Wrap(err, location, "Error purging inbox"))
+				}
+
+				report.Add(result)
+			}
+
+			following = model.NewFollowing()
+		}
+
+		log.Debug().Int("read", report.Read).Int("unread", report.Unread).Int("kept", report.Kept).Msg("Following: purged expired inbox messages")
+	}
+}
+
+// RetentionPolicy returns the effective RetentionPolicy for a Following,
+// combining its own rules with those of its Folder and the system defaults.
+func (service *Following) RetentionPolicy(following *model.Following) model.RetentionPolicy {
+
+	folder := model.NewFolder()
+
+	if err := service.folderService.LoadByID(following.UserID, following.FolderID, &folder); err != nil {
+		return following.Retention.Inherit(model.DefaultRetentionPolicy())
+	}
+
+	return following.Retention.Inherit(folder.Retention).Inherit(model.DefaultRetentionPolicy())
+}
+
+// PurgeInbox removes all inbox Messages from a Following that have expired under its RetentionPolicy.
+// Messages that the User has responded to are kept unless the policy says otherwise.  If dryRun
+// is TRUE, then no Messages are removed, and the report shows what WOULD have been purged.
+func (service *Following) PurgeInbox(following model.Following, dryRun bool) (model.RetentionReport, error) {
+
+	const location = "service.Following.PurgeInbox"
+
+	report := model.RetentionReport{}
+	policy := service.RetentionPolicy(&following)
+
+	if policy.IsForever() {
+		return report, nil
+	}
+
+	// Find all expired Messages from this Following
+	messages, err := service.inboxService.QueryPurgeable(&following, policy, time.Now())
+
+	if err != nil {
+		return report, derp.Wrap(err, location, "Error querying purgeable messages", following.FollowingID)
+	}
+
+	if len(messages) == 0 {
+		return report, nil
+	}
+
+	// Find Messages that the User has responded to
+	responded := sliceof.NewString()
+
+	if !policy.PurgeResponses {
+
+		urls := make([]string, len(messages))
+		for index, message := range messages {
+			urls[index] = message.URL
+		}
+
+		if responded, err = service.inboxService.RespondedURLs(following.UserID, urls); err != nil {
+			return report, derp.Wrap(err, location, "Error querying responses", following.FollowingID)
+		}
+	}
+
+	// Purge each Message that has expired
+	for _, message := range messages {
+
+		if (message.URL != "") && responded.Contains(message.URL) {
+			report.Kept++
+			continue
+		}
+
+		if message.IsRead() {
+			report.Read++
+		} else {
+			report.Unread++
+		}
+
+		if dryRun {
+			continue
+		}
+
+		if err := service.inboxService.Delete(&message, "Purged by retention policy"); err != nil {
+			return report, derp.Wrap(err, location, "Error purging message", message.MessageID)
+		}
+	}
+
+	// Purging unread Messages changes the Folder's unread count
+	if (report.Unread > 0) && !dryRun {
+		if err := service.folderService.CalculateUnreadCount(following.UserID, following.FolderID); err != nil {
+			return report, derp.Wrap(err, location, "Error recalculating unread count", following.FolderID)
+		}
+	}
+
+	return report, nil
+}
+
+// RetentionReportByFolder returns a dry-run report of the Messages that would be
+// purged from every Following in the provided Folder.
+func (service *Following) RetentionReportByFolder(userID primitive.ObjectID, folderID primitive.ObjectID) (model.RetentionReport, error) {
+
+	const location = "service.Following.RetentionReportByFolder"
+
+	report := model.RetentionReport{}
+	followings, err := service.Query(exp.Equal("userId", userID).AndEqual("folderId", folderID))
+
+	if err != nil {
+		return report, derp.Wrap(err, location, "Error loading Following records", folderID)
+	}
+
+	for _, following := range followings {
+
+		result, err := service.PurgeInbox(following, true)
+
+		if err != nil {
+			return report, derp.Wrap(err, location, "Error calculating retention report", following.FollowingID)
+		}
+
+		report.Add(result)
+	}
+
+	return report, nil
+}
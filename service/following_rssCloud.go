@@ -0,0 +1,145 @@
+package service
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/remote"
+	"github.com/mmcdole/gofeed/rss"
+)
+
+// rssCloudResult is the response returned by an RSSCloud server
+type rssCloudResult struct {
+	Success bool   `xml:"success,attr"`
+	Message string `xml:"msg,attr"`
+}
+
+// connect_RSSCloud registers for update notifications with the RSSCloud server
+// listed in the feed's <cloud> element.
+// https://www.rssboard.org/rsscloud-interface
+func (service *Following) connect_RSSCloud(following *model.Following, feedBody []byte) (bool, error) {
+
+	const location = "service.Following.connect_RSSCloud"
+
+	endpoint, ok := feedCloudEndpoint(feedBody)
+
+	if !ok {
+		return false, nil
+	}
+
+	// Parse our own callback URL into the parts that RSSCloud requires
+	callbackURL, err := url.Parse(service.rssCloudCallbackURL(following))
+
+	if err != nil {
+		return false, derp.Wrap(err, location, "Error parsing callback URL", following.FollowingID)
+	}
+
+	port := callbackURL.Port()
+
+	if port == "" {
+		port = "443"
+		if callbackURL.Scheme == "http" {
+			port = "80"
+		}
+	}
+
+	protocol := "http-post"
+
+	if callbackURL.Scheme == "https" {
+		protocol = "https-post"
+	}
+
+	// Send the registration request to the cloud server.  Including our domain
+	// means that the server will verify the subscription with a challenge.
+	var response string
+
+	transaction := remote.Post(endpoint).
+		Form("notifyProcedure", "").
+		Form("domain", callbackURL.Hostname()).
+		Form("port", port).
+		Form("path", callbackURL.Path).
+		Form("protocol", protocol).
+		Form("url1", following.FeedURL).
+		Result(&response)
+
+	if err := transaction.Send(); err != nil {
+		return false, derp.Wrap(err, location, "Error sending RSSCloud registration", endpoint)
+	}
+
+	result := rssCloudResult{}
+
+	if err := xml.Unmarshal([]byte(response), &result); err != nil {
+		return false, derp.Wrap(err, location, "Error parsing RSSCloud response", endpoint, response)
+	}
+
+	if !result.Success {
+		return false, derp.NewBadRequestError(location, "RSSCloud registration failed", endpoint, result.Message)
+	}
+
+	// RSSCloud registrations expire after 25 hours, so renew every day
+	following.Method = model.FollowingMethodRSSCloud
+	following.PollDuration = 24
+	following.NextPoll = time.Now().Add(24 * time.Hour).Unix()
+
+	if err := service.collection.Save(following, "Connected via RSSCloud"); err != nil {
+		return false, derp.Wrap(err, location, "Error saving Following", following.FollowingID)
+	}
+
+	return true, nil
+}
+
+// rssCloudCallbackURL returns the URL where an RSSCloud server will send update notifications
+func (service *Following) rssCloudCallbackURL(following *model.Following) string {
+	return service.host + "/.rsscloud/" + following.UserID.Hex() + "/" + following.FollowingID.Hex()
+}
+
+// feedCloudEndpoint returns the registration URL from an RSS feed's <cloud> element.
+// Only the REST (http-post) protocol is supported, so the second value is FALSE
+// if the feed does not include a usable <cloud> element.
+func feedCloudEndpoint(feedBody []byte) (string, bool) {
+
+	if len(feedBody) == 0 {
+		return "", false
+	}
+
+	feed, err := (&rss.Parser{}).Parse(bytes.NewReader(feedBody))
+
+	if (err != nil) || (feed.Cloud == nil) {
+		return "", false
+	}
+
+	cloud := feed.Cloud
+
+	if (cloud.Domain == "") || !strings.HasSuffix(cloud.Protocol, "-post") {
+		return "", false
+	}
+
+	result := url.URL{
+		Scheme: "http",
+		Host:   cloud.Domain,
+		Path:   "/" + strings.TrimPrefix(cloud.Path, "/"),
+	}
+
+	switch cloud.Port {
+
+	case "", "80":
+
+	case "443":
+		result.Scheme = "https"
+
+	default:
+		result.Host = net.JoinHostPort(cloud.Domain, cloud.Port)
+	}
+
+	if cloud.Protocol == "https-post" {
+		result.Scheme = "https"
+	}
+
+	return result.String(), true
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedCloudEndpoint(t *testing.T) {
+
+	feed := func(cloud string) []byte {
+		return []byte(`<?xml version="1.0"?><rss version="2.0"><channel><title>Example</title><link>https://example.com/</link>` + cloud + `</channel></rss>`)
+	}
+
+	{
+		result, ok := feedCloudEndpoint(feed(`<cloud domain="rpc.example.com" port="80" path="/rsscloud/pleaseNotify" registerProcedure="" protocol="http-post"/>`))
+		require.True(t, ok)
+		require.Equal(t, "http://rpc.example.com/rsscloud/pleaseNotify", result)
+	}
+
+	{
+		result, ok := feedCloudEndpoint(feed(`<cloud domain="rpc.example.com" port="443" path="/stream/rsscloud" registerProcedure="" protocol="http-post"/>`))
+		require.True(t, ok)
+		require.Equal(t, "https://rpc.example.com/stream/rsscloud", result)
+	}
+
+	{
+		result, ok := feedCloudEndpoint(feed(`<cloud domain="rpc.example.com" port="5337" path="/rsscloud/pleaseNotify" registerProcedure="" protocol="http-post"/>`))
+		require.True(t, ok)
+		require.Equal(t, "http://rpc.example.com:5337/rsscloud/pleaseNotify", result)
+	}
+
+	{
+		// XML-RPC and SOAP are not supported
+		_, ok := feedCloudEndpoint(feed(`<cloud domain="rpc.example.com" port="80" path="/RPC2" registerProcedure="pleaseNotify" protocol="xml-rpc"/>`))
+		require.False(t, ok)
+	}
+
+	{
+		_, ok := feedCloudEndpoint(feed(``))
+		require.False(t, ok)
+	}
+
+	{
+		_, ok := feedCloudEndpoint(nil)
+		require.False(t, ok)
+	}
+}
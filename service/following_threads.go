@@ -1,11 +1,17 @@
 package service
 
 import (
+	"strings"
+
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/benpate/derp"
 	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/rosetta/html"
 )
 
+// messageSearchTextLength is the maximum number of characters stored in a Message's search text
+const messageSearchTextLength = 4096
+
 // saveToInbox adds/updates an individual Message based on an RSS item.  It returns TRUE if a new record was created
 func (service *Following) SaveMessage(following *model.Following, document streams.Document, originType string) error {
 
@@ -22,18 +28,66 @@ func (service *Following) SaveMessage(following *model.Following, document strea
 		return nil
 	}
 
+	// RULE: Apply DomainPolicies before saving anything into the inbox
+	if !service.allowMessage(&document, originType) {
+		return nil
+	}
+
 	// Convert the document into a message (and traverse responses if necessary)
 	message := getMessage(following, document, originType)
+	message.InReplyToAuthor = service.getInReplyToAuthor(document)
 
 	// Try to save a unique version of this message to the database (always collapse duplicates)
 	if err := service.saveUniqueMessage(message); err != nil {
 		return derp.Wrap(err, location, "Error saving message")
 	}
 
+	// Deliver public documents to other Users who follow their hashtags
+	service.followedTagService.Deliver(document)
+
 	// Yee. Haw.
 	return nil
 }
 
+// RemoveMessageReference removes a reference (such as a boost) from the Message with the
+// provided URL.  If the Message has no remaining references, then it is removed from the Inbox.
+func (service *Following) RemoveMessageReference(following *model.Following, url string, originType string) error {
+
+	const location = "service.Following.RemoveMessageReference"
+
+	message := model.NewMessage()
+
+	if err := service.inboxService.LoadByURL(following.UserID, url, &message); err != nil {
+
+		if derp.NotFound(err) {
+			return nil
+		}
+
+		return derp.Wrap(err, location, "Error loading message", following.UserID, url)
+	}
+
+	// If the reference is not in the Message, then there's nothing to do
+	if !message.RemoveReference(following.Origin(originType)) {
+		return nil
+	}
+
+	// Remove Messages that no longer have any references
+	if len(message.References) == 0 {
+
+		if err := service.inboxService.Delete(&message, "All references removed"); err != nil {
+			return derp.Wrap(err, location, "Error deleting message", message)
+		}
+
+		return nil
+	}
+
+	if err := service.inboxService.Save(&message, "Reference removed"); err != nil {
+		return derp.Wrap(err, location, "Error saving message", message)
+	}
+
+	return nil
+}
+
 // saveUnique adds/updates a message in the database.  If the message.URL does not already
 // exist, then a new message is added to the Inbox.  Otherwise, the "references" data will
 // of the existing record be updated and the unique value will be re-saved.
@@ -81,6 +135,29 @@ func (service *Following) saveUniqueMessage(message model.Message) error {
 	return nil
 }
 
+// allowMessage applies DomainPolicies to a document before it is saved into an inbox.
+// Documents from suspended domains are never saved.  Documents from silenced domains are
+// only saved when they come directly from a followed account, and not when they are
+// boosted, liked, or replied to.  Media is removed from domains whose media is rejected.
+func (service *Following) allowMessage(document *streams.Document, originType string) bool {
+
+	publicOnly := (originType != model.OriginTypePrimary)
+
+	// Activities (like boosts) are sent by the followed account itself
+	if document.IsActivity() {
+
+		if !service.domainPolicyService.Allow(document, false) {
+			return false
+		}
+
+		// ...but their objects may come from a different domain
+		object := document.UnwrapActivity()
+		return service.domainPolicyService.Allow(&object, publicOnly)
+	}
+
+	return service.domainPolicyService.Allow(document, publicOnly)
+}
+
 /******************************************
  * Helper Functions
  ******************************************/
@@ -142,9 +219,74 @@ func getMessage(following *model.Following, document streams.Document, originTyp
 	result.FolderID = following.FolderID
 	result.SocialRole = document.Type()
 	result.URL = document.ID()
+	result.Domain = domainPolicyHostname(document.ID())
+	result.AttributedTo = getMessageAuthor(document)
+	result.SearchText = getMessageSearchText(document)
+	result.HasAttachment = document.Attachment().NotNil()
 	result.InReplyTo = document.InReplyTo().ID()
 	result.PublishDate = document.Published().Unix()
 	result.AddReference(following.Origin(originType))
 
+	// Only public hashtags and links are shown in hashtag timelines and trends
+	if relayIsPublic(document) {
+		result.Hashtags = DocumentHashtags(document)
+		result.Links = trendLinks(document.Content())
+	}
+
+	return result
+}
+
+// getMessageAuthor returns a PersonLink for the author of a document, using only
+// the data that is already embedded in the document (no additional network requests)
+func getMessageAuthor(document streams.Document) model.PersonLink {
+
+	author := document.AttributedTo()
+
+	return model.PersonLink{
+		Name:       author.Name(),
+		ProfileURL: author.ID(),
+		IconURL:    author.IconOrImage().URL(),
+	}
+}
+
+// getInReplyToAuthor returns the profile URL of the author of the document that the provided
+// document replies to.  This is resolved once, when the message is saved, so that list
+// timelines can filter replies in the database.
+func (service *Following) getInReplyToAuthor(document streams.Document) string {
+
+	inReplyTo := document.InReplyTo()
+
+	if inReplyTo.IsNil() {
+		return ""
+	}
+
+	// Use the author embedded in the document, if it is available
+	if authorID := inReplyTo.AttributedTo().ID(); authorID != "" {
+		return authorID
+	}
+
+	// Otherwise, load the document being replied to
+	parent, err := service.activityService.Load(inReplyTo.ID())
+
+	if err != nil {
+		return ""
+	}
+
+	return parent.AttributedTo().ID()
+}
+
+// getMessageSearchText returns the plain text of a document (name, summary, and content)
+// that smart folders use for keyword searches.
+func getMessageSearchText(document streams.Document) string {
+
+	result := html.ToSearchText(document.Name() + " " + document.Summary() + " " + document.Content())
+	result = html.CollapseWhitespace(result)
+	result = strings.TrimSpace(result)
+
+	// Limit the size of the search index for very long documents
+	if runes := []rune(result); len(runes) > messageSearchTextLength {
+		result = string(runes[:messageSearchTextLength])
+	}
+
 	return result
 }
@@ -35,3 +35,30 @@ func TestGetPrimaryDocument_2(t *testing.T) {
 	require.Equal(t, model.OriginTypeReply, originType)
 	require.Equal(t, "https://document-2.com/", primary.ID())
 }
+
+func TestGetMessage_SmartFolderFields(t *testing.T) {
+
+	following := model.NewFollowing()
+
+	document := streams.NewDocument(map[string]any{
+		vocab.PropertyID:      "https://Remote.Social/posts/1",
+		vocab.PropertyType:    vocab.ObjectTypeNote,
+		vocab.PropertyName:    "Hello",
+		vocab.PropertyContent: "<p>This is <b>important</b> news</p>",
+		vocab.PropertyAttributedTo: map[string]any{
+			vocab.PropertyID:   "https://remote.social/@alice",
+			vocab.PropertyName: "Alice",
+		},
+		vocab.PropertyAttachment: []any{
+			map[string]any{vocab.PropertyURL: "https://remote.social/image.jpg"},
+		},
+	})
+
+	message := getMessage(&following, document, model.OriginTypePrimary)
+
+	require.Equal(t, "remote.social", message.Domain)
+	require.Equal(t, "Alice", message.AttributedTo.Name)
+	require.Equal(t, "https://remote.social/@alice", message.AttributedTo.ProfileURL)
+	require.Equal(t, "Hello This is important news", message.SearchText)
+	require.True(t, message.HasAttachment)
+}
@@ -194,6 +194,8 @@ func (service Icons) Get(name string) string {
 		return service.get("play")
 	case "play-fill":
 		return service.get("play-fill")
+	case "quote":
+		return service.get("quote")
 	case "reply":
 		return service.get("reply")
 	case "reply-fill":
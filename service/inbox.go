@@ -11,17 +11,19 @@ import (
 	"github.com/benpate/derp"
 	"github.com/benpate/exp"
 	"github.com/benpate/rosetta/schema"
+	"github.com/benpate/rosetta/sliceof"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // Inbox manages all Inbox records for a User.  This includes Inbox and Outbox
 type Inbox struct {
-	collection    data.Collection
-	ruleService   *Rule
-	folderService *Folder
-	host          string
-	counter       int
-	mutex         *sync.Mutex
+	collection      data.Collection
+	ruleService     *Rule
+	folderService   *Folder
+	responseService *Response
+	host            string
+	counter         int
+	mutex           *sync.Mutex
 }
 
 // NewInbox returns a fully populated Inbox service
@@ -36,10 +38,11 @@ func NewInbox() Inbox {
  ******************************************/
 
 // Refresh updates any stateful data that is cached inside this service.
-func (service *Inbox) Refresh(collection data.Collection, ruleService *Rule, folderService *Folder, host string) {
+func (service *Inbox) Refresh(collection data.Collection, ruleService *Rule, folderService *Folder, responseService *Response, host string) {
 	service.collection = collection
 	service.ruleService = ruleService
 	service.folderService = folderService
+	service.responseService = responseService
 	service.host = host
 }
 
@@ -452,6 +455,9 @@ func (service *Inbox) recalculateUnreadCounts(message *model.Message) error {
 		return derp.Wrap(err, location, "Error setting unread count")
 	}
 
+	// Update the "unread" counts for all smart folders in the background
+	service.folderService.QueueSmartFolders(message.UserID)
+
 	// Lo hicimos! we did it.
 	return nil
 }
@@ -489,6 +495,35 @@ func (service *Inbox) CountUnreadMessages(userID primitive.ObjectID, folderID pr
 	return int(count), err
 }
 
+// FolderQueryCriteria returns the criteria that selects all of a User's messages that match
+// the provided smart folder query.
+func (service *Inbox) FolderQueryCriteria(userID primitive.ObjectID, query model.FolderQuery) exp.Expression {
+	return exp.Equal("userId", userID).And(query.Expression())
+}
+
+// CountUnreadByFolderQuery counts the number of unread messages for a user that match a smart folder query.
+func (service *Inbox) CountUnreadByFolderQuery(userID primitive.ObjectID, query model.FolderQuery) (int, error) {
+
+	criteria := service.FolderQueryCriteria(userID, query).
+		AndEqual("readDate", math.MaxInt64).
+		AndEqual("deleteDate", 0)
+
+	count, err := service.collection.Count(criteria)
+	return int(count), err
+}
+
+// CountNewByFolderQuery counts the number of messages for a user that match a smart folder query
+// and that were received after the provided date.
+func (service *Inbox) CountNewByFolderQuery(userID primitive.ObjectID, query model.FolderQuery, since int64) (int, error) {
+
+	criteria := service.FolderQueryCriteria(userID, query).
+		AndGreaterThan("createDate", since).
+		AndEqual("deleteDate", 0)
+
+	count, err := service.collection.Count(criteria)
+	return int(count), err
+}
+
 func (service *Inbox) UpdateInboxFolders(userID primitive.ObjectID, followingID primitive.ObjectID, folderID primitive.ObjectID) {
 
 	it, err := service.ListByFollowingID(userID, followingID)
@@ -513,6 +548,32 @@ func (service *Inbox) UpdateInboxFolders(userID primitive.ObjectID, followingID
 	}
 }
 
+// MoveOrigin re-points all messages received from one Following record onto another
+// (for instance, when a followed actor moves to an account that the User already follows)
+func (service *Inbox) MoveOrigin(userID primitive.ObjectID, followingID primitive.ObjectID, target *model.Following) error {
+
+	const location = "service.Inbox.MoveOrigin"
+
+	it, err := service.ListByFollowingID(userID, followingID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Cannot list Activities by following", userID, followingID)
+	}
+
+	message := model.NewMessage()
+	for it.Next(&message) {
+		message.Origin.FollowingID = target.FollowingID
+		message.FolderID = target.FolderID
+
+		if err := service.Save(&message, "MoveOrigin"); err != nil {
+			return derp.Wrap(err, location, "Cannot save Inbox Message", message)
+		}
+		message = model.NewMessage()
+	}
+
+	return nil
+}
+
 func (service *Inbox) DeleteByOrigin(internalID primitive.ObjectID, note string) error {
 	return service.DeleteMany(exp.Equal("origin.followingId", internalID), note)
 }
@@ -536,17 +597,64 @@ func (service *Inbox) DeleteByFolder(userID primitive.ObjectID, folderID primiti
 	return nil
 }
 
-// QueryPurgeable returns a list of Inboxs that are older than the purge date for this following
-// TODO: HIGH: ReadDate is gone.  Need another way to purge messages.
-func (service *Inbox) QueryPurgeable(following *model.Following) ([]model.Message, error) {
+// QueryPurgeable returns all Messages from a Following that have expired under the provided RetentionPolicy.
+// Read Messages expire based on the date they were read, and unread Messages expire based on the date
+// they were received.
+func (service *Inbox) QueryPurgeable(following *model.Following, policy model.RetentionPolicy, now time.Time) ([]model.Message, error) {
+
+	const location = "service.Inbox.QueryPurgeable"
+
+	result := make([]model.Message, 0)
+	criteria := exp.Equal("userId", following.UserID).
+		AndEqual("origin.followingId", following.FollowingID)
+
+	// Read Messages are purged N days after they were read
+	if cutoff := policy.ReadCutoff(now); cutoff > 0 {
 
-	// Purge date is X days before the current date
-	purgeDuration := time.Duration(following.PurgeDuration) * 24 * time.Hour
-	purgeDate := time.Now().Add(0 - purgeDuration).Unix()
+		read, err := service.Query(criteria.AndLessThan("readDate", cutoff))
 
-	// Activities in the INBOX can be purged if they are READ and older than the purge date
-	criteria := exp.GreaterThan("readDate", 0).
-		AndLessThan("readDate", purgeDate)
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Error querying read messages", following.FollowingID)
+		}
+
+		result = append(result, read...)
+	}
+
+	// Unread Messages are purged M days after they were received
+	if cutoff := policy.UnreadCutoff(now); cutoff > 0 {
+
+		unread, err := service.Query(criteria.AndEqual("readDate", math.MaxInt64).AndLessThan("createDate", cutoff))
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Error querying unread messages", following.FollowingID)
+		}
+
+		result = append(result, unread...)
+	}
+
+	return result, nil
+}
+
+// RespondedURLs returns the subset of the provided URLs that the User has responded to
+// (liked, bookmarked, announced, etc.)  Messages with responses are kept by retention policies.
+func (service *Inbox) RespondedURLs(userID primitive.ObjectID, urls []string) (sliceof.String, error) {
+
+	const location = "service.Inbox.RespondedURLs"
+
+	if len(urls) == 0 {
+		return sliceof.NewString(), nil
+	}
+
+	responses, err := service.responseService.Query(exp.Equal("userId", userID).AndIn("object", urls))
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error querying responses", userID)
+	}
+
+	result := make(sliceof.String, 0, len(responses))
+	for _, response := range responses {
+		result = append(result, response.Object)
+	}
 
-	return service.Query(criteria)
+	return result, nil
 }
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"crypto/rsa"
+	"strings"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/digit"
+	"github.com/benpate/domain"
+	"github.com/benpate/hannibal/outbox"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/rosetta/sliceof"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InstanceActor service manages the ActivityPub "Application" actor that represents
+// this Domain as a whole.  The instance actor signs server-to-server requests (such
+// as fetches from servers that require authorized fetch) and is the identity used to
+// subscribe to relays.  It is discoverable via WebFinger as hostname@hostname.
+type InstanceActor struct {
+	domainService       *Domain
+	keyService          *EncryptionKey
+	domainPolicyService *DomainPolicy
+	requestSigner       *RequestSigner
+	host                string
+	hostname            string
+}
+
+// NewInstanceActor returns a fully initialized InstanceActor service
+func NewInstanceActor() InstanceActor {
+	return InstanceActor{}
+}
+
+// Refresh updates any stateful data that is cached inside this service.
+func (service *InstanceActor) Refresh(domainService *Domain, keyService *EncryptionKey, domainPolicyService *DomainPolicy, requestSigner *RequestSigner, host string, hostname string) {
+	service.domainService = domainService
+	service.keyService = keyService
+	service.domainPolicyService = domainPolicyService
+	service.requestSigner = requestSigner
+	service.host = host
+	service.hostname = hostname
+
+	// Register the instance key so that outbound fetches are signed
+	if err := service.register(); err != nil {
+		derp.Report(derp.Wrap(err, "service.InstanceActor.Refresh", "Error registering instance actor key", hostname))
+	}
+}
+
+// Close stops any background processes controlled by this service
+func (service *InstanceActor) Close() {
+	if service.requestSigner != nil {
+		service.requestSigner.Remove(service.hostname)
+	}
+}
+
+/******************************************
+ * URLs and Identifiers
+ ******************************************/
+
+// ActorID returns the ActivityPub ID of the instance actor
+func (service *InstanceActor) ActorID() string {
+	return service.host + "/pub/actor"
+}
+
+// KeyID returns the ID of the instance actor's public key
+func (service *InstanceActor) KeyID() string {
+	return service.ActorID() + "#main-key"
+}
+
+// InboxURL returns the URL of the instance actor's inbox
+func (service *InstanceActor) InboxURL() string {
+	return service.ActorID() + "/inbox"
+}
+
+// OutboxURL returns the URL of the instance actor's outbox
+func (service *InstanceActor) OutboxURL() string {
+	return service.ActorID() + "/outbox"
+}
+
+// Username returns the preferred username of the instance actor, which is the domain name
+func (service *InstanceActor) Username() string {
+	return domain.NameOnly(service.host)
+}
+
+// IsInstanceActor returns TRUE if the provided URL identifies the instance actor
+func (service *InstanceActor) IsInstanceActor(url string) bool {
+	url, _, _ = strings.Cut(url, "#")
+	return url == service.ActorID()
+}
+
+/******************************************
+ * ActivityPub
+ ******************************************/
+
+// JSONLD returns the ActivityPub representation of the instance actor
+func (service *InstanceActor) JSONLD() (mapof.Any, error) {
+
+	const location = "service.InstanceActor.JSONLD"
+
+	encryptionKey := model.NewEncryptionKey()
+
+	if err := service.keyService.LoadByParentID(model.EncryptionKeyTypeInstance, primitive.NilObjectID, &encryptionKey); err != nil {
+		return nil, derp.Wrap(err, location, "Error loading encryption key")
+	}
+
+	domainInfo := service.domainService.Get()
+
+	result := mapof.Any{
+		vocab.AtContext:                 sliceof.String{"https://www.w3.org/ns/activitystreams", "https://w3id.org/security/v1"},
+		vocab.PropertyID:                service.ActorID(),
+		vocab.PropertyType:              vocab.ActorTypeApplication,
+		vocab.PropertyURL:               service.host,
+		vocab.PropertyName:              domainInfo.Label,
+		vocab.PropertyPreferredUsername: service.Username(),
+		vocab.PropertySummary:           domainInfo.Description,
+		vocab.PropertyInbox:             service.InboxURL(),
+		vocab.PropertyOutbox:            service.OutboxURL(),
+		"manuallyApprovesFollowers":     true,
+		// NOTE: No sharedInbox endpoint, so that relays deliver to the instance actor's own inbox
+		vocab.PropertyPublicKey: mapof.Any{
+			vocab.PropertyID:   service.KeyID(),
+			vocab.PropertyType: "Key",
+			"owner":            service.ActorID(),
+			"publicKeyPem":     encryptionKey.PublicPEM,
+		},
+	}
+
+	return result, nil
+}
+
+// ActivityPubActor returns an outbox.Actor that sends activities on behalf of the instance actor
+func (service *InstanceActor) ActivityPubActor() (outbox.Actor, error) {
+
+	privateKey, err := service.privateKey()
+
+	if err != nil {
+		return outbox.Actor{}, derp.Wrap(err, "service.InstanceActor.ActivityPubActor", "Error loading private key")
+	}
+
+	// Deliveries never reach suspended domains.
+	return outbox.NewActor(service.ActorID(), privateKey, outbox.WithClient(service.domainPolicyService.Client(streams.NewDefaultClient()))), nil
+}
+
+// LoadWebFinger returns a WebFinger resource for the instance actor.  The instance actor
+// can be addressed as acct:hostname@hostname, or by its ActivityPub ID.
+func (service *InstanceActor) LoadWebFinger(resource string) (digit.Resource, error) {
+
+	const location = "service.InstanceActor.LoadWebFinger"
+
+	username := service.Username()
+	account := username + "@" + username
+
+	switch strings.TrimPrefix(strings.TrimPrefix(resource, "acct:"), "@") {
+
+	case account, service.ActorID():
+
+		result := digit.NewResource("acct:"+account).
+			Alias(service.ActorID()).
+			Link(digit.RelationTypeSelf, model.MimeTypeActivityPub, service.ActorID()).
+			Link(digit.RelationTypeProfile, model.MimeTypeHTML, service.host)
+
+		return result, nil
+	}
+
+	return digit.Resource{}, derp.NewNotFoundError(location, "Resource is not the instance actor", resource)
+}
+
+/******************************************
+ * Helper Methods
+ ******************************************/
+
+// register loads (or creates) the instance actor's key and registers it with the RequestSigner
+func (service *InstanceActor) register() error {
+
+	if service.requestSigner == nil {
+		return nil
+	}
+
+	privateKey, err := service.privateKey()
+
+	if err != nil {
+		return derp.Wrap(err, "service.InstanceActor.register", "Error loading private key")
+	}
+
+	service.requestSigner.Set(service.hostname, service.KeyID(), privateKey)
+	return nil
+}
+
+// privateKey loads (or creates) the instance actor's private key
+func (service *InstanceActor) privateKey() (*rsa.PrivateKey, error) {
+
+	const location = "service.InstanceActor.privateKey"
+
+	encryptionKey := model.NewEncryptionKey()
+
+	if err := service.keyService.LoadByParentID(model.EncryptionKeyTypeInstance, primitive.NilObjectID, &encryptionKey); err != nil {
+		return nil, derp.Wrap(err, location, "Error loading encryption key")
+	}
+
+	privateKey, err := service.keyService.GetPrivateKey(&encryptionKey)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error extracting private key")
+	}
+
+	return privateKey, nil
+}
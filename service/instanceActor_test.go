@@ -0,0 +1,27 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstanceActorWebFinger(t *testing.T) {
+
+	service := NewInstanceActor()
+	service.host = "https://local.social"
+
+	require.Equal(t, "https://local.social/pub/actor", service.ActorID())
+	require.Equal(t, "local.social", service.Username())
+	require.True(t, service.IsInstanceActor("https://local.social/pub/actor#main-key"))
+	require.False(t, service.IsInstanceActor("https://local.social/@bob"))
+
+	for _, resource := range []string{"acct:local.social@local.social", "local.social@local.social", "https://local.social/pub/actor"} {
+		result, err := service.LoadWebFinger(resource)
+		require.Nil(t, err)
+		require.Equal(t, "acct:local.social@local.social", result.Subject)
+	}
+
+	_, err := service.LoadWebFinger("acct:bob@local.social")
+	require.NotNil(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/tools/dataset"
 	"github.com/benpate/form"
+	"github.com/benpate/hannibal/vocab"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -13,12 +14,14 @@ type LookupProvider struct {
 	registrationService *Registration
 	templateService     *Template
 	themeService        *Theme
+	userService         *User
 	userID              primitive.ObjectID
 }
 
-func NewLookupProvider(folderService *Folder, groupService *Group, registrationService *Registration, templateService *Template, themeService *Theme, userID primitive.ObjectID) LookupProvider {
+func NewLookupProvider(folderService *Folder, groupService *Group, registrationService *Registration, templateService *Template, themeService *Theme, userService *User, userID primitive.ObjectID) LookupProvider {
 	return LookupProvider{
 		themeService:        themeService,
+		userService:         userService,
 		templateService:     templateService,
 		registrationService: registrationService,
 		groupService:        groupService,
@@ -31,6 +34,13 @@ func (service LookupProvider) Group(path string) form.LookupGroup {
 
 	switch path {
 
+	case "domain-policy-severities":
+		return form.NewReadOnlyLookupGroup(
+			form.LookupCode{Value: model.DomainPolicySeveritySuspend, Label: "SUSPEND all federation (reject inbound, stop outbound)"},
+			form.LookupCode{Value: model.DomainPolicySeveritySilence, Label: "SILENCE (hide from public views)"},
+			form.LookupCode{Value: model.DomainPolicySeverityNone, Label: "None (only apply media and content warning policies)"},
+		)
+
 	case "following-behaviors":
 		return form.NewReadOnlyLookupGroup(
 			form.LookupCode{Value: "POSTS+REPLIES", Label: "Posts and Replies"},
@@ -45,6 +55,32 @@ func (service LookupProvider) Group(path string) form.LookupGroup {
 			form.LookupCode{Value: "BLOCK", Label: "BLOCK senders and prevent followers who are blocked by this source (two-way block)"},
 		)
 
+	case "retention-days":
+		return form.NewReadOnlyLookupGroup(
+			form.LookupCode{Value: "0", Label: "Use the default"},
+			form.LookupCode{Value: "1", Label: "1 day"},
+			form.LookupCode{Value: "3", Label: "3 days"},
+			form.LookupCode{Value: "7", Label: "1 week"},
+			form.LookupCode{Value: "14", Label: "2 weeks"},
+			form.LookupCode{Value: "30", Label: "1 month"},
+			form.LookupCode{Value: "90", Label: "3 months"},
+			form.LookupCode{Value: "365", Label: "1 year"},
+			form.LookupCode{Value: "-1", Label: "Forever"},
+		)
+
+	case "report-categories":
+		return form.NewReadOnlyLookupGroup(
+			form.LookupCode{Value: model.ReportCategorySpam, Label: "Spam"},
+			form.LookupCode{Value: model.ReportCategoryViolation, Label: "Breaks the server rules"},
+			form.LookupCode{Value: model.ReportCategoryOther, Label: "Something else"},
+		)
+
+	case "review-decisions":
+		return form.NewReadOnlyLookupGroup(
+			form.LookupCode{Value: model.ReviewDecisionApprove, Label: "Approve"},
+			form.LookupCode{Value: model.ReviewDecisionReject, Label: "Request Changes"},
+		)
+
 	case "rule-actions":
 		return form.NewReadOnlyLookupGroup(
 			form.LookupCode{Value: "LABEL", Label: "LABEL posts that match this rule"},
@@ -65,6 +101,13 @@ func (service LookupProvider) Group(path string) form.LookupGroup {
 	case "folder-icons":
 		return form.NewReadOnlyLookupGroup(dataset.Icons()...)
 
+	case "folder-types":
+		return form.NewReadOnlyLookupGroup(
+			form.LookupCode{Value: model.FolderTypeStandard, Label: "Standard Folder (holds messages from the people I follow)"},
+			form.LookupCode{Value: model.FolderTypeSmart, Label: "Smart Folder (shows all messages that match a search)"},
+			form.LookupCode{Value: model.FolderTypeSearch, Label: "Saved Search (a smart folder that also emails me about new matches)"},
+		)
+
 	case "groups":
 		return NewGroupLookupProvider(service.groupService)
 
@@ -99,6 +142,21 @@ func (service LookupProvider) Group(path string) form.LookupGroup {
 	case "themes":
 		return NewThemeLookupProvider(service.themeService)
 
+	case "users":
+		return NewUserLookupProvider(service.userService)
+
+	case "social-roles":
+		return form.NewReadOnlyLookupGroup(
+			form.LookupCode{Value: "", Label: "Any Type"},
+			form.LookupCode{Value: vocab.ObjectTypeArticle, Label: "Articles"},
+			form.LookupCode{Value: vocab.ObjectTypeNote, Label: "Notes"},
+			form.LookupCode{Value: vocab.ObjectTypePage, Label: "Pages"},
+			form.LookupCode{Value: vocab.ObjectTypeImage, Label: "Images"},
+			form.LookupCode{Value: vocab.ObjectTypeAudio, Label: "Audio"},
+			form.LookupCode{Value: vocab.ObjectTypeVideo, Label: "Video"},
+			form.LookupCode{Value: vocab.ObjectTypeEvent, Label: "Events"},
+		)
+
 	case "signup-templates":
 		return form.ReadOnlyLookupGroup(service.registrationService.List())
 
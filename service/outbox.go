@@ -19,6 +19,7 @@ type Outbox struct {
 	activityService *ActivityStream
 	streamService   *Stream
 	followerService *Follower
+	relayService    *Relay
 	templateService *Template
 	userService     *User
 	domainEmail     *DomainEmail
@@ -38,11 +39,12 @@ func NewOutbox() Outbox {
  ******************************************/
 
 // Refresh updates any stateful data that is cached inside this service.
-func (service *Outbox) Refresh(collection data.Collection, streamService *Stream, activityService *ActivityStream, followerService *Follower, templateService *Template, userService *User, domainEmail *DomainEmail, queue queue.Queue) {
+func (service *Outbox) Refresh(collection data.Collection, streamService *Stream, activityService *ActivityStream, followerService *Follower, relayService *Relay, templateService *Template, userService *User, domainEmail *DomainEmail, queue queue.Queue) {
 	service.collection = collection
 	service.streamService = streamService
 	service.activityService = activityService
 	service.followerService = followerService
+	service.relayService = relayService
 	service.templateService = templateService
 	service.userService = userService
 	service.domainEmail = domainEmail
@@ -6,6 +6,7 @@ import (
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/benpate/derp"
 	"github.com/benpate/hannibal/outbox"
+	"github.com/benpate/hannibal/streams"
 	"github.com/benpate/hannibal/vocab"
 	"github.com/benpate/rosetta/mapof"
 	"github.com/rs/zerolog/log"
@@ -43,7 +44,9 @@ func (service *Outbox) Publish(actor *outbox.Actor, parentType string, parentID
 
 	// Send notifications to all Followers
 	go service.sendNotifications_ActivityPub(actor, activity)
-	go service.sendNotifications_WebSub(parentType, parentID)
+	go service.sendNotifications_Relay(actor, activity)
+	go service.sendNotifications_WebSub(parentType, parentID, activity)
+	go service.sendNotifications_RSSCloud(parentType, parentID, activity)
 	go service.sendNotifications_WebMention(activity)
 	go service.sendNotifications_Email(parentType, parentID, activity)
 
@@ -78,6 +81,7 @@ func (service *Outbox) UnPublish(actor *outbox.Actor, parentType string, parentI
 	if message.ActivityType == vocab.ActivityTypeCreate {
 		log.Debug().Str("id", url).Msg("Sending Delete Activity")
 		go actor.SendDelete(document)
+		go service.relayService.Unpublish(actor, url)
 		return nil
 	}
 
@@ -96,21 +100,80 @@ func (service Outbox) sendNotifications_ActivityPub(actor *outbox.Actor, activit
 	actor.Send(activity)
 }
 
-// TODO: HIGH: Thoroughly re-test WebSub notifications.  They've been rebuilt from scratch.
-func (service Outbox) sendNotifications_WebSub(parentType string, parentID primitive.ObjectID) {
+// sendNotifications_Relay publishes public activities to all ActivityPub Relays
+func (service Outbox) sendNotifications_Relay(actor *outbox.Actor, activity mapof.Any) {
+	service.relayService.Publish(actor, activity)
+}
+
+// sendNotifications_WebSub notifies all WebSub Followers that the feed has changed.  Public
+// documents are sent as "fat pings" and all other changes are sent as "thin pings"
+func (service Outbox) sendNotifications_WebSub(parentType string, parentID primitive.ObjectID, activity mapof.Any) {
 
 	const location = "service.Outbox.sendNotifications_WebSub"
 
+	document, ok := webSubDocument(service.activityService.NewDocument(activity))
+
+	// RULE: Activities that do not change the feed (Likes, Blocks, etc) are not sent
+	if !ok {
+		return
+	}
+
 	// Get this User's Followers from the database
 	followers, err := service.followerService.WebSubFollowersChannel(parentType, parentID)
 
 	if err != nil {
 		derp.Report(derp.Wrap(err, location, "Error loading Followers", parentType, parentID))
+		return
+	}
+
+	// Queue up all WebSub messages to be sent
+	for follower := range followers {
+		service.queue.Push(NewTaskSendWebSubMessage(follower, document))
+	}
+}
+
+// webSubDocument returns the document to include in a WebSub notification for the provided
+// activity.  Only public documents that were created or updated are sent as "fat pings".
+// Other changes to the feed return an empty document (a "thin ping"), and activities that
+// do not change the feed return FALSE.  RSSCloud uses the same rules to decide which
+// activities to notify about.
+func webSubDocument(activity streams.Document) (streams.Document, bool) {
+
+	switch activity.Type() {
+
+	case vocab.ActivityTypeCreate, vocab.ActivityTypeUpdate:
+
+		// Fat pings include the object that was published, not the activity itself
+		if object := activity.Object(); relayIsPublic(object) {
+			return object, true
+		}
+
+	case vocab.ActivityTypeLike, vocab.ActivityTypeDislike, vocab.ActivityTypeBlock:
+		return streams.NilDocument(), false
+	}
+
+	return streams.NilDocument(), true
+}
+
+// sendNotifications_RSSCloud notifies all RSSCloud Followers that the feed has changed
+func (service Outbox) sendNotifications_RSSCloud(parentType string, parentID primitive.ObjectID, activity mapof.Any) {
+
+	const location = "service.Outbox.sendNotifications_RSSCloud"
+
+	// RULE: Activities that do not change the feed (Likes, Blocks, etc) are not sent
+	if _, ok := webSubDocument(service.activityService.NewDocument(activity)); !ok {
+		return
+	}
+
+	followers, err := service.followerService.RSSCloudFollowersChannel(parentType, parentID)
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, location, "Error loading Followers", parentType, parentID))
+		return
 	}
 
-	// Queue up all ActivityPub messages to be sent
 	for follower := range followers {
-		service.queue.Push(NewTaskSendWebSubMessage(follower))
+		service.queue.Push(NewTaskSendRSSCloudMessage(follower))
 	}
 }
 
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebSubDocument(t *testing.T) {
+
+	activity := func(activityType string, to string) streams.Document {
+		return streams.NewDocument(mapof.Any{
+			vocab.PropertyType: activityType,
+			vocab.PropertyObject: mapof.Any{
+				vocab.PropertyID:   "https://example.com/posts/1",
+				vocab.PropertyType: vocab.ObjectTypeNote,
+				vocab.PropertyTo:   to,
+			},
+		})
+	}
+
+	// Public documents are sent as fat pings
+	document, ok := webSubDocument(activity(vocab.ActivityTypeCreate, vocab.NamespaceActivityStreamsPublic))
+	require.True(t, ok)
+	require.Equal(t, "https://example.com/posts/1", document.ID())
+
+	document, ok = webSubDocument(activity(vocab.ActivityTypeUpdate, vocab.NamespaceActivityStreamsPublic))
+	require.True(t, ok)
+	require.Equal(t, "https://example.com/posts/1", document.ID())
+
+	// Private documents are sent as thin pings
+	document, ok = webSubDocument(activity(vocab.ActivityTypeCreate, "https://example.com/@user/followers"))
+	require.True(t, ok)
+	require.True(t, document.IsNil())
+
+	document, ok = webSubDocument(activity(vocab.ActivityTypeDelete, vocab.NamespaceActivityStreamsPublic))
+	require.True(t, ok)
+	require.True(t, document.IsNil())
+
+	// Activities that do not change the feed are not sent
+	_, ok = webSubDocument(activity(vocab.ActivityTypeLike, vocab.NamespaceActivityStreamsPublic))
+	require.False(t, ok)
+
+	_, ok = webSubDocument(activity(vocab.ActivityTypeBlock, vocab.NamespaceActivityStreamsPublic))
+	require.False(t, ok)
+}
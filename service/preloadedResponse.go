@@ -0,0 +1,44 @@
+package service
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/benpate/remote"
+)
+
+// PreloadedResponse is a load option that supplies a response that has already been
+// downloaded (for instance, by a conditional GET) so that reloading a document does not
+// request the same URL a second time.  Like SignAs, it is passed through every client in
+// the ActivityStream stack until it reaches the RequestSignerClient.
+type PreloadedResponse struct {
+	URL    string
+	Header http.Header
+	Body   []byte
+}
+
+// RemoteOption returns a remote.Option that answers requests for this URL with the preloaded response
+func (preloaded PreloadedResponse) RemoteOption() remote.Option {
+
+	return remote.Option{
+		ModifyRequest: func(_ *remote.Transaction, request *http.Request) *http.Response {
+
+			if (request.Method != http.MethodGet) || (request.URL.String() != preloaded.URL) {
+				return nil
+			}
+
+			return &http.Response{
+				Status:        "200 OK",
+				StatusCode:    http.StatusOK,
+				Proto:         "HTTP/1.1",
+				ProtoMajor:    1,
+				ProtoMinor:    1,
+				Header:        preloaded.Header.Clone(),
+				Body:          io.NopCloser(bytes.NewReader(preloaded.Body)),
+				ContentLength: int64(len(preloaded.Body)),
+				Request:       request,
+			}
+		},
+	}
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreloadedResponse(t *testing.T) {
+
+	preloaded := PreloadedResponse{
+		URL:    "https://example.com/feed.xml",
+		Header: http.Header{"Content-Type": []string{"application/rss+xml"}},
+		Body:   []byte("<rss></rss>"),
+	}
+
+	option := preloaded.RemoteOption()
+
+	// Requests for the preloaded URL are answered without a network request (every time)
+	for range 2 {
+		request, err := http.NewRequest(http.MethodGet, "https://example.com/feed.xml", nil)
+		require.Nil(t, err)
+
+		response := option.ModifyRequest(nil, request)
+		require.NotNil(t, response)
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		require.Equal(t, "application/rss+xml", response.Header.Get("Content-Type"))
+
+		body, err := io.ReadAll(response.Body)
+		require.Nil(t, err)
+		require.Equal(t, "<rss></rss>", string(body))
+	}
+
+	// Other URLs are sent to the remote server
+	request, err := http.NewRequest(http.MethodGet, "https://example.com/", nil)
+	require.Nil(t, err)
+	require.Nil(t, option.ModifyRequest(nil, request))
+}
@@ -0,0 +1,576 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/hannibal"
+	"github.com/benpate/hannibal/outbox"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/remote"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/rosetta/schema"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// relayRetention is the amount of time that relayed messages remain in the public timeline
+const relayRetention = 14 * 24 * time.Hour
+
+// Relay manages this Domain's subscriptions to ActivityPub relays.  Relays are followed
+// by the Domain's instance actor, and the public posts that they forward are stored in
+// the Domain's public timeline.  Local public posts can also be published to relays.
+type Relay struct {
+	collection           data.Collection
+	relayMessageService  *RelayMessage
+	instanceActorService *InstanceActor
+	domainPolicyService  *DomainPolicy
+	ruleService          *Rule
+	followedTagService   *FollowedTag
+	closed               chan bool
+}
+
+// NewRelay returns a fully initialized Relay service
+func NewRelay() Relay {
+	return Relay{
+		closed: make(chan bool),
+	}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+// Refresh updates any stateful data that is cached inside this service.
+func (service *Relay) Refresh(collection data.Collection, relayMessageService *RelayMessage, instanceActorService *InstanceActor, domainPolicyService *DomainPolicy, ruleService *Rule, followedTagService *FollowedTag) {
+	service.collection = collection
+	service.relayMessageService = relayMessageService
+	service.instanceActorService = instanceActorService
+	service.domainPolicyService = domainPolicyService
+	service.ruleService = ruleService
+	service.followedTagService = followedTagService
+}
+
+// Close stops the background process that purges old relay messages
+func (service *Relay) Close() {
+	close(service.closed)
+}
+
+// Start begins the background process that removes relayed messages
+// from the public timeline once they are older than the retention period
+func (service *Relay) Start() {
+
+	// Wait until the service has booted up correctly.
+	for service.relayMessageService == nil {
+		time.Sleep(1 * time.Minute)
+	}
+
+	for {
+
+		select {
+
+		case <-service.closed:
+			return
+
+		case <-time.After(1 * time.Hour):
+			if err := service.relayMessageService.Purge(time.Now().Add(0 - relayRetention)); err != nil {
+				derp.Report(derp.Wrap(err, "service.Relay.Start", "Error purging relay messages"))
+			}
+		}
+	}
+}
+
+/******************************************
+ * Common Data Methods
+ ******************************************/
+
+// Query returns a slice of Relays that match the provided criteria
+func (service *Relay) Query(criteria exp.Expression, options ...option.Option) ([]model.Relay, error) {
+	result := make([]model.Relay, 0)
+	err := service.collection.Query(&result, notDeleted(criteria), options...)
+	return result, err
+}
+
+// List returns an iterator containing all of the Relays that match the provided criteria
+func (service *Relay) List(criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.collection.Iterator(notDeleted(criteria), options...)
+}
+
+// Load retrieves a Relay from the database
+func (service *Relay) Load(criteria exp.Expression, relay *model.Relay) error {
+
+	if err := service.collection.Load(notDeleted(criteria), relay); err != nil {
+		return derp.Wrap(err, "service.Relay.Load", "Error loading Relay", criteria)
+	}
+
+	return nil
+}
+
+// Save adds/updates a Relay in the database
+func (service *Relay) Save(relay *model.Relay, note string) error {
+
+	const location = "service.Relay.Save"
+
+	// Validate the value before saving
+	if err := service.Schema().Validate(relay); err != nil {
+		return derp.Wrap(err, location, "Error validating Relay", relay)
+	}
+
+	// Save the value to the database
+	if err := service.collection.Save(relay, note); err != nil {
+		return derp.Wrap(err, location, "Error saving Relay", relay, note)
+	}
+
+	return nil
+}
+
+// Delete unsubscribes from a Relay and removes it from the database (virtual delete),
+// along with all of the messages that it delivered to the public timeline
+func (service *Relay) Delete(relay *model.Relay, note string) error {
+
+	const location = "service.Relay.Delete"
+
+	// Unsubscribing is "best effort" because the relay may no longer exist
+	if err := service.Unsubscribe(relay); err != nil {
+		derp.Report(derp.Wrap(err, location, "Error unsubscribing from Relay", relay.URL))
+	}
+
+	if err := service.relayMessageService.DeleteByRelay(relay.RelayID); err != nil {
+		return derp.Wrap(err, location, "Error deleting messages for Relay", relay.RelayID)
+	}
+
+	if err := service.collection.Delete(relay, note); err != nil {
+		return derp.Wrap(err, location, "Error deleting Relay", relay, note)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Model Service Methods
+ ******************************************/
+
+// ObjectType returns the type of object that this service manages
+func (service *Relay) ObjectType() string {
+	return "Relay"
+}
+
+// New returns a fully initialized model.Relay as a data.Object.
+func (service *Relay) ObjectNew() data.Object {
+	result := model.NewRelay()
+	return &result
+}
+
+func (service *Relay) ObjectID(object data.Object) primitive.ObjectID {
+
+	if relay, ok := object.(*model.Relay); ok {
+		return relay.RelayID
+	}
+
+	return primitive.NilObjectID
+}
+
+func (service *Relay) ObjectQuery(result any, criteria exp.Expression, options ...option.Option) error {
+	return service.collection.Query(result, notDeleted(criteria), options...)
+}
+
+func (service *Relay) ObjectList(criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.List(criteria, options...)
+}
+
+func (service *Relay) ObjectLoad(criteria exp.Expression) (data.Object, error) {
+	result := model.NewRelay()
+	err := service.Load(criteria, &result)
+	return &result, err
+}
+
+func (service *Relay) ObjectSave(object data.Object, comment string) error {
+	if relay, ok := object.(*model.Relay); ok {
+		return service.Save(relay, comment)
+	}
+	return derp.NewInternalError("service.Relay.ObjectSave", "Invalid Object Type", object)
+}
+
+func (service *Relay) ObjectDelete(object data.Object, comment string) error {
+	if relay, ok := object.(*model.Relay); ok {
+		return service.Delete(relay, comment)
+	}
+	return derp.NewInternalError("service.Relay.ObjectDelete", "Invalid Object Type", object)
+}
+
+func (service *Relay) ObjectUserCan(object data.Object, authorization model.Authorization, action string) error {
+	return derp.NewUnauthorizedError("service.Relay", "Not Authorized")
+}
+
+func (service *Relay) Schema() schema.Schema {
+	return schema.New(model.RelaySchema())
+}
+
+/******************************************
+ * Custom Queries
+ ******************************************/
+
+// LoadByID retrieves a single Relay by its ID
+func (service *Relay) LoadByID(relayID primitive.ObjectID, relay *model.Relay) error {
+	return service.Load(exp.Equal("_id", relayID), relay)
+}
+
+// LoadByFollowID retrieves the Relay that was subscribed with the provided Follow activity
+func (service *Relay) LoadByFollowID(followID string, relay *model.Relay) error {
+	return service.Load(exp.Equal("followId", followID), relay)
+}
+
+// LoadByActorID retrieves the Relay that is represented by the provided ActivityPub actor
+func (service *Relay) LoadByActorID(actorID string, relay *model.Relay) error {
+	return service.Load(exp.Equal("actorId", actorID), relay)
+}
+
+// QueryPublishable returns all active Relays that local posts should be published to
+func (service *Relay) QueryPublishable() ([]model.Relay, error) {
+	criteria := exp.Equal("stateId", model.RelayStateActive).AndEqual("publish", true)
+	return service.Query(criteria)
+}
+
+/******************************************
+ * Subscriptions
+ ******************************************/
+
+// Subscribe sends a Follow activity from the instance actor to the Relay.  URLs that end
+// in "/inbox" are treated as Mastodon-style relays (which receive a Follow of the public
+// collection) and all other URLs are loaded as LitePub relay actors.
+func (service *Relay) Subscribe(relay *model.Relay) error {
+
+	const location = "service.Relay.Subscribe"
+
+	if err := service.resolve(relay); err != nil {
+		return service.subscribeFailure(relay, derp.Wrap(err, location, "Error resolving relay", relay.URL))
+	}
+
+	actor, err := service.instanceActorService.ActivityPubActor()
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading instance actor")
+	}
+
+	relay.FollowID = service.instanceActorService.ActorID() + "/relays/" + relay.RelayID.Hex()
+
+	follow := mapof.Any{
+		vocab.AtContext:      vocab.ContextTypeActivityStreams,
+		vocab.PropertyID:     relay.FollowID,
+		vocab.PropertyType:   vocab.ActivityTypeFollow,
+		vocab.PropertyActor:  service.instanceActorService.ActorID(),
+		vocab.PropertyObject: relay.FollowObject(),
+	}
+
+	if err := relayDeliver(actor, relay.InboxURL, follow); err != nil {
+		return service.subscribeFailure(relay, derp.Wrap(err, location, "Error sending Follow to relay", relay.InboxURL))
+	}
+
+	relay.SetState(model.RelayStatePending, "")
+
+	if err := service.Save(relay, "Subscribed"); err != nil {
+		return derp.Wrap(err, location, "Error saving Relay", relay)
+	}
+
+	return nil
+}
+
+// Unsubscribe sends an Undo of the original Follow activity to the Relay
+func (service *Relay) Unsubscribe(relay *model.Relay) error {
+
+	const location = "service.Relay.Unsubscribe"
+
+	// Nothing to undo if the Follow was never sent
+	if (relay.FollowID == "") || (relay.InboxURL == "") {
+		return nil
+	}
+
+	actor, err := service.instanceActorService.ActivityPubActor()
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading instance actor")
+	}
+
+	follow := mapof.Any{
+		vocab.PropertyID:     relay.FollowID,
+		vocab.PropertyType:   vocab.ActivityTypeFollow,
+		vocab.PropertyActor:  service.instanceActorService.ActorID(),
+		vocab.PropertyObject: relay.FollowObject(),
+	}
+
+	undo := outbox.MakeUndo(service.instanceActorService.ActorID(), follow)
+	undo[vocab.AtContext] = vocab.ContextTypeActivityStreams
+
+	if err := relayDeliver(actor, relay.InboxURL, undo); err != nil {
+		return derp.Wrap(err, location, "Error sending Undo to relay", relay.InboxURL)
+	}
+
+	return nil
+}
+
+// Accept marks a Relay as ACTIVE once it has accepted our Follow activity
+func (service *Relay) Accept(relay *model.Relay, actorID string) error {
+
+	// Mastodon-style relays are subscribed by inbox, so this is the first time we see their actor
+	if relay.ActorID == "" {
+		relay.ActorID = actorID
+	}
+
+	relay.SetState(model.RelayStateActive, "")
+
+	if err := service.Save(relay, "Accepted by relay"); err != nil {
+		return derp.Wrap(err, "service.Relay.Accept", "Error saving Relay", relay)
+	}
+
+	return nil
+}
+
+// Reject marks a Relay as REJECTED when it has declined our Follow activity
+func (service *Relay) Reject(relay *model.Relay) error {
+
+	relay.SetState(model.RelayStateRejected, "Subscription was rejected by the relay")
+
+	if err := service.Save(relay, "Rejected by relay"); err != nil {
+		return derp.Wrap(err, "service.Relay.Reject", "Error saving Relay", relay)
+	}
+
+	return nil
+}
+
+// resolve calculates the protocol and inbox of a Relay from the URL entered by the administrator
+func (service *Relay) resolve(relay *model.Relay) error {
+
+	relay.URL = strings.TrimSpace(relay.URL)
+
+	// Mastodon-style relays are subscribed directly at their inbox
+	if strings.HasSuffix(relay.URL, "/inbox") {
+		relay.Type = model.RelayTypeMastodon
+		relay.InboxURL = relay.URL
+		return nil
+	}
+
+	// Everything else is loaded as a LitePub relay actor
+	document, err := service.domainPolicyService.Client(streams.NewDefaultClient()).Load(relay.URL)
+
+	if err != nil {
+		return derp.Wrap(err, "service.Relay.resolve", "Error loading relay actor", relay.URL)
+	}
+
+	if !document.IsActor() || (document.Inbox().ID() == "") {
+		return derp.NewBadRequestError("service.Relay.resolve", "URL is not an ActivityPub actor", relay.URL)
+	}
+
+	relay.Type = model.RelayTypeLitePub
+	relay.ActorID = document.ID()
+	relay.InboxURL = document.Inbox().ID()
+
+	if relay.Label == "" {
+		relay.Label = document.Name()
+	}
+
+	return nil
+}
+
+// subscribeFailure records a failed subscription on the Relay, and returns the original error
+func (service *Relay) subscribeFailure(relay *model.Relay, err error) error {
+
+	relay.SetState(model.RelayStateFailure, derp.Message(err))
+
+	if saveErr := service.Save(relay, "Subscription failed"); saveErr != nil {
+		derp.Report(derp.Wrap(saveErr, "service.Relay.subscribeFailure", "Error saving Relay", relay))
+	}
+
+	return err
+}
+
+/******************************************
+ * Receiving Messages
+ ******************************************/
+
+// Receive adds a public document that was forwarded by an active Relay into the
+// Domain's public timeline.  Documents from suspended or silenced domains, and
+// from actors or domains that are blocked by the administrator, are ignored.
+func (service *Relay) Receive(relay *model.Relay, document streams.Document) error {
+
+	const location = "service.Relay.Receive"
+
+	// RULE: Only accept messages from relays that have accepted our subscription
+	if !relay.IsActive() {
+		return derp.NewForbiddenError(location, "Relay is not active", relay.RelayID)
+	}
+
+	// RULE: Only public documents belong in the public timeline
+	if !relayIsPublic(document) {
+		return nil
+	}
+
+	// RULE: Suspended and silenced domains are not shown in the public timeline
+	if !service.domainPolicyService.Allow(&document, true) {
+		return nil
+	}
+
+	// RULE: Authors blocked by the administrator are not shown in the public timeline
+	authorID := document.AttributedTo().ID()
+
+	if rules, err := service.ruleService.QueryDomainBlocksByActor(authorID); err != nil {
+		return derp.Wrap(err, location, "Error loading domain rules", authorID)
+	} else if len(rules) > 0 {
+		return nil
+	}
+
+	added, err := service.relayMessageService.Receive(relay.RelayID, document)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error saving relay message", document.ID())
+	}
+
+	if !added {
+		return nil
+	}
+
+	// Deliver the document to Users who follow its hashtags
+	service.followedTagService.Deliver(document)
+
+	// Update statistics for this Relay
+	relay.MessageCount++
+	relay.LastMessageDate = time.Now().Unix()
+
+	if err := service.Save(relay, "Received message"); err != nil {
+		return derp.Wrap(err, location, "Error saving Relay", relay)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Publishing Messages
+ ******************************************/
+
+// Publish sends a local public activity to every Relay that is configured for publishing.
+// Mastodon-style relays receive the original activity (signed by its author) and LitePub
+// relays receive an Announce of new documents from the instance actor.
+func (service *Relay) Publish(actor *outbox.Actor, activity mapof.Any) {
+
+	const location = "service.Relay.Publish"
+
+	document := streams.NewDocument(activity)
+	activityType := document.Type()
+
+	switch activityType {
+	case vocab.ActivityTypeCreate, vocab.ActivityTypeUpdate, vocab.ActivityTypeDelete:
+	default:
+		return
+	}
+
+	// RULE: Only public activities are sent to relays
+	if !relayIsPublic(document) {
+		return
+	}
+
+	relays, err := service.QueryPublishable()
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, location, "Error loading relays"))
+		return
+	}
+
+	for _, relay := range relays {
+
+		if relay.IsLitePub() && (activityType == vocab.ActivityTypeCreate) {
+			if err := service.announce(relay, document.Object().ID()); err != nil {
+				derp.Report(derp.Wrap(err, location, "Error announcing to relay", relay.InboxURL))
+			}
+			continue
+		}
+
+		if err := relayDeliver(*actor, relay.InboxURL, activity); err != nil {
+			derp.Report(derp.Wrap(err, location, "Error forwarding to relay", relay.InboxURL))
+		}
+	}
+}
+
+// Unpublish sends a Delete of a local document to every Relay that is configured for publishing
+func (service *Relay) Unpublish(actor *outbox.Actor, url string) {
+
+	service.Publish(actor, mapof.Any{
+		vocab.AtContext:         vocab.ContextTypeActivityStreams,
+		vocab.PropertyID:        url + "#delete",
+		vocab.PropertyType:      vocab.ActivityTypeDelete,
+		vocab.PropertyActor:     actor.ActorID(),
+		vocab.PropertyObject:    url,
+		vocab.PropertyTo:        []string{vocab.NamespaceActivityStreamsPublic},
+		vocab.PropertyPublished: hannibal.TimeFormat(time.Now()),
+	})
+}
+
+// announce sends an Announce of a local document from the instance actor to a LitePub relay
+func (service *Relay) announce(relay model.Relay, objectID string) error {
+
+	const location = "service.Relay.announce"
+
+	actor, err := service.instanceActorService.ActivityPubActor()
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading instance actor")
+	}
+
+	announce := mapof.Any{
+		vocab.AtContext:         vocab.ContextTypeActivityStreams,
+		vocab.PropertyID:        service.instanceActorService.ActorID() + "/announce/" + primitive.NewObjectID().Hex(),
+		vocab.PropertyType:      vocab.ActivityTypeAnnounce,
+		vocab.PropertyActor:     service.instanceActorService.ActorID(),
+		vocab.PropertyObject:    objectID,
+		vocab.PropertyTo:        []string{vocab.NamespaceActivityStreamsPublic},
+		vocab.PropertyPublished: hannibal.TimeFormat(time.Now()),
+	}
+
+	if err := relayDeliver(actor, relay.InboxURL, announce); err != nil {
+		return derp.Wrap(err, location, "Error sending Announce", relay.InboxURL)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Helper Functions
+ ******************************************/
+
+// relayDeliver POSTs an activity directly to a relay inbox, signed by the provided actor.
+// Relays are not addressed in the activity itself, so this bypasses the outbox queue.
+func relayDeliver(actor outbox.Actor, inboxURL string, message mapof.Any) error {
+
+	log.Debug().Str("inbox", inboxURL).Str("type", message.GetString(vocab.PropertyType)).Msg("Relay: sending activity")
+
+	transaction := remote.Post(inboxURL).
+		Accept(vocab.ContentTypeActivityPub).
+		ContentType(vocab.ContentTypeActivityPub).
+		With(outbox.SignRequest(actor)).
+		JSON(message)
+
+	if err := transaction.Send(); err != nil {
+		return derp.Wrap(err, "service.relayDeliver", "Error sending activity to relay", inboxURL)
+	}
+
+	return nil
+}
+
+// relayIsPublic returns TRUE if the document is addressed to the public collection
+func relayIsPublic(document streams.Document) bool {
+
+	for _, recipients := range []streams.Document{document.To(), document.CC()} {
+		for ; recipients.NotNil(); recipients = recipients.Tail() {
+			switch recipients.Head().ID() {
+			case vocab.NamespaceActivityStreamsPublic, "as:Public", "Public":
+				return true
+			}
+		}
+	}
+
+	return false
+}
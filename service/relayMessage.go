@@ -0,0 +1,179 @@
+package service
+
+import (
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RelayMessage manages the public posts that are forwarded to this Domain by ActivityPub relays.
+// Together, these make up the Domain's public (federated) timeline.
+type RelayMessage struct {
+	collection data.Collection
+}
+
+// NewRelayMessage returns a fully initialized RelayMessage service
+func NewRelayMessage() RelayMessage {
+	return RelayMessage{}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+// Refresh updates any stateful data that is cached inside this service.
+func (service *RelayMessage) Refresh(collection data.Collection) {
+	service.collection = collection
+}
+
+// Close stops any background processes controlled by this service
+func (service *RelayMessage) Close() {
+}
+
+/******************************************
+ * Common Data Methods
+ ******************************************/
+
+// Query returns a slice of RelayMessages that match the provided criteria
+func (service *RelayMessage) Query(criteria exp.Expression, options ...option.Option) ([]model.RelayMessage, error) {
+	result := make([]model.RelayMessage, 0)
+	err := service.collection.Query(&result, notDeleted(criteria), options...)
+	return result, err
+}
+
+// Load retrieves a RelayMessage from the database
+func (service *RelayMessage) Load(criteria exp.Expression, message *model.RelayMessage) error {
+
+	if err := service.collection.Load(notDeleted(criteria), message); err != nil {
+		return derp.Wrap(err, "service.RelayMessage.Load", "Error loading RelayMessage", criteria)
+	}
+
+	return nil
+}
+
+// Save adds/updates a RelayMessage in the database
+func (service *RelayMessage) Save(message *model.RelayMessage, note string) error {
+
+	const location = "service.RelayMessage.Save"
+
+	// Validate the value before saving
+	if err := service.Schema().Validate(message); err != nil {
+		return derp.Wrap(err, location, "Error validating RelayMessage", message)
+	}
+
+	// Save the value to the database
+	if err := service.collection.Save(message, note); err != nil {
+		return derp.Wrap(err, location, "Error saving RelayMessage", message, note)
+	}
+
+	return nil
+}
+
+// Delete removes a RelayMessage from the database (hard delete)
+func (service *RelayMessage) Delete(message *model.RelayMessage) error {
+
+	if err := service.collection.HardDelete(exp.Equal("_id", message.RelayMessageID)); err != nil {
+		return derp.Wrap(err, "service.RelayMessage.Delete", "Error deleting RelayMessage", message.RelayMessageID)
+	}
+
+	return nil
+}
+
+// Schema returns the validating schema for RelayMessages
+func (service *RelayMessage) Schema() schema.Schema {
+	return schema.New(model.RelayMessageSchema())
+}
+
+/******************************************
+ * Custom Queries
+ ******************************************/
+
+// QueryTimeline returns a page of the public timeline, newest first
+func (service *RelayMessage) QueryTimeline(criteria exp.Expression, maxRows int64) ([]model.RelayMessage, error) {
+
+	if (maxRows <= 0) || (maxRows > 40) {
+		maxRows = 20
+	}
+
+	return service.Query(criteria, option.SortDesc("createDate"), option.MaxRows(maxRows))
+}
+
+// LoadByURL retrieves a RelayMessage using the ID of the original document
+func (service *RelayMessage) LoadByURL(url string, message *model.RelayMessage) error {
+	return service.Load(exp.Equal("url", url), message)
+}
+
+// DeleteByRelay removes all RelayMessages that were delivered by the provided Relay
+func (service *RelayMessage) DeleteByRelay(relayID primitive.ObjectID) error {
+
+	if err := service.collection.HardDelete(exp.Equal("relayId", relayID)); err != nil {
+		return derp.Wrap(err, "service.RelayMessage.DeleteByRelay", "Error deleting RelayMessages", relayID)
+	}
+
+	return nil
+}
+
+// Purge removes all RelayMessages that were received before the provided time
+func (service *RelayMessage) Purge(before time.Time) error {
+
+	if err := service.collection.HardDelete(exp.LessThan("createDate", before.UnixMilli())); err != nil {
+		return derp.Wrap(err, "service.RelayMessage.Purge", "Error purging RelayMessages", before)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Relay Methods
+ ******************************************/
+
+// Receive adds a document that was forwarded by a Relay to the public timeline.
+// It returns FALSE if the document has already been received (possibly by another Relay).
+func (service *RelayMessage) Receive(relayID primitive.ObjectID, document streams.Document) (bool, error) {
+
+	const location = "service.RelayMessage.Receive"
+
+	// Skip documents that we already have
+	message := model.NewRelayMessage()
+
+	if err := service.LoadByURL(document.ID(), &message); err == nil {
+		return false, nil
+	} else if !derp.NotFound(err) {
+		return false, derp.Wrap(err, location, "Error checking for existing RelayMessage", document.ID())
+	}
+
+	// Populate the RelayMessage from the document
+	author := document.AttributedTo()
+
+	if loaded, err := author.Load(); err == nil {
+		author = loaded
+	}
+
+	message.RelayID = relayID
+	message.URL = document.ID()
+	message.AttributedTo.ProfileURL = author.ID()
+	message.AttributedTo.Name = author.Name()
+	message.AttributedTo.IconURL = author.IconOrImage().URL()
+	message.Summary = document.Summary()
+	message.ContentHTML = document.Content()
+	message.Hashtags = DocumentHashtags(document)
+	message.Sensitive = document.Get("sensitive").Bool()
+	message.PublishDate = time.Now().Unix()
+
+	if published := document.Published(); !published.IsZero() {
+		message.PublishDate = published.Unix()
+	}
+
+	if err := service.Save(&message, "Received from relay"); err != nil {
+		return false, derp.Wrap(err, location, "Error saving RelayMessage", message.URL)
+	}
+
+	return true, nil
+}
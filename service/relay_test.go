@@ -0,0 +1,32 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayIsPublic(t *testing.T) {
+
+	// Public in the TO field
+	document := streams.NewDocument(mapof.Any{
+		vocab.PropertyTo: []string{vocab.NamespaceActivityStreamsPublic},
+	})
+	require.True(t, relayIsPublic(document))
+
+	// Public (unlisted) in the CC field, using the compact form
+	document = streams.NewDocument(mapof.Any{
+		vocab.PropertyTo: "https://remote.social/users/bob/followers",
+		vocab.PropertyCC: []string{"as:Public"},
+	})
+	require.True(t, relayIsPublic(document))
+
+	// Followers-only
+	document = streams.NewDocument(mapof.Any{
+		vocab.PropertyTo: "https://remote.social/users/bob/followers",
+	})
+	require.False(t, relayIsPublic(document))
+}
@@ -0,0 +1,468 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/rosetta/schema"
+	"github.com/benpate/rosetta/sliceof"
+	"github.com/microcosm-cc/bluemonday"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Report manages moderation Reports filed by local Users and received from other servers
+type Report struct {
+	collection           data.Collection
+	activityService      *ActivityStream
+	instanceActorService *InstanceActor
+	streamService        *Stream
+	userService          *User
+	host                 string
+}
+
+// NewReport returns a fully initialized Report service
+func NewReport() Report {
+	return Report{}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+// Refresh updates any stateful data that is cached inside this service.
+func (service *Report) Refresh(collection data.Collection, activityService *ActivityStream, instanceActorService *InstanceActor, streamService *Stream, userService *User, host string) {
+	service.collection = collection
+	service.activityService = activityService
+	service.instanceActorService = instanceActorService
+	service.streamService = streamService
+	service.userService = userService
+	service.host = host
+}
+
+// Close stops any background processes controlled by this service
+func (service *Report) Close() {
+	// Nothin to do here.
+}
+
+/******************************************
+ * Common Data Methods
+ ******************************************/
+
+// Query returns a slice of Reports that match the provided criteria
+func (service *Report) Query(criteria exp.Expression, options ...option.Option) ([]model.Report, error) {
+	result := make([]model.Report, 0)
+	err := service.collection.Query(&result, notDeleted(criteria), options...)
+	return result, err
+}
+
+// List returns an iterator containing all of the Reports that match the provided criteria
+func (service *Report) List(criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.collection.Iterator(notDeleted(criteria), options...)
+}
+
+// Load retrieves a Report from the database
+func (service *Report) Load(criteria exp.Expression, report *model.Report) error {
+
+	if err := service.collection.Load(notDeleted(criteria), report); err != nil {
+		return derp.Wrap(err, "service.Report.Load", "Error loading Report", criteria)
+	}
+
+	return nil
+}
+
+// Save adds/updates a Report in the database
+func (service *Report) Save(report *model.Report, note string) error {
+
+	// Validate the value before saving
+	if err := service.Schema().Validate(report); err != nil {
+		return derp.Wrap(err, "service.Report.Save", "Error validating Report", report)
+	}
+
+	// Save the value to the database
+	if err := service.collection.Save(report, note); err != nil {
+		return derp.Wrap(err, "service.Report.Save", "Error saving Report", report, note)
+	}
+
+	return nil
+}
+
+// Delete removes a Report from the database (virtual delete)
+func (service *Report) Delete(report *model.Report, note string) error {
+
+	if err := service.collection.Delete(report, note); err != nil {
+		return derp.Wrap(err, "service.Report.Delete", "Error deleting Report", report, note)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Model Service Methods
+ ******************************************/
+
+// ObjectType returns the type of object that this service manages
+func (service *Report) ObjectType() string {
+	return "Report"
+}
+
+// New returns a fully initialized model.Report as a data.Object.
+func (service *Report) ObjectNew() data.Object {
+	result := model.NewReport()
+	return &result
+}
+
+func (service *Report) ObjectID(object data.Object) primitive.ObjectID {
+
+	if report, ok := object.(*model.Report); ok {
+		return report.ReportID
+	}
+
+	return primitive.NilObjectID
+}
+
+func (service *Report) ObjectQuery(result any, criteria exp.Expression, options ...option.Option) error {
+	return service.collection.Query(result, notDeleted(criteria), options...)
+}
+
+func (service *Report) ObjectList(criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.List(criteria, options...)
+}
+
+func (service *Report) ObjectLoad(criteria exp.Expression) (data.Object, error) {
+	result := model.NewReport()
+	err := service.Load(criteria, &result)
+	return &result, err
+}
+
+func (service *Report) ObjectSave(object data.Object, comment string) error {
+	if report, ok := object.(*model.Report); ok {
+		return service.Save(report, comment)
+	}
+	return derp.NewInternalError("service.Report.ObjectSave", "Invalid Object Type", object)
+}
+
+func (service *Report) ObjectDelete(object data.Object, comment string) error {
+	if report, ok := object.(*model.Report); ok {
+		return service.Delete(report, comment)
+	}
+	return derp.NewInternalError("service.Report.ObjectDelete", "Invalid Object Type", object)
+}
+
+func (service *Report) ObjectUserCan(object data.Object, authorization model.Authorization, action string) error {
+	return derp.NewUnauthorizedError("service.Report", "Not Authorized")
+}
+
+func (service *Report) Schema() schema.Schema {
+	return schema.New(model.ReportSchema())
+}
+
+/******************************************
+ * Custom Queries
+ ******************************************/
+
+// LoadByID retrieves a single Report by its ID
+func (service *Report) LoadByID(reportID primitive.ObjectID, report *model.Report) error {
+	return service.Load(exp.Equal("_id", reportID), report)
+}
+
+// LoadByActivityID retrieves a single Report by the ID of its "Flag" activity
+func (service *Report) LoadByActivityID(activityID string, report *model.Report) error {
+	return service.Load(exp.Equal("activityId", activityID), report)
+}
+
+// QueryByStatus returns all Reports with the provided status, newest first
+func (service *Report) QueryByStatus(statusID string, options ...option.Option) ([]model.Report, error) {
+	options = append(options, option.SortDesc("createDate"))
+	return service.Query(exp.Equal("statusId", statusID), options...)
+}
+
+// CountPending returns the number of Reports that are waiting to be reviewed
+func (service *Report) CountPending() (int64, error) {
+	return service.collection.Count(notDeleted(exp.Equal("statusId", model.ReportStatusPending)))
+}
+
+/******************************************
+ * Custom Actions
+ ******************************************/
+
+// File creates a new Report on behalf of a local User.  Copies of the reported documents
+// are saved with the Report so that moderators can review them later.
+func (service *Report) File(reporter *model.User, targetURL string, objectURLs []string, category string, comment string, forward bool) (model.Report, error) {
+
+	const location = "service.Report.File"
+
+	// If no target was provided, then use the author of the first reported document
+	if (targetURL == "") && (len(objectURLs) > 0) {
+		if document, err := service.activityService.Load(objectURLs[0]); err == nil {
+			targetURL = document.AttributedTo().ID()
+		}
+	}
+
+	if targetURL == "" {
+		return model.Report{}, derp.NewBadRequestError(location, "Report must include an account or a document")
+	}
+
+	report := model.NewReport()
+	report.OriginID = model.ReportOriginLocal
+	report.ReporterID = reporter.UserID
+	report.ReporterURL = reporter.ProfileURL
+	report.TargetURL = targetURL
+	report.ObjectURLs = cleanReportURLs(objectURLs)
+	report.Category = parseReportCategory(category)
+	report.Comment = comment
+
+	// RULE: Only reports about remote accounts can be forwarded
+	report.Forward = forward && !service.isLocal(targetURL)
+
+	service.snapshot(&report)
+
+	if err := service.Save(&report, "Filed by "+reporter.Username); err != nil {
+		return model.Report{}, derp.Wrap(err, location, "Error saving Report", report)
+	}
+
+	return report, nil
+}
+
+// Receive creates a new Report from an ActivityPub "Flag" activity sent by another server.
+// Duplicate "Flag" activities are ignored.
+func (service *Report) Receive(activity streams.Document) error {
+
+	const location = "service.Report.Receive"
+
+	// RULE: Ignore Flags that have already been received
+	if activityID := activity.ID(); activityID != "" {
+		existing := model.NewReport()
+		if err := service.LoadByActivityID(activityID, &existing); err == nil {
+			return nil
+		} else if !derp.NotFound(err) {
+			return derp.Wrap(err, location, "Error searching for duplicate Report", activityID)
+		}
+	}
+
+	report := model.NewReport()
+	report.OriginID = model.ReportOriginRemote
+	report.ReporterURL = activity.Actor().ID()
+	report.ActivityID = activity.ID()
+	report.Comment = bluemonday.StrictPolicy().Sanitize(activity.Content())
+
+	// The Object contains the reported account and (optionally) the reported documents.
+	// Local Users are always the target.  Otherwise, the author of the first document is the target.
+	objectURLs := make([]string, 0)
+	authorURL := ""
+
+	for object := range activity.Object().Channel() {
+
+		objectID := object.ID()
+
+		// RULE: Other servers can only report accounts and documents that are hosted here
+		if !service.isLocal(objectID) {
+			continue
+		}
+
+		if _, err := service.userService.ParseProfileURL(objectID); err == nil {
+			if report.TargetURL == "" {
+				report.TargetURL = objectID
+			}
+			continue
+		}
+
+		stream := model.NewStream()
+		if err := service.streamService.LoadByURL(objectID, &stream); err != nil {
+			continue
+		}
+
+		if authorURL == "" {
+			authorURL = stream.AttributedTo.ProfileURL
+		}
+
+		objectURLs = append(objectURLs, objectID)
+	}
+
+	if report.TargetURL == "" {
+		report.TargetURL = authorURL
+	}
+
+	if report.TargetURL == "" {
+		return derp.NewBadRequestError(location, "Flag must include a local account or document", activity.Value())
+	}
+
+	report.ObjectURLs = cleanReportURLs(objectURLs)
+	service.snapshot(&report)
+
+	if err := service.Save(&report, "Received from "+report.ReporterURL); err != nil {
+		return derp.Wrap(err, location, "Error saving Report", report)
+	}
+
+	return nil
+}
+
+// Forward sends a Report to the server that hosts the reported account, as an ActivityPub
+// "Flag" activity.  The Flag is sent by the instance actor, so that neither the original
+// reporter nor the moderator who forwards the Report is revealed to the remote server.
+func (service *Report) Forward(report *model.Report) error {
+
+	const location = "service.Report.Forward"
+
+	// RULE: Reports about local accounts cannot be forwarded
+	if service.isLocal(report.TargetURL) {
+		return derp.NewBadRequestError(location, "Reports about local accounts cannot be forwarded", report.TargetURL)
+	}
+
+	actor, err := service.instanceActorService.ActivityPubActor()
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading instance actor")
+	}
+
+	// Update the Report
+	report.ForwardDate = time.Now().Unix()
+	report.ActivityID = service.instanceActorService.ActorID() + "#flag-" + report.ReportID.Hex()
+
+	if err := service.Save(report, "Forwarded"); err != nil {
+		return derp.Wrap(err, location, "Error saving Report", report)
+	}
+
+	// Send the Flag to the remote server
+	objects := append([]string{report.TargetURL}, report.ObjectURLs...)
+
+	activity := mapof.Any{
+		vocab.AtContext:       vocab.ContextTypeActivityStreams,
+		vocab.PropertyID:      report.ActivityID,
+		vocab.PropertyType:    vocab.ActivityTypeFlag,
+		vocab.PropertyActor:   service.instanceActorService.ActorID(),
+		vocab.PropertyObject:  objects,
+		vocab.PropertyContent: report.Comment,
+		vocab.PropertyTo:      report.TargetURL,
+	}
+
+	go actor.Send(activity)
+	return nil
+}
+
+// Resolve marks a Report as RESOLVED or DISMISSED by the provided Domain Owner
+func (service *Report) Resolve(report *model.Report, userID primitive.ObjectID, statusID string) error {
+
+	const location = "service.Report.Resolve"
+
+	if (statusID != model.ReportStatusResolved) && (statusID != model.ReportStatusDismissed) {
+		return derp.NewBadRequestError(location, "Invalid status", statusID)
+	}
+
+	report.StatusID = statusID
+	report.ResolvedBy = userID
+	report.ResolveDate = time.Now().Unix()
+
+	if err := service.Save(report, "Closed"); err != nil {
+		return derp.Wrap(err, location, "Error saving Report", report)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Helper Methods
+ ******************************************/
+
+// snapshot copies each of the reported documents into the Report.  Local documents are
+// copied from the database.  Remote documents are only loaded for Reports filed by local
+// Users, so that other servers cannot use Reports to make this server fetch arbitrary URLs.
+// Documents that cannot be loaded are skipped.
+func (service *Report) snapshot(report *model.Report) {
+
+	const location = "service.Report.snapshot"
+
+	policy := bluemonday.UGCPolicy()
+	report.Snapshots = sliceof.NewObject[model.ReportSnapshot]()
+
+	for _, objectURL := range report.ObjectURLs {
+
+		if service.isLocal(objectURL) {
+
+			stream := model.NewStream()
+
+			if err := service.streamService.LoadByURL(objectURL, &stream); err != nil {
+				derp.Report(derp.Wrap(err, location, "Unable to load reported Stream", objectURL))
+				continue
+			}
+
+			report.Snapshots = append(report.Snapshots, model.ReportSnapshot{
+				URL:          objectURL,
+				Type:         stream.ActivityPubType(),
+				AttributedTo: stream.AttributedTo.ProfileURL,
+				Name:         stream.Label,
+				Summary:      stream.Summary,
+				Content:      policy.Sanitize(stream.Content.HTML),
+				Published:    stream.PublishDate,
+			})
+
+			continue
+		}
+
+		// RULE: Never fetch remote documents on behalf of other servers
+		if report.OriginID != model.ReportOriginLocal {
+			continue
+		}
+
+		document, err := service.activityService.Load(objectURL)
+
+		if err != nil {
+			derp.Report(derp.Wrap(err, location, "Unable to load reported document", objectURL))
+			continue
+		}
+
+		report.Snapshots = append(report.Snapshots, model.ReportSnapshot{
+			URL:          document.ID(),
+			Type:         document.Type(),
+			AttributedTo: document.AttributedTo().ID(),
+			Name:         document.Name(),
+			Summary:      document.Summary(),
+			Content:      policy.Sanitize(document.Content()),
+			Published:    document.Published().Unix(),
+		})
+	}
+}
+
+// isLocal returns TRUE if the provided URL is hosted on this server
+func (service *Report) isLocal(url string) bool {
+	return (url != "") && strings.HasPrefix(url, service.host+"/")
+}
+
+// cleanReportURLs removes empty and duplicate values from a list of URLs
+func cleanReportURLs(urls []string) sliceof.String {
+
+	result := sliceof.NewString()
+
+	for _, url := range urls {
+
+		url = strings.TrimSpace(url)
+
+		if (url != "") && !result.Contains(url) {
+			result = append(result, url)
+		}
+	}
+
+	return result
+}
+
+// parseReportCategory translates Mastodon-style categories into model.ReportCategory constants
+func parseReportCategory(category string) string {
+
+	switch strings.ToUpper(category) {
+
+	case model.ReportCategorySpam:
+		return model.ReportCategorySpam
+
+	case model.ReportCategoryViolation:
+		return model.ReportCategoryViolation
+	}
+
+	return model.ReportCategoryOther
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	mockdb "github.com/benpate/data-mock"
+	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestReportReceive_LocalUser(t *testing.T) {
+
+	service, collection := testReportService(t)
+	userURL := "https://local.social/@" + primitive.NewObjectID().Hex()
+
+	// Remote objects are ignored, and are never fetched
+	err := service.Receive(testReportFlag("https://remote.social/flags/1",
+		"https://remote.social/users/innocent",
+		userURL,
+		"https://remote.social/notes/1",
+	))
+	require.Nil(t, err)
+
+	it, err := collection.Iterator(exp.All())
+	require.Nil(t, err)
+
+	report := model.NewReport()
+	require.True(t, it.Next(&report))
+	require.Equal(t, model.ReportOriginRemote, report.OriginID)
+	require.Equal(t, "https://remote.social/users/moderator", report.ReporterURL)
+	require.Equal(t, "https://remote.social/flags/1", report.ActivityID)
+	require.Equal(t, userURL, report.TargetURL)
+	require.Empty(t, report.ObjectURLs)
+	require.Empty(t, report.Snapshots)
+	require.False(t, it.Next(&report))
+}
+
+func TestReportReceive_RemoteOnly(t *testing.T) {
+
+	service, collection := testReportService(t)
+
+	// Flags that do not include any local objects are rejected
+	err := service.Receive(testReportFlag("https://remote.social/flags/3",
+		"https://remote.social/users/innocent",
+		"https://attacker.example/large-file",
+		"https://local.social/"+primitive.NewObjectID().Hex(),
+	))
+	require.NotNil(t, err)
+
+	count, err := collection.Count(exp.All())
+	require.Nil(t, err)
+	require.Zero(t, count)
+}
+
+// testReportService returns a Report service backed by a mock database.  The ActivityStream
+// service is not configured, so any attempt to fetch a remote document will fail the test.
+func testReportService(t *testing.T) (Report, data.Collection) {
+
+	server := mockdb.New()
+	session, err := server.Session(context.TODO())
+	require.Nil(t, err)
+
+	streamService := NewStream()
+	streamService.collection = session.Collection("Stream")
+	streamService.host = "https://local.social"
+
+	userService := NewUser()
+	userService.host = "https://local.social"
+
+	collection := session.Collection("Report")
+
+	service := NewReport()
+	service.Refresh(collection, nil, nil, &streamService, &userService, "https://local.social")
+
+	return service, collection
+}
+
+func testReportFlag(id string, objects ...string) streams.Document {
+
+	objectList := make([]any, len(objects))
+	for index, object := range objects {
+		objectList[index] = object
+	}
+
+	return streams.NewDocument(mapof.Any{
+		vocab.PropertyID:      id,
+		vocab.PropertyType:    vocab.ActivityTypeFlag,
+		vocab.PropertyActor:   mapof.Any{vocab.PropertyID: "https://remote.social/users/moderator"},
+		vocab.PropertyObject:  objectList,
+		vocab.PropertyContent: "Spam",
+	})
+}
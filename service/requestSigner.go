@@ -0,0 +1,110 @@
+package service
+
+import (
+	"crypto"
+	"net/http"
+	"sync"
+
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/sigs"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/remote"
+	"github.com/benpate/sherlock"
+)
+
+// RequestSigner is a server-level service that signs outbound ActivityPub GET requests
+// so that they are accepted by remote servers that require "authorized fetch".
+// Each Domain registers the key of its instance actor, and requests are signed
+// with the key of the Domain that made them.  Requests from unknown Domains
+// (or from the server itself) are sent unsigned.
+type RequestSigner struct {
+	signers map[string]sigs.Signer
+	mutex   sync.RWMutex
+}
+
+// NewRequestSigner returns a fully initialized RequestSigner service
+func NewRequestSigner() RequestSigner {
+	return RequestSigner{
+		signers: make(map[string]sigs.Signer),
+	}
+}
+
+// Set registers the signing key for a Domain
+func (service *RequestSigner) Set(hostname string, publicKeyID string, privateKey crypto.PrivateKey) {
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	service.signers[hostname] = sigs.NewSigner(
+		publicKeyID,
+		privateKey,
+		sigs.SignerFields(sigs.FieldRequestTarget, sigs.FieldHost, sigs.FieldDate),
+	)
+}
+
+// Remove unregisters the signing key for a Domain
+func (service *RequestSigner) Remove(hostname string) {
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	delete(service.signers, hostname)
+}
+
+// Sign applies an HTTP signature to an outbound GET request, using the key of the provided
+// Domain.  Other request methods are signed by the sending actor, and are left unchanged.
+func (service *RequestSigner) Sign(request *http.Request, hostname string) error {
+
+	if request.Method != http.MethodGet {
+		return nil
+	}
+
+	// Do not overwrite signatures that were applied elsewhere
+	if request.Header.Get("Signature") != "" {
+		return nil
+	}
+
+	signer, ok := service.signer(hostname)
+
+	if !ok {
+		return nil
+	}
+
+	// Digests are calculated from the request body, which is empty for GET requests
+	if request.Body == nil {
+		request.Body = http.NoBody
+	}
+
+	if err := signer.Sign(request); err != nil {
+		return derp.Wrap(err, "service.RequestSigner.Sign", "Error signing request", request.URL.String())
+	}
+
+	return nil
+}
+
+// RemoteOption returns a remote.Option that signs outbound GET requests with the key of the provided Domain
+func (service *RequestSigner) RemoteOption(hostname string) remote.Option {
+
+	return remote.Option{
+		ModifyRequest: func(_ *remote.Transaction, request *http.Request) *http.Response {
+			derp.Report(service.Sign(request, hostname))
+			return nil
+		},
+	}
+}
+
+// Client wraps a sherlock.Client so that documents loaded with the SignAs option
+// are requested using the key of that Domain
+func (service *RequestSigner) Client(innerClient sherlock.Client) streams.Client {
+	return NewRequestSignerClient(service, innerClient)
+}
+
+// signer returns the Signer registered for the provided Domain
+func (service *RequestSigner) signer(hostname string) (sigs.Signer, bool) {
+
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+
+	signer, ok := service.signers[hostname]
+	return signer, ok
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/remote"
+	"github.com/benpate/sherlock"
+)
+
+// SignAs is a load option that identifies the Domain whose instance actor
+// signs the outbound request.  It is passed through every client in the
+// ActivityStream stack until it reaches the RequestSignerClient.
+type SignAs string
+
+// RequestSignerClient is a streams.Client that signs outbound GET requests with
+// the key of the Domain that requested the document.  Documents loaded without
+// a SignAs option are requested unsigned.  It also answers requests that have
+// already been downloaded from the PreloadedResponse option (if any).
+type RequestSignerClient struct {
+	requestSigner *RequestSigner
+	innerClient   sherlock.Client
+}
+
+// NewRequestSignerClient returns a fully initialized RequestSignerClient that wraps the provided client
+func NewRequestSignerClient(requestSigner *RequestSigner, innerClient sherlock.Client) *RequestSignerClient {
+	return &RequestSignerClient{
+		requestSigner: requestSigner,
+		innerClient:   innerClient,
+	}
+}
+
+// Load implements the streams.Client interface
+func (client *RequestSignerClient) Load(uri string, options ...any) (streams.Document, error) {
+
+	remoteOptions := requestSignerRemoteOptions(client.requestSigner, options)
+
+	if len(remoteOptions) == 0 {
+		return client.innerClient.Load(uri, options...)
+	}
+
+	// Make a copy of the inner client that signs as the requesting Domain
+	// and/or uses responses that have already been downloaded
+	optionsCopy := make([]remote.Option, 0, len(client.innerClient.RemoteOptions)+len(remoteOptions))
+	optionsCopy = append(optionsCopy, client.innerClient.RemoteOptions...)
+	optionsCopy = append(optionsCopy, remoteOptions...)
+
+	signedClient := client.innerClient
+	signedClient.RemoteOptions = optionsCopy
+
+	return signedClient.Load(uri, options...)
+}
+
+// requestSignerRemoteOptions returns the remote.Options required by the SignAs
+// and PreloadedResponse options in the list
+func requestSignerRemoteOptions(requestSigner *RequestSigner, options []any) []remote.Option {
+
+	result := make([]remote.Option, 0)
+
+	for _, option := range options {
+		if preloaded, ok := option.(PreloadedResponse); ok {
+			result = append(result, preloaded.RemoteOption())
+		}
+	}
+
+	if hostname := requestSignerHostname(options); hostname != "" {
+		result = append(result, requestSigner.RemoteOption(hostname))
+	}
+
+	return result
+}
+
+// requestSignerHostname returns the hostname from the first SignAs option in the list
+func requestSignerHostname(options []any) string {
+
+	for _, option := range options {
+		if hostname, ok := option.(SignAs); ok {
+			return string(hostname)
+		}
+	}
+
+	return ""
+}
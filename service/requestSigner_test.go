@@ -0,0 +1,82 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"testing"
+
+	"github.com/benpate/hannibal/sigs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestSigner(t *testing.T) {
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+
+	publicPEM := sigs.EncodePublicPEM(privateKey)
+	keyFinder := func(keyID string) (string, error) {
+		require.Equal(t, "https://local.social/@application#main-key", keyID)
+		return publicPEM, nil
+	}
+
+	signer := NewRequestSigner()
+
+	// Requests are unsigned until a key is registered
+	request, _ := http.NewRequest(http.MethodGet, "https://remote.social/users/bob", nil)
+	require.Nil(t, signer.Sign(request, "local.social"))
+	require.Equal(t, "", request.Header.Get("Signature"))
+
+	// GET requests are signed with the registered key
+	signer.Set("local.social", "https://local.social/@application#main-key", privateKey)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://remote.social/users/bob", nil)
+	require.Nil(t, signer.Sign(request, "local.social"))
+	require.Nil(t, sigs.Verify(request, keyFinder, sigs.VerifierFields(sigs.FieldRequestTarget, sigs.FieldHost, sigs.FieldDate)))
+
+	// Other methods are left alone
+	request, _ = http.NewRequest(http.MethodPost, "https://remote.social/inbox", nil)
+	require.Nil(t, signer.Sign(request, "local.social"))
+	require.Equal(t, "", request.Header.Get("Signature"))
+
+	// Removing the key stops signing
+	signer.Remove("local.social")
+
+	request, _ = http.NewRequest(http.MethodGet, "https://remote.social/users/bob", nil)
+	require.Nil(t, signer.Sign(request, "local.social"))
+	require.Equal(t, "", request.Header.Get("Signature"))
+}
+
+func TestRequestSigner_ByDomain(t *testing.T) {
+
+	aliceKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+
+	bobKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+
+	signer := NewRequestSigner()
+	signer.Set("alice.social", "https://alice.social/@application#main-key", aliceKey)
+	signer.Set("bob.social", "https://bob.social/@application#main-key", bobKey)
+
+	// Each Domain signs with its own instance key
+	request, _ := http.NewRequest(http.MethodGet, "https://remote.social/users/carol", nil)
+	require.Nil(t, signer.Sign(request, "bob.social"))
+	require.Equal(t, "https://bob.social/@application#main-key", domainPolicyKeyID(request.Header.Get("Signature")))
+
+	request, _ = http.NewRequest(http.MethodGet, "https://remote.social/users/carol", nil)
+	require.Nil(t, signer.Sign(request, "alice.social"))
+	require.Equal(t, "https://alice.social/@application#main-key", domainPolicyKeyID(request.Header.Get("Signature")))
+
+	// Requests from unknown Domains are never signed with another Domain's key
+	request, _ = http.NewRequest(http.MethodGet, "https://remote.social/users/carol", nil)
+	require.Nil(t, signer.Sign(request, ""))
+	require.Equal(t, "", request.Header.Get("Signature"))
+}
+
+func TestRequestSignerHostname(t *testing.T) {
+	require.Equal(t, "local.social", requestSignerHostname([]any{"other", SignAs("local.social")}))
+	require.Equal(t, "", requestSignerHostname([]any{"local.social"}))
+	require.Equal(t, "", requestSignerHostname(nil))
+}
@@ -0,0 +1,493 @@
+package service
+
+import (
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/schema"
+	"github.com/benpate/rosetta/slice"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Review manages the editorial workflow Reviews that move Streams between States
+type Review struct {
+	collection    data.Collection
+	streamService *Stream
+	userService   *User
+	domainEmail   *DomainEmail
+	host          string
+}
+
+// NewReview returns a fully initialized Review service
+func NewReview() Review {
+	return Review{}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+// Refresh updates any stateful data that is cached inside this service.
+func (service *Review) Refresh(collection data.Collection, streamService *Stream, userService *User, domainEmail *DomainEmail, host string) {
+	service.collection = collection
+	service.streamService = streamService
+	service.userService = userService
+	service.domainEmail = domainEmail
+	service.host = host
+}
+
+// Close stops any background processes controlled by this service
+func (service *Review) Close() {
+	// Nothin to do here.
+}
+
+/******************************************
+ * Common Data Methods
+ ******************************************/
+
+// Query returns a slice of Reviews that match the provided criteria
+func (service *Review) Query(criteria exp.Expression, options ...option.Option) ([]model.Review, error) {
+	result := make([]model.Review, 0)
+	err := service.collection.Query(&result, notDeleted(criteria), options...)
+	return result, err
+}
+
+// List returns an iterator containing all of the Reviews that match the provided criteria
+func (service *Review) List(criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.collection.Iterator(notDeleted(criteria), options...)
+}
+
+// Load retrieves a Review from the database
+func (service *Review) Load(criteria exp.Expression, review *model.Review) error {
+
+	if err := service.collection.Load(notDeleted(criteria), review); err != nil {
+		return derp.Wrap(err, "service.Review.Load", "Error loading Review", criteria)
+	}
+
+	return nil
+}
+
+// Save adds/updates a Review in the database
+func (service *Review) Save(review *model.Review, note string) error {
+
+	// Validate the value before saving
+	if err := service.Schema().Validate(review); err != nil {
+		return derp.Wrap(err, "service.Review.Save", "Error validating Review", review)
+	}
+
+	// Save the value to the database
+	if err := service.collection.Save(review, note); err != nil {
+		return derp.Wrap(err, "service.Review.Save", "Error saving Review", review, note)
+	}
+
+	return nil
+}
+
+// Delete removes a Review from the database (virtual delete)
+func (service *Review) Delete(review *model.Review, note string) error {
+
+	if err := service.collection.Delete(review, note); err != nil {
+		return derp.Wrap(err, "service.Review.Delete", "Error deleting Review", review, note)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Model Service Methods
+ ******************************************/
+
+// ObjectType returns the type of object that this service manages
+func (service *Review) ObjectType() string {
+	return "Review"
+}
+
+// New returns a fully initialized model.Review as a data.Object.
+func (service *Review) ObjectNew() data.Object {
+	result := model.NewReview()
+	return &result
+}
+
+func (service *Review) ObjectID(object data.Object) primitive.ObjectID {
+
+	if review, ok := object.(*model.Review); ok {
+		return review.ReviewID
+	}
+
+	return primitive.NilObjectID
+}
+
+func (service *Review) ObjectQuery(result any, criteria exp.Expression, options ...option.Option) error {
+	return service.collection.Query(result, notDeleted(criteria), options...)
+}
+
+func (service *Review) ObjectList(criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.List(criteria, options...)
+}
+
+func (service *Review) ObjectLoad(criteria exp.Expression) (data.Object, error) {
+	result := model.NewReview()
+	err := service.Load(criteria, &result)
+	return &result, err
+}
+
+func (service *Review) ObjectSave(object data.Object, comment string) error {
+	if review, ok := object.(*model.Review); ok {
+		return service.Save(review, comment)
+	}
+	return derp.NewInternalError("service.Review.ObjectSave", "Invalid Object Type", object)
+}
+
+func (service *Review) ObjectDelete(object data.Object, comment string) error {
+	if review, ok := object.(*model.Review); ok {
+		return service.Delete(review, comment)
+	}
+	return derp.NewInternalError("service.Review.ObjectDelete", "Invalid Object Type", object)
+}
+
+func (service *Review) ObjectUserCan(object data.Object, authorization model.Authorization, action string) error {
+	return derp.NewUnauthorizedError("service.Review", "Not Authorized")
+}
+
+func (service *Review) Schema() schema.Schema {
+	return schema.New(model.ReviewSchema())
+}
+
+/******************************************
+ * Custom Queries
+ ******************************************/
+
+// LoadByID retrieves a single Review for the provided Stream
+func (service *Review) LoadByID(streamID primitive.ObjectID, reviewID primitive.ObjectID, review *model.Review) error {
+
+	criteria := exp.Equal("_id", reviewID).
+		AndEqual("streamId", streamID)
+
+	return service.Load(criteria, review)
+}
+
+// LoadByToken retrieves a single Review for the provided Stream, using a string token
+func (service *Review) LoadByToken(streamID primitive.ObjectID, token string, review *model.Review) error {
+
+	reviewID, err := primitive.ObjectIDFromHex(token)
+
+	if err != nil {
+		return derp.Wrap(err, "service.Review.LoadByToken", "Invalid token", token)
+	}
+
+	return service.LoadByID(streamID, reviewID, review)
+}
+
+// LoadPending retrieves the pending Review (if any) for the provided Stream
+func (service *Review) LoadPending(streamID primitive.ObjectID, review *model.Review) error {
+
+	criteria := exp.Equal("streamId", streamID).
+		AndEqual("statusId", model.ReviewStatusPending)
+
+	return service.Load(criteria, review)
+}
+
+// QueryPendingStreamIDs returns the IDs of all Streams that are waiting on a Review
+func (service *Review) QueryPendingStreamIDs() ([]primitive.ObjectID, error) {
+
+	reviews, err := service.Query(exp.Equal("statusId", model.ReviewStatusPending), option.Fields("streamId"))
+
+	if err != nil {
+		return nil, derp.Wrap(err, "service.Review.QueryPendingStreamIDs", "Error loading pending reviews")
+	}
+
+	return slice.Map(reviews, func(review model.Review) primitive.ObjectID {
+		return review.StreamID
+	}), nil
+}
+
+// QueryByStream returns all Reviews for the provided Stream, newest first
+func (service *Review) QueryByStream(streamID primitive.ObjectID, options ...option.Option) ([]model.Review, error) {
+	options = append(options, option.SortDesc("createDate"))
+	return service.Query(exp.Equal("streamId", streamID), options...)
+}
+
+// DeleteByStream removes all Reviews for the provided Stream
+func (service *Review) DeleteByStream(streamID primitive.ObjectID, note string) error {
+
+	reviews, err := service.Query(exp.Equal("streamId", streamID))
+
+	if err != nil {
+		return derp.Wrap(err, "service.Review.DeleteByStream", "Error querying Reviews", streamID)
+	}
+
+	for index := range reviews {
+		if err := service.Delete(&reviews[index], note); err != nil {
+			return derp.Wrap(err, "service.Review.DeleteByStream", "Error deleting Review", reviews[index])
+		}
+	}
+
+	return nil
+}
+
+/******************************************
+ * Workflow Methods
+ ******************************************/
+
+// Request creates a new Review that asks the assigned Users to approve a Transition for the provided Stream.
+func (service *Review) Request(stream *model.Stream, transition model.Transition, requester *model.User, assigneeIDs []primitive.ObjectID, comment string) (model.Review, error) {
+
+	const location = "service.Review.Request"
+
+	// RULE: Transition must be valid from the Stream's current state
+	if !transition.CanStartFrom(stream.StateID) {
+		return model.Review{}, derp.NewBadRequestError(location, "Transition is not allowed from the current state", transition.TransitionID, stream.StateID)
+	}
+
+	// RULE: Enough reviewers must be assigned to complete the Review
+	assigneeIDs, err := reviewAssignees(transition, assigneeIDs)
+
+	if err != nil {
+		return model.Review{}, derp.Wrap(err, location, "Invalid reviewers", transition.TransitionID)
+	}
+
+	// RULE: Only one pending Review is allowed per Stream.  Reviews that were requested from
+	// a different State can never be decided, so they are canceled to make room for the new one.
+	pending := model.NewReview()
+	if err := service.LoadPending(stream.StreamID, &pending); err == nil {
+
+		if pending.FromStateID == stream.StateID {
+			return model.Review{}, derp.NewBadRequestError(location, "This Stream already has a pending Review", stream.StreamID)
+		}
+
+		if err := service.Cancel(&pending, "Canceled: Stream state has changed"); err != nil {
+			return model.Review{}, derp.Wrap(err, location, "Error canceling outdated Review", pending.ReviewID)
+		}
+
+	} else if !derp.NotFound(err) {
+		return model.Review{}, derp.Wrap(err, location, "Error searching for pending Reviews", stream.StreamID)
+	}
+
+	// Create the new Review
+	review := model.NewReview()
+	review.StreamID = stream.StreamID
+	review.TransitionID = transition.TransitionID
+	review.FromStateID = stream.StateID
+	review.ToStateID = transition.ToState
+	review.RequestedBy = requester.PersonLink()
+	review.AssigneeIDs = assigneeIDs
+	review.Required = transition.RequiredApprovals()
+	review.Comment = comment
+
+	if err := service.Save(&review, "Requested by "+requester.DisplayName); err != nil {
+		return model.Review{}, derp.Wrap(err, location, "Error saving Review", review)
+	}
+
+	// Notify all assignees in the background
+	go service.notifyAssignees(*stream, review)
+
+	return review, nil
+}
+
+// Decide records a reviewer's decision on a pending Review.  Once enough approvals have been
+// collected, the Stream is moved into the Review's target State.  Transitions that publish the
+// Stream use the same publish path as the "publish" step, on behalf of the User who requested the Review.
+func (service *Review) Decide(review *model.Review, stream *model.Stream, transition model.Transition, reviewer *model.User, authorization *model.Authorization, decision string, comment string) error {
+
+	const location = "service.Review.Decide"
+
+	if err := recordReviewDecision(review, stream, transition, reviewer, authorization, decision, comment, time.Now().Unix()); err != nil {
+		return derp.Wrap(err, location, "Unable to record decision", review.ReviewID)
+	}
+
+	// Enough approvals moves the Stream into its new State
+	if review.IsApproved() {
+		if err := service.applyTransition(review, stream, transition, reviewer); err != nil {
+			return derp.Wrap(err, location, "Error applying Transition", review.TransitionID)
+		}
+	}
+
+	if err := service.Save(review, "Decision by "+reviewer.DisplayName); err != nil {
+		return derp.Wrap(err, location, "Error saving Review", review)
+	}
+
+	// Let the requester know that the Review is complete
+	if !review.IsPending() {
+		go service.notifyRequester(*stream, *review)
+	}
+
+	return nil
+}
+
+// applyTransition moves a Stream into the target State of an approved Review, and publishes it if the Transition requires.
+func (service *Review) applyTransition(review *model.Review, stream *model.Stream, transition model.Transition, reviewer *model.User) error {
+
+	const location = "service.Review.applyTransition"
+
+	stream.SetState(review.ToStateID)
+
+	if !transition.Publish {
+
+		if err := service.streamService.Save(stream, "Review approved by "+reviewer.DisplayName); err != nil {
+			return derp.Wrap(err, location, "Error saving Stream", stream)
+		}
+
+		return nil
+	}
+
+	// Published Streams are attributed to the User who requested the Review, not the reviewer
+	requester := model.NewUser()
+
+	if err := service.userService.LoadByID(review.RequestedBy.UserID, &requester); err != nil {
+		return derp.Wrap(err, location, "Error loading requester", review.RequestedBy)
+	}
+
+	if err := service.streamService.Publish(&requester, stream, true); err != nil {
+		return derp.Wrap(err, location, "Error publishing Stream", stream)
+	}
+
+	return nil
+}
+
+// Cancel withdraws a pending Review
+func (service *Review) Cancel(review *model.Review, note string) error {
+
+	const location = "service.Review.Cancel"
+
+	if !review.IsPending() {
+		return derp.NewBadRequestError(location, "Review is no longer pending", review.ReviewID, review.StatusID)
+	}
+
+	review.StatusID = model.ReviewStatusCanceled
+	review.CompleteDate = time.Now().Unix()
+
+	if err := service.Save(review, note); err != nil {
+		return derp.Wrap(err, location, "Error saving Review", review)
+	}
+
+	return nil
+}
+
+// reviewAssignees removes empty and duplicate reviewers from the provided list, and confirms
+// that enough reviewers remain to give all of the approvals that the Transition requires.
+func reviewAssignees(transition model.Transition, assigneeIDs []primitive.ObjectID) ([]primitive.ObjectID, error) {
+
+	const location = "service.reviewAssignees"
+
+	assigneeIDs = slice.Unique(slice.Filter(assigneeIDs, func(assigneeID primitive.ObjectID) bool {
+		return !assigneeID.IsZero()
+	}))
+
+	if len(assigneeIDs) < transition.RequiredApprovals() {
+		return nil, derp.NewBadRequestError(location, "Not enough reviewers assigned", len(assigneeIDs), transition.RequiredApprovals())
+	}
+
+	return assigneeIDs, nil
+}
+
+// recordReviewDecision validates a reviewer's decision and records it in the Review.
+// It updates the Review's status, but does not save anything to the database.
+func recordReviewDecision(review *model.Review, stream *model.Stream, transition model.Transition, reviewer *model.User, authorization *model.Authorization, decision string, comment string, now int64) error {
+
+	const location = "service.recordReviewDecision"
+
+	// RULE: Review must belong to this Stream
+	if review.StreamID != stream.StreamID {
+		return derp.NewBadRequestError(location, "Review does not belong to this Stream", review.ReviewID, stream.StreamID)
+	}
+
+	// RULE: Review must still be pending
+	if !review.IsPending() {
+		return derp.NewBadRequestError(location, "Review is no longer pending", review.ReviewID, review.StatusID)
+	}
+
+	// RULE: Stream must not have changed state since the Review was requested
+	if stream.StateID != review.FromStateID {
+		return derp.NewBadRequestError(location, "Stream state has changed since this Review was requested", review.FromStateID, stream.StateID)
+	}
+
+	// RULE: Reviewer must have been assigned to this Review
+	if !review.IsAssigned(reviewer.UserID) {
+		return derp.NewForbiddenError(location, "User is not assigned to this Review", reviewer.UserID, review.ReviewID)
+	}
+
+	// RULE: Reviewer must hold one of the approver roles for this Transition
+	if !transition.UserCanApprove(stream, authorization) {
+		return derp.NewForbiddenError(location, "User is not allowed to approve this Transition", reviewer.UserID, transition.TransitionID)
+	}
+
+	// RULE: Reviewers can only decide once
+	if review.HasDecided(reviewer.UserID) {
+		return derp.NewBadRequestError(location, "Reviewer has already recorded a decision", reviewer.UserID)
+	}
+
+	// RULE: Decision must be valid
+	if (decision != model.ReviewDecisionApprove) && (decision != model.ReviewDecisionReject) {
+		return derp.NewBadRequestError(location, "Invalid decision", decision)
+	}
+
+	// Record the decision
+	review.Approvals = append(review.Approvals, model.ReviewRecord{
+		Reviewer: reviewer.PersonLink(),
+		Decision: decision,
+		Comment:  comment,
+		Date:     now,
+	})
+
+	switch {
+
+	// A single rejection closes the Review
+	case decision == model.ReviewDecisionReject:
+		review.StatusID = model.ReviewStatusRejected
+		review.CompleteDate = now
+
+	// Enough approvals completes the Review
+	case review.RemainingApprovals() == 0:
+		review.StatusID = model.ReviewStatusApproved
+		review.CompleteDate = now
+	}
+
+	return nil
+}
+
+/******************************************
+ * Notifications
+ ******************************************/
+
+// notifyAssignees sends an email to every User assigned to a Review.
+// Errors are reported but not returned, so this can be run as a goroutine.
+func (service *Review) notifyAssignees(stream model.Stream, review model.Review) {
+
+	const location = "service.Review.notifyAssignees"
+
+	for _, assigneeID := range review.AssigneeIDs {
+
+		assignee := model.NewUser()
+
+		if err := service.userService.LoadByID(assigneeID, &assignee); err != nil {
+			derp.Report(derp.Wrap(err, location, "Error loading assignee", assigneeID))
+			continue
+		}
+
+		if err := service.domainEmail.SendReviewRequest(&assignee, &stream, &review); err != nil {
+			derp.Report(derp.Wrap(err, location, "Error sending review request", assigneeID))
+		}
+	}
+}
+
+// notifyRequester sends an email to the User who requested a Review, once it is complete.
+// Errors are reported but not returned, so this can be run as a goroutine.
+func (service *Review) notifyRequester(stream model.Stream, review model.Review) {
+
+	const location = "service.Review.notifyRequester"
+
+	requester := model.NewUser()
+
+	if err := service.userService.LoadByID(review.RequestedBy.UserID, &requester); err != nil {
+		derp.Report(derp.Wrap(err, location, "Error loading requester", review.RequestedBy))
+		return
+	}
+
+	if err := service.domainEmail.SendReviewDecision(&requester, &stream, &review); err != nil {
+		derp.Report(derp.Wrap(err, location, "Error sending review decision", review.RequestedBy))
+	}
+}
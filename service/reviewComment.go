@@ -0,0 +1,219 @@
+package service
+
+import (
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReviewComment manages the inline editorial comments that reviewers leave on Streams
+type ReviewComment struct {
+	collection data.Collection
+}
+
+// NewReviewComment returns a fully initialized ReviewComment service
+func NewReviewComment() ReviewComment {
+	return ReviewComment{}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+// Refresh updates any stateful data that is cached inside this service.
+func (service *ReviewComment) Refresh(collection data.Collection) {
+	service.collection = collection
+}
+
+// Close stops any background processes controlled by this service
+func (service *ReviewComment) Close() {
+	// Nothin to do here.
+}
+
+/******************************************
+ * Common Data Methods
+ ******************************************/
+
+// Query returns a slice of ReviewComments that match the provided criteria
+func (service *ReviewComment) Query(criteria exp.Expression, options ...option.Option) ([]model.ReviewComment, error) {
+	result := make([]model.ReviewComment, 0)
+	err := service.collection.Query(&result, notDeleted(criteria), options...)
+	return result, err
+}
+
+// List returns an iterator containing all of the ReviewComments that match the provided criteria
+func (service *ReviewComment) List(criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.collection.Iterator(notDeleted(criteria), options...)
+}
+
+// Load retrieves a ReviewComment from the database
+func (service *ReviewComment) Load(criteria exp.Expression, comment *model.ReviewComment) error {
+
+	if err := service.collection.Load(notDeleted(criteria), comment); err != nil {
+		return derp.Wrap(err, "service.ReviewComment.Load", "Error loading ReviewComment", criteria)
+	}
+
+	return nil
+}
+
+// Save adds/updates a ReviewComment in the database
+func (service *ReviewComment) Save(comment *model.ReviewComment, note string) error {
+
+	// Validate the value before saving
+	if err := service.Schema().Validate(comment); err != nil {
+		return derp.Wrap(err, "service.ReviewComment.Save", "Error validating ReviewComment", comment)
+	}
+
+	// Save the value to the database
+	if err := service.collection.Save(comment, note); err != nil {
+		return derp.Wrap(err, "service.ReviewComment.Save", "Error saving ReviewComment", comment, note)
+	}
+
+	return nil
+}
+
+// Delete removes a ReviewComment from the database (virtual delete)
+func (service *ReviewComment) Delete(comment *model.ReviewComment, note string) error {
+
+	if err := service.collection.Delete(comment, note); err != nil {
+		return derp.Wrap(err, "service.ReviewComment.Delete", "Error deleting ReviewComment", comment, note)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Model Service Methods
+ ******************************************/
+
+// ObjectType returns the type of object that this service manages
+func (service *ReviewComment) ObjectType() string {
+	return "ReviewComment"
+}
+
+// New returns a fully initialized model.ReviewComment as a data.Object.
+func (service *ReviewComment) ObjectNew() data.Object {
+	result := model.NewReviewComment()
+	return &result
+}
+
+func (service *ReviewComment) ObjectID(object data.Object) primitive.ObjectID {
+
+	if comment, ok := object.(*model.ReviewComment); ok {
+		return comment.ReviewCommentID
+	}
+
+	return primitive.NilObjectID
+}
+
+func (service *ReviewComment) ObjectQuery(result any, criteria exp.Expression, options ...option.Option) error {
+	return service.collection.Query(result, notDeleted(criteria), options...)
+}
+
+func (service *ReviewComment) ObjectList(criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.List(criteria, options...)
+}
+
+func (service *ReviewComment) ObjectLoad(criteria exp.Expression) (data.Object, error) {
+	result := model.NewReviewComment()
+	err := service.Load(criteria, &result)
+	return &result, err
+}
+
+func (service *ReviewComment) ObjectSave(object data.Object, comment string) error {
+	if reviewComment, ok := object.(*model.ReviewComment); ok {
+		return service.Save(reviewComment, comment)
+	}
+	return derp.NewInternalError("service.ReviewComment.ObjectSave", "Invalid Object Type", object)
+}
+
+func (service *ReviewComment) ObjectDelete(object data.Object, comment string) error {
+	if reviewComment, ok := object.(*model.ReviewComment); ok {
+		return service.Delete(reviewComment, comment)
+	}
+	return derp.NewInternalError("service.ReviewComment.ObjectDelete", "Invalid Object Type", object)
+}
+
+func (service *ReviewComment) ObjectUserCan(object data.Object, authorization model.Authorization, action string) error {
+	return derp.NewUnauthorizedError("service.ReviewComment", "Not Authorized")
+}
+
+func (service *ReviewComment) Schema() schema.Schema {
+	return schema.New(model.ReviewCommentSchema())
+}
+
+/******************************************
+ * Custom Queries
+ ******************************************/
+
+// LoadByID retrieves a single ReviewComment for the provided Stream
+func (service *ReviewComment) LoadByID(streamID primitive.ObjectID, reviewCommentID primitive.ObjectID, comment *model.ReviewComment) error {
+
+	criteria := exp.Equal("_id", reviewCommentID).
+		AndEqual("streamId", streamID)
+
+	return service.Load(criteria, comment)
+}
+
+// LoadByToken retrieves a single ReviewComment for the provided Stream, using a string token
+func (service *ReviewComment) LoadByToken(streamID primitive.ObjectID, token string, comment *model.ReviewComment) error {
+
+	reviewCommentID, err := primitive.ObjectIDFromHex(token)
+
+	if err != nil {
+		return derp.Wrap(err, "service.ReviewComment.LoadByToken", "Invalid token", token)
+	}
+
+	return service.LoadByID(streamID, reviewCommentID, comment)
+}
+
+// QueryByStream returns all ReviewComments for the provided Stream, in the order they were written
+func (service *ReviewComment) QueryByStream(streamID primitive.ObjectID, options ...option.Option) ([]model.ReviewComment, error) {
+	options = append(options, option.SortAsc("createDate"))
+	return service.Query(exp.Equal("streamId", streamID), options...)
+}
+
+// QueryOpenByStream returns all unresolved ReviewComments for the provided Stream
+func (service *ReviewComment) QueryOpenByStream(streamID primitive.ObjectID, options ...option.Option) ([]model.ReviewComment, error) {
+
+	criteria := exp.Equal("streamId", streamID).
+		AndEqual("isResolved", false)
+
+	options = append(options, option.SortAsc("createDate"))
+	return service.Query(criteria, options...)
+}
+
+// Resolve marks a ReviewComment as addressed by the provided User
+func (service *ReviewComment) Resolve(comment *model.ReviewComment, user *model.User) error {
+
+	comment.IsResolved = true
+	comment.ResolvedBy = user.PersonLink()
+
+	if err := service.Save(comment, "Resolved by "+user.DisplayName); err != nil {
+		return derp.Wrap(err, "service.ReviewComment.Resolve", "Error saving ReviewComment", comment)
+	}
+
+	return nil
+}
+
+// DeleteByStream removes all ReviewComments for the provided Stream
+func (service *ReviewComment) DeleteByStream(streamID primitive.ObjectID, note string) error {
+
+	comments, err := service.Query(exp.Equal("streamId", streamID))
+
+	if err != nil {
+		return derp.Wrap(err, "service.ReviewComment.DeleteByStream", "Error querying ReviewComments", streamID)
+	}
+
+	for index := range comments {
+		if err := service.Delete(&comments[index], note); err != nil {
+			return derp.Wrap(err, "service.ReviewComment.DeleteByStream", "Error deleting ReviewComment", comments[index])
+		}
+	}
+
+	return nil
+}
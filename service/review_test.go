@@ -0,0 +1,177 @@
+package service
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRecordReviewDecision_Approve(t *testing.T) {
+
+	stream, transition, review := testReviewFixture(2)
+	sarah, sarahAuth := testReviewer(&review)
+	john, johnAuth := testReviewer(&review)
+
+	// First approval leaves the Review pending
+	err := recordReviewDecision(&review, &stream, transition, &sarah, &sarahAuth, model.ReviewDecisionApprove, "Looks good", 1000)
+	require.Nil(t, err)
+	require.True(t, review.IsPending())
+	require.Equal(t, 1, review.RemainingApprovals())
+	require.Zero(t, review.CompleteDate)
+
+	// Reviewers can only decide once
+	err = recordReviewDecision(&review, &stream, transition, &sarah, &sarahAuth, model.ReviewDecisionApprove, "", 1001)
+	require.NotNil(t, err)
+	require.Equal(t, 1, len(review.Approvals))
+
+	// Second approval completes the Review
+	err = recordReviewDecision(&review, &stream, transition, &john, &johnAuth, model.ReviewDecisionApprove, "", 1002)
+	require.Nil(t, err)
+	require.True(t, review.IsApproved())
+	require.Equal(t, int64(1002), review.CompleteDate)
+
+	// Completed Reviews cannot be decided again
+	other, otherAuth := testReviewer(&review)
+	err = recordReviewDecision(&review, &stream, transition, &other, &otherAuth, model.ReviewDecisionReject, "", 1003)
+	require.NotNil(t, err)
+	require.True(t, review.IsApproved())
+}
+
+func TestRecordReviewDecision_Reject(t *testing.T) {
+
+	stream, transition, review := testReviewFixture(2)
+	sarah, sarahAuth := testReviewer(&review)
+
+	// A single rejection closes the Review
+	err := recordReviewDecision(&review, &stream, transition, &sarah, &sarahAuth, model.ReviewDecisionReject, "Needs work", 1000)
+	require.Nil(t, err)
+	require.Equal(t, model.ReviewStatusRejected, review.StatusID)
+	require.Equal(t, int64(1000), review.CompleteDate)
+	require.Equal(t, "Needs work", review.Approvals[0].Comment)
+}
+
+func TestRecordReviewDecision_Rules(t *testing.T) {
+
+	// Reviewers must be assigned to the Review, even if they are allowed to approve the Transition
+	{
+		stream, transition, review := testReviewFixture(1)
+		stranger, strangerAuth := testReviewer(&review)
+		review.AssigneeIDs = review.AssigneeIDs[:0]
+
+		err := recordReviewDecision(&review, &stream, transition, &stranger, &strangerAuth, model.ReviewDecisionApprove, "", 1000)
+		require.NotNil(t, err)
+		require.Empty(t, review.Approvals)
+		require.True(t, review.IsPending())
+	}
+
+	// Reviewers must hold an approver role
+	{
+		stream, transition, review := testReviewFixture(1)
+		sarah, sarahAuth := testReviewer(&review)
+		sarahAuth.DomainOwner = false
+
+		err := recordReviewDecision(&review, &stream, transition, &sarah, &sarahAuth, model.ReviewDecisionApprove, "", 1000)
+		require.NotNil(t, err)
+		require.Empty(t, review.Approvals)
+	}
+
+	// Review must belong to the Stream
+	{
+		stream, transition, review := testReviewFixture(1)
+		sarah, sarahAuth := testReviewer(&review)
+		stream.StreamID = primitive.NewObjectID()
+
+		err := recordReviewDecision(&review, &stream, transition, &sarah, &sarahAuth, model.ReviewDecisionApprove, "", 1000)
+		require.NotNil(t, err)
+	}
+
+	// Stream must still be in the State where the Review was requested
+	{
+		stream, transition, review := testReviewFixture(1)
+		sarah, sarahAuth := testReviewer(&review)
+		stream.StateID = "published"
+
+		err := recordReviewDecision(&review, &stream, transition, &sarah, &sarahAuth, model.ReviewDecisionApprove, "", 1000)
+		require.NotNil(t, err)
+	}
+
+	// Review must still be pending
+	{
+		stream, transition, review := testReviewFixture(1)
+		sarah, sarahAuth := testReviewer(&review)
+		review.StatusID = model.ReviewStatusCanceled
+
+		err := recordReviewDecision(&review, &stream, transition, &sarah, &sarahAuth, model.ReviewDecisionApprove, "", 1000)
+		require.NotNil(t, err)
+	}
+
+	// Decision must be valid
+	{
+		stream, transition, review := testReviewFixture(1)
+		sarah, sarahAuth := testReviewer(&review)
+
+		err := recordReviewDecision(&review, &stream, transition, &sarah, &sarahAuth, "MAYBE", "", 1000)
+		require.NotNil(t, err)
+		require.Empty(t, review.Approvals)
+	}
+}
+
+func TestReviewAssignees(t *testing.T) {
+
+	_, transition, _ := testReviewFixture(2)
+	sarah := primitive.NewObjectID()
+	john := primitive.NewObjectID()
+
+	// Duplicate and empty reviewers do not count toward the required approvals
+	_, err := reviewAssignees(transition, []primitive.ObjectID{sarah, sarah, primitive.NilObjectID})
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusBadRequest, derp.ErrorCode(err))
+
+	// Reviews need at least as many reviewers as required approvals
+	assigneeIDs, err := reviewAssignees(transition, []primitive.ObjectID{sarah, john, sarah})
+	require.Nil(t, err)
+	require.Equal(t, []primitive.ObjectID{sarah, john}, assigneeIDs)
+
+	// Transitions always require at least one reviewer
+	transition.Approvals = 0
+	_, err = reviewAssignees(transition, nil)
+	require.NotNil(t, err)
+}
+
+// testReviewFixture returns a Stream, Transition, and pending Review that requires the provided number of approvals
+func testReviewFixture(required int) (model.Stream, model.Transition, model.Review) {
+
+	stream := model.NewStream()
+	stream.StateID = "draft"
+
+	transition := model.NewTransition()
+	transition.TransitionID = "submit"
+	transition.ToState = "published"
+	transition.Approvals = required
+
+	review := model.NewReview()
+	review.StreamID = stream.StreamID
+	review.TransitionID = transition.TransitionID
+	review.FromStateID = stream.StateID
+	review.ToStateID = transition.ToState
+	review.Required = transition.RequiredApprovals()
+
+	return stream, transition, review
+}
+
+// testReviewer returns a new User (and Authorization) who is assigned to the provided Review
+func testReviewer(review *model.Review) (model.User, model.Authorization) {
+
+	user := model.NewUser()
+	review.AssigneeIDs = append(review.AssigneeIDs, user.UserID)
+
+	authorization := model.NewAuthorization()
+	authorization.UserID = user.UserID
+	authorization.DomainOwner = true
+
+	return user, authorization
+}
@@ -346,8 +346,39 @@ func (service *Rule) QueryDomainBlocks() ([]model.Rule, error) {
 
 	criteria := exp.Equal("userId", primitive.NilObjectID).
 		AndEqual("type", model.RuleTypeDomain).
-		AndEqual("behavior", model.RuleActionBlock)
+		AndEqual("action", model.RuleActionBlock)
+
+	return service.Query(criteria, option.SortAsc("trigger"))
+}
+
+// QueryDomainRules returns all external domains that are blocked or muted by this Instance/Domain.
+func (service *Rule) QueryDomainRules() ([]model.Rule, error) {
+
+	criteria := exp.Equal("userId", primitive.NilObjectID).
+		AndEqual("type", model.RuleTypeDomain).
+		AndIn("action", []string{model.RuleActionBlock, model.RuleActionMute})
+
+	return service.Query(criteria, option.SortAsc("trigger"))
+}
+
+// QueryDomainBlocksByActor returns all Actor and Domain Rules (created by this Instance/Domain) that block the provided Actor
+func (service *Rule) QueryDomainBlocksByActor(actorID string) ([]model.RuleSummary, error) {
+
+	criteria := exp.And(
+		exp.Equal("userId", primitive.NilObjectID),
+		exp.Or(
+			exp.Equal("type", model.RuleTypeActor).AndEqual("trigger", actorID),
+			exp.Equal("type", model.RuleTypeDomain).AndEqual("trigger", domain.NameOnly(actorID)),
+		),
+		exp.Equal("action", model.RuleActionBlock),
+	)
+
+	return service.QuerySummary(criteria)
+}
 
+// QueryByBlocklist returns all Rules that were imported from the provided Blocklist subscription.
+func (service *Rule) QueryByBlocklist(blocklistID primitive.ObjectID) ([]model.Rule, error) {
+	criteria := exp.Equal("userId", primitive.NilObjectID).AndEqual("blocklistId", blocklistID)
 	return service.Query(criteria, option.SortAsc("trigger"))
 }
 
@@ -69,3 +69,24 @@ func (filter *RuleFilter) ChannelSend(ch <-chan model.Follower) <-chan string {
 
 	return result
 }
+
+// ChannelAllow inspects the channel of Followers and passes through only
+// those who are allowed to receive messages.
+func (filter *RuleFilter) ChannelAllow(ch <-chan model.Follower) <-chan model.Follower {
+
+	result := make(chan model.Follower)
+	go func() {
+		defer close(result)
+
+		for follower := range ch {
+			if filter.AllowSend(follower.Actor.ProfileURL) {
+				log.Trace().Str("loc", "service.RuleFilter.ChannelAllow").Str("actorID", follower.Actor.ProfileURL).Msg("Allowed")
+				result <- follower
+			} else {
+				log.Trace().Str("loc", "service.RuleFilter.ChannelAllow").Str("actorID", follower.Actor.ProfileURL).Msg("Blocked")
+			}
+		}
+	}()
+
+	return result
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+)
+
+// SharedInboxClient is a streams.Client that collapses outbound deliveries onto the
+// shared inboxes of remote servers.  Followers that advertise a shared inbox are
+// replaced with a single synthetic recipient whose inbox is the shared inbox, so that
+// each remote server receives only one copy of each activity.
+type SharedInboxClient struct {
+	innerClient streams.Client
+	inboxes     sync.Map
+}
+
+// NewSharedInboxClient returns a fully initialized SharedInboxClient that wraps the provided client
+func NewSharedInboxClient(innerClient streams.Client) *SharedInboxClient {
+	return &SharedInboxClient{
+		innerClient: innerClient,
+	}
+}
+
+// Load implements the streams.Client interface.  Shared inbox URLs that were
+// registered by Collapse return a synthetic document whose inbox is the shared
+// inbox itself.  All other URLs are passed to the inner client.
+func (client *SharedInboxClient) Load(uri string, options ...any) (streams.Document, error) {
+
+	if _, ok := client.inboxes.Load(uri); ok {
+		return streams.NewDocument(
+			mapof.Any{
+				vocab.PropertyID:    uri,
+				vocab.PropertyType:  vocab.ActorTypeService,
+				vocab.PropertyInbox: uri,
+			},
+			streams.WithClient(client),
+		), nil
+	}
+
+	return client.innerClient.Load(uri, options...)
+}
+
+// Collapse returns a channel of recipient IDs for the provided Followers.  Followers
+// without a shared inbox are returned once each, and all Followers that share an inbox
+// are replaced by a single delivery to that shared inbox.
+func (client *SharedInboxClient) Collapse(followers <-chan model.Follower) <-chan string {
+
+	result := make(chan string)
+
+	go func() {
+		defer close(result)
+
+		profiles := make(map[string]bool)
+
+		for follower := range followers {
+
+			sharedInboxURL := follower.Actor.SharedInboxURL
+
+			// Followers without a shared inbox receive messages individually,
+			// but only once, even if they appear in the list more than once.
+			if sharedInboxURL == "" {
+
+				if profiles[follower.Actor.ProfileURL] {
+					continue
+				}
+
+				profiles[follower.Actor.ProfileURL] = true
+				result <- follower.Actor.ProfileURL
+				continue
+			}
+
+			// Only send to each shared inbox once
+			if _, loaded := client.inboxes.LoadOrStore(sharedInboxURL, true); loaded {
+				continue
+			}
+
+			result <- sharedInboxURL
+		}
+	}()
+
+	return result
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSharedInboxClient_Collapse(t *testing.T) {
+
+	client := NewSharedInboxClient(streams.NewDefaultClient())
+
+	followers := testSharedInboxFollowers(
+		testSharedInboxFollower("https://a.social/users/alice", "https://a.social/inbox"),
+		testSharedInboxFollower("https://a.social/users/andy", "https://a.social/inbox"),
+		testSharedInboxFollower("https://b.social/users/bob", ""),
+		testSharedInboxFollower("https://c.social/users/carol", "https://c.social/inbox"),
+		testSharedInboxFollower("https://b.social/users/betty", ""),
+		testSharedInboxFollower("https://a.social/users/amy", "https://a.social/inbox"),
+	)
+
+	require.Equal(t, []string{
+		"https://a.social/inbox",
+		"https://b.social/users/bob",
+		"https://c.social/inbox",
+		"https://b.social/users/betty",
+	}, testSharedInboxCollect(client.Collapse(followers)))
+}
+
+func TestSharedInboxClient_Collapse_Duplicates(t *testing.T) {
+
+	client := NewSharedInboxClient(streams.NewDefaultClient())
+
+	// The same actors can appear more than once (for instance, if they follow
+	// both a User and one of their Streams) but still receive only one copy.
+	followers := testSharedInboxFollowers(
+		testSharedInboxFollower("https://b.social/users/bob", ""),
+		testSharedInboxFollower("https://a.social/users/alice", "https://a.social/inbox"),
+		testSharedInboxFollower("https://b.social/users/bob", ""),
+		testSharedInboxFollower("https://a.social/users/alice", "https://a.social/inbox"),
+	)
+
+	require.Equal(t, []string{
+		"https://b.social/users/bob",
+		"https://a.social/inbox",
+	}, testSharedInboxCollect(client.Collapse(followers)))
+}
+
+func TestSharedInboxClient_Load(t *testing.T) {
+
+	client := NewSharedInboxClient(streams.NewDefaultClient())
+
+	followers := testSharedInboxFollowers(
+		testSharedInboxFollower("https://a.social/users/alice", "https://a.social/inbox"),
+	)
+
+	testSharedInboxCollect(client.Collapse(followers))
+
+	// Shared inboxes load as synthetic actors whose inbox is the shared inbox itself
+	document, err := client.Load("https://a.social/inbox")
+	require.Nil(t, err)
+	require.Equal(t, "https://a.social/inbox", document.ID())
+	require.Equal(t, vocab.ActorTypeService, document.Type())
+	require.Equal(t, "https://a.social/inbox", document.Inbox().ID())
+}
+
+func testSharedInboxFollower(profileURL string, sharedInboxURL string) model.Follower {
+	follower := model.NewFollower()
+	follower.Actor.ProfileURL = profileURL
+	follower.Actor.SharedInboxURL = sharedInboxURL
+	return follower
+}
+
+func testSharedInboxFollowers(followers ...model.Follower) <-chan model.Follower {
+
+	result := make(chan model.Follower, len(followers))
+
+	for _, follower := range followers {
+		result <- follower
+	}
+
+	close(result)
+	return result
+}
+
+func testSharedInboxCollect(recipients <-chan string) []string {
+
+	result := make([]string, 0)
+
+	for recipient := range recipients {
+		result = append(result, recipient)
+	}
+
+	return result
+}
@@ -0,0 +1,389 @@
+package service
+
+import (
+	"math/rand"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/afero"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// megabyte is the number of bytes in each unit of a storage quota
+const megabyte = 1024 * 1024
+
+// unattachedMediaTimeout is how long media uploaded via the Mastodon API can remain
+// unattached to a Stream before it is removed by the sweeper.
+const unattachedMediaTimeout = 24 * time.Hour
+
+// Storage enforces storage quotas on uploaded attachments, and runs a background
+// sweeper that removes orphaned attachments and files.
+type Storage struct {
+	attachmentService *Attachment
+	domainService     *Domain
+	groupService      *Group
+	streamService     *Stream
+	userService       *User
+	originals         afero.Fs
+	cache             afero.Fs
+	closed            chan bool
+}
+
+// NewStorage returns a fully initialized Storage service
+func NewStorage() Storage {
+	return Storage{
+		closed: make(chan bool),
+	}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+// Refresh updates any stateful data that is cached inside this service.
+func (service *Storage) Refresh(attachmentService *Attachment, domainService *Domain, groupService *Group, streamService *Stream, userService *User, originals afero.Fs, cache afero.Fs) {
+	service.attachmentService = attachmentService
+	service.domainService = domainService
+	service.groupService = groupService
+	service.streamService = streamService
+	service.userService = userService
+	service.originals = originals
+	service.cache = cache
+}
+
+// Close stops the background sweeper
+func (service *Storage) Close() {
+	close(service.closed)
+}
+
+// Start begins the background sweeper that removes orphaned attachments and files
+func (service *Storage) Start() {
+
+	const location = "service.Storage.Start"
+
+	// Wait until the service has booted up correctly.
+	for service.originals == nil {
+		time.Sleep(1 * time.Minute)
+	}
+
+	for {
+
+		// Sweep randomly between 6 and 7 hours
+		select {
+
+		case <-service.closed:
+			return
+
+		case <-time.After(time.Duration(rand.Intn(60)+360) * time.Minute):
+
+			if err := service.Sweep(); err != nil {
+				derp.Report(derp.Wrap(err, location, "Error sweeping orphaned attachments"))
+			}
+		}
+	}
+}
+
+/******************************************
+ * Quotas
+ ******************************************/
+
+// Quota returns the maximum number of bytes that the provided User can upload.
+// Members of groups with a storage quota receive the largest of their groups' quotas.
+// Everyone else receives the domain default.  Zero means unlimited.
+func (service *Storage) Quota(user *model.User) (int64, error) {
+
+	const location = "service.Storage.Quota"
+
+	groups, err := service.groupService.ListByIDs(user.GroupIDs...)
+
+	if err != nil {
+		return 0, derp.Wrap(err, location, "Error loading groups", user.GroupIDs)
+	}
+
+	result := int64(0)
+
+	for _, group := range groups {
+		result = max(result, group.StorageQuota)
+	}
+
+	if result == 0 {
+		result = service.domainService.Get().StorageQuota
+	}
+
+	return result * megabyte, nil
+}
+
+// Usage returns the number of files and total number of bytes uploaded by the provided User
+func (service *Storage) Usage(userID primitive.ObjectID) (int, int64, error) {
+
+	files, used, err := service.attachmentService.Usage(userID)
+
+	if err != nil {
+		return 0, 0, derp.Wrap(err, "service.Storage.Usage", "Error calculating usage", userID)
+	}
+
+	return files, used, nil
+}
+
+// CheckQuota returns an error if uploading the provided number of bytes
+// would put the User over their storage quota.
+func (service *Storage) CheckQuota(userID primitive.ObjectID, size int64) error {
+
+	const location = "service.Storage.CheckQuota"
+
+	// Anonymous uploads (if any) are not tracked
+	if userID.IsZero() {
+		return nil
+	}
+
+	user := model.NewUser()
+
+	if err := service.userService.LoadByID(userID, &user); err != nil {
+		return derp.Wrap(err, location, "Error loading user", userID)
+	}
+
+	quota, err := service.Quota(&user)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error calculating quota", userID)
+	}
+
+	// Zero means unlimited
+	if quota == 0 {
+		return nil
+	}
+
+	_, used, err := service.Usage(userID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error calculating usage", userID)
+	}
+
+	if used+size > quota {
+		return derp.New(http.StatusRequestEntityTooLarge, location, "Storage quota exceeded. This upload requires "+humanize.IBytes(uint64(size))+", but only "+humanize.IBytes(uint64(max(quota-used, 0)))+" remains.")
+	}
+
+	return nil
+}
+
+// Report returns the storage used by every User who has uploaded at least one file,
+// sorted with the largest usage first.
+func (service *Storage) Report() ([]model.StorageUsage, error) {
+
+	const location = "service.Storage.Report"
+
+	// Total all attachments by User
+	it, err := service.attachmentService.List(exp.All(), option.Fields("userId", "size"))
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Error listing attachments")
+	}
+
+	usage := make(map[primitive.ObjectID]*model.StorageUsage)
+	attachment := model.Attachment{}
+
+	for it.Next(&attachment) {
+
+		if !attachment.UserID.IsZero() {
+
+			item, exists := usage[attachment.UserID]
+
+			if !exists {
+				item = &model.StorageUsage{UserID: attachment.UserID}
+				usage[attachment.UserID] = item
+			}
+
+			item.Files++
+			item.Used += attachment.Size
+		}
+
+		attachment = model.Attachment{}
+	}
+
+	// Populate User details and quotas
+	result := make([]model.StorageUsage, 0, len(usage))
+
+	for userID, item := range usage {
+
+		user := model.NewUser()
+
+		if err := service.userService.LoadByID(userID, &user); err != nil {
+
+			if derp.NotFound(err) {
+				item.DisplayName = "(deleted user)"
+				result = append(result, *item)
+				continue
+			}
+
+			return nil, derp.Wrap(err, location, "Error loading user", userID)
+		}
+
+		quota, err := service.Quota(&user)
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Error calculating quota", userID)
+		}
+
+		item.DisplayName = user.DisplayName
+		item.Username = user.Username
+		item.Quota = quota
+		result = append(result, *item)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Used > result[j].Used
+	})
+
+	return result, nil
+}
+
+/******************************************
+ * Garbage Collection
+ ******************************************/
+
+// Sweep removes attachments whose Stream or User no longer exists, media uploads that
+// were never attached to a Stream, and any original or cached files that no longer
+// belong to an Attachment.
+func (service *Storage) Sweep() error {
+
+	const location = "service.Storage.Sweep"
+
+	if err := service.sweepAttachments(); err != nil {
+		return derp.Wrap(err, location, "Error sweeping attachments")
+	}
+
+	if err := service.sweepOriginals(); err != nil {
+		return derp.Wrap(err, location, "Error sweeping original files")
+	}
+
+	if err := service.sweepCache(); err != nil {
+		return derp.Wrap(err, location, "Error sweeping cached files")
+	}
+
+	return nil
+}
+
+// sweepAttachments removes Attachment records that no longer have a valid owner
+func (service *Storage) sweepAttachments() error {
+
+	const location = "service.Storage.sweepAttachments"
+
+	it, err := service.attachmentService.List(exp.All())
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error listing attachments")
+	}
+
+	expired := time.Now().Add(-unattachedMediaTimeout).Unix()
+	attachment := model.NewAttachment("", primitive.NilObjectID)
+
+	for it.Next(&attachment) {
+
+		if service.isOrphaned(&attachment, expired) {
+			if err := service.attachmentService.Delete(&attachment, "Removed by storage sweeper"); err != nil {
+				derp.Report(derp.Wrap(err, location, "Error deleting orphaned attachment", attachment.AttachmentID))
+			}
+		}
+
+		attachment = model.NewAttachment("", primitive.NilObjectID)
+	}
+
+	return nil
+}
+
+// isOrphaned returns TRUE if the provided Attachment should be removed by the sweeper
+func (service *Storage) isOrphaned(attachment *model.Attachment, expired int64) bool {
+
+	switch attachment.ObjectType {
+
+	case model.AttachmentObjectTypeStream:
+		stream := model.NewStream()
+		err := service.streamService.LoadByID(attachment.ObjectID, &stream)
+		return derp.NotFound(err)
+
+	case model.AttachmentObjectTypeUser:
+
+		// Mastodon uploads that were never attached to a Stream
+		if (attachment.Category == model.AttachmentCategoryMedia) && (attachment.CreateDate < expired) {
+			return true
+		}
+
+		user := model.NewUser()
+		err := service.userService.LoadByID(attachment.ObjectID, &user)
+		return derp.NotFound(err)
+	}
+
+	return false
+}
+
+// sweepOriginals removes original files that do not belong to an Attachment
+func (service *Storage) sweepOriginals() error {
+
+	const location = "service.Storage.sweepOriginals"
+
+	files, err := afero.ReadDir(service.originals, "")
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error reading originals directory")
+	}
+
+	for _, file := range files {
+
+		if file.IsDir() || service.attachmentExists(file.Name()) {
+			continue
+		}
+
+		if err := service.originals.Remove(file.Name()); err != nil {
+			derp.Report(derp.Wrap(err, location, "Error removing orphaned original", file.Name()))
+		}
+	}
+
+	return nil
+}
+
+// sweepCache removes cached renditions that do not belong to an Attachment
+func (service *Storage) sweepCache() error {
+
+	const location = "service.Storage.sweepCache"
+
+	files, err := afero.ReadDir(service.cache, "")
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error reading cache directory")
+	}
+
+	for _, file := range files {
+
+		if !file.IsDir() || service.attachmentExists(file.Name()) {
+			continue
+		}
+
+		if err := service.cache.RemoveAll(file.Name()); err != nil {
+			derp.Report(derp.Wrap(err, location, "Error removing orphaned cache folder", file.Name()))
+		}
+	}
+
+	return nil
+}
+
+// attachmentExists returns TRUE if the filename belongs to a current Attachment.
+// Files that are not named after an Attachment are never considered orphans.
+func (service *Storage) attachmentExists(filename string) bool {
+
+	attachmentID, err := primitive.ObjectIDFromHex(filename)
+
+	if err != nil {
+		return true
+	}
+
+	attachment := model.NewAttachment("", primitive.NilObjectID)
+	err = service.attachmentService.Load(exp.Equal("_id", attachmentID), &attachment)
+
+	return !derp.NotFound(err)
+}
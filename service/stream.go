@@ -36,8 +36,12 @@ type Stream struct {
 	contentService      *Content
 	keyService          *EncryptionKey
 	followerService     *Follower
+	followingService    *Following
 	ruleService         *Rule
+	domainPolicy        *DomainPolicy
 	userService         *User
+	reviewService       *Review
+	reviewComment       *ReviewComment
 	host                string
 	streamUpdateChannel chan<- model.Stream
 }
@@ -52,7 +56,7 @@ func NewStream() Stream {
  ******************************************/
 
 // Refresh updates any stateful data that is cached inside this service.
-func (service *Stream) Refresh(collection data.Collection, templateService *Template, draftService *StreamDraft, outboxService *Outbox, attachmentService *Attachment, activityService *ActivityStream, contentService *Content, keyService *EncryptionKey, followerService *Follower, ruleService *Rule, userService *User, host string, streamUpdateChannel chan model.Stream) {
+func (service *Stream) Refresh(collection data.Collection, templateService *Template, draftService *StreamDraft, outboxService *Outbox, attachmentService *Attachment, activityService *ActivityStream, contentService *Content, keyService *EncryptionKey, followerService *Follower, followingService *Following, ruleService *Rule, domainPolicyService *DomainPolicy, userService *User, reviewService *Review, reviewCommentService *ReviewComment, host string, streamUpdateChannel chan model.Stream) {
 	service.collection = collection
 	service.templateService = templateService
 	service.draftService = draftService
@@ -62,8 +66,12 @@ func (service *Stream) Refresh(collection data.Collection, templateService *Temp
 	service.contentService = contentService
 	service.keyService = keyService
 	service.followerService = followerService
+	service.followingService = followingService
 	service.ruleService = ruleService
+	service.domainPolicy = domainPolicyService
 	service.userService = userService
+	service.reviewService = reviewService
+	service.reviewComment = reviewCommentService
 
 	service.host = host
 	service.streamUpdateChannel = streamUpdateChannel
@@ -280,6 +288,16 @@ func (service *Stream) Delete(stream *model.Stream, note string) error {
 			derp.Report(derp.Wrap(err, "service.Stream.Delete", "Error deleting outbox messages", stream, note))
 		}
 
+		// RULE: Delete Reviews
+		if err := service.reviewService.DeleteByStream(stream.StreamID, note); err != nil {
+			derp.Report(derp.Wrap(err, "service.Stream.Delete", "Error deleting reviews", stream, note))
+		}
+
+		// RULE: Delete Review Comments
+		if err := service.reviewComment.DeleteByStream(stream.StreamID, note); err != nil {
+			derp.Report(derp.Wrap(err, "service.Stream.Delete", "Error deleting review comments", stream, note))
+		}
+
 	}()
 
 	// Bueno!!
@@ -402,6 +420,23 @@ func (service *Stream) QueryByAncestorAndDate(streamID primitive.ObjectID, publi
 	return service.Query(criteria, option.SortDesc("publishDate"), option.MaxRows(int64(pageSize)))
 }
 
+// QueryByHashtags returns the published, public Streams that include any of the provided (normalized) hashtag names
+func (service *Stream) QueryByHashtags(names []string, criteria exp.Expression, options ...option.Option) ([]model.Stream, error) {
+
+	if len(names) == 0 {
+		return make([]model.Stream, 0), nil
+	}
+
+	criteria = exp.And(
+		criteria,
+		hashtagExpression("tags.name", names),
+		exp.Equal("defaultAllow", model.MagicGroupIDAnonymous),
+		exp.LessThan("publishDate", time.Now().Unix()),
+	)
+
+	return service.Query(criteria, options...)
+}
+
 // LoadByToken returns a single `Stream` that matches a particular `Token`
 func (service *Stream) LoadByToken(token string, result *model.Stream) error {
 
@@ -871,7 +906,7 @@ func (service *Stream) CalcTagsFromPaths(stream *model.Stream, paths ...string)
 		for _, value := range hashtags {
 
 			tag := model.NewTag()
-			tag.Type = "Hashtag" // TODO: This constant should be defined by Hannibal
+			tag.Type = model.TagTypeHashtag
 			tag.Name = string(value.Char) + value.Tag
 			tag.Name = strings.TrimSuffix(tag.Name, ".")
 			tag.Name = strings.TrimSuffix(tag.Name, ",")
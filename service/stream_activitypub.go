@@ -6,6 +6,7 @@ import (
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/benpate/derp"
 	"github.com/benpate/hannibal/outbox"
+	"github.com/benpate/hannibal/streams"
 	"github.com/benpate/hannibal/vocab"
 	"github.com/benpate/rosetta/mapof"
 	"github.com/benpate/rosetta/slice"
@@ -67,6 +68,10 @@ func (service *Stream) JSONLD(stream *model.Stream) mapof.Any {
 		result[vocab.PropertyTag] = slice.Map(stream.Tags, model.TagAsJSONLD)
 	}
 
+	// Quote posts and quote permissions
+	service.quoteJSONLD(stream, result)
+	service.interactionPolicyJSONLD(stream, result)
+
 	// NOTE: According to Mastodon ActivityPub guide (https://docs.joinmastodon.org/spec/activitypub/)
 	// putting as:public in the To field means that this mesage is public, and "listed"
 	// putting as:public in the Cc field means that this message is public, but "unlisted"
@@ -113,8 +118,8 @@ func (service *Stream) ActivityPubActor(streamID primitive.ObjectID, withFollowe
 		return outbox.Actor{}, derp.Wrap(err, location, "Error extracting private key", encryptionKey)
 	}
 
-	// Return the ActivityPub Actor
-	actor := outbox.NewActor(service.ActivityPubURL(streamID), privateKey)
+	// Return the ActivityPub Actor.  Deliveries never reach suspended domains.
+	actor := outbox.NewActor(service.ActivityPubURL(streamID), privateKey, outbox.WithClient(service.domainPolicy.Client(streams.NewDefaultClient())))
 
 	// Populate the Actor's ActivityPub Followers, if requested
 	if withFollowers {
@@ -128,10 +133,13 @@ func (service *Stream) ActivityPubActor(streamID primitive.ObjectID, withFollowe
 
 		// Get a filter to prevent sending to "Blocked" followers
 		ruleFilter := service.ruleService.Filter(primitive.NilObjectID, WithBlocksOnly())
-		followerIDs := ruleFilter.ChannelSend(followers)
+
+		// Collapse followers onto the shared inboxes of their servers
+		sharedInboxes := NewSharedInboxClient(service.domainPolicy.Client(streams.NewDefaultClient()))
+		followerIDs := sharedInboxes.Collapse(service.domainPolicy.ChannelAllow(ruleFilter.ChannelAllow(followers)))
 
 		// Add the channel of follower IDs to the Actor
-		actor.With(outbox.WithFollowers(followerIDs))
+		actor.With(outbox.WithFollowers(followerIDs), outbox.WithClient(sharedInboxes))
 	}
 
 	return actor, nil
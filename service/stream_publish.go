@@ -39,6 +39,13 @@ func (service *Stream) Publish(user *model.User, stream *model.Stream, outbox bo
 	// RULE: Set Author to the currently logged in user.
 	stream.SetAttributedTo(user.PersonLink())
 
+	// RULE: Quoted documents must allow this User to quote them
+	if stream.QuoteURL != "" {
+		if err := service.CanQuote(user, stream.QuoteURL); err != nil {
+			return derp.Wrap(err, location, "User cannot quote this document", stream.QuoteURL)
+		}
+	}
+
 	// Re-save the Stream with the updated values.
 	if err := service.Save(stream, "Publishing"); err != nil {
 		return derp.Wrap(err, location, "Error saving stream", stream)
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"html"
+	"strings"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/rosetta/sliceof"
+)
+
+/******************************************
+ * Quote Posts
+ ******************************************/
+
+// quoteJSONLD adds the (many, overlapping) properties that other ActivityPub
+// servers use to identify a quote post.  This includes the FEP-e232 Link tag,
+// the Misskey/Fedibird properties, and an inline "RE:" fallback for servers
+// that do not understand quotes at all.
+func (service *Stream) quoteJSONLD(stream *model.Stream, result mapof.Any) {
+
+	if stream.QuoteURL == "" {
+		return
+	}
+
+	result["quote"] = stream.QuoteURL
+	result["quoteUrl"] = stream.QuoteURL
+	result["quoteUri"] = stream.QuoteURL
+	result["_misskey_quote"] = stream.QuoteURL
+
+	// FEP-e232 object link
+	tags, _ := result[vocab.PropertyTag].([]mapof.Any)
+	result[vocab.PropertyTag] = append(tags, mapof.Any{
+		vocab.PropertyType:      vocab.CoreTypeLink,
+		vocab.PropertyMediaType: vocab.ContentTypeJSONLDWithProfile,
+		vocab.PropertyHref:      stream.QuoteURL,
+		vocab.PropertyName:      "RE: " + stream.QuoteURL,
+	})
+
+	// Fallback for servers that cannot display quotes
+	content := result.GetString(vocab.PropertyContent)
+	if !strings.Contains(content, stream.QuoteURL) {
+		quoteURL := html.EscapeString(stream.QuoteURL)
+		result[vocab.PropertyContent] = content + `<p class="quote-inline">RE: <a href="` + quoteURL + `">` + quoteURL + `</a></p>`
+	}
+}
+
+// interactionPolicyJSONLD adds a GoToSocial/Mastodon style `interactionPolicy`
+// that tells other servers who is allowed to quote this stream.
+func (service *Stream) interactionPolicyJSONLD(stream *model.Stream, result mapof.Any) {
+
+	if stream.AttributedTo.UserID.IsZero() {
+		return
+	}
+
+	author := model.NewUser()
+	if err := service.userService.LoadByID(stream.AttributedTo.UserID, &author); err != nil {
+		return
+	}
+
+	result["interactionPolicy"] = mapof.Any{
+		"canQuote": mapof.Any{
+			"automaticApproval": quoteApproval(&author),
+		},
+	}
+}
+
+// quoteApproval returns the list of actors who are automatically allowed to quote
+// the provided author's posts.
+func quoteApproval(author *model.User) []string {
+
+	switch author.QuotePolicy {
+
+	case model.UserQuotePolicyNobody:
+		return []string{author.ActivityPubURL()}
+
+	case model.UserQuotePolicyFollowers:
+		return []string{author.ActivityPubURL(), author.ActivityPubFollowersURL()}
+	}
+
+	return []string{vocab.NamespaceActivityStreamsPublic}
+}
+
+// CanQuote returns an error if the provided User is not allowed to quote the document at `quoteURL`.
+// Local streams are checked against their author's quote policy.  Remote documents are checked
+// against their published `interactionPolicy`, and documents without a policy may be quoted by anyone.
+func (service *Stream) CanQuote(user *model.User, quoteURL string) error {
+
+	const location = "service.Stream.CanQuote"
+
+	// Local streams use the author's quote policy directly
+	if strings.HasPrefix(quoteURL, service.host+"/") {
+		return service.canQuoteLocal(user, quoteURL)
+	}
+
+	// Remote documents publish their own interaction policy
+	document, err := service.activityService.Load(quoteURL)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading quoted document", quoteURL)
+	}
+
+	approval := document.Get("interactionPolicy").Get("canQuote").Get("automaticApproval")
+
+	if approval.IsNil() {
+		return nil
+	}
+
+	// Approval may be granted to the author's followers, which requires loading the author
+	author := streams.NilDocument()
+
+	followersID := func() string {
+		author, _ = document.AttributedTo().Load()
+		return author.Followers().ID()
+	}
+
+	isFollowing := func() bool {
+		return service.isFollowing(user, author.ID())
+	}
+
+	if quoteAllowed(quoteApprovalList(approval), user.ActivityPubURL(), followersID, isFollowing) {
+		return nil
+	}
+
+	return derp.NewForbiddenError(location, "The author of this document does not allow quotes", quoteURL)
+}
+
+// canQuoteLocal checks the quote policy of a locally hosted stream's author
+func (service *Stream) canQuoteLocal(user *model.User, quoteURL string) error {
+
+	const location = "service.Stream.canQuoteLocal"
+
+	stream := model.NewStream()
+	if err := service.LoadByURL(quoteURL, &stream); err != nil {
+		return derp.Wrap(err, location, "Error loading quoted stream", quoteURL)
+	}
+
+	// Authors can always quote themselves
+	if stream.AttributedTo.UserID.IsZero() || (stream.AttributedTo.UserID == user.UserID) {
+		return nil
+	}
+
+	author := model.NewUser()
+	if err := service.userService.LoadByID(stream.AttributedTo.UserID, &author); err != nil {
+		return derp.Wrap(err, location, "Error loading quoted stream's author", stream.AttributedTo.UserID)
+	}
+
+	follower := model.NewFollower()
+	isFollower := service.followerService.LoadByActor(author.UserID, user.ActivityPubURL(), &follower) == nil
+
+	if author.AllowsQuotes(isFollower) {
+		return nil
+	}
+
+	return derp.NewForbiddenError(location, "The author of this stream does not allow quotes", quoteURL)
+}
+
+// isFollowing returns TRUE if the provided User follows the remote actor
+func (service *Stream) isFollowing(user *model.User, actorID string) bool {
+
+	following := model.NewFollowing()
+
+	if err := service.followingService.LoadByURL(user.UserID, actorID, &following); err != nil {
+		return false
+	}
+
+	return following.Status != model.FollowingStatusRejected
+}
+
+// quoteApprovalList returns the IDs of the actors and collections in an `automaticApproval` list
+func quoteApprovalList(approval streams.Document) sliceof.String {
+
+	result := sliceof.String{}
+
+	for item := approval; item.NotNil(); item = item.Tail() {
+		result = append(result, item.Head().ID())
+	}
+
+	return result
+}
+
+// quoteAllowed returns TRUE if an `automaticApproval` list allows the actor to quote a document.
+// Approval granted to the author's followers collection only applies if the actor follows the
+// author.  The followers collection and follow status are only looked up when they are needed.
+func quoteAllowed(allowed sliceof.String, actorID string, followersID func() string, isFollowing func() bool) bool {
+
+	if allowed.Contains(vocab.NamespaceActivityStreamsPublic) || allowed.Contains(actorID) {
+		return true
+	}
+
+	if followers := followersID(); (followers == "") || !allowed.Contains(followers) {
+		return false
+	}
+
+	return isFollowing()
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/sliceof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuoteApproval(t *testing.T) {
+
+	author := model.NewUser()
+	author.ProfileURL = "https://local.social/@alice"
+
+	tests := []struct {
+		policy   string
+		expected []string
+	}{
+		{model.UserQuotePolicyAnyone, []string{vocab.NamespaceActivityStreamsPublic}},
+		{model.UserQuotePolicyFollowers, []string{"https://local.social/@alice", "https://local.social/@alice/pub/followers"}},
+		{model.UserQuotePolicyNobody, []string{"https://local.social/@alice"}},
+		{"", []string{vocab.NamespaceActivityStreamsPublic}},
+	}
+
+	for _, test := range tests {
+		author.QuotePolicy = test.policy
+		require.Equal(t, test.expected, quoteApproval(&author), test.policy)
+	}
+}
+
+func TestQuoteApprovalList(t *testing.T) {
+
+	// Approval lists may be a single value, or an array of IDs and objects
+	require.Equal(t, sliceof.String{vocab.NamespaceActivityStreamsPublic}, quoteApprovalList(streams.NewDocument(vocab.NamespaceActivityStreamsPublic)))
+
+	approval := streams.NewDocument([]any{
+		"https://remote.social/users/bob",
+		map[string]any{vocab.PropertyID: "https://remote.social/users/bob/followers"},
+	})
+
+	require.Equal(t, sliceof.String{"https://remote.social/users/bob", "https://remote.social/users/bob/followers"}, quoteApprovalList(approval))
+}
+
+func TestQuoteAllowed(t *testing.T) {
+
+	const actorID = "https://local.social/@alice"
+	const followersID = "https://remote.social/users/bob/followers"
+
+	tests := []struct {
+		name        string
+		allowed     sliceof.String
+		followersID string
+		isFollowing bool
+		expected    bool
+	}{
+		{"public", sliceof.String{vocab.NamespaceActivityStreamsPublic}, followersID, false, true},
+		{"actor", sliceof.String{"https://remote.social/users/bob", actorID}, followersID, false, true},
+		{"follower", sliceof.String{"https://remote.social/users/bob", followersID}, followersID, true, true},
+		{"not a follower", sliceof.String{"https://remote.social/users/bob", followersID}, followersID, false, false},
+		{"followers not approved", sliceof.String{"https://remote.social/users/bob"}, followersID, true, false},
+		{"unknown followers", sliceof.String{"https://remote.social/users/bob", ""}, "", true, false},
+		{"nobody", sliceof.String{}, followersID, true, false},
+	}
+
+	for _, test := range tests {
+
+		followersLoaded := false
+
+		allowed := quoteAllowed(test.allowed, actorID, func() string {
+			followersLoaded = true
+			return test.followersID
+		}, func() bool {
+			return test.isFollowing
+		})
+
+		require.Equal(t, test.expected, allowed, test.name)
+
+		// The author is only loaded when public/direct approval is not enough
+		if test.name == "public" || test.name == "actor" {
+			require.False(t, followersLoaded, test.name)
+		}
+	}
+}
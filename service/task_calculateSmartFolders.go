@@ -0,0 +1,39 @@
+package service
+
+import (
+	"time"
+
+	"github.com/benpate/derp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// smartFolderDelay is how long to wait (collecting new messages) before recalculating a User's smart folders
+const smartFolderDelay = 30 * time.Second
+
+// TaskCalculateSmartFolders recalculates the unread counts of a User's smart folders,
+// and sends alerts for saved searches, outside of the message ingest path.
+type TaskCalculateSmartFolders struct {
+	folderService *Folder
+	userID        primitive.ObjectID
+}
+
+func NewTaskCalculateSmartFolders(folderService *Folder, userID primitive.ObjectID) TaskCalculateSmartFolders {
+	return TaskCalculateSmartFolders{
+		folderService: folderService,
+		userID:        userID,
+	}
+}
+
+func (task TaskCalculateSmartFolders) Run() error {
+
+	const location = "service.TaskCalculateSmartFolders.Run"
+
+	// Allow messages that arrive from here on to schedule another recalculation
+	task.folderService.clearPendingSmartFolders(task.userID)
+
+	if err := task.folderService.CalculateSmartFolders(task.userID); err != nil {
+		return derp.Wrap(err, location, "Error calculating smart folders", task.userID)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/remote"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/labstack/gommon/random"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// rssCloudLease is how long an RSSCloud subscription lasts before it must be renewed
+// https://www.rssboard.org/rsscloud-interface#notifications-expire
+const rssCloudLease = 25 * time.Hour
+
+// TaskCreateRSSCloudFollower verifies an RSSCloud "pleaseNotify" request and creates/updates
+// the corresponding Follower record.
+type TaskCreateRSSCloudFollower struct {
+	followerService *Follower
+	locatorService  Locator
+	objectType      string
+	objectID        primitive.ObjectID
+	feedURL         string
+	callback        string
+	challenge       bool
+}
+
+// NewTaskCreateRSSCloudFollower returns a fully initialized TaskCreateRSSCloudFollower.  If challenge is TRUE,
+// then the subscriber is verified with a challenge (GET) request.  Otherwise, it is verified with a test notification (POST).
+func NewTaskCreateRSSCloudFollower(followerService *Follower, locatorService Locator, objectType string, objectID primitive.ObjectID, feedURL string, callback string, challenge bool) TaskCreateRSSCloudFollower {
+	return TaskCreateRSSCloudFollower{
+		followerService: followerService,
+		locatorService:  locatorService,
+		objectType:      objectType,
+		objectID:        objectID,
+		feedURL:         feedURL,
+		callback:        callback,
+		challenge:       challenge,
+	}
+}
+
+func (task TaskCreateRSSCloudFollower) Run() error {
+
+	const location = "service.TaskCreateRSSCloudFollower.Run"
+
+	// Validate the feed in our own database
+	objectType, objectID, err := task.locatorService.GetObjectFromURL(task.feedURL)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error parsing feed URL", task.feedURL)
+	}
+
+	if (objectType != task.objectType) || (objectID != task.objectID) {
+		return derp.NewBadRequestError(location, "Feed URL does not match this object", task.feedURL)
+	}
+
+	// Validate the request with the subscriber
+	if err := task.validate(); err != nil {
+		return derp.Wrap(err, location, "Error validating request", task.callback)
+	}
+
+	// Create/Update the Follower record
+	follower, err := task.followerService.LoadOrCreateByRSSCloud(task.objectType, task.objectID, task.callback)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading follower", task.objectID, task.callback)
+	}
+
+	follower.StateID = model.FollowerStateActive
+	follower.Format = model.MimeTypeRSS
+	follower.ExpireDate = time.Now().Add(rssCloudLease).Unix()
+	follower.Data = mapof.Any{
+		"url": task.feedURL,
+	}
+
+	if err := task.followerService.Save(&follower, "Created via RSSCloud"); err != nil {
+		return derp.Wrap(err, location, "Error saving follower", follower.ID)
+	}
+
+	return nil
+}
+
+// validate confirms that the subscriber is able to receive notifications for this feed
+func (task TaskCreateRSSCloudFollower) validate() error {
+
+	const location = "service.TaskCreateRSSCloudFollower.validate"
+
+	// If the subscriber did not include a domain, then it only needs to accept a test notification
+	if !task.challenge {
+
+		transaction := remote.Post(task.callback).
+			Form("url", task.feedURL)
+
+		if err := transaction.Send(); err != nil {
+			return derp.Wrap(err, location, "Error sending test notification", task.callback)
+		}
+
+		return nil
+	}
+
+	// Otherwise, the subscriber must echo back a challenge
+	var body string
+
+	challenge := random.String(42)
+	transaction := remote.Get(task.callback).
+		Query("url", task.feedURL).
+		Query("challenge", challenge).
+		Result(&body)
+
+	if err := transaction.Send(); err != nil {
+		return derp.Wrap(err, location, "Error sending verification request", task.callback)
+	}
+
+	if body != challenge {
+		return derp.NewBadRequestError(location, "Invalid challenge response", task.callback)
+	}
+
+	return nil
+}
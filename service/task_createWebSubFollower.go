@@ -82,6 +82,7 @@ func (task TaskCreateWebSubFollower) subscribe() error {
 	follower.ExpireDate = time.Now().Add(time.Second * time.Duration(task.leaseSeconds)).Unix()
 	follower.Data = mapof.Any{
 		"secret": task.secret,
+		"topic":  task.topic,
 	}
 
 	// Validate the request with the client
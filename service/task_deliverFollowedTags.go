@@ -0,0 +1,33 @@
+package service
+
+import (
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+)
+
+// TaskDeliverFollowedTags adds a public document to the hashtag Folder of every
+// User who follows one of its hashtags, outside of the message ingest path.
+type TaskDeliverFollowedTags struct {
+	followedTagService *FollowedTag
+	document           streams.Document
+	names              []string
+}
+
+func NewTaskDeliverFollowedTags(followedTagService *FollowedTag, document streams.Document, names []string) TaskDeliverFollowedTags {
+	return TaskDeliverFollowedTags{
+		followedTagService: followedTagService,
+		document:           document,
+		names:              names,
+	}
+}
+
+func (task TaskDeliverFollowedTags) Run() error {
+
+	const location = "service.TaskDeliverFollowedTags.Run"
+
+	if err := task.followedTagService.deliverAll(task.document, task.names); err != nil {
+		return derp.Wrap(err, location, "Error delivering document", task.document.ID())
+	}
+
+	return nil
+}
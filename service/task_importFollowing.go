@@ -0,0 +1,31 @@
+package service
+
+import (
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+)
+
+// TaskImportFollowing saves a single imported Following record in the background,
+// which also connects to the remote feed.
+type TaskImportFollowing struct {
+	followingService *Following
+	following        model.Following
+	note             string
+}
+
+func NewTaskImportFollowing(followingService *Following, following model.Following, note string) TaskImportFollowing {
+	return TaskImportFollowing{
+		followingService: followingService,
+		following:        following,
+		note:             note,
+	}
+}
+
+func (task TaskImportFollowing) Run() error {
+
+	if err := task.followingService.Save(&task.following, task.note); err != nil {
+		return derp.Wrap(err, "service.TaskImportFollowing.Run", "Error saving imported Following", task.following.URL)
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/remote"
+)
+
+// TaskSendRSSCloudMessage notifies a single RSSCloud follower that a feed has changed.
+type TaskSendRSSCloudMessage struct {
+	follower model.Follower
+}
+
+func NewTaskSendRSSCloudMessage(follower model.Follower) TaskSendRSSCloudMessage {
+	return TaskSendRSSCloudMessage{
+		follower: follower,
+	}
+}
+
+func (task TaskSendRSSCloudMessage) Run() error {
+
+	// RSSCloud notifications only include the URL of the changed feed
+	transaction := remote.Post(task.follower.Actor.InboxURL).
+		Form("url", task.follower.Data.GetString("url"))
+
+	if err := transaction.Send(); err != nil {
+		return derp.Wrap(err, "service.TaskSendRSSCloudMessage", "Error sending RSSCloud notification", task.follower)
+	}
+
+	return nil
+}
@@ -1,60 +1,112 @@
 package service
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
+	"time"
 
 	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/convert"
+	"github.com/EmissarySocial/emissary/tools/hmac"
 	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
 	"github.com/benpate/remote"
+	"github.com/benpate/rosetta/first"
+	"github.com/gorilla/feeds"
+	"github.com/kr/jsonfeed"
 )
 
 // TaskSendWebSubMessage sends a WebSub notification to a single WebSub follower.
+// Notifications are "fat pings" that include the changed entry in the follower's
+// requested format, so that subscribers do not need to re-fetch the feed.  If the
+// document is empty, then the notification is a "thin ping" with no entries.
+// https://www.w3.org/TR/websub/#content-distribution
 type TaskSendWebSubMessage struct {
 	follower model.Follower
+	document streams.Document
 }
 
-func NewTaskSendWebSubMessage(follower model.Follower) TaskSendWebSubMessage {
+func NewTaskSendWebSubMessage(follower model.Follower, document streams.Document) TaskSendWebSubMessage {
 	return TaskSendWebSubMessage{
 		follower: follower,
+		document: document,
 	}
 }
 
 func (task TaskSendWebSubMessage) Run() error {
 
-	var body []byte
+	const location = "service.TaskSendWebSubMessage.Run"
 
-	// TODO: LOW: SendWebSubMessage will require a refactor if we want to send "fat pings":
-	// https://indieweb.org/How_to_publish_and_consume_WebSub
-	switch task.follower.Format {
-
-	case model.MimeTypeJSONFeed:
-		// Convert & Marshall
-
-	case model.MimeTypeAtom:
-		// Convert & Marshall
+	body, contentType, err := task.body()
 
-	case model.MimeTypeRSS:
-		// Convert & Marshall
+	if err != nil {
+		return derp.Wrap(err, location, "Error generating WebSub message", task.follower)
 	}
 
 	transaction := remote.Post(task.follower.Actor.InboxURL).
-		Header("Content-Type", task.follower.Format).
+		Header("Content-Type", contentType).
 		Body(string(body))
 
 	// Add HMAC signature, if necessary
 	if secret := task.follower.Data.GetString("secret"); secret != "" {
-		mac := hmac.New(sha256.New, []byte(secret))
-		mac.Write(body)
-		transaction.Header("X-Hub-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		if signature, ok := hmac.Sign("sha256", secret, body); ok {
+			transaction.Header("X-Hub-Signature", "sha256="+hex.EncodeToString(signature))
+		}
 	}
 
 	// Try to send the transaction to the remote WebSub client
 	if err := transaction.Send(); err != nil {
-		return derp.Wrap(err, "service.TaskSendWebSubMessage", "Error sending WebSub message", task.follower)
+		return derp.Wrap(err, location, "Error sending WebSub message", task.follower)
 	}
 
 	// Woot woot!
 	return nil
 }
+
+// body generates a feed containing the changed document, in the format requested by the follower.
+func (task TaskSendWebSubMessage) body() ([]byte, string, error) {
+
+	isFatPing := task.document.NotNil()
+
+	// The topic is the URL that the follower subscribed to.  Older
+	// subscriptions did not record it, so fall back to the document's actor.
+	topic := first.String(task.follower.Data.GetString("topic"), task.document.AttributedTo().ID())
+
+	switch task.follower.Format {
+
+	case model.MimeTypeAtom, model.MimeTypeRSS, model.MimeTypeXML, model.MimeTypeXMLText:
+
+		feed := feeds.Feed{
+			Title:   topic,
+			Link:    &feeds.Link{Href: topic},
+			Created: time.Now(),
+		}
+
+		if isFatPing {
+			feed.Items = []*feeds.Item{convert.DocumentToGorillaFeed(task.document)}
+		}
+
+		if task.follower.Format == model.MimeTypeAtom {
+			result, err := feed.ToAtom()
+			return []byte(result), model.MimeTypeAtom, err
+		}
+
+		result, err := feed.ToRss()
+		return []byte(result), model.MimeTypeRSS, err
+	}
+
+	// Default to JSONFeed
+	feed := jsonfeed.Feed{
+		Version: "https://jsonfeed.org/version/1.1",
+		Title:   topic,
+		FeedURL: topic,
+		Items:   []jsonfeed.Item{},
+	}
+
+	if isFatPing {
+		feed.Items = append(feed.Items, convert.DocumentToJsonFeed(task.document))
+	}
+
+	result, err := json.Marshal(feed)
+	return result, model.MimeTypeJSONFeed, err
+}
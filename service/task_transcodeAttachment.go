@@ -0,0 +1,253 @@
+package service
+
+import (
+	"io"
+	"mime"
+	"os"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/transcoder"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/mediaserver"
+	"github.com/spf13/afero"
+)
+
+// TaskTranscodeAttachment converts an uploaded video or audio file into a web-safe
+// rendition, records its dimensions and duration, and extracts a poster frame for videos.
+// Renditions are written into the mediaserver cache so that they are served directly
+// by the attachment handler.
+type TaskTranscodeAttachment struct {
+	attachmentService *Attachment
+	originals         afero.Fs
+	cache             afero.Fs
+	attachment        model.Attachment
+}
+
+func NewTaskTranscodeAttachment(attachmentService *Attachment, originals afero.Fs, cache afero.Fs, attachment model.Attachment) TaskTranscodeAttachment {
+	return TaskTranscodeAttachment{
+		attachmentService: attachmentService,
+		originals:         originals,
+		cache:             cache,
+		attachment:        attachment,
+	}
+}
+
+func (task TaskTranscodeAttachment) Run() error {
+
+	const location = "service.TaskTranscodeAttachment.Run"
+
+	attachment := task.attachment
+
+	// Mark the Attachment as READY no matter what happens next,
+	// so that the original file can still be downloaded.
+	defer func() {
+		if err := task.save(&attachment); err != nil {
+			derp.Report(derp.Wrap(err, location, "Error saving Attachment", attachment.AttachmentID))
+		}
+	}()
+
+	// If ffmpeg is not available then there's nothing else to do.
+	if !transcoder.IsInstalled() {
+		return nil
+	}
+
+	// ffmpeg needs a real file to work with, so copy the original into a temp file
+	input, err := task.copyOriginal()
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error copying original file", attachment.AttachmentID)
+	}
+
+	defer os.Remove(input)
+
+	// Read dimensions and duration
+	info, err := transcoder.Probe(input)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error probing media file", attachment.AttachmentID)
+	}
+
+	attachment.Width = info.Width
+	attachment.Height = info.Height
+	attachment.Duration = info.Duration
+
+	// Create a web-safe rendition
+	var renditionSize int64
+
+	if attachment.IsVideo() {
+		renditionSize, err = task.write(input, info, transcoder.VideoExtension, transcoder.Video)
+	} else {
+		renditionSize, err = task.write(input, info, transcoder.AudioExtension, transcoder.Audio)
+	}
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error transcoding media file", attachment.AttachmentID)
+	}
+
+	// Only download the rendition once it has been written successfully
+	if attachment.IsVideo() {
+		attachment.Rendition = transcoder.VideoExtension
+	} else {
+		attachment.Rendition = transcoder.AudioExtension
+	}
+
+	attachment.RenditionSize = renditionSize
+
+	// Videos also get a poster frame
+	if attachment.IsVideo() {
+
+		if _, err := task.write(input, info, ".webp", transcoder.Poster); err != nil {
+			return derp.Wrap(err, location, "Error creating poster frame", attachment.AttachmentID)
+		}
+
+		attachment.PosterURL = attachment.URL + ".webp"
+
+		// Use the poster frame as a placeholder for the video
+		if hash, err := task.posterBlurhash(); err == nil {
+			attachment.Blurhash = hash
+		} else {
+			derp.Report(derp.Wrap(err, location, "Error calculating blurhash", attachment.AttachmentID))
+		}
+	}
+
+	return nil
+}
+
+// save reloads the Attachment and updates only the values calculated by the transcoder,
+// so that edits made while transcoding (descriptions, focal points, new owners) are not lost.
+func (task TaskTranscodeAttachment) save(transcoded *model.Attachment) error {
+
+	const location = "service.TaskTranscodeAttachment.save"
+
+	attachment := model.Attachment{}
+
+	if err := task.attachmentService.Load(exp.Equal("_id", transcoded.AttachmentID), &attachment); err != nil {
+		return derp.Wrap(err, location, "Error reloading Attachment")
+	}
+
+	attachment.Width = transcoded.Width
+	attachment.Height = transcoded.Height
+	attachment.Duration = transcoded.Duration
+	attachment.Rendition = transcoded.Rendition
+	attachment.RenditionSize = transcoded.RenditionSize
+	attachment.Blurhash = transcoded.Blurhash
+	attachment.Status = model.AttachmentStatusReady
+
+	// The Attachment may have moved while it was transcoding
+	if transcoded.PosterURL != "" {
+		attachment.PosterURL = attachment.URL + ".webp"
+	}
+
+	if err := task.attachmentService.Save(&attachment, "Transcoded"); err != nil {
+		return derp.Wrap(err, location, "Error saving Attachment")
+	}
+
+	return nil
+}
+
+// posterBlurhash calculates a BlurHash from the cached poster frame
+func (task TaskTranscodeAttachment) posterBlurhash() (string, error) {
+
+	filespec := mediaserver.FileSpec{
+		Filename:  task.attachment.AttachmentID.Hex(),
+		Extension: ".webp",
+		MimeType:  "image/webp",
+	}
+
+	poster, err := task.cache.Open(filespec.CachePath())
+
+	if err != nil {
+		return "", derp.Wrap(err, "service.TaskTranscodeAttachment.posterBlurhash", "Error opening poster frame")
+	}
+
+	defer poster.Close()
+
+	return encodeBlurhash(poster)
+}
+
+// copyOriginal copies the original file into a temporary file on the local filesystem
+func (task TaskTranscodeAttachment) copyOriginal() (string, error) {
+
+	const location = "service.TaskTranscodeAttachment.copyOriginal"
+
+	source, err := task.originals.Open(task.attachment.AttachmentID.Hex())
+
+	if err != nil {
+		return "", derp.Wrap(err, location, "Error opening original file")
+	}
+
+	defer source.Close()
+
+	destination, err := os.CreateTemp("", "emissary-original-*"+task.attachment.OriginalExtension())
+
+	if err != nil {
+		return "", derp.Wrap(err, location, "Error creating temp file")
+	}
+
+	defer destination.Close()
+
+	if _, err := io.Copy(destination, source); err != nil {
+		os.Remove(destination.Name())
+		return "", derp.Wrap(err, location, "Error writing temp file")
+	}
+
+	return destination.Name(), nil
+}
+
+// write runs the provided transcoder function and saves the result into the mediaserver cache.
+// It returns the size (in bytes) of the file that was written.
+func (task TaskTranscodeAttachment) write(input string, info transcoder.Info, extension string, fn func(string, string, transcoder.Info) error) (int64, error) {
+
+	const location = "service.TaskTranscodeAttachment.write"
+
+	filespec := mediaserver.FileSpec{
+		Filename:  task.attachment.AttachmentID.Hex(),
+		Extension: extension,
+		MimeType:  mime.TypeByExtension(extension),
+	}
+
+	// Reserve a temporary file for ffmpeg to write into
+	output, err := os.CreateTemp("", "emissary-rendition-*"+extension)
+
+	if err != nil {
+		return 0, derp.Wrap(err, location, "Error creating temp file")
+	}
+
+	output.Close()
+	defer os.Remove(output.Name())
+
+	// Run the transcoder
+	if err := fn(input, output.Name(), info); err != nil {
+		return 0, derp.Wrap(err, location, "Error running transcoder")
+	}
+
+	// Copy the rendition into the cache
+	if err := task.cache.MkdirAll(filespec.CacheDir(), 0777); err != nil {
+		return 0, derp.Wrap(err, location, "Error creating cache directory", filespec.CacheDir())
+	}
+
+	source, err := os.Open(output.Name())
+
+	if err != nil {
+		return 0, derp.Wrap(err, location, "Error opening rendition")
+	}
+
+	defer source.Close()
+
+	destination, err := task.cache.Create(filespec.CachePath())
+
+	if err != nil {
+		return 0, derp.Wrap(err, location, "Error creating cached file", filespec.CachePath())
+	}
+
+	defer destination.Close()
+
+	size, err := io.Copy(destination, source)
+
+	if err != nil {
+		return 0, derp.Wrap(err, location, "Error writing cached file", filespec.CachePath())
+	}
+
+	return size, nil
+}
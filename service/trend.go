@@ -0,0 +1,235 @@
+package service
+
+import (
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Trend manages the hashtags, links, and statuses that are popular in recent activity
+// on this server.  Trends are recalculated by a background process, and are only
+// shown publicly once they have been approved by a Domain Owner.
+type Trend struct {
+	collection      data.Collection
+	inboxService    *Inbox
+	responseService *Response
+	streamService   *Stream
+	reviewService   *Review
+	activityService *ActivityStream
+	host            string
+	closed          chan bool
+}
+
+// NewTrend returns a fully initialized Trend service
+func NewTrend() Trend {
+	return Trend{
+		closed: make(chan bool),
+	}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+// Refresh updates any stateful data that is cached inside this service.
+func (service *Trend) Refresh(collection data.Collection, inboxService *Inbox, responseService *Response, streamService *Stream, reviewService *Review, activityService *ActivityStream, host string) {
+	service.collection = collection
+	service.inboxService = inboxService
+	service.responseService = responseService
+	service.streamService = streamService
+	service.reviewService = reviewService
+	service.activityService = activityService
+	service.host = host
+}
+
+// Close stops the background process that calculates trends
+func (service *Trend) Close() {
+	close(service.closed)
+}
+
+// Start begins the background process that recalculates trends every hour
+func (service *Trend) Start() {
+
+	// Wait until the service has booted up correctly.
+	for service.collection == nil {
+		time.Sleep(1 * time.Minute)
+	}
+
+	for {
+
+		select {
+
+		case <-service.closed:
+			return
+
+		case <-time.After(1 * time.Hour):
+			if err := service.Calculate(time.Now()); err != nil {
+				derp.Report(derp.Wrap(err, "service.Trend.Start", "Error calculating trends"))
+			}
+		}
+	}
+}
+
+/******************************************
+ * Common Data Methods
+ ******************************************/
+
+// Query returns a slice of Trends that match the provided criteria
+func (service *Trend) Query(criteria exp.Expression, options ...option.Option) ([]model.Trend, error) {
+	result := make([]model.Trend, 0)
+	err := service.collection.Query(&result, notDeleted(criteria), options...)
+	return result, err
+}
+
+// List returns an iterator containing all of the Trends that match the provided criteria
+func (service *Trend) List(criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.collection.Iterator(notDeleted(criteria), options...)
+}
+
+// Load retrieves a Trend from the database
+func (service *Trend) Load(criteria exp.Expression, trend *model.Trend) error {
+
+	if err := service.collection.Load(notDeleted(criteria), trend); err != nil {
+		return derp.Wrap(err, "service.Trend.Load", "Error loading Trend", criteria)
+	}
+
+	return nil
+}
+
+// Save adds/updates a Trend in the database
+func (service *Trend) Save(trend *model.Trend, note string) error {
+
+	const location = "service.Trend.Save"
+
+	// Validate the value before saving
+	if err := service.Schema().Validate(trend); err != nil {
+		return derp.Wrap(err, location, "Error validating Trend", trend)
+	}
+
+	// Save the value to the database
+	if err := service.collection.Save(trend, note); err != nil {
+		return derp.Wrap(err, location, "Error saving Trend", trend, note)
+	}
+
+	return nil
+}
+
+// Delete removes a Trend from the database (hard delete).  Deleted Trends
+// will be re-created (and require review again) if they are still popular.
+func (service *Trend) Delete(trend *model.Trend, note string) error {
+
+	if err := service.collection.HardDelete(exp.Equal("_id", trend.TrendID)); err != nil {
+		return derp.Wrap(err, "service.Trend.Delete", "Error deleting Trend", trend.TrendID, note)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Model Service Methods
+ ******************************************/
+
+// ObjectType returns the type of object that this service manages
+func (service *Trend) ObjectType() string {
+	return "Trend"
+}
+
+// New returns a fully initialized model.Trend as a data.Object.
+func (service *Trend) ObjectNew() data.Object {
+	result := model.NewTrend()
+	return &result
+}
+
+func (service *Trend) ObjectID(object data.Object) primitive.ObjectID {
+
+	if trend, ok := object.(*model.Trend); ok {
+		return trend.TrendID
+	}
+
+	return primitive.NilObjectID
+}
+
+func (service *Trend) ObjectQuery(result any, criteria exp.Expression, options ...option.Option) error {
+	return service.collection.Query(result, notDeleted(criteria), options...)
+}
+
+func (service *Trend) ObjectList(criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.List(criteria, options...)
+}
+
+func (service *Trend) ObjectLoad(criteria exp.Expression) (data.Object, error) {
+	result := model.NewTrend()
+	err := service.Load(criteria, &result)
+	return &result, err
+}
+
+func (service *Trend) ObjectSave(object data.Object, comment string) error {
+	if trend, ok := object.(*model.Trend); ok {
+		return service.Save(trend, comment)
+	}
+	return derp.NewInternalError("service.Trend.ObjectSave", "Invalid Object Type", object)
+}
+
+func (service *Trend) ObjectDelete(object data.Object, comment string) error {
+	if trend, ok := object.(*model.Trend); ok {
+		return service.Delete(trend, comment)
+	}
+	return derp.NewInternalError("service.Trend.ObjectDelete", "Invalid Object Type", object)
+}
+
+func (service *Trend) ObjectUserCan(object data.Object, authorization model.Authorization, action string) error {
+	return derp.NewUnauthorizedError("service.Trend", "Not Authorized")
+}
+
+func (service *Trend) Schema() schema.Schema {
+	return schema.New(model.TrendSchema())
+}
+
+/******************************************
+ * Custom Queries
+ ******************************************/
+
+// LoadByID retrieves a single Trend by its ID
+func (service *Trend) LoadByID(trendID primitive.ObjectID, trend *model.Trend) error {
+	return service.Load(exp.Equal("_id", trendID), trend)
+}
+
+// LoadByKey retrieves a single Trend by its type and key
+func (service *Trend) LoadByKey(trendType string, key string, trend *model.Trend) error {
+	return service.Load(exp.Equal("type", trendType).AndEqual("key", key), trend)
+}
+
+// QueryByType returns the active Trends of a given type, most popular first.
+func (service *Trend) QueryByType(trendType string, criteria exp.Expression, options ...option.Option) ([]model.Trend, error) {
+
+	criteria = exp.And(
+		criteria,
+		exp.Equal("type", trendType),
+		exp.GreaterThan("score", 0),
+	)
+
+	options = append(options, option.SortDesc("score"))
+	return service.Query(criteria, options...)
+}
+
+// QueryApproved returns the active Trends of a given type that can be shown publicly, most popular first.
+func (service *Trend) QueryApproved(trendType string, options ...option.Option) ([]model.Trend, error) {
+	return service.QueryByType(trendType, exp.Equal("stateId", model.TrendStateApproved), options...)
+}
+
+// LoadApprovedTag retrieves an approved, active hashtag Trend.  This is used to
+// populate the usage history of hashtags in the Mastodon API.
+func (service *Trend) LoadApprovedTag(name string, trend *model.Trend) error {
+
+	criteria := exp.Equal("type", model.TrendTypeTag).
+		AndEqual("key", model.NormalizeHashtag(name)).
+		AndEqual("stateId", model.TrendStateApproved)
+
+	return service.Load(criteria, trend)
+}
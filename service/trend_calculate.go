@@ -0,0 +1,541 @@
+package service
+
+import (
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/striputm"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/html"
+	"github.com/benpate/rosetta/sliceof"
+	"github.com/benpate/sherlock"
+)
+
+// trendPeriod is the amount of recent activity that is included when calculating trends
+const trendPeriod = 7 * 24 * time.Hour
+
+// trendHalfLife is the amount of time it takes for an account's contribution to a trend to lose half of its weight
+const trendHalfLife = 24 * time.Hour
+
+// trendRejectedRetention is the amount of time that rejected trends are remembered after they stop trending
+const trendRejectedRetention = 30 * 24 * time.Hour
+
+// trendHistoryDays is the number of days of usage history that are stored for each trend
+const trendHistoryDays = 7
+
+// trendMinimumAccounts is the number of distinct accounts that must use a hashtag, link, or status before it can trend
+const trendMinimumAccounts = 2
+
+// trendMaxCandidates is the maximum number of trends of each type that are saved during each calculation
+const trendMaxCandidates = 50
+
+/******************************************
+ * Calculation Methods
+ ******************************************/
+
+// Calculate recalculates all trends using recent inbox Messages, Responses, and public Streams.
+// New trends are saved in the PENDING state, and must be approved before they are shown publicly.
+func (service *Trend) Calculate(now time.Time) error {
+
+	const location = "service.Trend.Calculate"
+
+	since := now.Add(0 - trendPeriod)
+	tallies := trendTallies{}
+
+	if err := service.tallyMessages(tallies, since, now); err != nil {
+		return derp.Wrap(err, location, "Error counting inbox messages")
+	}
+
+	if err := service.tallyStreams(tallies, since, now); err != nil {
+		return derp.Wrap(err, location, "Error counting streams")
+	}
+
+	if err := service.tallyResponses(tallies, since, now); err != nil {
+		return derp.Wrap(err, location, "Error counting responses")
+	}
+
+	// Save the most popular trends of each type
+	for _, trendType := range []string{model.TrendTypeTag, model.TrendTypeLink, model.TrendTypeStatus} {
+		for _, tally := range tallies.Top(trendType, now, trendMaxCandidates) {
+			if err := service.saveTally(tally, now); err != nil {
+				derp.Report(derp.Wrap(err, location, "Error saving trend", tally.trendType, tally.key))
+			}
+		}
+	}
+
+	// Remove trends that are no longer popular
+	if err := service.expire(now); err != nil {
+		return derp.Wrap(err, location, "Error expiring trends")
+	}
+
+	return nil
+}
+
+// tallyMessages counts the hashtags and links in public documents that were delivered to local inboxes.
+// Hashtags and links are recorded on each Message when it is received, so no documents are loaded here.
+func (service *Trend) tallyMessages(tallies trendTallies, since time.Time, now time.Time) error {
+
+	const location = "service.Trend.tallyMessages"
+
+	criteria := exp.GreaterOrEqual("publishDate", since.Unix()).AndLessThan("publishDate", now.Unix())
+	iterator, err := service.inboxService.List(criteria, option.Fields("url", "attributedTo", "hashtags", "links", "publishDate"))
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error querying messages")
+	}
+
+	defer iterator.Close()
+
+	// The same document may be delivered to many inboxes, but only counts once
+	counted := make(map[string]bool)
+	message := model.NewMessage()
+
+	for iterator.Next(&message) {
+
+		if (message.URL != "") && !counted[message.URL] {
+
+			counted[message.URL] = true
+			account := message.AttributedTo.ProfileURL
+
+			for _, name := range message.Hashtags {
+				tallies.Add(model.TrendTypeTag, name, account, message.PublishDate)
+			}
+
+			for _, link := range message.Links {
+				tallies.Add(model.TrendTypeLink, link, account, message.PublishDate)
+			}
+		}
+
+		message = model.NewMessage()
+	}
+
+	return nil
+}
+
+// tallyStreams counts the hashtags and links in public Streams that are currently published on this server.
+// Streams that are waiting on an editorial Review are not counted.
+func (service *Trend) tallyStreams(tallies trendTallies, since time.Time, now time.Time) error {
+
+	const location = "service.Trend.tallyStreams"
+
+	criteria := exp.GreaterOrEqual("publishDate", since.Unix()).
+		AndLessThan("publishDate", now.Unix()).
+		AndGreaterThan("unpublishDate", now.Unix()).
+		AndEqual("defaultAllow", model.MagicGroupIDAnonymous)
+
+	inReview, err := service.reviewService.QueryPendingStreamIDs()
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error querying pending reviews")
+	}
+
+	if len(inReview) > 0 {
+		criteria = criteria.AndNotIn("_id", inReview)
+	}
+
+	iterator, err := service.streamService.List(criteria)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error querying streams")
+	}
+
+	defer iterator.Close()
+
+	stream := model.NewStream()
+
+	for iterator.Next(&stream) {
+
+		account := stream.AttributedTo.ProfileURL
+
+		for _, name := range stream.Hashtags() {
+			tallies.Add(model.TrendTypeTag, name, account, stream.PublishDate)
+		}
+
+		for _, link := range trendLinks(stream.Content.HTML) {
+			tallies.Add(model.TrendTypeLink, link, account, stream.PublishDate)
+		}
+
+		if link := normalizeTrendLink(stream.QuoteURL); link != "" {
+			tallies.Add(model.TrendTypeLink, link, account, stream.PublishDate)
+		}
+
+		stream = model.NewStream()
+	}
+
+	return nil
+}
+
+// tallyResponses counts the Likes and Announces (boosts) that each status has received.
+func (service *Trend) tallyResponses(tallies trendTallies, since time.Time, now time.Time) error {
+
+	const location = "service.Trend.tallyResponses"
+
+	criteria := exp.GreaterOrEqual("createDate", since.UnixMilli()).
+		AndLessThan("createDate", now.UnixMilli()).
+		AndIn("type", []string{vocab.ActivityTypeLike, vocab.ActivityTypeAnnounce})
+
+	iterator, err := service.responseService.List(criteria)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error querying responses")
+	}
+
+	defer iterator.Close()
+
+	response := model.NewResponse()
+
+	for iterator.Next(&response) {
+		tallies.Add(model.TrendTypeStatus, response.Object, response.Actor, response.CreateDateSeconds())
+		response = model.NewResponse()
+	}
+
+	return nil
+}
+
+// saveTally updates (or creates) the Trend that matches a tally
+func (service *Trend) saveTally(tally *trendTally, now time.Time) error {
+
+	const location = "service.Trend.saveTally"
+
+	trend := model.NewTrend()
+
+	if err := service.LoadByKey(tally.trendType, tally.key, &trend); err != nil {
+
+		if !derp.NotFound(err) {
+			return derp.Wrap(err, location, "Error loading trend", tally.key)
+		}
+
+		// Describe new trends so that they can be reviewed
+		trend.Type = tally.trendType
+		trend.Key = tally.key
+
+		if !service.describe(&trend) {
+			return nil
+		}
+	}
+
+	trend.Score = tally.Score(now)
+	trend.Uses = tally.uses
+	trend.Accounts = int64(len(tally.accounts))
+	trend.History = tally.History(now)
+	trend.CalculatedDate = now.Unix()
+
+	if err := service.Save(&trend, "Calculated"); err != nil {
+		return derp.Wrap(err, location, "Error saving trend", trend.Key)
+	}
+
+	return nil
+}
+
+// describe populates the labels and preview information for a new Trend.
+// It returns FALSE if the Trend cannot be shown publicly.
+func (service *Trend) describe(trend *model.Trend) bool {
+
+	switch trend.Type {
+
+	case model.TrendTypeTag:
+		trend.Label = "#" + trend.Key
+		return true
+
+	case model.TrendTypeLink:
+
+		// Links that cannot be loaded still trend, but without a preview card
+		trend.Label = trend.Key
+
+		if parsed, err := url.Parse(trend.Key); err == nil {
+			trend.ProviderName = parsed.Hostname()
+		}
+
+		document, err := service.activityService.Load(trend.Key, sherlock.AsDocument())
+
+		if err != nil {
+			return true
+		}
+
+		if name := document.Name(); name != "" {
+			trend.Label = trendTruncate(name, 256)
+		}
+
+		trend.Description = html.Summary(document.Summary())
+		trend.ImageURL = document.ImageOrIcon().URL()
+		trend.AuthorName = trendTruncate(document.AttributedTo().Name(), 128)
+		return true
+
+	case model.TrendTypeStatus:
+
+		document, err := service.activityService.Load(trend.Key)
+
+		if err != nil {
+			return false
+		}
+
+		// RULE: Only public statuses can trend
+		if !relayIsPublic(document) {
+			return false
+		}
+
+		author := document.AttributedTo()
+
+		trend.Label = trendTruncate(author.Name(), 256)
+		trend.Description = html.Summary(document.Content())
+		trend.ImageURL = author.IconOrImage().URL()
+		trend.AuthorName = trendTruncate(author.Name(), 128)
+		return true
+	}
+
+	return false
+}
+
+// expire deactivates trends that were not seen in the most recent calculation,
+// and removes them entirely once they have been inactive for the trending period.
+func (service *Trend) expire(now time.Time) error {
+
+	const location = "service.Trend.expire"
+
+	stale, err := service.Query(exp.LessThan("calculatedDate", now.Unix()).AndGreaterThan("score", 0))
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error querying stale trends")
+	}
+
+	for index := range stale {
+
+		trend := &stale[index]
+		trend.Score = 0
+		trend.Uses = 0
+		trend.Accounts = 0
+
+		if err := service.Save(trend, "Deactivated"); err != nil {
+			return derp.Wrap(err, location, "Error deactivating trend", trend.TrendID)
+		}
+	}
+
+	// Rejected trends are remembered longer so that they are not submitted for review again
+	criteria := exp.Or(
+		exp.LessThan("calculatedDate", now.Add(0-trendPeriod).Unix()).AndNotEqual("stateId", model.TrendStateRejected),
+		exp.LessThan("calculatedDate", now.Add(0-trendRejectedRetention).Unix()),
+	)
+
+	if err := service.collection.HardDelete(criteria); err != nil {
+		return derp.Wrap(err, location, "Error removing expired trends")
+	}
+
+	return nil
+}
+
+/******************************************
+ * Tallies
+ ******************************************/
+
+// trendTally accumulates the uses of a single hashtag, link, or status during a calculation
+type trendTally struct {
+	trendType string
+	key       string
+	uses      int64
+	accounts  map[string]int64 // most recent use by each account
+	days      map[int64]map[string]int64
+}
+
+// trendTallies is a set of trendTally records, indexed by type and key
+type trendTallies map[string]*trendTally
+
+// Add records a single use of a trend by an account at the provided unix timestamp
+func (tallies trendTallies) Add(trendType string, key string, account string, timestamp int64) {
+
+	if (key == "") || (account == "") {
+		return
+	}
+
+	index := trendType + " " + key
+	tally, ok := tallies[index]
+
+	if !ok {
+		tally = &trendTally{
+			trendType: trendType,
+			key:       key,
+			accounts:  make(map[string]int64),
+			days:      make(map[int64]map[string]int64),
+		}
+		tallies[index] = tally
+	}
+
+	tally.uses++
+
+	if timestamp > tally.accounts[account] {
+		tally.accounts[account] = timestamp
+	}
+
+	day := trendDay(timestamp)
+
+	if tally.days[day] == nil {
+		tally.days[day] = make(map[string]int64)
+	}
+
+	tally.days[day][account]++
+}
+
+// Top returns the most popular tallies of a given type that have been used by enough accounts
+func (tallies trendTallies) Top(trendType string, now time.Time, maxCount int) []*trendTally {
+
+	result := make([]*trendTally, 0)
+	scores := make(map[*trendTally]float64)
+
+	for _, tally := range tallies {
+
+		if tally.trendType != trendType {
+			continue
+		}
+
+		if len(tally.accounts) < trendMinimumAccounts {
+			continue
+		}
+
+		scores[tally] = tally.Score(now)
+		result = append(result, tally)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+
+		if scores[result[i]] == scores[result[j]] {
+			return result[i].key < result[j].key
+		}
+
+		return scores[result[i]] > scores[result[j]]
+	})
+
+	if len(result) > maxCount {
+		result = result[:maxCount]
+	}
+
+	return result
+}
+
+// Score returns the popularity of this tally.  Each account contributes once, and
+// its contribution decays with the age of its most recent use.
+func (tally *trendTally) Score(now time.Time) float64 {
+
+	result := float64(0)
+
+	for _, timestamp := range tally.accounts {
+
+		age := now.Sub(time.Unix(timestamp, 0))
+
+		if age < 0 {
+			age = 0
+		}
+
+		result += math.Pow(0.5, age.Hours()/trendHalfLife.Hours())
+	}
+
+	return result
+}
+
+// History returns the daily usage statistics for this tally, newest first
+func (tally *trendTally) History(now time.Time) sliceof.Object[model.TrendHistory] {
+
+	today := trendDay(now.Unix())
+	result := make(sliceof.Object[model.TrendHistory], trendHistoryDays)
+
+	for index := range result {
+
+		day := today - int64(index*86400)
+		result[index].Day = day
+
+		for _, uses := range tally.days[day] {
+			result[index].Uses += uses
+			result[index].Accounts++
+		}
+	}
+
+	return result
+}
+
+/******************************************
+ * Helper Functions
+ ******************************************/
+
+// trendDay returns the unix timestamp of midnight (UTC) on the day of the provided timestamp
+func trendDay(timestamp int64) int64 {
+	return timestamp - (timestamp % 86400)
+}
+
+// trendLinks returns the external links in an HTML document.  Hashtags and mentions are skipped,
+// and tracking parameters are removed so that shares of the same page are counted together.
+func trendLinks(body string) []string {
+
+	result := make([]string, 0)
+
+	if body == "" {
+		return result
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+
+	if err != nil {
+		return result
+	}
+
+	found := make(map[string]bool)
+
+	doc.Find("a[href]").Each(func(_ int, node *goquery.Selection) {
+
+		if node.HasClass("mention") || node.HasClass("hashtag") {
+			return
+		}
+
+		for _, rel := range strings.Fields(node.AttrOr("rel", "")) {
+			if rel == "tag" {
+				return
+			}
+		}
+
+		link := normalizeTrendLink(node.AttrOr("href", ""))
+
+		if (link == "") || found[link] {
+			return
+		}
+
+		found[link] = true
+		result = append(result, link)
+	})
+
+	return result
+}
+
+// normalizeTrendLink removes tracking parameters and fragments from an external link.
+// It returns an empty string if the link is not an external http(s) URL.
+func normalizeTrendLink(href string) string {
+
+	if !isExternalHref(href) {
+		return ""
+	}
+
+	parsed, err := url.Parse(href)
+
+	if err != nil {
+		return ""
+	}
+
+	striputm.StripFromURL(parsed)
+	parsed.Fragment = ""
+
+	return parsed.String()
+}
+
+// trendTruncate limits a string to the provided number of characters
+func trendTruncate(value string, length int) string {
+
+	if runes := []rune(value); len(runes) > length {
+		return string(runes[:length])
+	}
+
+	return value
+}
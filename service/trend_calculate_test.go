@@ -0,0 +1,98 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrendTallies_Top(t *testing.T) {
+
+	now := time.Unix(1700000000, 0)
+	tallies := trendTallies{}
+
+	// Used by three accounts
+	tallies.Add(model.TrendTypeTag, "caturday", "https://a.social/alice", now.Unix())
+	tallies.Add(model.TrendTypeTag, "caturday", "https://b.social/bob", now.Unix())
+	tallies.Add(model.TrendTypeTag, "caturday", "https://c.social/carol", now.Unix())
+
+	// Used many times, but only by two accounts
+	tallies.Add(model.TrendTypeTag, "golang", "https://a.social/alice", now.Unix())
+	tallies.Add(model.TrendTypeTag, "golang", "https://a.social/alice", now.Unix())
+	tallies.Add(model.TrendTypeTag, "golang", "https://a.social/alice", now.Unix())
+	tallies.Add(model.TrendTypeTag, "golang", "https://b.social/bob", now.Unix())
+
+	// Only used by one account, so it cannot trend
+	tallies.Add(model.TrendTypeTag, "spam", "https://d.social/dave", now.Unix())
+	tallies.Add(model.TrendTypeTag, "spam", "https://d.social/dave", now.Unix())
+
+	// Different type
+	tallies.Add(model.TrendTypeLink, "https://example.com", "https://a.social/alice", now.Unix())
+	tallies.Add(model.TrendTypeLink, "https://example.com", "https://b.social/bob", now.Unix())
+
+	top := tallies.Top(model.TrendTypeTag, now, 10)
+	require.Equal(t, 2, len(top))
+	require.Equal(t, "caturday", top[0].key)
+	require.Equal(t, "golang", top[1].key)
+	require.Equal(t, int64(4), top[1].uses)
+
+	top = tallies.Top(model.TrendTypeTag, now, 1)
+	require.Equal(t, 1, len(top))
+
+	top = tallies.Top(model.TrendTypeLink, now, 10)
+	require.Equal(t, 1, len(top))
+	require.Equal(t, "https://example.com", top[0].key)
+}
+
+func TestTrendTally_Score(t *testing.T) {
+
+	now := time.Unix(1700000000, 0)
+	tallies := trendTallies{}
+
+	tallies.Add(model.TrendTypeTag, "recent", "https://a.social/alice", now.Unix())
+	tallies.Add(model.TrendTypeTag, "older", "https://a.social/alice", now.Add(-24*time.Hour).Unix())
+
+	// Each account's contribution halves every day
+	require.InDelta(t, 1.0, tallies["TAG recent"].Score(now), 0.0001)
+	require.InDelta(t, 0.5, tallies["TAG older"].Score(now), 0.0001)
+
+	// Only the most recent use by each account counts
+	tallies.Add(model.TrendTypeTag, "older", "https://a.social/alice", now.Unix())
+	require.InDelta(t, 1.0, tallies["TAG older"].Score(now), 0.0001)
+}
+
+func TestTrendTally_History(t *testing.T) {
+
+	now := time.Unix(1700000000, 0)
+	today := trendDay(now.Unix())
+	tallies := trendTallies{}
+
+	tallies.Add(model.TrendTypeTag, "caturday", "https://a.social/alice", now.Unix())
+	tallies.Add(model.TrendTypeTag, "caturday", "https://a.social/alice", now.Unix())
+	tallies.Add(model.TrendTypeTag, "caturday", "https://b.social/bob", now.Unix())
+	tallies.Add(model.TrendTypeTag, "caturday", "https://b.social/bob", now.Add(-48*time.Hour).Unix())
+
+	history := tallies["TAG caturday"].History(now)
+
+	require.Equal(t, trendHistoryDays, len(history))
+	require.Equal(t, model.TrendHistory{Day: today, Uses: 3, Accounts: 2}, history[0])
+	require.Equal(t, model.TrendHistory{Day: today - 86400, Uses: 0, Accounts: 0}, history[1])
+	require.Equal(t, model.TrendHistory{Day: today - 2*86400, Uses: 1, Accounts: 1}, history[2])
+}
+
+func TestTrendLinks(t *testing.T) {
+
+	body := `<p>Read this: <a href="https://example.com/article?utm_source=mastodon#comments">article</a>
+		by <span class="h-card"><a href="https://a.social/@alice" class="u-url mention">@alice</a></span>
+		<a href="https://a.social/tags/news" class="mention hashtag" rel="tag">#news</a>
+		<a href="https://example.com/article">again</a>
+		<a href="/relative/link">relative</a>
+		<a href="https://other.com/page" rel="nofollow noopener">other</a></p>`
+
+	links := trendLinks(body)
+
+	require.Equal(t, []string{"https://example.com/article", "https://other.com/page"}, links)
+	require.Empty(t, trendLinks(""))
+}
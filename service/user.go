@@ -30,11 +30,13 @@ type User struct {
 	followers         data.Collection
 	following         data.Collection
 	rules             data.Collection
+	activityService   *ActivityStream
 	attachmentService *Attachment
 	ruleService       *Rule
 	emailService      *DomainEmail
 	keyService        *EncryptionKey
 	domainService     *Domain
+	domainPolicy      *DomainPolicy
 	folderService     *Folder
 	followerService   *Follower
 	streamService     *Stream
@@ -51,14 +53,16 @@ func NewUser() User {
  ******************************************/
 
 // Refresh updates any stateful data that is cached inside this service.
-func (service *User) Refresh(userCollection data.Collection, followerCollection data.Collection, followingCollection data.Collection, ruleCollection data.Collection, attachmentService *Attachment, domainService *Domain, emailService *DomainEmail, folderService *Folder, followerService *Follower, keyService *EncryptionKey, ruleService *Rule, streamService *Stream, host string) {
+func (service *User) Refresh(userCollection data.Collection, followerCollection data.Collection, followingCollection data.Collection, ruleCollection data.Collection, activityService *ActivityStream, attachmentService *Attachment, domainService *Domain, domainPolicyService *DomainPolicy, emailService *DomainEmail, folderService *Folder, followerService *Follower, keyService *EncryptionKey, ruleService *Rule, streamService *Stream, host string) {
 	service.collection = userCollection
 	service.followers = followerCollection
 	service.following = followingCollection
 	service.rules = ruleCollection
 
+	service.activityService = activityService
 	service.attachmentService = attachmentService
 	service.domainService = domainService
+	service.domainPolicy = domainPolicyService
 	service.emailService = emailService
 	service.folderService = folderService
 	service.followerService = followerService
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"github.com/benpate/data/option"
+	"github.com/benpate/exp"
+	"github.com/benpate/form"
+)
+
+// UserLookupProvider lists all of the Users on this domain, for use in reviewer/assignee pickers.
+type UserLookupProvider struct {
+	userService *User
+}
+
+func NewUserLookupProvider(userService *User) UserLookupProvider {
+	return UserLookupProvider{
+		userService: userService,
+	}
+}
+
+func (service UserLookupProvider) Get() []form.LookupCode {
+	users, _ := service.userService.Query(exp.All(), option.SortAsc("displayName"))
+	result := make([]form.LookupCode, 0, len(users))
+
+	for _, user := range users {
+		result = append(result, form.LookupCode{
+			Value: user.UserID.Hex(),
+			Label: user.DisplayName,
+		})
+	}
+
+	return result
+}
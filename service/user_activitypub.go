@@ -7,6 +7,7 @@ import (
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/benpate/derp"
 	"github.com/benpate/hannibal/outbox"
+	"github.com/benpate/hannibal/streams"
 	"github.com/benpate/rosetta/list"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -86,8 +87,8 @@ func (service *User) ActivityPubActor(userID primitive.ObjectID, withFollowers b
 		return outbox.Actor{}, derp.Wrap(err, location, "Error extracting private key", encryptionKey)
 	}
 
-	// Return the ActivityPub Actor
-	actor := outbox.NewActor(service.ActivityPubURL(userID), privateKey)
+	// Return the ActivityPub Actor.  Deliveries never reach suspended domains.
+	actor := outbox.NewActor(service.ActivityPubURL(userID), privateKey, outbox.WithClient(service.domainPolicy.Client(streams.NewDefaultClient())))
 
 	// Populate the Actor's ActivityPub Followers, if requested
 	if withFollowers {
@@ -101,10 +102,13 @@ func (service *User) ActivityPubActor(userID primitive.ObjectID, withFollowers b
 
 		// Get a filter to prevent sending to "Blocked" followers
 		ruleFilter := service.ruleService.Filter(userID, WithBlocksOnly())
-		followerIDs := ruleFilter.ChannelSend(followers)
+
+		// Collapse followers onto the shared inboxes of their servers
+		sharedInboxes := NewSharedInboxClient(service.domainPolicy.Client(streams.NewDefaultClient()))
+		followerIDs := sharedInboxes.Collapse(service.domainPolicy.ChannelAllow(ruleFilter.ChannelAllow(followers)))
 
 		// Add the channel of follower IDs to the Actor
-		actor.With(outbox.WithFollowers(followerIDs))
+		actor.With(outbox.WithFollowers(followerIDs), outbox.WithClient(sharedInboxes))
 	}
 
 	return actor, nil
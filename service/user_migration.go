@@ -0,0 +1,118 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/rosetta/sliceof"
+	"github.com/benpate/sherlock"
+)
+
+/******************************************
+ * Account Migration Methods
+ ******************************************/
+
+// SetAliases updates the list of other ActivityPub accounts that also belong to this User.
+// Aliases are resolved into their canonical actor IDs before they are saved.
+func (service *User) SetAliases(user *model.User, aliases []string) error {
+
+	const location = "service.User.SetAliases"
+
+	result := sliceof.NewString()
+
+	for _, alias := range aliases {
+
+		alias = strings.TrimSpace(alias)
+
+		if alias == "" {
+			continue
+		}
+
+		// Resolve the alias into an ActivityPub actor
+		actor, err := service.activityService.Load(alias, sherlock.AsActor())
+
+		if err != nil {
+			return derp.Wrap(err, location, "Unable to find account", alias, derp.WithBadRequest())
+		}
+
+		// RULE: Users cannot be aliases of themselves
+		if actor.ID() == user.ProfileURL {
+			continue
+		}
+
+		result = append(result, actor.ID())
+	}
+
+	user.AlsoKnownAs = result
+	return nil
+}
+
+// Move migrates this User to another ActivityPub account, and sends a "Move" activity
+// to all of the User's followers.  Per convention, the new account must already list
+// this User's profile URL in its "alsoKnownAs" property.
+func (service *User) Move(user *model.User, targetURL string) error {
+
+	const location = "service.User.Move"
+
+	// Load the target actor from the remote server
+	target, err := service.activityService.Load(targetURL, sherlock.AsActor())
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to find account", targetURL, derp.WithBadRequest())
+	}
+
+	// RULE: Users cannot move to themselves
+	if target.ID() == user.ProfileURL {
+		return derp.NewBadRequestError(location, "Cannot move an account to itself", targetURL)
+	}
+
+	// RULE: The new account must list this User as an alias
+	if !isAlsoKnownAs(target, user.ProfileURL) {
+		return derp.NewBadRequestError(location, "The new account must list this account as an alias before moving", target.ID())
+	}
+
+	// Update the User record
+	user.MovedTo = target.ID()
+
+	if err := service.Save(user, "Moved to "+target.ID()); err != nil {
+		return derp.Wrap(err, location, "Error saving User", user.UserID)
+	}
+
+	// Send the "Move" activity to all Followers
+	actor, err := service.ActivityPubActor(user.UserID, true)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Error loading ActivityPub actor", user.UserID)
+	}
+
+	activity := mapof.Any{
+		vocab.AtContext:      vocab.ContextTypeActivityStreams,
+		vocab.PropertyID:     user.ProfileURL + "#move-" + strconv.FormatInt(time.Now().Unix(), 10),
+		vocab.PropertyType:   vocab.ActivityTypeMove,
+		vocab.PropertyActor:  user.ProfileURL,
+		vocab.PropertyObject: user.ProfileURL,
+		vocab.PropertyTarget: target.ID(),
+		vocab.PropertyTo:     user.ActivityPubFollowersURL(),
+	}
+
+	go actor.Send(activity)
+	return nil
+}
+
+// isAlsoKnownAs returns TRUE if the provided actor lists the provided URL as one of its aliases
+func isAlsoKnownAs(actor streams.Document, url string) bool {
+
+	for alias := range actor.Get("alsoKnownAs").Channel() {
+		if alias.ID() == url {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsAlsoKnownAs(t *testing.T) {
+
+	single := streams.NewDocument(mapof.Any{
+		vocab.PropertyID: "https://new.social/users/alice",
+		"alsoKnownAs":    "https://old.social/users/alice",
+	})
+
+	multiple := streams.NewDocument(mapof.Any{
+		vocab.PropertyID: "https://new.social/users/alice",
+		"alsoKnownAs":    []any{"https://other.social/@alice", "https://old.social/users/alice"},
+	})
+
+	none := streams.NewDocument(mapof.Any{
+		vocab.PropertyID: "https://new.social/users/alice",
+	})
+
+	require.True(t, isAlsoKnownAs(single, "https://old.social/users/alice"))
+	require.True(t, isAlsoKnownAs(multiple, "https://old.social/users/alice"))
+	require.True(t, isAlsoKnownAs(multiple, "https://other.social/@alice"))
+	require.False(t, isAlsoKnownAs(single, "https://old.social/users/mallory"))
+	require.False(t, isAlsoKnownAs(none, "https://old.social/users/alice"))
+}
+
+func TestUserMove_Rules(t *testing.T) {
+
+	activityService := NewActivityStream()
+	activityService.Refresh(testMigrationClient{
+		"https://new.social/users/alice": {
+			vocab.PropertyID:   "https://new.social/users/alice",
+			vocab.PropertyType: vocab.ActorTypePerson,
+			"alsoKnownAs":      "https://local.social/@alice",
+		},
+		"https://new.social/users/mallory": {
+			vocab.PropertyID:   "https://new.social/users/mallory",
+			vocab.PropertyType: vocab.ActorTypePerson,
+		},
+		"https://local.social/@alice": {
+			vocab.PropertyID:   "https://local.social/@alice",
+			vocab.PropertyType: vocab.ActorTypePerson,
+		},
+	}, nil, nil)
+
+	service := User{activityService: &activityService}
+
+	user := model.NewUser()
+	user.ProfileURL = "https://local.social/@alice"
+
+	// Users cannot move to accounts that do not exist
+	err := service.Move(&user, "https://new.social/users/nobody")
+	require.NotNil(t, err)
+
+	// Users cannot move to themselves
+	err = service.Move(&user, "https://local.social/@alice")
+	require.NotNil(t, err)
+	require.Equal(t, 400, derp.ErrorCode(err))
+
+	// The new account must list this User as an alias
+	err = service.Move(&user, "https://new.social/users/mallory")
+	require.NotNil(t, err)
+	require.Equal(t, 400, derp.ErrorCode(err))
+
+	require.Empty(t, user.MovedTo)
+}
+
+// testMigrationClient is a streams.Client that returns documents from a map
+type testMigrationClient map[string]mapof.Any
+
+func (client testMigrationClient) Load(uri string, options ...any) (streams.Document, error) {
+
+	if value, ok := client[uri]; ok {
+		return streams.NewDocument(value, streams.WithClient(client)), nil
+	}
+
+	return streams.NilDocument(), derp.NewNotFoundError("testMigrationClient.Load", "Document not found", uri)
+}
@@ -18,6 +18,8 @@ func Attachment(document streams.Document) []map[string]any {
 			vocab.PropertyHeight:    attachment.Height(),
 			vocab.PropertyWidth:     attachment.Width(),
 			vocab.PropertyContent:   first(attachment.Content(), attachment.Get("value").String()),
+			vocab.PropertyName:      attachment.Name(),
+			"blurhash":              attachment.Get("blurhash").String(),
 		}
 
 		result = append(result, file)
@@ -39,6 +39,11 @@ func Object(document streams.Document) map[string]any {
 		"x-original":               document.Value(),
 	}
 
+	if quoteURL := Quote(actual); quoteURL != "" {
+		result["quoteUrl"] = quoteURL
+		result[vocab.PropertyContent] = removeQuoteInline(actual.Content())
+	}
+
 	if attachments := actual.Attachment(); attachments.NotNil() {
 
 		for attachment := attachments; attachment.NotNil(); attachment = attachment.Tail() {
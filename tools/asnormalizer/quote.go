@@ -0,0 +1,53 @@
+package asnormalizer
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+)
+
+// quoteInline matches the "RE: ..." paragraph that Mastodon (and Emissary)
+// add to quote posts for the benefit of servers that cannot display quotes.
+var quoteInline = regexp.MustCompile(`(?s)<p class="quote-inline">.*?</p>`)
+
+// Quote returns the URL of the document quoted by this document, if any.
+// It checks the many overlapping properties used by other servers,
+// then falls back to FEP-e232 object links.
+func Quote(document streams.Document) string {
+
+	for _, property := range []string{"quote", "quoteUrl", "quoteUri", "_misskey_quote"} {
+		if value := document.Get(property).ID(); value != "" {
+			return value
+		}
+	}
+
+	for tag := document.Tag(); tag.NotNil(); tag = tag.Tail() {
+		if head := tag.Head(); isQuoteLink(head) {
+			return first(head.Href(), head.ID())
+		}
+	}
+
+	return ""
+}
+
+// removeQuoteInline removes the fallback "RE: ..." paragraph from quote posts
+func removeQuoteInline(content string) string {
+	return strings.TrimSpace(quoteInline.ReplaceAllString(content, ""))
+}
+
+// isQuoteLink returns TRUE if the provided tag is an FEP-e232 object link
+func isQuoteLink(tag streams.Document) bool {
+
+	if tag.Type() != vocab.CoreTypeLink {
+		return false
+	}
+
+	switch tag.MediaType() {
+	case vocab.ContentTypeActivityPub, vocab.ContentTypeJSONLDWithProfile:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,57 @@
+package asnormalizer
+
+import (
+	"testing"
+
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveQuoteInline(t *testing.T) {
+
+	tests := []struct {
+		content  string
+		expected string
+	}{
+		{`<p>Look at this</p><p class="quote-inline">RE: <a href="https://remote.social/notes/1">https://remote.social/notes/1</a></p>`, `<p>Look at this</p>`},
+		{`<p class="quote-inline">RE:<br/><a href="https://remote.social/notes/1">link</a></p> <p>Look at this</p>`, `<p>Look at this</p>`},
+		{`<p>Look at this</p><p>RE: the meeting</p>`, `<p>Look at this</p><p>RE: the meeting</p>`},
+		{``, ``},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.expected, removeQuoteInline(test.content), test.content)
+	}
+}
+
+func TestQuote(t *testing.T) {
+
+	// Direct properties are preferred
+	document := streams.NewDocument(mapof.Any{
+		"quoteUrl": "https://remote.social/notes/1",
+		vocab.PropertyTag: []any{mapof.Any{
+			vocab.PropertyType:      vocab.CoreTypeLink,
+			vocab.PropertyMediaType: vocab.ContentTypeActivityPub,
+			vocab.PropertyHref:      "https://remote.social/notes/2",
+		}},
+	})
+	require.Equal(t, "https://remote.social/notes/1", Quote(document))
+
+	// FEP-e232 object links are used as a fallback
+	document = streams.NewDocument(mapof.Any{
+		vocab.PropertyTag: []any{
+			mapof.Any{vocab.PropertyType: vocab.LinkTypeMention, vocab.PropertyHref: "https://remote.social/users/bob"},
+			mapof.Any{
+				vocab.PropertyType:      vocab.CoreTypeLink,
+				vocab.PropertyMediaType: vocab.ContentTypeJSONLDWithProfile,
+				vocab.PropertyHref:      "https://remote.social/notes/2",
+			},
+		},
+	})
+	require.Equal(t, "https://remote.social/notes/2", Quote(document))
+
+	// Documents without quotes
+	require.Equal(t, "", Quote(streams.NewDocument(mapof.Any{vocab.PropertyContent: "Hello"})))
+}
@@ -29,6 +29,11 @@ func Tags(document streams.Document) []map[string]any {
 			continue
 		}
 
+		// Quote links are normalized separately (see Quote)
+		if isQuoteLink(tag) {
+			continue
+		}
+
 		// If the tag is allowed, then include it in the result.
 		result = append(result, map[string]any{
 			vocab.PropertyType: vocab.PropertyTag,
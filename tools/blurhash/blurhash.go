@@ -0,0 +1,166 @@
+// Package blurhash encodes images into compact BlurHash strings, which clients use to
+// display colorful placeholders while the full image is loading.
+// https://github.com/woltapp/blurhash/blob/master/Algorithm.md
+package blurhash
+
+import (
+	"image"
+	"math"
+	"strings"
+
+	"github.com/benpate/derp"
+)
+
+// maxSamples is the maximum number of pixels sampled along each axis.  BlurHashes only
+// describe the low-frequency colors of an image, so larger images are sampled on a grid.
+const maxSamples = 64
+
+const characters = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// Encode returns the BlurHash for the provided image, using the provided number of
+// horizontal and vertical components (each between 1 and 9)
+func Encode(img image.Image, xComponents int, yComponents int) (string, error) {
+
+	const location = "blurhash.Encode"
+
+	if (xComponents < 1) || (xComponents > 9) || (yComponents < 1) || (yComponents > 9) {
+		return "", derp.NewInternalError(location, "Components must be between 1 and 9", xComponents, yComponents)
+	}
+
+	bounds := img.Bounds()
+	width := bounds.Dx()
+	height := bounds.Dy()
+
+	if (width == 0) || (height == 0) {
+		return "", derp.NewBadRequestError(location, "Image must not be empty")
+	}
+
+	// Read (a sample of) the image into linear RGB
+	sampleWidth := min(width, maxSamples)
+	sampleHeight := min(height, maxSamples)
+	pixels := make([][3]float64, sampleWidth*sampleHeight)
+
+	for y := 0; y < sampleHeight; y++ {
+		for x := 0; x < sampleWidth; x++ {
+			r, g, b, _ := img.At(bounds.Min.X+x*width/sampleWidth, bounds.Min.Y+y*height/sampleHeight).RGBA()
+			pixels[y*sampleWidth+x] = [3]float64{
+				sRGBToLinear(int(r >> 8)),
+				sRGBToLinear(int(g >> 8)),
+				sRGBToLinear(int(b >> 8)),
+			}
+		}
+	}
+
+	// Calculate DCT factors
+	factors := make([][3]float64, 0, xComponents*yComponents)
+
+	for j := 0; j < yComponents; j++ {
+		for i := 0; i < xComponents; i++ {
+
+			normalization := 2.0
+			if (i == 0) && (j == 0) {
+				normalization = 1.0
+			}
+
+			var factor [3]float64
+
+			for y := 0; y < sampleHeight; y++ {
+				for x := 0; x < sampleWidth; x++ {
+					basis := normalization *
+						math.Cos(math.Pi*float64(i)*float64(x)/float64(sampleWidth)) *
+						math.Cos(math.Pi*float64(j)*float64(y)/float64(sampleHeight))
+
+					pixel := pixels[y*sampleWidth+x]
+					factor[0] += basis * pixel[0]
+					factor[1] += basis * pixel[1]
+					factor[2] += basis * pixel[2]
+				}
+			}
+
+			scale := 1.0 / float64(sampleWidth*sampleHeight)
+			factors = append(factors, [3]float64{factor[0] * scale, factor[1] * scale, factor[2] * scale})
+		}
+	}
+
+	// Encode the result
+	var result strings.Builder
+
+	dc := factors[0]
+	ac := factors[1:]
+
+	result.WriteString(encode83((xComponents-1)+(yComponents-1)*9, 1))
+
+	maximumValue := 1.0
+
+	if len(ac) > 0 {
+		actualMaximum := 0.0
+		for _, factor := range ac {
+			actualMaximum = math.Max(actualMaximum, math.Max(math.Abs(factor[0]), math.Max(math.Abs(factor[1]), math.Abs(factor[2]))))
+		}
+
+		quantizedMaximum := int(math.Max(0, math.Min(82, math.Floor(actualMaximum*166-0.5))))
+		maximumValue = float64(quantizedMaximum+1) / 166
+		result.WriteString(encode83(quantizedMaximum, 1))
+	} else {
+		result.WriteString(encode83(0, 1))
+	}
+
+	result.WriteString(encode83(encodeDC(dc), 4))
+
+	for _, factor := range ac {
+		result.WriteString(encode83(encodeAC(factor, maximumValue), 2))
+	}
+
+	return result.String(), nil
+}
+
+func encodeDC(value [3]float64) int {
+	return (linearToSRGB(value[0]) << 16) + (linearToSRGB(value[1]) << 8) + linearToSRGB(value[2])
+}
+
+func encodeAC(value [3]float64, maximumValue float64) int {
+
+	quantize := func(v float64) int {
+		return int(math.Max(0, math.Min(18, math.Floor(signPow(v/maximumValue, 0.5)*9+9.5))))
+	}
+
+	return quantize(value[0])*19*19 + quantize(value[1])*19 + quantize(value[2])
+}
+
+func encode83(value int, length int) string {
+
+	result := make([]byte, length)
+
+	for i := 1; i <= length; i++ {
+		digit := (value / int(math.Pow(83, float64(length-i)))) % 83
+		result[i-1] = characters[digit]
+	}
+
+	return string(result)
+}
+
+func sRGBToLinear(value int) float64 {
+
+	v := float64(value) / 255
+
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(value float64) int {
+
+	v := math.Max(0, math.Min(1, value))
+
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+func signPow(value float64, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(value), exp), value)
+}
@@ -0,0 +1,35 @@
+package blurhash
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode_SolidColor(t *testing.T) {
+
+	img := image.NewRGBA(image.Rect(0, 0, 200, 100))
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.RGBA{255, 0, 0, 255}}, image.Point{}, draw.Src)
+
+	hash, err := Encode(img, 4, 3)
+	require.Nil(t, err)
+
+	// Size flag (4x3), quantized maximum, DC (pure red), followed by 11 AC components
+	require.Equal(t, 28, len(hash))
+	require.Equal(t, "L", hash[:1])
+	require.Equal(t, encode83(0xFF0000, 4), hash[2:6])
+}
+
+func TestEncode_InvalidComponents(t *testing.T) {
+
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+
+	_, err := Encode(img, 0, 3)
+	require.NotNil(t, err)
+
+	_, err = Encode(img, 4, 10)
+	require.NotNil(t, err)
+}
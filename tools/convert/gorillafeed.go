@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/hannibal/streams"
 	"github.com/gorilla/feeds"
 )
 
@@ -27,3 +28,28 @@ func StreamToGorillaFeed(stream model.Stream) *feeds.Item {
 
 	return result
 }
+
+// DocumentToGorillaFeed converts an ActivityStreams document into a gorilla/feeds
+// item, so that it can be included in RSS and Atom feeds.
+func DocumentToGorillaFeed(document streams.Document) *feeds.Item {
+
+	result := &feeds.Item{
+		Id:          document.ID(),
+		Title:       document.Name(),
+		Description: document.Summary(),
+		Content:     document.Content(),
+		Link: &feeds.Link{
+			Href: document.URLOrID(),
+		},
+		Created: document.Published(),
+		Updated: document.Updated(),
+	}
+
+	if author := document.AttributedTo(); author.Name() != "" {
+		result.Author = &feeds.Author{
+			Name: author.Name(),
+		}
+	}
+
+	return result
+}
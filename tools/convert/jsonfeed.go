@@ -5,6 +5,7 @@ import (
 
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/benpate/data"
+	"github.com/benpate/hannibal/streams"
 	"github.com/benpate/rosetta/first"
 	"github.com/benpate/rosetta/html"
 	"github.com/benpate/rosetta/iterator"
@@ -50,7 +51,32 @@ func StreamToJsonFeed(stream model.Stream) jsonfeed.Item {
 		}
 	}
 
-	// TODO: LOW: Attachments for podcasts, etc.
+	// Attachments (podcasts, etc.) are loaded separately.  See AttachmentsToJsonFeed
+
+	return result
+}
+
+// DocumentToJsonFeed converts an ActivityStreams document into a JSON Feed item
+func DocumentToJsonFeed(document streams.Document) jsonfeed.Item {
+
+	result := jsonfeed.Item{
+		ID:            document.ID(),
+		URL:           document.URLOrID(),
+		Title:         document.Name(),
+		ContentHTML:   first.String(document.Content(), " "),
+		Summary:       document.Summary(),
+		Image:         document.Image().URL(),
+		DatePublished: document.Published(),
+		DateModified:  document.Updated(),
+	}
+
+	if author := document.AttributedTo(); author.Name() != "" {
+		result.Author = &jsonfeed.Author{
+			Name:   author.Name(),
+			URL:    author.ID(),
+			Avatar: author.Icon().URL(),
+		}
+	}
 
 	return result
 }
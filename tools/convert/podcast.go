@@ -0,0 +1,194 @@
+package convert
+
+import (
+	"encoding/xml"
+	"strconv"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/rosetta/first"
+	"github.com/kr/jsonfeed"
+)
+
+// PodcastFeed is an RSS 2.0 document that includes the iTunes namespace, so that podcast apps can subscribe to Streams with audio attachments.
+type PodcastFeed struct {
+	XMLName   xml.Name       `xml:"rss"`
+	Version   string         `xml:"version,attr"`
+	ITunesNS  string         `xml:"xmlns:itunes,attr"`
+	ContentNS string         `xml:"xmlns:content,attr"`
+	Channel   PodcastChannel `xml:"channel"`
+}
+
+// PodcastChannel contains the show-level metadata for a podcast feed
+type PodcastChannel struct {
+	Title          string         `xml:"title"`
+	Link           string         `xml:"link"`
+	Description    string         `xml:"description"`
+	Generator      string         `xml:"generator,omitempty"`
+	Cloud          *RSSCloud      `xml:"cloud,omitempty"`
+	LastBuildDate  string         `xml:"lastBuildDate,omitempty"`
+	ITunesAuthor   string         `xml:"itunes:author,omitempty"`
+	ITunesSummary  string         `xml:"itunes:summary,omitempty"`
+	ITunesImage    *PodcastImage  `xml:"itunes:image,omitempty"`
+	ITunesExplicit string         `xml:"itunes:explicit"`
+	ITunesType     string         `xml:"itunes:type"`
+	Items          []PodcastItem  `xml:"item"`
+	Image          *PodcastRSSImg `xml:"image,omitempty"`
+}
+
+// PodcastItem is a single episode in a podcast feed
+type PodcastItem struct {
+	Title             string            `xml:"title"`
+	Link              string            `xml:"link,omitempty"`
+	Description       string            `xml:"description,omitempty"`
+	ContentEncoded    *PodcastCDATA     `xml:"content:encoded,omitempty"`
+	GUID              PodcastGUID       `xml:"guid"`
+	PubDate           string            `xml:"pubDate,omitempty"`
+	Author            string            `xml:"itunes:author,omitempty"`
+	Enclosure         *PodcastEnclosure `xml:"enclosure,omitempty"`
+	ITunesDuration    string            `xml:"itunes:duration,omitempty"`
+	ITunesImage       *PodcastImage     `xml:"itunes:image,omitempty"`
+	ITunesEpisodeType string            `xml:"itunes:episodeType,omitempty"`
+}
+
+// PodcastCDATA wraps a string in a CDATA section
+type PodcastCDATA struct {
+	Value string `xml:",cdata"`
+}
+
+// PodcastGUID is the globally unique identifier of an episode
+type PodcastGUID struct {
+	IsPermaLink string `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// PodcastEnclosure links to the media file of an episode
+type PodcastEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int64  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// PodcastImage is an iTunes image tag
+type PodcastImage struct {
+	Href string `xml:"href,attr"`
+}
+
+// PodcastRSSImg is a standard RSS 2.0 channel image
+type PodcastRSSImg struct {
+	URL   string `xml:"url"`
+	Title string `xml:"title"`
+	Link  string `xml:"link"`
+}
+
+// NewPodcastFeed returns a fully initialized PodcastFeed
+func NewPodcastFeed(title string, link string, description string, author string, imageURL string) PodcastFeed {
+
+	result := PodcastFeed{
+		Version:   "2.0",
+		ITunesNS:  "http://www.itunes.com/dtds/podcast-1.0.dtd",
+		ContentNS: "http://purl.org/rss/1.0/modules/content/",
+		Channel: PodcastChannel{
+			Title:          title,
+			Link:           link,
+			Description:    first.String(description, title),
+			Generator:      "Emissary",
+			LastBuildDate:  time.Now().UTC().Format(time.RFC1123Z),
+			ITunesAuthor:   author,
+			ITunesSummary:  description,
+			ITunesExplicit: "false",
+			ITunesType:     "episodic",
+			Items:          make([]PodcastItem, 0),
+		},
+	}
+
+	if imageURL != "" {
+		result.Channel.ITunesImage = &PodcastImage{Href: imageURL}
+		result.Channel.Image = &PodcastRSSImg{URL: imageURL, Title: title, Link: link}
+	}
+
+	return result
+}
+
+// StreamToPodcastItem converts a Stream and its Attachments into a podcast episode.
+// The first audio (or video) Attachment becomes the enclosure.
+func StreamToPodcastItem(stream model.Stream, attachments []model.Attachment) PodcastItem {
+
+	result := PodcastItem{
+		Title:             stream.Label,
+		Link:              stream.URL,
+		Description:       first.String(stream.Summary, stream.Label),
+		GUID:              PodcastGUID{IsPermaLink: "false", Value: stream.StreamID.Hex()},
+		PubDate:           time.Unix(stream.PublishDate, 0).UTC().Format(time.RFC1123Z),
+		ITunesEpisodeType: "full",
+	}
+
+	if stream.Content.HTML != "" {
+		result.ContentEncoded = &PodcastCDATA{Value: stream.Content.HTML}
+	}
+
+	if stream.AttributedTo.NotEmpty() {
+		result.Author = stream.AttributedTo.Name
+	}
+
+	if stream.IconURL != "" {
+		result.ITunesImage = &PodcastImage{Href: stream.IconURL}
+	}
+
+	for _, attachment := range attachments {
+
+		if attachment.IsAudio() || attachment.IsVideo() {
+
+			result.Enclosure = &PodcastEnclosure{
+				URL:    attachment.URL,
+				Length: attachment.DownloadSize(),
+				Type:   attachment.DownloadMimeType(),
+			}
+
+			if attachment.Duration > 0 {
+				result.ITunesDuration = strconv.Itoa(attachment.Duration)
+			}
+
+			break
+		}
+	}
+
+	return result
+}
+
+// IsPodcast returns TRUE if any of the provided Attachments is an audio file
+func IsPodcast(attachments map[string][]model.Attachment) bool {
+
+	for _, streamAttachments := range attachments {
+		for _, attachment := range streamAttachments {
+			if attachment.IsAudio() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// AttachmentsToJsonFeed converts a Stream's media Attachments into JSON Feed attachments
+func AttachmentsToJsonFeed(attachments []model.Attachment) []jsonfeed.Attachment {
+
+	result := make([]jsonfeed.Attachment, 0, len(attachments))
+
+	for _, attachment := range attachments {
+
+		if !attachment.IsAudio() && !attachment.IsVideo() {
+			continue
+		}
+
+		result = append(result, jsonfeed.Attachment{
+			URL:               attachment.URL,
+			MIMEType:          attachment.DownloadMimeType(),
+			Title:             attachment.Label,
+			SizeInBytes:       int(attachment.DownloadSize()),
+			DurationInSeconds: attachment.Duration,
+		})
+	}
+
+	return result
+}
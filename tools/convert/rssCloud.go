@@ -0,0 +1,61 @@
+package convert
+
+import (
+	"encoding/xml"
+	"net/url"
+	"strings"
+)
+
+// RSSCloud is the RSS 2.0 <cloud> element, which tells subscribers where to
+// register for real-time update notifications.
+// https://www.rssboard.org/rsscloud-interface
+type RSSCloud struct {
+	XMLName           xml.Name `xml:"cloud"`
+	Domain            string   `xml:"domain,attr"`
+	Port              string   `xml:"port,attr"`
+	Path              string   `xml:"path,attr"`
+	RegisterProcedure string   `xml:"registerProcedure,attr"`
+	Protocol          string   `xml:"protocol,attr"`
+}
+
+// NewRSSCloud returns an RSSCloud element that points to the provided
+// "pleaseNotify" endpoint.  The second value is FALSE if the endpoint is not a valid URL.
+func NewRSSCloud(endpoint string) (RSSCloud, bool) {
+
+	endpointURL, err := url.Parse(endpoint)
+
+	if (err != nil) || (endpointURL.Hostname() == "") {
+		return RSSCloud{}, false
+	}
+
+	port := endpointURL.Port()
+
+	if port == "" {
+		if endpointURL.Scheme == "http" {
+			port = "80"
+		} else {
+			port = "443"
+		}
+	}
+
+	return RSSCloud{
+		Domain:            endpointURL.Hostname(),
+		Port:              port,
+		Path:              endpointURL.EscapedPath(),
+		RegisterProcedure: "",
+		Protocol:          "http-post",
+	}, true
+}
+
+// InsertRSSCloud adds an RSSCloud element to the <channel> of an RSS document
+// that has already been generated (e.g. by gorilla/feeds, which does not support it)
+func InsertRSSCloud(rss string, cloud RSSCloud) string {
+
+	element, err := xml.Marshal(cloud)
+
+	if err != nil {
+		return rss
+	}
+
+	return strings.Replace(rss, "<channel>", "<channel>\n    "+string(element), 1)
+}
@@ -0,0 +1,175 @@
+// Package readability extracts the main article content from an HTML page,
+// discarding navigation, sidebars, comments, and other page chrome.
+package readability
+
+import (
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/benpate/derp"
+	"golang.org/x/net/html"
+)
+
+// minimumLength is the shortest amount of text (in characters) that counts as an article
+const minimumLength = 250
+
+// unlikelySelectors identifies page elements that are never part of the main article
+const unlikelySelectors = "script, style, noscript, template, nav, header, footer, aside, form, button, select, svg, " +
+	"[role=navigation], [role=banner], [role=contentinfo], [role=complementary], [aria-hidden=true], " +
+	".comments, #comments, .sidebar, #sidebar, .share, .social, .related, .advertisement, .ad, .newsletter"
+
+// Extract reads an HTML page and returns the HTML of its main article.  Relative links
+// and images are resolved against baseURL.  The returned HTML is NOT sanitized.
+func Extract(reader io.Reader, baseURL string) (string, error) {
+
+	const location = "readability.Extract"
+
+	doc, err := goquery.NewDocumentFromReader(reader)
+
+	if err != nil {
+		return "", derp.Wrap(err, location, "Error parsing HTML document", baseURL)
+	}
+
+	// Remove everything that is obviously not content
+	doc.Find(unlikelySelectors).Remove()
+
+	// Find the element that contains the article
+	best := bestCandidate(doc)
+
+	if best == nil {
+		return "", derp.NewNotFoundError(location, "No article content found", baseURL)
+	}
+
+	if length := textLength(best); length < minimumLength {
+		return "", derp.NewNotFoundError(location, "Article content is too short", baseURL, length)
+	}
+
+	resolveURLs(best, baseURL)
+
+	result, err := best.Html()
+
+	if err != nil {
+		return "", derp.Wrap(err, location, "Error rendering article HTML", baseURL)
+	}
+
+	return strings.TrimSpace(result), nil
+}
+
+// bestCandidate returns the element most likely to contain the article text.
+// Semantic <article> and [itemprop=articleBody] elements win if they are present.
+// Otherwise, each paragraph awards points to its parent (and half as many to its
+// grandparent), and the element with the most points wins.
+func bestCandidate(doc *goquery.Document) *goquery.Selection {
+
+	// Prefer semantic markup when it is available
+	if semantic := longest(doc.Find("[itemprop=articleBody], article, main, [role=main]")); semantic != nil {
+		if textLength(semantic) >= minimumLength {
+			return semantic
+		}
+	}
+
+	// Otherwise, score paragraphs by their containers
+	scores := make(map[*html.Node]float64)
+
+	addScore := func(selection *goquery.Selection, score float64) {
+		if selection.Length() > 0 {
+			scores[selection.Get(0)] += score
+		}
+	}
+
+	doc.Find("p, pre, td, blockquote").Each(func(_ int, paragraph *goquery.Selection) {
+
+		text := strings.TrimSpace(paragraph.Text())
+
+		if len(text) < 25 {
+			return
+		}
+
+		score := 1 + float64(strings.Count(text, ",")) + min(float64(len(text))/100, 3)
+
+		parent := paragraph.Parent()
+		addScore(parent, score)
+		addScore(parent.Parent(), score/2)
+	})
+
+	var result *goquery.Selection
+	var bestScore float64
+
+	for node, score := range scores {
+
+		selection := doc.FindNodes(node)
+
+		// Penalize containers that are mostly links
+		score = score * (1 - linkDensity(selection))
+
+		if score > bestScore {
+			result = selection
+			bestScore = score
+		}
+	}
+
+	return result
+}
+
+// longest returns the selected element with the most text
+func longest(selection *goquery.Selection) *goquery.Selection {
+
+	var result *goquery.Selection
+	var length int
+
+	selection.Each(func(_ int, item *goquery.Selection) {
+		if itemLength := textLength(item); itemLength > length {
+			result = item
+			length = itemLength
+		}
+	})
+
+	return result
+}
+
+// textLength returns the length of the visible text in a selection
+func textLength(selection *goquery.Selection) int {
+	return len(strings.Join(strings.Fields(selection.Text()), " "))
+}
+
+// linkDensity returns the fraction of a selection's text that is inside of links
+func linkDensity(selection *goquery.Selection) float64 {
+
+	total := textLength(selection)
+
+	if total == 0 {
+		return 0
+	}
+
+	links := 0
+	selection.Find("a").Each(func(_ int, link *goquery.Selection) {
+		links += textLength(link)
+	})
+
+	return float64(links) / float64(total)
+}
+
+// resolveURLs rewrites relative links and images so that they point to the original website
+func resolveURLs(selection *goquery.Selection, baseURL string) {
+
+	base, err := url.Parse(baseURL)
+
+	if err != nil {
+		return
+	}
+
+	resolve := func(attribute string) func(int, *goquery.Selection) {
+		return func(_ int, item *goquery.Selection) {
+			if value, ok := item.Attr(attribute); ok {
+				if ref, err := url.Parse(strings.TrimSpace(value)); err == nil {
+					item.SetAttr(attribute, base.ResolveReference(ref).String())
+				}
+			}
+		}
+	}
+
+	selection.Find("a[href]").Each(resolve("href"))
+	selection.Find("img[src]").Each(resolve("src"))
+}
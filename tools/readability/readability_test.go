@@ -0,0 +1,56 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testParagraph = "This is a long paragraph of article text, with several commas, clauses, and enough words to look like real content to the extraction algorithm."
+
+func TestExtract_Paragraphs(t *testing.T) {
+
+	body := `<html><body>
+		<nav><a href="/">Home</a> <a href="/about">About</a></nav>
+		<div class="layout">
+			<div class="menu"><p><a href="/one">A list of links that is long enough to be scored</a></p></div>
+			<div class="post">
+				<p>` + testParagraph + `</p>
+				<p>` + testParagraph + ` <a href="/more">Read more</a></p>
+				<p><img src="images/photo.jpg"></p>
+				<p>` + testParagraph + `</p>
+			</div>
+		</div>
+		<footer><p>Copyright, all rights reserved, by somebody with a long footer paragraph.</p></footer>
+		<script>alert("nope")</script>
+	</body></html>`
+
+	result, err := Extract(strings.NewReader(body), "https://example.com/blog/post")
+	require.Nil(t, err)
+	require.Equal(t, 3, strings.Count(result, testParagraph))
+	require.Contains(t, result, `href="https://example.com/more"`)
+	require.Contains(t, result, `src="https://example.com/blog/images/photo.jpg"`)
+	require.NotContains(t, result, "Home")
+	require.NotContains(t, result, "Copyright")
+	require.NotContains(t, result, "alert")
+	require.NotContains(t, result, "A list of links")
+}
+
+func TestExtract_Article(t *testing.T) {
+
+	body := `<html><body>
+		<aside><p>` + testParagraph + `</p></aside>
+		<article><h1>Title</h1><div><p>` + testParagraph + `</p><p>` + testParagraph + `</p></div></article>
+	</body></html>`
+
+	result, err := Extract(strings.NewReader(body), "https://example.com/")
+	require.Nil(t, err)
+	require.Contains(t, result, "<h1>Title</h1>")
+	require.Equal(t, 2, strings.Count(result, testParagraph))
+}
+
+func TestExtract_TooShort(t *testing.T) {
+	_, err := Extract(strings.NewReader(`<html><body><p>Just a short note, nothing more to see here.</p></body></html>`), "https://example.com/")
+	require.NotNil(t, err)
+}
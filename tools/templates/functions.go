@@ -189,6 +189,10 @@ func FuncMap(icons icon.Provider) template.FuncMap {
 			return humanize.Time(valueTime)
 		},
 
+		"humanizeBytes": func(value any) string {
+			return humanize.IBytes(uint64(max(convert.Int64(value), 0)))
+		},
+
 		"tinyDate": func(value any) string {
 			valueTime := convert.Time(value)
 			if valueTime.IsZero() {
@@ -0,0 +1,190 @@
+// Package transcoder is a thin wrapper around the ffmpeg and ffprobe command line tools.
+// It probes uploaded video and audio files, converts them into web-safe renditions,
+// and extracts poster frames from videos.
+package transcoder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/benpate/derp"
+)
+
+// VideoExtension is the file extension of renditions created by Video
+const VideoExtension = ".mp4"
+
+// AudioExtension is the file extension of renditions created by Audio
+const AudioExtension = ".mp3"
+
+// ProbeTimeout is the maximum amount of time that ffprobe can run
+const ProbeTimeout = time.Minute
+
+// MinimumTimeout is the least amount of time that ffmpeg is allowed to run, even for very short files
+const MinimumTimeout = 2 * time.Minute
+
+// MaximumTimeout is the most amount of time that ffmpeg is allowed to run, no matter how long the file is
+const MaximumTimeout = 2 * time.Hour
+
+// timeoutPerSecond is the amount of time that ffmpeg is allowed to run for each second of media
+const timeoutPerSecond = 2 * time.Second
+
+// IsInstalled returns TRUE if both ffmpeg and ffprobe are available on this system
+func IsInstalled() bool {
+
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return false
+	}
+
+	if _, err := exec.LookPath("ffprobe"); err != nil {
+		return false
+	}
+
+	return true
+}
+
+// Info describes the dimensions and duration of a media file
+type Info struct {
+	Width    int // Width of the first video stream (in pixels)
+	Height   int // Height of the first video stream (in pixels)
+	Duration int // Duration of the file (in seconds)
+}
+
+// Timeout returns the amount of time that ffmpeg is allowed to run on this media file.
+// Files whose duration is unknown are allowed the maximum amount of time.
+func (info Info) Timeout() time.Duration {
+
+	if info.Duration <= 0 {
+		return MaximumTimeout
+	}
+
+	return min(max(time.Duration(info.Duration)*timeoutPerSecond, MinimumTimeout), MaximumTimeout)
+}
+
+// Probe uses ffprobe to read the dimensions and duration of a media file
+func Probe(filename string) (Info, error) {
+
+	const location = "transcoder.Probe"
+
+	output, err := run(ProbeTimeout, "ffprobe", "-v", "error", "-print_format", "json", "-show_format", "-show_streams", filename)
+
+	if err != nil {
+		return Info{}, derp.Wrap(err, location, "Error probing media file", filename)
+	}
+
+	return parseProbe(output)
+}
+
+// parseProbe reads the dimensions and duration from ffprobe's JSON output
+func parseProbe(output []byte) (Info, error) {
+
+	probe := struct {
+		Format struct {
+			Duration string `json:"duration"`
+		} `json:"format"`
+		Streams []struct {
+			CodecType string `json:"codec_type"`
+			Width     int    `json:"width"`
+			Height    int    `json:"height"`
+		} `json:"streams"`
+	}{}
+
+	if err := json.Unmarshal(output, &probe); err != nil {
+		return Info{}, derp.Wrap(err, "transcoder.parseProbe", "Error parsing ffprobe output", string(output))
+	}
+
+	result := Info{}
+
+	if duration, err := strconv.ParseFloat(probe.Format.Duration, 64); err == nil {
+		result.Duration = int(math.Ceil(duration))
+	}
+
+	for _, stream := range probe.Streams {
+		if stream.CodecType == "video" {
+			result.Width = stream.Width
+			result.Height = stream.Height
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// Video converts a video file into an H.264/AAC MP4 that can be played by all major browsers
+func Video(input string, output string, info Info) error {
+
+	_, err := run(info.Timeout(), "ffmpeg", "-y", "-v", "error",
+		"-i", input,
+		"-c:v", "libx264", "-preset", "veryfast", "-crf", "23", "-pix_fmt", "yuv420p",
+		"-vf", "scale=trunc(iw/2)*2:trunc(ih/2)*2",
+		"-c:a", "aac", "-b:a", "128k",
+		"-movflags", "+faststart",
+		"-f", "mp4", output,
+	)
+
+	if err != nil {
+		return derp.Wrap(err, "transcoder.Video", "Error transcoding video", input)
+	}
+
+	return nil
+}
+
+// Audio converts an audio file into an MP3 that can be played by all major browsers
+func Audio(input string, output string, info Info) error {
+
+	_, err := run(info.Timeout(), "ffmpeg", "-y", "-v", "error",
+		"-i", input,
+		"-vn",
+		"-c:a", "libmp3lame", "-q:a", "4",
+		"-f", "mp3", output,
+	)
+
+	if err != nil {
+		return derp.Wrap(err, "transcoder.Audio", "Error transcoding audio", input)
+	}
+
+	return nil
+}
+
+// Poster extracts a representative frame from a video file and saves it as a WebP image
+func Poster(input string, output string, info Info) error {
+
+	_, err := run(info.Timeout(), "ffmpeg", "-y", "-v", "error",
+		"-i", input,
+		"-vf", "thumbnail",
+		"-frames:v", "1",
+		"-c:v", "libwebp",
+		"-f", "image2", output,
+	)
+
+	if err != nil {
+		return derp.Wrap(err, "transcoder.Poster", "Error extracting poster frame", input)
+	}
+
+	return nil
+}
+
+// run executes a command and returns its standard output.  The command
+// is killed if it does not finish before the provided timeout.
+func run(timeout time.Duration, name string, args ...string) ([]byte, error) {
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	command := exec.CommandContext(ctx, name, args...)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+
+	if err := command.Run(); err != nil {
+		return nil, derp.Wrap(err, "transcoder.run", "Error running "+name, stderr.String(), args)
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,61 @@
+package transcoder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProbe_Video(t *testing.T) {
+
+	output := []byte(`{
+		"streams": [
+			{"index": 0, "codec_type": "audio", "codec_name": "aac"},
+			{"index": 1, "codec_type": "video", "codec_name": "h264", "width": 1920, "height": 1080},
+			{"index": 2, "codec_type": "video", "codec_name": "mjpeg", "width": 320, "height": 240}
+		],
+		"format": {"filename": "test.mov", "duration": "12.200000"}
+	}`)
+
+	info, err := parseProbe(output)
+
+	require.Nil(t, err)
+	require.Equal(t, Info{Width: 1920, Height: 1080, Duration: 13}, info)
+}
+
+func TestParseProbe_Audio(t *testing.T) {
+
+	output := []byte(`{
+		"streams": [{"index": 0, "codec_type": "audio", "codec_name": "flac"}],
+		"format": {"filename": "test.flac", "duration": "185.000000"}
+	}`)
+
+	info, err := parseProbe(output)
+
+	require.Nil(t, err)
+	require.Equal(t, Info{Duration: 185}, info)
+}
+
+func TestParseProbe_MissingDuration(t *testing.T) {
+
+	output := []byte(`{"streams": [{"codec_type": "video", "width": 640, "height": 480}], "format": {"duration": "N/A"}}`)
+
+	info, err := parseProbe(output)
+
+	require.Nil(t, err)
+	require.Equal(t, Info{Width: 640, Height: 480}, info)
+}
+
+func TestParseProbe_Invalid(t *testing.T) {
+
+	_, err := parseProbe([]byte("ffprobe: not json"))
+	require.NotNil(t, err)
+}
+
+func TestInfoTimeout(t *testing.T) {
+	require.Equal(t, MaximumTimeout, Info{}.Timeout())
+	require.Equal(t, MinimumTimeout, Info{Duration: 5}.Timeout())
+	require.Equal(t, 20*time.Minute, Info{Duration: 600}.Timeout())
+	require.Equal(t, MaximumTimeout, Info{Duration: 24 * 60 * 60}.Timeout())
+}